@@ -4,6 +4,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
@@ -56,7 +58,12 @@ func cmdAPIRun(cmd *cobra.Command, args []string) (err error) {
 		err = errs.Combine(err, revocationDB.Close())
 	}()
 
-	accountingCache, err := live.NewCache(log.Named("live-accounting"), runCfg.LiveAccounting)
+	// the memory backend only sees the uploads of its own process, the api would
+	// never see the corrections of the tally running in the core process.
+	if strings.HasPrefix(runCfg.LiveAccounting.StorageBackend, "memory:") {
+		return errs.New("Error creating live accounting cache on satellite api: the memory backend requires a single satellite process, use redis://...")
+	}
+	accountingCache, err := live.NewCache(log.Named("live-accounting"), db.ProjectAccounting(), runCfg.LiveAccounting)
 	if err != nil {
 		return errs.New("Error creating live accounting cache on satellite api: %+v", err)
 	}
@@ -4,14 +4,19 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/rpc"
 	"storj.io/private/process"
 	"storj.io/private/version"
 	"storj.io/storj/pkg/revocation"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/satellitedb"
 )
@@ -80,3 +85,94 @@ func cmdGCRun(cmd *cobra.Command, args []string) (err error) {
 	closeError := peer.Close()
 	return errs.Combine(runError, closeError)
 }
+
+func cmdGCGenerate(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	if gcGenerateCfg.SnapshotTime == "" {
+		return errs.New("the time the pointer database snapshot was taken is required")
+	}
+	snapshotTime, err := time.Parse(time.RFC3339, gcGenerateCfg.SnapshotTime)
+	if err != nil {
+		return errs.New("invalid snapshot time: %+v", err)
+	}
+
+	db, err := satellitedb.New(log.Named("db"), gcGenerateCfg.Database, satellitedb.Options{})
+	if err != nil {
+		return errs.New("Error starting master database on satellite GC: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	pointerDB, err := metainfo.NewStore(log.Named("pointerdb"), gcGenerateCfg.Metainfo.DatabaseURL)
+	if err != nil {
+		return errs.New("Error creating pointerDB connection GC: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, pointerDB.Close())
+	}()
+
+	pieceCounts, err := db.OverlayCache().AllPieceCounts(ctx)
+	if err != nil {
+		log.Warn("error getting last piece counts", zap.Error(err))
+	}
+
+	retainInfos, err := gc.Generate(ctx, log.Named("gc observer"), gcGenerateCfg.GarbageCollection, pointerDB, gcGenerateCfg.Metainfo.Loop.RateLimit, pieceCounts, snapshotTime)
+	if err != nil {
+		return err
+	}
+
+	err = gc.WriteRetainInfos(args[0], retainInfos)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Generated garbage collection filters.", zap.Int("Nodes", len(retainInfos)), zap.String("Dir", args[0]))
+	return nil
+}
+
+func cmdGCSend(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	identity, err := runCfg.Identity.Load()
+	if err != nil {
+		log.Fatal("Failed to load identity.", zap.Error(err))
+	}
+
+	retainInfos, err := gc.ReadRetainInfos(args[0])
+	if err != nil {
+		return err
+	}
+
+	db, err := satellitedb.New(log.Named("db"), runCfg.Database, satellitedb.Options{})
+	if err != nil {
+		return errs.New("Error starting master database on satellite GC: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.CheckVersion(ctx)
+	if err != nil {
+		return errs.New("Error checking version for satellitedb for GC: %+v", err)
+	}
+
+	revocationDB, err := revocation.NewDBFromCfg(runCfg.Server.Config)
+	if err != nil {
+		return errs.New("Error creating revocation database GC: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, revocationDB.Close())
+	}()
+
+	tlsOptions, err := tlsopts.NewOptions(identity, runCfg.Server.Config, revocationDB)
+	if err != nil {
+		return err
+	}
+
+	service := gc.NewService(log.Named("garbage-collection"), runCfg.GarbageCollection, rpc.NewDefaultDialer(tlsOptions), db.OverlayCache(), nil, nil, nil)
+	return service.SendRetainInfos(ctx, retainInfos)
+}
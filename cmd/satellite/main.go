@@ -30,9 +30,11 @@ import (
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/accounting/live"
 	"storj.io/storj/satellite/compensation"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/payments/stripecoinpayments"
+	"storj.io/storj/satellite/repair/checker"
 	"storj.io/storj/satellite/satellitedb"
 	"storj.io/storj/satellite/satellitedb/dbx"
 )
@@ -94,6 +96,24 @@ var (
 		Short: "Run the satellite garbage collection process",
 		RunE:  cmdGCRun,
 	}
+	gcCmd = &cobra.Command{
+		Use:   "garbage-collection",
+		Short: "Garbage collection commands",
+	}
+	gcGenerateCmd = &cobra.Command{
+		Use:   "generate [filter dir]",
+		Short: "Generate garbage collection bloom filters",
+		Long:  "Generate garbage collection bloom filters from a snapshot or backup of the pointer database and store them in the filter dir.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdGCGenerate,
+	}
+	gcSendCmd = &cobra.Command{
+		Use:   "send [filter dir]",
+		Short: "Send stored garbage collection bloom filters",
+		Long:  "Send the garbage collection bloom filters stored in the filter dir to the storage nodes.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdGCSend,
+	}
 	setupCmd = &cobra.Command{
 		Use:         "setup",
 		Short:       "Create config files",
@@ -130,6 +150,13 @@ var (
 		Args:  cobra.MinimumNArgs(2),
 		RunE:  cmdGracefulExit,
 	}
+	durabilityCmd = &cobra.Command{
+		Use:   "durability [report file]",
+		Short: "Render the durability report of the last repair checker run",
+		Long:  "Render the durability report of the last repair checker run. The report file defaults to the configured checker.durability-report-path.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  cmdDurability,
+	}
 	verifyGracefulExitReceiptCmd = &cobra.Command{
 		Use:   "verify-exit-receipt [storage node ID] [receipt]",
 		Short: "Verify a graceful exit receipt",
@@ -242,6 +269,17 @@ var (
 	}
 	verifyGracefulExitReceiptCfg struct {
 	}
+	gcGenerateCfg struct {
+		Database          string `help:"satellite database connection string, used for sizing the filters by the piece counts of the last run" releaseDefault:"postgres://" devDefault:"postgres://"`
+		Metainfo          metainfo.Config
+		GarbageCollection gc.Config
+		SnapshotTime      string `help:"when the pointer database snapshot was taken, formatted as RFC3339, pieces created after it are kept by storage nodes" default:""`
+	}
+	durabilityCfg struct {
+		Checker checker.Config
+		Output  string `help:"destination of report output" default:""`
+		Limit   int    `help:"maximum number of projects, buckets and lost segments to list, 0 lists all of them" default:"20"`
+	}
 	confDir     string
 	identityDir string
 )
@@ -258,6 +296,9 @@ func init() {
 	runCmd.AddCommand(runAdminCmd)
 	runCmd.AddCommand(runRepairerCmd)
 	runCmd.AddCommand(runGCCmd)
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.AddCommand(gcGenerateCmd)
+	gcCmd.AddCommand(gcSendCmd)
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(qdiagCmd)
 	rootCmd.AddCommand(reportsCmd)
@@ -267,6 +308,7 @@ func init() {
 	reportsCmd.AddCommand(partnerAttributionCmd)
 	reportsCmd.AddCommand(gracefulExitCmd)
 	reportsCmd.AddCommand(verifyGracefulExitReceiptCmd)
+	reportsCmd.AddCommand(durabilityCmd)
 	compensationCmd.AddCommand(generateInvoicesCmd)
 	compensationCmd.AddCommand(recordPeriodCmd)
 	compensationCmd.AddCommand(recordOneOffPaymentsCmd)
@@ -282,6 +324,8 @@ func init() {
 	process.Bind(runAdminCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(runRepairerCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(runGCCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(gcGenerateCmd, &gcGenerateCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(gcSendCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(setupCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir), cfgstruct.SetupMode())
 	process.Bind(qdiagCmd, &qdiagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(nodeUsageCmd, &nodeUsageCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
@@ -290,6 +334,7 @@ func init() {
 	process.Bind(recordOneOffPaymentsCmd, &recordOneOffPaymentsCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(gracefulExitCmd, &gracefulExitCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(verifyGracefulExitReceiptCmd, &verifyGracefulExitReceiptCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(durabilityCmd, &durabilityCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(partnerAttributionCmd, &partnerAttribtionCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(stripeCustomerCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(prepareCustomerInvoiceRecordsCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
@@ -338,7 +383,7 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		err = errs.Combine(err, revocationDB.Close())
 	}()
 
-	liveAccounting, err := live.NewCache(log.Named("live-accounting"), runCfg.LiveAccounting)
+	liveAccounting, err := live.NewCache(log.Named("live-accounting"), db.ProjectAccounting(), runCfg.LiveAccounting)
 	if err != nil {
 		return errs.New("Error creating live accounting cache: %+v", err)
 	}
@@ -509,6 +554,25 @@ func cmdGracefulExit(cmd *cobra.Command, args []string) (err error) {
 	return generateGracefulExitCSV(ctx, gracefulExitCfg.Completed, start, end, file)
 }
 
+func cmdDurability(cmd *cobra.Command, args []string) (err error) {
+	reportPath := durabilityCfg.Checker.DurabilityReportPath
+	if len(args) > 0 {
+		reportPath = args[0]
+	}
+	if reportPath == "" {
+		return errs.New("durability report path is not configured")
+	}
+
+	report, err := checker.ReadDurabilityReport(reportPath)
+	if err != nil {
+		return err
+	}
+
+	return runWithOutput(durabilityCfg.Output, func(output io.Writer) error {
+		return reports.RenderDurability(report, durabilityCfg.Limit, output)
+	})
+}
+
 func cmdNodeUsage(cmd *cobra.Command, args []string) (err error) {
 	ctx, _ := process.Ctx(cmd)
 
@@ -65,6 +65,7 @@ func cmdRepairerRun(cmd *cobra.Command, args []string) (err error) {
 		revocationDB,
 		db.RepairQueue(),
 		db.Buckets(),
+		db.SegmentReferences(),
 		db.OverlayCache(),
 		rollupsWriteCache,
 		db.Irreparable(),
@@ -0,0 +1,74 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/satellite/repair/checker"
+)
+
+// RenderDurability writes a human readable form of the durability report to output.
+// At most limit projects, buckets and lost segments are listed, 0 lists all of them.
+func RenderDurability(report *checker.DurabilityReport, limit int, output io.Writer) (err error) {
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	defer func() {
+		err = errs.Combine(err, w.Flush())
+	}()
+
+	_, _ = fmt.Fprintf(w, "checker run\t%s - %s (%s)\n\n",
+		report.Started.Format(time.RFC3339), report.Finished.Format(time.RFC3339),
+		report.Finished.Sub(report.Started).Round(time.Second))
+
+	for _, scheme := range report.Schemes {
+		_, _ = fmt.Fprintf(w, "scheme %s\t%d segments\n", scheme.Scheme, scheme.Segments)
+
+		counts := make([]int32, 0, len(scheme.HealthyPieces))
+		for healthy := range scheme.HealthyPieces {
+			counts = append(counts, healthy)
+		}
+		sort.Slice(counts, func(i, k int) bool { return counts[i] < counts[k] })
+
+		for _, healthy := range counts {
+			_, _ = fmt.Fprintf(w, "  %d healthy\t%d\n", healthy, scheme.HealthyPieces[healthy])
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	_, _ = fmt.Fprintln(w, "project\tat risk\tlost")
+	for _, count := range truncate(report.Projects, limit) {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\n", count.ProjectID, count.AtRisk, count.Lost)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, "project\tbucket\tat risk\tlost")
+	for _, count := range truncate(report.Buckets, limit) {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", count.ProjectID, count.Bucket, count.AtRisk, count.Lost)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	lost := report.Lost
+	if limit > 0 && len(lost) > limit {
+		lost = lost[:limit]
+	}
+	_, _ = fmt.Fprintf(w, "lost segments\t%d\n", len(report.Lost))
+	for _, path := range lost {
+		_, _ = fmt.Fprintf(w, "  %s\n", path)
+	}
+
+	return nil
+}
+
+func truncate(counts []checker.AtRiskCount, limit int) []checker.AtRiskCount {
+	if limit > 0 && len(counts) > limit {
+		return counts[:limit]
+	}
+	return counts
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reports_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/cmd/satellite/reports"
+	"storj.io/storj/satellite/repair/checker"
+)
+
+func TestRenderDurability(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &checker.DurabilityReport{
+		Started:  now.Add(-time.Minute),
+		Finished: now,
+		Schemes: []checker.SchemeDurability{
+			{Scheme: "29/35/80/110", Segments: 3, HealthyPieces: map[int32]int64{35: 2, 28: 1}},
+		},
+		Projects: []checker.AtRiskCount{
+			{ProjectID: "project-a", AtRisk: 2, Lost: 1},
+			{ProjectID: "project-b", AtRisk: 1},
+		},
+		Buckets: []checker.AtRiskCount{
+			{ProjectID: "project-a", Bucket: "bucket", AtRisk: 2, Lost: 1},
+		},
+		Lost: []string{"project-a/s0/bucket/lost-1", "project-a/s0/bucket/lost-2"},
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, reports.RenderDurability(report, 1, &output))
+
+	rendered := output.String()
+	require.Contains(t, rendered, "scheme 29/35/80/110")
+	require.Regexp(t, `(?s)28 healthy\s+1\n.*35 healthy\s+2\n`, rendered)
+	require.Contains(t, rendered, "project-a")
+	require.NotContains(t, rendered, "project-b", "limited to one project")
+	require.Regexp(t, `lost segments\s+2\n`, rendered)
+	require.Contains(t, rendered, "lost-1")
+	require.NotContains(t, rendered, "lost-2", "limited to one lost segment")
+}
@@ -15,10 +15,11 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/zombiedetection"
 )
 
 const (
-	lastSegment = int(-1)
+	lastSegment = zombiedetection.LastSegment
 	rateLimit   = 0
 )
 
@@ -212,56 +213,14 @@ func (obsvr *observer) analyzeProject(ctx context.Context) error {
 	return nil
 }
 
-func (obsvr *observer) findZombieSegments(object *object) error {
-	obsvr.resetZombieBuffer()
-
-	if !object.hasLastSegment {
-		obsvr.appendAllObjectSegments(object)
-		return nil
+func (obsvr *observer) findZombieSegments(object *object) (err error) {
+	detected := zombiedetection.Object{
+		Segments:                 object.segments,
+		ExpectedNumberOfSegments: object.expectedNumberOfSegments,
+		HasLastSegment:           object.hasLastSegment,
 	}
-
-	segmentsCount := object.segments.Count()
-
-	switch {
-	// this case is only for old style pointers with encrypted number of segments
-	// value 0 means that we don't know how much segments object should have
-	case object.expectedNumberOfSegments == 0:
-		sequenceLength := firstSequenceLength(object.segments)
-
-		for index := sequenceLength; index < object.segments.Length(); index++ {
-			has, err := object.segments.Has(index)
-			if err != nil {
-				panic(err)
-			}
-			if has {
-				obsvr.appendSegment(index)
-			}
-		}
-	// using 'expectedNumberOfSegments-1' because 'segments' doesn't contain last segment
-	case segmentsCount > object.expectedNumberOfSegments-1:
-		sequenceLength := firstSequenceLength(object.segments)
-
-		if sequenceLength == object.expectedNumberOfSegments-1 {
-			for index := sequenceLength; index < object.segments.Length(); index++ {
-				has, err := object.segments.Has(index)
-				if err != nil {
-					panic(err)
-				}
-				if has {
-					obsvr.appendSegment(index)
-				}
-			}
-		} else {
-			obsvr.appendAllObjectSegments(object)
-			obsvr.appendSegment(lastSegment)
-		}
-	case segmentsCount < object.expectedNumberOfSegments-1,
-		segmentsCount == object.expectedNumberOfSegments-1 && !object.segments.IsSequence():
-		obsvr.appendAllObjectSegments(object)
-		obsvr.appendSegment(lastSegment)
-	}
-
-	return nil
+	obsvr.zombieBuffer, err = detected.ZombieSegments(obsvr.zombieBuffer[:0])
+	return err
 }
 
 func (obsvr *observer) printSegment(ctx context.Context, segmentIndex int, bucket, path string) error {
@@ -309,26 +268,6 @@ func pointerCreationDateAndSize(
 	return pointer.CreationDate.Format(time.RFC3339Nano), pointer.SegmentSize, nil
 }
 
-func (obsvr *observer) resetZombieBuffer() {
-	obsvr.zombieBuffer = obsvr.zombieBuffer[:0]
-}
-
-func (obsvr *observer) appendSegment(segmentIndex int) {
-	obsvr.zombieBuffer = append(obsvr.zombieBuffer, segmentIndex)
-}
-
-func (obsvr *observer) appendAllObjectSegments(object *object) {
-	for index := 0; index < object.segments.Length(); index++ {
-		has, err := object.segments.Has(index)
-		if err != nil {
-			panic(err)
-		}
-		if has {
-			obsvr.appendSegment(index)
-		}
-	}
-}
-
 // clearBucketsObjects clears up the buckets objects map for reusing it.
 func (obsvr *observer) clearBucketsObjects() {
 	// This is an idiomatic way of not having to destroy and recreate a new map
@@ -354,16 +293,3 @@ func findOrCreate(bucketName string, path string, buckets bucketsObjects) *objec
 
 	return obj
 }
-
-func firstSequenceLength(segments bitArray) int {
-	for index := 0; index < segments.Length(); index++ {
-		has, err := segments.Has(index)
-		if err != nil {
-			panic(err)
-		}
-		if !has {
-			return index
-		}
-	}
-	return segments.Length()
-}
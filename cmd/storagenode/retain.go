@@ -0,0 +1,78 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/private/process"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func cmdRetainStatus(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	diagDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	// check if the directory exists
+	_, err = os.Stat(diagDir)
+	if err != nil {
+		fmt.Println("storage node directory doesn't exist", diagDir)
+		return err
+	}
+
+	db, err := storagenodedb.New(zap.L().Named("db"), diagCfg.DatabaseConfig())
+	if err != nil {
+		return errs.New("Error starting master database on storage node: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	summaries, err := db.Retain().Summaries(ctx)
+	if err != nil {
+		fmt.Printf("unable to get retain summaries: %v\n", err)
+		return err
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No retain requests have been received.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	defer func() { err = errs.Combine(err, w.Flush()) }()
+
+	fmt.Fprint(w, "Satellite\tReceived\tCreated Before\tFinished\tChecked\tTrashed\tBytes Trashed\n")
+
+	for _, summary := range summaries {
+		finished := "in progress"
+		if summary.FinishedAt != nil {
+			finished = summary.FinishedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%d\t%d\t%v\n",
+			summary.SatelliteID,
+			summary.ReceivedAt.Format(time.RFC3339),
+			summary.CreatedBefore.Format(time.RFC3339),
+			finished,
+			summary.PiecesChecked,
+			summary.PiecesTrashed,
+			memory.Size(summary.BytesTrashed),
+		)
+	}
+
+	return nil
+}
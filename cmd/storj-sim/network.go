@@ -336,6 +336,8 @@ func newNetwork(flags *Flags) (*Processes, error) {
 				"--debug.addr", net.JoinHostPort(host, port(satellitePeer, i, debugHTTP)),
 
 				"--admin.address", net.JoinHostPort(host, port(satellitePeer, i, adminHTTP)),
+
+				"--repairer.checkpoint-dir", filepath.Join(apiProcess.Directory, "repair-checkpoints"),
 			},
 			"run": {"api"},
 		})
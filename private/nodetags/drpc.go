@@ -0,0 +1,95 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodetags
+
+import (
+	"context"
+	"fmt"
+
+	"storj.io/drpc"
+)
+
+// SetTagsRequest replaces the tags of the node making the request.
+type SetTagsRequest struct {
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3"`
+}
+
+// SetTagsResponse is the response to SetTagsRequest.
+type SetTagsResponse struct{}
+
+// DRPCNodeTagsServer is the satellite side of the node tags, which aren't part
+// of the Node contact service yet.
+type DRPCNodeTagsServer interface {
+	SetTags(context.Context, *SetTagsRequest) (*SetTagsResponse, error)
+}
+
+// DRPCNodeTagsDescription describes the node tags for registering them on a drpc mux.
+type DRPCNodeTagsDescription struct{}
+
+// NumMethods returns the number of node tags operations.
+func (DRPCNodeTagsDescription) NumMethods() int { return 1 }
+
+// Method returns the nth node tags operation.
+func (DRPCNodeTagsDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/contact.NodeTags/SetTags",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeTagsServer).SetTags(ctx, in1.(*SetTagsRequest))
+			}, DRPCNodeTagsServer.SetTags, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterNodeTags registers the node tags operations of impl on the mux.
+func DRPCRegisterNodeTags(mux drpc.Mux, impl DRPCNodeTagsServer) error {
+	return mux.Register(impl, DRPCNodeTagsDescription{})
+}
+
+// DRPCNodeTagsClient is the storage node side of the node tags.
+type DRPCNodeTagsClient interface {
+	DRPCConn() drpc.Conn
+
+	SetTags(ctx context.Context, in *SetTagsRequest) (*SetTagsResponse, error)
+}
+
+type drpcNodeTagsClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCNodeTagsClient returns a client of the node tags operations on the connection.
+func NewDRPCNodeTagsClient(cc drpc.Conn) DRPCNodeTagsClient {
+	return &drpcNodeTagsClient{cc}
+}
+
+func (c *drpcNodeTagsClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcNodeTagsClient) SetTags(ctx context.Context, in *SetTagsRequest) (*SetTagsResponse, error) {
+	out := new(SetTagsResponse)
+	if err := c.cc.Invoke(ctx, "/contact.NodeTags/SetTags", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// The requests and responses are encoded using the protobuf tags of their fields.
+
+// Reset resets the request.
+func (m *SetTagsRequest) Reset() { *m = SetTagsRequest{} }
+
+// String formats the request.
+func (m *SetTagsRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*SetTagsRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *SetTagsResponse) Reset() { *m = SetTagsResponse{} }
+
+// String formats the response.
+func (m *SetTagsResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*SetTagsResponse) ProtoMessage() {}
@@ -0,0 +1,54 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package nodetags implements the region tags, such as countries or larger
+// regions, which storage nodes report to the satellites at check-in.
+package nodetags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the default error class for node tags.
+var Error = errs.Class("node tags")
+
+const (
+	// MaxTags is the maximum number of tags a node may report.
+	MaxTags = 16
+	// MaxTagLength is the maximum length of a single tag.
+	MaxTagLength = 32
+)
+
+// Parse parses a comma-separated list of tags, such as "DE,EU".
+func Parse(s string) ([]string, error) {
+	return Normalize(strings.Split(s, ","))
+}
+
+// Normalize converts the tags to upper case, removes empty and duplicate tags
+// and sorts them. It fails when the tags are invalid or there are too many.
+func Normalize(tags []string) ([]string, error) {
+	var normalized []string
+	seen := map[string]struct{}{}
+	for _, tag := range tags {
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > MaxTagLength || strings.ContainsAny(tag, ", \t\r\n") {
+			return nil, Error.New("invalid tag %q", tag)
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTags {
+		return nil, Error.New("too many tags: %d > %d", len(normalized), MaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
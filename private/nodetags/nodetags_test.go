@@ -0,0 +1,36 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodetags_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/private/nodetags"
+)
+
+func TestParse(t *testing.T) {
+	tags, err := nodetags.Parse(" eu, DE,,de ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"DE", "EU"}, tags)
+
+	tags, err = nodetags.Parse("")
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	_, err = nodetags.Parse("DE EU")
+	require.Error(t, err)
+
+	_, err = nodetags.Parse(strings.Repeat("X", nodetags.MaxTagLength+1))
+	require.Error(t, err)
+
+	var many []string
+	for i := 0; i <= nodetags.MaxTags; i++ {
+		many = append(many, strings.Repeat("X", i+1))
+	}
+	_, err = nodetags.Normalize(many)
+	require.Error(t, err)
+}
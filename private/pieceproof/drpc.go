@@ -0,0 +1,114 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieceproof
+
+import (
+	"context"
+	"fmt"
+
+	"storj.io/common/pb"
+	"storj.io/drpc"
+)
+
+// ProveRangeRequest asks a node for a range proof of the piece of the order limit.
+type ProveRangeRequest struct {
+	// Limit is the audit order limit signed by the satellite.
+	Limit *pb.OrderLimit `protobuf:"bytes,1,opt,name=limit,proto3"`
+	// Order pays for the data after Offset.
+	Order *pb.Order `protobuf:"bytes,2,opt,name=order,proto3"`
+	// Offset is where the node stops hashing and starts sending the piece.
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3"`
+}
+
+// ProveRangeResponse is the range proof of a node.
+type ProveRangeResponse struct {
+	// Hash is the piece hash signed by the uplink.
+	Hash *pb.PieceHash `protobuf:"bytes,1,opt,name=hash,proto3"`
+	// Limit is the original order limit of the upload.
+	Limit *pb.OrderLimit `protobuf:"bytes,2,opt,name=limit,proto3"`
+	// Offset is the offset of the request.
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3"`
+	// State is the state of the SHA-256 hash of the piece up to Offset.
+	State []byte `protobuf:"bytes,4,opt,name=state,proto3"`
+	// Data is the piece after Offset.
+	Data []byte `protobuf:"bytes,5,opt,name=data,proto3"`
+	// Signature is the signature of the node, see SignedData.
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3"`
+}
+
+// DRPCPieceProofsServer is the storage node side of the range proofs, which
+// aren't part of the Piecestore service yet.
+type DRPCPieceProofsServer interface {
+	ProveRange(context.Context, *ProveRangeRequest) (*ProveRangeResponse, error)
+}
+
+// DRPCPieceProofsDescription describes the range proofs for registering them on a drpc mux.
+type DRPCPieceProofsDescription struct{}
+
+// NumMethods returns the number of range proof operations.
+func (DRPCPieceProofsDescription) NumMethods() int { return 1 }
+
+// Method returns the nth range proof operation.
+func (DRPCPieceProofsDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/piecestore.PieceProofs/ProveRange",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPieceProofsServer).ProveRange(ctx, in1.(*ProveRangeRequest))
+			}, DRPCPieceProofsServer.ProveRange, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterPieceProofs registers the range proof operations of impl on the mux.
+func DRPCRegisterPieceProofs(mux drpc.Mux, impl DRPCPieceProofsServer) error {
+	return mux.Register(impl, DRPCPieceProofsDescription{})
+}
+
+// DRPCPieceProofsClient is the satellite side of the range proofs.
+type DRPCPieceProofsClient interface {
+	DRPCConn() drpc.Conn
+
+	ProveRange(ctx context.Context, in *ProveRangeRequest) (*ProveRangeResponse, error)
+}
+
+type drpcPieceProofsClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCPieceProofsClient returns a client of the range proof operations on the connection.
+func NewDRPCPieceProofsClient(cc drpc.Conn) DRPCPieceProofsClient {
+	return &drpcPieceProofsClient{cc}
+}
+
+func (c *drpcPieceProofsClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcPieceProofsClient) ProveRange(ctx context.Context, in *ProveRangeRequest) (*ProveRangeResponse, error) {
+	out := new(ProveRangeResponse)
+	if err := c.cc.Invoke(ctx, "/piecestore.PieceProofs/ProveRange", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// The requests and responses are encoded using the protobuf tags of their fields.
+
+// Reset resets the request.
+func (m *ProveRangeRequest) Reset() { *m = ProveRangeRequest{} }
+
+// String formats the request.
+func (m *ProveRangeRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*ProveRangeRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *ProveRangeResponse) Reset() { *m = ProveRangeResponse{} }
+
+// String formats the response.
+func (m *ProveRangeResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*ProveRangeResponse) ProtoMessage() {}
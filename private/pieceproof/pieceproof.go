@@ -0,0 +1,86 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package pieceproof implements the range proofs, with which storage nodes show
+// that they still store a piece without sending all of it.
+//
+// The uplink only signs the SHA-256 hash of the whole piece. For a range proof
+// the node hashes the piece up to an offset chosen at random by the satellite,
+// and sends the intermediate state of the hash together with the rest of the
+// piece. Finishing the hash with the rest must give the hash signed by the
+// uplink, which isn't possible without the piece, since the offset isn't known
+// in advance.
+package pieceproof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"io"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+)
+
+// Error is the default error class for piece proofs.
+var Error = errs.Class("piece proof")
+
+// BlockSize is the alignment of proof offsets, the state of the hash can only
+// be sent at block boundaries.
+const BlockSize = sha256.BlockSize
+
+// HashState hashes data up to offset, which must be a multiple of BlockSize,
+// and returns the intermediate state of the hash.
+func HashState(data io.Reader, offset int64) (_ []byte, err error) {
+	if offset < 0 || offset%BlockSize != 0 {
+		return nil, Error.New("invalid offset %d", offset)
+	}
+	h := sha256.New()
+	if _, err := io.CopyN(h, data, offset); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	return state, Error.Wrap(err)
+}
+
+// FinishHash finishes the hash with the intermediate state and the rest of the piece.
+func FinishHash(state, rest []byte) (_ []byte, err error) {
+	h := sha256.New()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	_, _ = h.Write(rest)
+	return h.Sum(nil), nil
+}
+
+// SignedData returns the data signed by the node for a range proof in response
+// to the order limit with the serial number.
+func SignedData(serialNumber storj.SerialNumber, proof *ProveRangeResponse) []byte {
+	var buf bytes.Buffer
+	_, _ = buf.Write(serialNumber[:])
+	if proof.Hash != nil {
+		_, _ = buf.Write(proof.Hash.PieceId[:])
+		_, _ = buf.Write(proof.Hash.Hash)
+	}
+	_ = binary.Write(&buf, binary.BigEndian, proof.Offset)
+	_ = binary.Write(&buf, binary.BigEndian, int64(len(proof.State)))
+	_, _ = buf.Write(proof.State)
+	rest := sha256.Sum256(proof.Data)
+	_, _ = buf.Write(rest[:])
+	return buf.Bytes()
+}
+
+// Sign signs the range proof in response to the order limit with the serial number.
+func Sign(ctx context.Context, signer signing.Signer, serialNumber storj.SerialNumber, proof *ProveRangeResponse) (err error) {
+	proof.Signature, err = signer.HashAndSign(ctx, SignedData(serialNumber, proof))
+	return Error.Wrap(err)
+}
+
+// VerifySignature verifies that the range proof is signed by the node.
+func VerifySignature(ctx context.Context, signee signing.Signee, serialNumber storj.SerialNumber, proof *ProveRangeResponse) error {
+	return Error.Wrap(signee.HashAndVerifySignature(ctx, SignedData(serialNumber, proof), proof.Signature))
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieceproof_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/pieceproof"
+)
+
+func TestHashState(t *testing.T) {
+	data := testrand.BytesInt(10*pieceproof.BlockSize + 7)
+	for _, offset := range []int64{0, pieceproof.BlockSize, 10 * pieceproof.BlockSize} {
+		state, err := pieceproof.HashState(bytes.NewReader(data), offset)
+		require.NoError(t, err)
+
+		hash, err := pieceproof.FinishHash(state, data[offset:])
+		require.NoError(t, err)
+		require.Equal(t, pkcrypto.SHA256Hash(data), hash)
+
+		// the rest of another piece doesn't give the hash.
+		other := append([]byte{}, data...)
+		other[len(other)-1]++
+		hash, err = pieceproof.FinishHash(state, other[offset:])
+		require.NoError(t, err)
+		require.NotEqual(t, pkcrypto.SHA256Hash(data), hash)
+	}
+
+	_, err := pieceproof.HashState(bytes.NewReader(data), pieceproof.BlockSize+1)
+	require.Error(t, err)
+	_, err = pieceproof.HashState(bytes.NewReader(data), 11*pieceproof.BlockSize)
+	require.Error(t, err)
+}
+
+func TestSignature(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	node := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion())
+	other := testidentity.MustPregeneratedSignedIdentity(1, storj.LatestIDVersion())
+	serialNumber := testrand.SerialNumber()
+
+	proof := &pieceproof.ProveRangeResponse{
+		Hash:   &pb.PieceHash{PieceId: testrand.PieceID(), Hash: testrand.BytesInt(32)},
+		Limit:  &pb.OrderLimit{SerialNumber: testrand.SerialNumber()},
+		Offset: pieceproof.BlockSize,
+		State:  testrand.BytesInt(108),
+		Data:   testrand.BytesInt(100),
+	}
+	require.NoError(t, pieceproof.Sign(ctx, signing.SignerFromFullIdentity(node), serialNumber, proof))
+
+	// the proof survives the encoding.
+	data, err := pb.Marshal(proof)
+	require.NoError(t, err)
+	var decoded pieceproof.ProveRangeResponse
+	require.NoError(t, pb.Unmarshal(data, &decoded))
+	require.NoError(t, pieceproof.VerifySignature(ctx, signing.SigneeFromPeerIdentity(node.PeerIdentity()), serialNumber, &decoded))
+
+	require.Error(t, pieceproof.VerifySignature(ctx, signing.SigneeFromPeerIdentity(other.PeerIdentity()), serialNumber, &decoded))
+	require.Error(t, pieceproof.VerifySignature(ctx, signing.SigneeFromPeerIdentity(node.PeerIdentity()), testrand.SerialNumber(), &decoded))
+
+	decoded.Data[0]++
+	require.Error(t, pieceproof.VerifySignature(ctx, signing.SigneeFromPeerIdentity(node.PeerIdentity()), serialNumber, &decoded))
+}
@@ -49,8 +49,10 @@ import (
 	"storj.io/storj/satellite/mailservice"
 	"storj.io/storj/satellite/marketingweb"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/bucketlifecycle"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
 	"storj.io/storj/satellite/metainfo/piecedeletion"
+	"storj.io/storj/satellite/metainfo/zombiedeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/nodestats"
 	"storj.io/storj/satellite/orders"
@@ -120,11 +122,12 @@ type Satellite struct {
 		Inspector *irreparable.Inspector
 	}
 	Audit struct {
-		Queue    *audit.Queue
-		Worker   *audit.Worker
-		Chore    *audit.Chore
-		Verifier *audit.Verifier
-		Reporter *audit.Reporter
+		Queue     *audit.Queue
+		Worker    *audit.Worker
+		Chore     *audit.Chore
+		Verifier  *audit.Verifier
+		Reporter  *audit.Reporter
+		Scheduler *audit.Scheduler
 	}
 
 	GarbageCollection struct {
@@ -135,6 +138,14 @@ type Satellite struct {
 		Chore *expireddeletion.Chore
 	}
 
+	BucketLifecycle struct {
+		Chore *bucketlifecycle.Chore
+	}
+
+	ZombieDeletion struct {
+		Chore *zombiedeletion.Chore
+	}
+
 	DBCleanup struct {
 		Chore *dbcleanup.Chore
 	}
@@ -447,6 +458,13 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 					Rate:            1000,
 					CacheCapacity:   100,
 					CacheExpiration: 10 * time.Second,
+
+					KeyCacheCapacity:   100,
+					KeyCacheExpiration: 10 * time.Second,
+				},
+				// tests change the settings of buckets directly in the database.
+				BucketCache: metainfo.BucketCacheConfig{
+					Capacity: 0,
 				},
 				PieceDeletion: piecedeletion.Config{
 					MaxConcurrency: 100,
@@ -493,6 +511,8 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 				MaxBufferMem:                  4 * memory.MiB,
 				MaxExcessRateOptimalThreshold: 0.05,
 				InMemoryRepair:                false,
+				CheckpointDir:                 filepath.Join(storageDir, "repair-checkpoints"),
+				CheckpointExpiration:          24 * time.Hour,
 			},
 			Audit: audit.Config{
 				MaxRetriesStatDB:   0,
@@ -503,6 +523,14 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 				QueueInterval:      defaultInterval,
 				Slots:              3,
 				WorkerConcurrency:  1,
+				Schedule: audit.ScheduleConfig{
+					Priority:       "contained,failed,suspended,unvetted,vetted",
+					UnvettedSlots:  6,
+					SuspendedSlots: 3,
+					FailedSlots:    6,
+					ContainedSlots: 1,
+					FailureWindow:  24 * time.Hour,
+				},
 			},
 			GarbageCollection: gc.Config{
 				Interval:          defaultInterval,
@@ -516,6 +544,18 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 				Interval: defaultInterval,
 				Enabled:  true,
 			},
+			BucketLifecycle: bucketlifecycle.Config{
+				Interval:   defaultInterval,
+				Enabled:    true,
+				MaxObjects: 1000,
+				DeleteRate: 1000,
+			},
+			ZombieDeletion: zombiedeletion.Config{
+				Interval:    defaultInterval,
+				Enabled:     true,
+				MaxSegments: 1000,
+				DeleteRate:  1000,
+			},
 			DBCleanup: dbcleanup.Config{
 				SerialsInterval: defaultInterval,
 			},
@@ -603,7 +643,7 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 
 		planet.databases = append(planet.databases, revocationDB)
 
-		liveAccounting, err := live.NewCache(log.Named("live-accounting"), config.LiveAccounting)
+		liveAccounting, err := live.NewCache(log.Named("live-accounting"), db.ProjectAccounting(), config.LiveAccounting)
 		if err != nil {
 			return xs, errs.Wrap(err)
 		}
@@ -697,10 +737,13 @@ func createNewSystem(log *zap.Logger, config satellite.Config, peer *satellite.C
 	system.Audit.Chore = peer.Audit.Chore
 	system.Audit.Verifier = peer.Audit.Verifier
 	system.Audit.Reporter = peer.Audit.Reporter
+	system.Audit.Scheduler = peer.Audit.Scheduler
 
 	system.GarbageCollection.Service = gcPeer.GarbageCollection.Service
 
 	system.ExpiredDeletion.Chore = peer.ExpiredDeletion.Chore
+	system.BucketLifecycle.Chore = peer.BucketLifecycle.Chore
+	system.ZombieDeletion.Chore = peer.ZombieDeletion.Chore
 
 	system.DBCleanup.Chore = peer.DBCleanup.Chore
 
@@ -737,7 +780,7 @@ func (planet *Planet) newAPI(count int, identity *identity.FullIdentity, db sate
 	}
 	planet.databases = append(planet.databases, revocationDB)
 
-	liveAccounting, err := live.NewCache(log.Named("live-accounting"), config.LiveAccounting)
+	liveAccounting, err := live.NewCache(log.Named("live-accounting"), db.ProjectAccounting(), config.LiveAccounting)
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
@@ -776,7 +819,7 @@ func (planet *Planet) newRepairer(count int, identity *identity.FullIdentity, db
 	rollupsWriteCache := orders.NewRollupsWriteCache(log.Named("orders-write-cache"), db.Orders(), config.Orders.FlushBatchSize)
 	planet.databases = append(planet.databases, rollupsWriteCacheCloser{rollupsWriteCache})
 
-	return satellite.NewRepairer(log, identity, pointerDB, revocationDB, db.RepairQueue(), db.Buckets(), db.OverlayCache(), rollupsWriteCache, db.Irreparable(), versionInfo, &config, nil)
+	return satellite.NewRepairer(log, identity, pointerDB, revocationDB, db.RepairQueue(), db.Buckets(), db.SegmentReferences(), db.OverlayCache(), rollupsWriteCache, db.Irreparable(), versionInfo, &config, nil)
 }
 
 type rollupsWriteCacheCloser struct {
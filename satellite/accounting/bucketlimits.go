@@ -0,0 +1,26 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"storj.io/common/memory"
+)
+
+// BucketLimits are the optional usage limits of a bucket, a zero limit is not enforced.
+type BucketLimits struct {
+	Storage   memory.Size
+	Bandwidth memory.Size
+	Objects   int64
+}
+
+// IsZero returns true when none of the limits is set.
+func (limits BucketLimits) IsZero() bool {
+	return limits.Storage <= 0 && limits.Bandwidth <= 0 && limits.Objects <= 0
+}
+
+// BucketLiveUsage is the live usage of a bucket, back to the time of the last accounting tally.
+type BucketLiveUsage struct {
+	Storage int64
+	Objects int64
+}
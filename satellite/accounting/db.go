@@ -169,6 +169,8 @@ type ProjectAccounting interface {
 	CreateStorageTally(ctx context.Context, tally BucketStorageTally) error
 	// GetAllocatedBandwidthTotal returns the sum of GET bandwidth usage allocated for a projectID in the past time frame
 	GetAllocatedBandwidthTotal(ctx context.Context, projectID uuid.UUID, from time.Time) (int64, error)
+	// GetBucketAllocatedBandwidthTotal returns the sum of GET bandwidth usage allocated for a bucket in the past time frame
+	GetBucketAllocatedBandwidthTotal(ctx context.Context, projectID uuid.UUID, bucketName []byte, from time.Time) (int64, error)
 	// GetProjectAllocatedBandwidth returns project allocated bandwidth for the specified year and month.
 	GetProjectAllocatedBandwidth(ctx context.Context, projectID uuid.UUID, year int, month time.Month) (int64, error)
 	// GetStorageTotals returns the current inline and remote storage usage for a projectID
@@ -187,14 +189,22 @@ type ProjectAccounting interface {
 	GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID, since, before time.Time) ([]BucketUsageRollup, error)
 	// GetBucketTotals returns per bucket usage summary for specified period of time.
 	GetBucketTotals(ctx context.Context, projectID uuid.UUID, cursor BucketUsageCursor, since, before time.Time) (*BucketUsagePage, error)
+	// SaveLiveTotals replaces the stored totals of the in-process live accounting cache.
+	SaveLiveTotals(ctx context.Context, totals map[uuid.UUID]int64) error
+	// GetLiveTotals returns the stored totals of the in-process live accounting cache.
+	GetLiveTotals(ctx context.Context) (map[uuid.UUID]int64, error)
 }
 
-// Cache stores live information about project storage which has not yet been synced to ProjectAccounting.
+// Cache stores live information about project and bucket storage which has not yet been synced to ProjectAccounting.
 //
 // architecture: Database
 type Cache interface {
 	GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error)
 	AddProjectStorageUsage(ctx context.Context, projectID uuid.UUID, spaceUsed int64) error
 	GetAllProjectTotals(ctx context.Context) (map[uuid.UUID]int64, error)
+	GetBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (BucketLiveUsage, error)
+	AddBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, usage BucketLiveUsage) error
+	// GetAllBucketTotals returns the usage of every bucket, keyed by "<project id>/<bucket name>".
+	GetAllBucketTotals(ctx context.Context) (map[string]BucketLiveUsage, error)
 	Close() error
 }
@@ -169,6 +169,42 @@ func TestProjectLimits(t *testing.T) {
 	})
 }
 
+func TestLiveTotals(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		pdb := db.ProjectAccounting()
+
+		totals, err := pdb.GetLiveTotals(ctx)
+		require.NoError(t, err)
+		require.Empty(t, totals)
+
+		first := map[uuid.UUID]int64{
+			testrand.UUID(): 1,
+			testrand.UUID(): 2 * memory.GiB.Int64(),
+		}
+		require.NoError(t, pdb.SaveLiveTotals(ctx, first))
+
+		totals, err = pdb.GetLiveTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, first, totals)
+
+		// saving replaces the previous totals.
+		second := map[uuid.UUID]int64{
+			testrand.UUID(): 3,
+		}
+		require.NoError(t, pdb.SaveLiveTotals(ctx, second))
+
+		totals, err = pdb.GetLiveTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, second, totals)
+
+		require.NoError(t, pdb.SaveLiveTotals(ctx, nil))
+
+		totals, err = pdb.GetLiveTotals(ctx)
+		require.NoError(t, err)
+		require.Empty(t, totals)
+	})
+}
+
 func createBucketStorageTallies(projectID uuid.UUID) (map[string]*accounting.BucketTally, []accounting.BucketTally, error) {
 	bucketTallies := make(map[string]*accounting.BucketTally)
 	var expectedTallies []accounting.BucketTally
@@ -5,6 +5,7 @@ package live
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/zeebo/errs"
@@ -21,12 +22,13 @@ var (
 
 // Config contains configurable values for the live accounting service.
 type Config struct {
-	StorageBackend string `help:"what to use for storing real-time accounting data"`
+	StorageBackend  string        `help:"what to use for storing real-time accounting data, either redis://... or memory:// for a satellite without separate api processes"`
+	PersistInterval time.Duration `help:"how often the totals of the memory backend are stored in the satellite database" default:"1m"`
 }
 
 // NewCache creates a new accounting.Cache instance using the type specified backend in
-// the provided config.
-func NewCache(log *zap.Logger, config Config) (accounting.Cache, error) {
+// the provided config. The memory backend stores its totals in db, when it's not nil.
+func NewCache(log *zap.Logger, db TotalsDB, config Config) (accounting.Cache, error) {
 	parts := strings.SplitN(config.StorageBackend, ":", 2)
 	var backendType string
 	if len(parts) == 0 || parts[0] == "" {
@@ -37,7 +39,9 @@ func NewCache(log *zap.Logger, config Config) (accounting.Cache, error) {
 	switch backendType {
 	case "redis":
 		return newRedisLiveAccounting(log, config.StorageBackend)
+	case "memory":
+		return newMemoryLiveAccounting(log, db, config.PersistInterval)
 	default:
-		return nil, Error.New("unrecognized live accounting backend specifier %q. Currently only redis is supported", backendType)
+		return nil, Error.New("unrecognized live accounting backend specifier %q. Currently only redis and memory are supported", backendType)
 	}
 }
@@ -5,5 +5,8 @@
 Package live provides live accounting functionality. That is, it keeps track
 of deltas in the amount of storage used by each project relative to the last
 tally operation (see pkg/accounting/tally).
+
+The deltas are kept in Redis, or in memory for satellites running as a single
+process, in which case they are stored periodically in the satellite database.
 */
 package live
@@ -6,7 +6,9 @@ package live_test
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,6 +30,9 @@ func TestLiveAccountingCache(t *testing.T) {
 		{
 			backend: "redis",
 		},
+		{
+			backend: "memory",
+		},
 	}
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
@@ -43,8 +48,13 @@ func TestLiveAccountingCache(t *testing.T) {
 				StorageBackend: "redis://" + redis.Addr() + "?db=0",
 			}
 		}
+		if tt.backend == "memory" {
+			config = live.Config{
+				StorageBackend: "memory://",
+			}
+		}
 
-		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), nil, config)
 		require.NoError(t, err)
 
 		projectIDs, sum, err := populateCache(ctx, cache)
@@ -82,9 +92,27 @@ func TestRedisCacheConcurrency(t *testing.T) {
 	config := live.Config{
 		StorageBackend: "redis://" + redis.Addr() + "?db=0",
 	}
-	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), nil, config)
+	require.NoError(t, err)
+
+	testCacheConcurrency(ctx, t, cache)
+}
+
+func TestMemoryCacheConcurrency(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	config := live.Config{
+		StorageBackend: "memory://",
+	}
+	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), nil, config)
 	require.NoError(t, err)
+	defer ctx.Check(cache.Close)
 
+	testCacheConcurrency(ctx, t, cache)
+}
+
+func testCacheConcurrency(ctx *testcontext.Context, t *testing.T, cache accounting.Cache) {
 	projectID := testrand.UUID()
 
 	const (
@@ -158,6 +186,9 @@ func TestGetAllProjectTotals(t *testing.T) {
 		{
 			backend: "redis",
 		},
+		{
+			backend: "memory",
+		},
 	}
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
@@ -173,8 +204,13 @@ func TestGetAllProjectTotals(t *testing.T) {
 				StorageBackend: "redis://" + redis.Addr() + "?db=0",
 			}
 		}
+		if tt.backend == "memory" {
+			config = live.Config{
+				StorageBackend: "memory://",
+			}
+		}
 
-		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), nil, config)
 		require.NoError(t, err)
 
 		projectIDs := make([]uuid.UUID, 1000)
@@ -196,3 +232,128 @@ func TestGetAllProjectTotals(t *testing.T) {
 		}
 	}
 }
+
+func TestBucketTotals(t *testing.T) {
+	tests := []struct {
+		backend string
+	}{
+		{
+			backend: "redis",
+		},
+		{
+			backend: "memory",
+		},
+	}
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	redis, err := redisserver.Mini()
+	require.NoError(t, err)
+	defer ctx.Check(redis.Close)
+
+	for _, tt := range tests {
+		var config live.Config
+		if tt.backend == "redis" {
+			config = live.Config{
+				StorageBackend: "redis://" + redis.Addr() + "?db=1",
+			}
+		}
+		if tt.backend == "memory" {
+			config = live.Config{
+				StorageBackend: "memory://",
+			}
+		}
+
+		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), nil, config)
+		require.NoError(t, err)
+
+		projectID := testrand.UUID()
+		require.NoError(t, cache.AddProjectStorageUsage(ctx, projectID, 300))
+		require.NoError(t, cache.AddBucketUsage(ctx, projectID, []byte("alpha"), accounting.BucketLiveUsage{Storage: 100, Objects: 1}))
+		require.NoError(t, cache.AddBucketUsage(ctx, projectID, []byte("alpha"), accounting.BucketLiveUsage{Storage: 100}))
+		require.NoError(t, cache.AddBucketUsage(ctx, projectID, []byte("beta"), accounting.BucketLiveUsage{Objects: 2}))
+
+		usage, err := cache.GetBucketUsage(ctx, projectID, []byte("alpha"))
+		require.NoError(t, err)
+		require.Equal(t, accounting.BucketLiveUsage{Storage: 200, Objects: 1}, usage)
+
+		usage, err = cache.GetBucketUsage(ctx, projectID, []byte("unknown"))
+		require.NoError(t, err)
+		require.Equal(t, accounting.BucketLiveUsage{}, usage)
+
+		bucketTotals, err := cache.GetAllBucketTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]accounting.BucketLiveUsage{
+			projectID.String() + "/alpha": {Storage: 200, Objects: 1},
+			projectID.String() + "/beta":  {Objects: 2},
+		}, bucketTotals)
+
+		// the bucket totals aren't returned as projects
+		projectTotals, err := cache.GetAllProjectTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[uuid.UUID]int64{projectID: 300}, projectTotals)
+
+		require.NoError(t, cache.Close())
+	}
+}
+
+// totalsDB is an in-memory live.TotalsDB.
+type totalsDB struct {
+	mu     sync.Mutex
+	totals map[uuid.UUID]int64
+	saves  int
+}
+
+func (db *totalsDB) SaveLiveTotals(ctx context.Context, totals map[uuid.UUID]int64) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.totals = totals
+	db.saves++
+	return nil
+}
+
+func (db *totalsDB) GetLiveTotals(ctx context.Context) (map[uuid.UUID]int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	totals := make(map[uuid.UUID]int64, len(db.totals))
+	for projectID, total := range db.totals {
+		totals[projectID] = total
+	}
+	return totals, nil
+}
+
+func TestMemoryCachePersistence(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := &totalsDB{}
+	config := live.Config{
+		StorageBackend:  "memory://",
+		PersistInterval: time.Hour,
+	}
+
+	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), db, config)
+	require.NoError(t, err)
+
+	projectIDs, sum, err := populateCache(ctx, cache)
+	require.NoError(t, err)
+
+	// the totals are stored when closing
+	require.NoError(t, cache.Close())
+	require.Len(t, db.totals, len(projectIDs))
+
+	// a new cache continues with the stored totals
+	cache, err = live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), db, config)
+	require.NoError(t, err)
+
+	for _, projID := range projectIDs {
+		spaceUsed, err := cache.GetProjectStorageUsage(ctx, projID)
+		require.NoError(t, err)
+		assert.Equal(t, sum, spaceUsed)
+	}
+
+	// unchanged totals aren't stored again
+	saves := db.saves
+	require.NoError(t, cache.Close())
+	require.Equal(t, saves, db.saves)
+}
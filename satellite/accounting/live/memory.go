@@ -0,0 +1,194 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package live
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+)
+
+// TotalsDB stores the project totals of the in-memory backend, so they survive restarts.
+//
+// architecture: Database
+type TotalsDB interface {
+	// SaveLiveTotals replaces the stored totals.
+	SaveLiveTotals(ctx context.Context, totals map[uuid.UUID]int64) error
+	// GetLiveTotals returns the stored totals.
+	GetLiveTotals(ctx context.Context) (map[uuid.UUID]int64, error)
+}
+
+// memoryLiveAccounting keeps the project totals in memory. It only works when a
+// single process serves uploads and runs the tally, so the satellite api refuses
+// it. The totals are stored periodically in db.
+// The bucket totals aren't stored, they are restored by the next tally.
+type memoryLiveAccounting struct {
+	log *zap.Logger
+	db  TotalsDB
+
+	mu      sync.Mutex
+	totals  map[uuid.UUID]int64
+	buckets map[string]accounting.BucketLiveUsage
+	dirty   bool
+
+	persist *sync2.Cycle
+	cancel  context.CancelFunc
+	group   errgroup.Group
+}
+
+func newMemoryLiveAccounting(log *zap.Logger, db TotalsDB, persistInterval time.Duration) (*memoryLiveAccounting, error) {
+	cache := &memoryLiveAccounting{
+		log:     log,
+		db:      db,
+		totals:  make(map[uuid.UUID]int64),
+		buckets: make(map[string]accounting.BucketLiveUsage),
+	}
+
+	if db == nil || persistInterval <= 0 {
+		return cache, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	totals, err := db.GetLiveTotals(ctx)
+	if err != nil {
+		cancel()
+		return nil, Error.Wrap(err)
+	}
+	for projectID, total := range totals {
+		cache.totals[projectID] = total
+	}
+
+	cache.cancel = cancel
+	cache.persist = sync2.NewCycle(persistInterval)
+	cache.persist.Start(ctx, &cache.group, func(ctx context.Context) error {
+		if err := cache.save(ctx); err != nil {
+			cache.log.Error("failed to store live accounting totals", zap.Error(err))
+		}
+		return nil
+	})
+	return cache, nil
+}
+
+// GetProjectStorageUsage gets inline and remote storage totals for a given
+// project, back to the time of the last accounting tally.
+func (cache *memoryLiveAccounting) GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.totals[projectID], nil
+}
+
+// AddProjectStorageUsage lets the live accounting know that the given
+// project has just added spaceUsed bytes of storage (from the user's
+// perspective; i.e. segment size).
+func (cache *memoryLiveAccounting) AddProjectStorageUsage(ctx context.Context, projectID uuid.UUID, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.totals[projectID] += spaceUsed
+	cache.dirty = true
+	return nil
+}
+
+// GetAllProjectTotals returns a map of project IDs and totals.
+func (cache *memoryLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ map[uuid.UUID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.snapshot(), nil
+}
+
+// GetBucketUsage gets the storage and object count totals for a given
+// bucket, back to the time of the last accounting tally.
+func (cache *memoryLiveAccounting) GetBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ accounting.BucketLiveUsage, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.buckets[storj.JoinPaths(projectID.String(), string(bucketName))], nil
+}
+
+// AddBucketUsage lets the live accounting know that the given bucket
+// has just added storage or objects.
+func (cache *memoryLiveAccounting) AddBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, usage accounting.BucketLiveUsage) (err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	bucketID := storj.JoinPaths(projectID.String(), string(bucketName))
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	total := cache.buckets[bucketID]
+	total.Storage += usage.Storage
+	total.Objects += usage.Objects
+	cache.buckets[bucketID] = total
+	return nil
+}
+
+// GetAllBucketTotals returns a map of bucket ids and totals.
+func (cache *memoryLiveAccounting) GetAllBucketTotals(ctx context.Context) (_ map[string]accounting.BucketLiveUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	buckets := make(map[string]accounting.BucketLiveUsage, len(cache.buckets))
+	for bucketID, usage := range cache.buckets {
+		buckets[bucketID] = usage
+	}
+	return buckets, nil
+}
+
+// snapshot returns a copy of the totals, the caller must hold the lock.
+func (cache *memoryLiveAccounting) snapshot() map[uuid.UUID]int64 {
+	totals := make(map[uuid.UUID]int64, len(cache.totals))
+	for projectID, total := range cache.totals {
+		totals[projectID] = total
+	}
+	return totals
+}
+
+// save stores the totals in db when they have changed since the last save.
+func (cache *memoryLiveAccounting) save(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	if !cache.dirty {
+		cache.mu.Unlock()
+		return nil
+	}
+	totals := cache.snapshot()
+	cache.dirty = false
+	cache.mu.Unlock()
+
+	err = cache.db.SaveLiveTotals(ctx, totals)
+	if err != nil {
+		cache.mu.Lock()
+		cache.dirty = true
+		cache.mu.Unlock()
+	}
+	return Error.Wrap(err)
+}
+
+// Close stops the periodic saving and stores the totals a last time.
+func (cache *memoryLiveAccounting) Close() error {
+	if cache.persist == nil {
+		return nil
+	}
+
+	cache.persist.Close()
+	cache.cancel()
+	_ = cache.group.Wait()
+
+	return cache.save(context.Background())
+}
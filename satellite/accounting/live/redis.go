@@ -4,12 +4,15 @@
 package live
 
 import (
+	"bytes"
 	"context"
 	"strconv"
 
 	"go.uber.org/zap"
 
+	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/redis"
 )
@@ -35,15 +38,20 @@ func newRedisLiveAccounting(log *zap.Logger, address string) (*redisLiveAccounti
 // project, back to the time of the last accounting tally.
 func (cache *redisLiveAccounting) GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error) {
 	defer mon.Task()(&ctx, projectID)(&err)
-	val, err := cache.client.Get(ctx, projectID[:])
+	return cache.getInt(ctx, projectID[:])
+}
+
+// getInt returns the value stored at key, or zero when it doesn't exist.
+func (cache *redisLiveAccounting) getInt(ctx context.Context, key storage.Key) (_ int64, err error) {
+	val, err := cache.client.Get(ctx, key)
 	if err != nil {
 		if storage.ErrKeyNotFound.Has(err) {
 			return 0, nil
 		}
 		return 0, Error.Wrap(err)
 	}
-	intval, err := strconv.Atoi(string(val))
-	return int64(intval), Error.Wrap(err)
+	intval, err := strconv.ParseInt(string(val), 10, 64)
+	return intval, Error.Wrap(err)
 }
 
 // AddProjectStorageUsage lets the live accounting know that the given
@@ -66,6 +74,10 @@ func (cache *redisLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ ma
 			if item.Key == nil {
 				return Error.New("nil key")
 			}
+			// skip the bucket totals
+			if len(item.Key) != len(uuid.UUID{}) {
+				continue
+			}
 			id := new(uuid.UUID)
 			copy(id[:], item.Key[:])
 			intval, err := strconv.Atoi(string(item.Value))
@@ -79,6 +91,89 @@ func (cache *redisLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ ma
 	return projects, err
 }
 
+// The bucket totals are stored under keys with these prefixes, followed by the bucket id.
+var (
+	bucketStoragePrefix = []byte("bucket-storage/")
+	bucketObjectsPrefix = []byte("bucket-objects/")
+)
+
+// bucketKey returns the key of a bucket total.
+func bucketKey(prefix []byte, projectID uuid.UUID, bucketName []byte) storage.Key {
+	return storage.Key(append(append([]byte{}, prefix...), storj.JoinPaths(projectID.String(), string(bucketName))...))
+}
+
+// GetBucketUsage gets the storage and object count totals for a given
+// bucket, back to the time of the last accounting tally.
+func (cache *redisLiveAccounting) GetBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (usage accounting.BucketLiveUsage, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	usage.Storage, err = cache.getInt(ctx, bucketKey(bucketStoragePrefix, projectID, bucketName))
+	if err != nil {
+		return accounting.BucketLiveUsage{}, err
+	}
+	usage.Objects, err = cache.getInt(ctx, bucketKey(bucketObjectsPrefix, projectID, bucketName))
+	if err != nil {
+		return accounting.BucketLiveUsage{}, err
+	}
+	return usage, nil
+}
+
+// AddBucketUsage lets the live accounting know that the given bucket
+// has just added storage or objects.
+func (cache *redisLiveAccounting) AddBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, usage accounting.BucketLiveUsage) (err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	if usage.Storage != 0 {
+		if err := cache.client.IncrBy(ctx, bucketKey(bucketStoragePrefix, projectID, bucketName), usage.Storage); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+	if usage.Objects != 0 {
+		if err := cache.client.IncrBy(ctx, bucketKey(bucketObjectsPrefix, projectID, bucketName), usage.Objects); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// GetAllBucketTotals iterates through the live accounting DB and returns a map of bucket ids and totals.
+func (cache *redisLiveAccounting) GetAllBucketTotals(ctx context.Context) (_ map[string]accounting.BucketLiveUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	buckets := make(map[string]accounting.BucketLiveUsage)
+
+	err = cache.client.Iterate(ctx, storage.IterateOptions{Recurse: true}, func(ctx context.Context, it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(ctx, &item) {
+			var prefix []byte
+			switch {
+			case bytes.HasPrefix(item.Key, bucketStoragePrefix):
+				prefix = bucketStoragePrefix
+			case bytes.HasPrefix(item.Key, bucketObjectsPrefix):
+				prefix = bucketObjectsPrefix
+			default:
+				continue
+			}
+
+			bucketID := string(item.Key[len(prefix):])
+			total, err := strconv.ParseInt(string(item.Value), 10, 64)
+			if err != nil {
+				return Error.New("could not get total for bucket %s", bucketID)
+			}
+
+			usage := buckets[bucketID]
+			if bytes.Equal(prefix, bucketStoragePrefix) {
+				usage.Storage = total
+			} else {
+				usage.Objects = total
+			}
+			buckets[bucketID] = usage
+		}
+		return nil
+	})
+	return buckets, err
+}
+
 // Close the DB connection.
 func (cache *redisLiveAccounting) Close() error {
 	return cache.client.Close()
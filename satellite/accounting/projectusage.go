@@ -184,6 +184,55 @@ func (usage *Service) AddProjectStorageUsage(ctx context.Context, projectID uuid
 	return usage.liveAccounting.AddProjectStorageUsage(ctx, projectID, spaceUsed)
 }
 
+// ExceedsBucketStorageUsage returns true if the storage usage of a bucket is currently over the
+// storage limit or, when countObjects is set, the object count is at the object limit.
+func (usage *Service) ExceedsBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits, countObjects bool) (_ bool, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	if limits.Storage <= 0 && (!countObjects || limits.Objects <= 0) {
+		return false, nil
+	}
+
+	current, err := usage.liveAccounting.GetBucketUsage(ctx, projectID, bucketName)
+	if err != nil {
+		return false, ErrProjectUsage.Wrap(err)
+	}
+
+	if limits.Storage > 0 && current.Storage >= limits.Storage.Int64() {
+		return true, nil
+	}
+	if countObjects && limits.Objects > 0 && current.Objects >= limits.Objects {
+		return true, nil
+	}
+	return false, nil
+}
+
+// ExceedsBucketBandwidthUsage returns true if the egress of a bucket since the beginning of the
+// month is over its bandwidth limit.
+func (usage *Service) ExceedsBucketBandwidthUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits) (_ bool, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	if limits.Bandwidth <= 0 {
+		return false, nil
+	}
+
+	year, month, _ := usage.nowFn().Date()
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	total, err := usage.projectAccountingDB.GetBucketAllocatedBandwidthTotal(ctx, projectID, bucketName, from)
+	if err != nil {
+		return false, ErrProjectUsage.Wrap(err)
+	}
+	return total >= limits.Bandwidth.Int64(), nil
+}
+
+// AddBucketUsage lets the live accounting know that the given bucket
+// has just added storage or objects.
+func (usage *Service) AddBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, added BucketLiveUsage) (err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	return usage.liveAccounting.AddBucketUsage(ctx, projectID, bucketName, added)
+}
+
 // SetNow allows tests to have the Service act as if the current time is whatever they want.
 func (usage *Service) SetNow(now func() time.Time) {
 	usage.nowFn = now
@@ -360,6 +360,61 @@ func TestProjectUsageCustomLimit(t *testing.T) {
 	})
 }
 
+func TestBucketUsageLimits(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+		projectID := uplink.Projects[0].ID
+		bucketsDB := satellite.DB.Buckets()
+
+		for _, bucketName := range []string{"objects", "storage", "bandwidth"} {
+			require.NoError(t, uplink.CreateBucket(ctx, satellite, bucketName))
+		}
+		data := testrand.Bytes(50 * memory.KiB)
+
+		// the object limit only rejects new objects
+		err := bucketsDB.UpdateBucketLimits(ctx, []byte("objects"), projectID, accounting.BucketLimits{Objects: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, uplink.Upload(ctx, satellite, "objects", "first", data))
+		err = uplink.Upload(ctx, satellite, "objects", "second", data)
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted), err)
+
+		// overwriting an object doesn't add one
+		require.NoError(t, uplink.Upload(ctx, satellite, "objects", "first", data))
+		require.NoError(t, uplink.Upload(ctx, satellite, "objects", "first", data))
+
+		// the storage limit rejects uploads once the bucket is full
+		err = bucketsDB.UpdateBucketLimits(ctx, []byte("storage"), projectID, accounting.BucketLimits{Storage: 10 * memory.KiB})
+		require.NoError(t, err)
+
+		require.NoError(t, uplink.Upload(ctx, satellite, "storage", "first", data))
+		err = uplink.Upload(ctx, satellite, "storage", "second", data)
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted), err)
+
+		// the bandwidth limit rejects downloads
+		require.NoError(t, uplink.Upload(ctx, satellite, "bandwidth", "first", data))
+		err = bucketsDB.UpdateBucketLimits(ctx, []byte("bandwidth"), projectID, accounting.BucketLimits{Bandwidth: memory.MiB})
+		require.NoError(t, err)
+
+		_, err = uplink.Download(ctx, satellite, "bandwidth", "first")
+		require.NoError(t, err)
+
+		now := time.Now()
+		intervalStart := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+		err = satellite.DB.Orders().UpdateBucketBandwidthAllocation(ctx, projectID, []byte("bandwidth"), pb.PieceAction_GET, memory.MiB.Int64(), intervalStart)
+		require.NoError(t, err)
+
+		_, err = uplink.Download(ctx, satellite, "bandwidth", "first")
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted), err)
+
+		// the bandwidth limit doesn't affect uploads
+		require.NoError(t, uplink.Upload(ctx, satellite, "bandwidth", "second", data))
+	})
+}
+
 func TestUsageRollups(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 2,
@@ -5,6 +5,7 @@ package tally
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -91,6 +92,10 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 	if err != nil {
 		return Error.Wrap(err)
 	}
+	initialBucketTotals, err := service.liveAccounting.GetAllBucketTotals(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
 	// Fetch when the last tally happened so we can roughly calculate the byte-hours.
 	lastTime, err := service.storagenodeAccountingDB.LastTimestamp(ctx, accounting.LastAtRestTally)
 	if err != nil {
@@ -156,6 +161,11 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 				return Error.Wrap(err)
 			}
 		}
+
+		err = service.updateLiveBucketTotals(ctx, observer.Bucket, initialBucketTotals)
+		if err != nil {
+			return Error.Wrap(err)
+		}
 	}
 
 	// report bucket metrics
@@ -188,7 +198,77 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 	return errs.Combine(errAtRest, errBucketInfo)
 }
 
-var _ metainfo.Observer = (*Observer)(nil)
+// updateLiveBucketTotals resets the live bucket totals to the tally, keeping half of the usage
+// added while the tally was running, the same way as for projects.
+func (service *Service) updateLiveBucketTotals(ctx context.Context, buckets map[string]*accounting.BucketTally, initialTotals map[string]accounting.BucketLiveUsage) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	latestTotals, err := service.liveAccounting.GetAllBucketTotals(ctx)
+	if err != nil {
+		return err
+	}
+
+	update := func(bucketID string, projectID uuid.UUID, bucketName []byte, tallyTotal accounting.BucketLiveUsage) error {
+		latest, initial := latestTotals[bucketID], initialTotals[bucketID]
+
+		storageDelta := latest.Storage - initial.Storage
+		if storageDelta < 0 {
+			storageDelta = 0
+		}
+		objectsDelta := latest.Objects - initial.Objects
+		if objectsDelta < 0 {
+			objectsDelta = 0
+		}
+
+		return service.liveAccounting.AddBucketUsage(ctx, projectID, bucketName, accounting.BucketLiveUsage{
+			Storage: -latest.Storage + tallyTotal.Storage + storageDelta/2,
+			Objects: -latest.Objects + tallyTotal.Objects + objectsDelta/2,
+		})
+	}
+
+	for bucketID, bucket := range buckets {
+		err := update(bucketID, bucket.ProjectID, bucket.BucketName, accounting.BucketLiveUsage{
+			Storage: bucket.Bytes(),
+			Objects: bucket.ObjectCount,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// empty buckets are not returned by the metainfo observer, their totals are set to 0.
+	for bucketID := range latestTotals {
+		if _, ok := buckets[bucketID]; ok {
+			continue
+		}
+
+		projectID, bucketName, err := splitBucketID(bucketID)
+		if err != nil {
+			service.log.Warn("invalid bucket in live accounting", zap.String("Bucket ID", bucketID), zap.Error(err))
+			continue
+		}
+
+		if err := update(bucketID, projectID, bucketName, accounting.BucketLiveUsage{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitBucketID splits a "<project id>/<bucket name>" bucket id.
+func splitBucketID(bucketID string) (projectID uuid.UUID, bucketName []byte, err error) {
+	parts := strings.SplitN(bucketID, "/", 2)
+	if len(parts) != 2 {
+		return uuid.UUID{}, nil, errs.New("missing bucket name")
+	}
+	projectID, err = uuid.FromString(parts[0])
+	if err != nil {
+		return uuid.UUID{}, nil, err
+	}
+	return projectID, []byte(parts[1]), nil
+}
+
+var _ metainfo.PartialObserver = (*Observer)(nil)
 
 // Observer observes metainfo and adds up tallies for nodes and buckets
 type Observer struct {
@@ -210,7 +290,7 @@ func NewObserver(log *zap.Logger, now time.Time) *Observer {
 }
 
 func (observer *Observer) pointerExpired(pointer *pb.Pointer) bool {
-	return !pointer.ExpirationDate.IsZero() && pointer.ExpirationDate.Before(observer.Now)
+	return metainfo.IsExpired(pointer, observer.Now)
 }
 
 // ensureBucket returns bucket corresponding to the passed in path
@@ -282,6 +362,33 @@ func (observer *Observer) RemoteSegment(ctx context.Context, path metainfo.Scope
 	return nil
 }
 
+// Fork returns an observer for a range of the metainfo loop.
+func (observer *Observer) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return NewObserver(observer.Log, observer.Now), nil
+}
+
+// Merge adds the totals of a forked observer.
+func (observer *Observer) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*Observer)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+
+	for nodeID, total := range fork.Node {
+		observer.Node[nodeID] += total
+	}
+	for bucketID, tally := range fork.Bucket {
+		bucket, ok := observer.Bucket[bucketID]
+		if !ok {
+			observer.Bucket[bucketID] = tally
+			continue
+		}
+		bucket.Combine(tally)
+		bucket.MetadataSize += tally.MetadataSize
+	}
+	return nil
+}
+
 func projectTotalsFromBuckets(buckets map[string]*accounting.BucketTally) map[uuid.UUID]int64 {
 	projectTallyTotals := make(map[uuid.UUID]int64)
 	for _, bucket := range buckets {
@@ -113,6 +113,7 @@ func NewAdmin(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 		adminConfig := config.Admin
 		adminConfig.AuthorizationToken = config.Console.AuthToken
+		adminConfig.RSProfiles = config.Metainfo.RS.Profiles
 
 		peer.Admin.Server = admin.NewServer(log.Named("admin"), peer.Admin.Listener, peer.DB, adminConfig)
 		peer.Servers.Add(lifecycle.Item{
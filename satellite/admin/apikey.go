@@ -0,0 +1,121 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+
+	"storj.io/common/uuid"
+)
+
+func (server *Server) getAPIKeyLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	keyID, ok := apiKeyFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	keyInfo, err := server.db.Console().APIKeys().Get(ctx, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("api key not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get api key: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Rate struct {
+			RPS int `json:"rps"`
+		} `json:"rate"`
+	}
+	if keyInfo.RateLimit != nil {
+		output.Rate.RPS = *keyInfo.RateLimit
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putAPIKeyLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	keyID, ok := apiKeyFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Rate *int `schema:"rate"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if arguments.Rate == nil {
+		http.Error(w, "rate is missing", http.StatusBadRequest)
+		return
+	}
+	if *arguments.Rate < 0 {
+		http.Error(w, fmt.Sprintf("negative rate: %v", *arguments.Rate), http.StatusBadRequest)
+		return
+	}
+
+	_, err = server.db.Console().APIKeys().Get(ctx, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("api key not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get api key: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	err = server.db.Console().APIKeys().UpdateRateLimit(ctx, keyID, *arguments.Rate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update rate: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func apiKeyFromVars(w http.ResponseWriter, r *http.Request) (keyID uuid.UUID, ok bool) {
+	vars := mux.Vars(r)
+	keyIDString, ok := vars["apikey"]
+	if !ok {
+		http.Error(w, "apikey-id missing", http.StatusBadRequest)
+		return uuid.UUID{}, false
+	}
+
+	keyID, err := uuid.FromString(keyIDString)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid apikey-id: %v", err), http.StatusBadRequest)
+		return uuid.UUID{}, false
+	}
+
+	return keyID, true
+}
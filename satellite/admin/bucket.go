@@ -0,0 +1,496 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/nodeselection"
+)
+
+func (server *Server) getBucketPlacement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	placement, err := server.db.Buckets().GetBucketPlacement(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket placement: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Placement string `json:"placement"`
+	}
+	output.Placement = placement.String()
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketPlacement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	placement, err := nodeselection.ParsePlacement(r.Form.Get("placement"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid placement: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketPlacement(ctx, bucket, projectUUID, placement)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket placement: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	limits, err := server.db.Buckets().GetBucketLimits(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Storage struct {
+			Amount memory.Size `json:"amount"`
+			Bytes  int64       `json:"bytes"`
+		} `json:"storage"`
+		Bandwidth struct {
+			Amount memory.Size `json:"amount"`
+			Bytes  int64       `json:"bytes"`
+		} `json:"bandwidth"`
+		Objects int64 `json:"objects"`
+	}
+	output.Storage.Amount = limits.Storage
+	output.Storage.Bytes = limits.Storage.Int64()
+	output.Bandwidth.Amount = limits.Bandwidth
+	output.Bandwidth.Bytes = limits.Bandwidth.Int64()
+	output.Objects = limits.Objects
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Storage   *memory.Size `schema:"storage"`
+		Bandwidth *memory.Size `schema:"bandwidth"`
+		Objects   *int64       `schema:"objects"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limits, err := server.db.Buckets().GetBucketLimits(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if arguments.Storage != nil {
+		if *arguments.Storage < 0 {
+			http.Error(w, fmt.Sprintf("negative storage: %v", arguments.Storage), http.StatusBadRequest)
+			return
+		}
+		limits.Storage = *arguments.Storage
+	}
+
+	if arguments.Bandwidth != nil {
+		if *arguments.Bandwidth < 0 {
+			http.Error(w, fmt.Sprintf("negative bandwidth: %v", arguments.Bandwidth), http.StatusBadRequest)
+			return
+		}
+		limits.Bandwidth = *arguments.Bandwidth
+	}
+
+	if arguments.Objects != nil {
+		if *arguments.Objects < 0 {
+			http.Error(w, fmt.Sprintf("negative objects: %v", *arguments.Objects), http.StatusBadRequest)
+			return
+		}
+		limits.Objects = *arguments.Objects
+	}
+
+	err = server.db.Buckets().UpdateBucketLimits(ctx, bucket, projectUUID, limits)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketLifecycle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := server.db.Buckets().GetBucketLifecycle(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket lifecycle: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Rules metainfo.LifecycleRules `json:"rules"`
+	}
+	output.Rules = rules
+	if output.Rules == nil {
+		output.Rules = metainfo.LifecycleRules{}
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketLifecycle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var input struct {
+		Rules metainfo.LifecycleRules `json:"rules"`
+	}
+
+	err = json.Unmarshal(body, &input)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to unmarshal request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := input.Rules.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid rules: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketLifecycle(ctx, bucket, projectUUID, input.Rules)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket lifecycle: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketRSProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	profile, err := server.db.Buckets().GetBucketRSProfile(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket redundancy scheme profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Profile string `json:"profile"`
+		Scheme  string `json:"scheme"`
+	}
+	output.Profile = profile
+	if rsProfile, ok := server.rsProfiles.Lookup(profile); ok {
+		output.Scheme = rsProfile.String()
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketRSProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	profile := r.Form.Get("profile")
+	if profile != "" {
+		if _, ok := server.rsProfiles.Lookup(profile); !ok {
+			http.Error(w, fmt.Sprintf("unknown redundancy scheme profile %q", profile), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := server.db.Buckets().UpdateBucketRSProfile(ctx, bucket, projectUUID, profile)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket redundancy scheme profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := server.db.Buckets().GetBucketVersioning(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket versioning: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Enabled bool `json:"enabled"`
+	}
+	output.Enabled = enabled
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	enabled, err := strconv.ParseBool(r.Form.Get("enabled"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid enabled value: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketVersioning(ctx, bucket, projectUUID, enabled)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket versioning: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketRetention(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	days, err := server.db.Buckets().GetBucketDefaultRetention(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket default retention: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Days int `json:"days"`
+	}
+	output.Days = days
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
+
+func (server *Server) putBucketRetention(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketFromVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	days, err := strconv.Atoi(r.Form.Get("days"))
+	if err != nil || days < 0 {
+		http.Error(w, fmt.Sprintf("invalid days value: %q", r.Form.Get("days")), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketDefaultRetention(ctx, bucket, projectUUID, days)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket default retention: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// bucketFromVars parses the project and bucket from the request path,
+// responding with an error when they are invalid.
+func bucketFromVars(w http.ResponseWriter, r *http.Request) (projectUUID uuid.UUID, bucket []byte, ok bool) {
+	vars := mux.Vars(r)
+	projectUUIDString, ok := vars["project"]
+	if !ok {
+		http.Error(w, "project-uuid missing", http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	projectUUID, err := uuid.FromString(projectUUIDString)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid project-uuid: %v", err), http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	bucketName, ok := vars["bucket"]
+	if !ok || bucketName == "" {
+		http.Error(w, "bucket name missing", http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	return projectUUID, []byte(bucketName), true
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+)
+
+func TestBucketPlacement(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, sat, "placed"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/placed/placement"
+
+		assertGet(t, link, `{"placement":""}`)
+
+		req, err := http.NewRequest(http.MethodPut, link+"?placement=fr,de", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"placement":"DE,FR"}`)
+
+		req, err = http.NewRequest(http.MethodPut, link+"?placement=", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"placement":""}`)
+	})
+}
+
+func TestBucketLimit(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, sat, "limited"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/limited/limit"
+
+		assertGet(t, link, `{"storage":{"amount":"0 B","bytes":0},"bandwidth":{"amount":"0 B","bytes":0},"objects":0}`)
+
+		req, err := http.NewRequest(http.MethodPut, link+"?storage=1GB&objects=1000", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"storage":{"amount":"1.0 GB","bytes":1000000000},"bandwidth":{"amount":"0 B","bytes":0},"objects":1000}`)
+
+		// unset limits are kept
+		req, err = http.NewRequest(http.MethodPut, link+"?bandwidth=2GB&objects=0", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"storage":{"amount":"1.0 GB","bytes":1000000000},"bandwidth":{"amount":"2.0 GB","bytes":2000000000},"objects":0}`)
+
+		req, err = http.NewRequest(http.MethodPut, link+"?storage=-1", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+	})
+}
+
+func TestBucketRSProfile(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+				require.NoError(t, config.Metainfo.RS.Profiles.Set("archive:16/20/30/40"))
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, sat, "archived"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/archived/rsprofile"
+
+		assertGet(t, link, `{"profile":"","scheme":""}`)
+
+		for _, test := range []struct {
+			profile string
+			status  int
+		}{
+			{profile: "unknown", status: http.StatusBadRequest},
+			{profile: "archive", status: http.StatusOK},
+		} {
+			req, err := http.NewRequest(http.MethodPut, link+"?profile="+test.profile, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "very-secret-token")
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, test.status, response.StatusCode)
+			require.NoError(t, response.Body.Close())
+		}
+
+		assertGet(t, link, `{"profile":"archive","scheme":"archive:16/20/30/40"}`)
+	})
+}
+
+func TestBucketVersioning(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, sat, "versioned"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/versioned/versioning"
+
+		assertGet(t, link, `{"enabled":false}`)
+
+		for _, test := range []struct {
+			enabled string
+			status  int
+		}{
+			{enabled: "maybe", status: http.StatusBadRequest},
+			{enabled: "true", status: http.StatusOK},
+		} {
+			req, err := http.NewRequest(http.MethodPut, link+"?enabled="+test.enabled, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "very-secret-token")
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, test.status, response.StatusCode)
+			require.NoError(t, response.Body.Close())
+		}
+
+		assertGet(t, link, `{"enabled":true}`)
+	})
+}
+
+func TestBucketRetention(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, sat, "locked"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/locked/retention"
+
+		assertGet(t, link, `{"days":0}`)
+
+		for _, test := range []struct {
+			days   string
+			status int
+		}{
+			{days: "-1", status: http.StatusBadRequest},
+			{days: "month", status: http.StatusBadRequest},
+			{days: "30", status: http.StatusOK},
+		} {
+			req, err := http.NewRequest(http.MethodPut, link+"?days="+test.days, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "very-secret-token")
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, test.status, response.StatusCode)
+			require.NoError(t, response.Body.Close())
+		}
+
+		assertGet(t, link, `{"days":30}`)
+	})
+}
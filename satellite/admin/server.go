@@ -26,7 +26,8 @@ import (
 type Config struct {
 	Address string `help:"admin peer http listening address" releaseDefault:"" devDefault:""`
 
-	AuthorizationToken string `internal:"true"`
+	AuthorizationToken string              `internal:"true"`
+	RSProfiles         metainfo.RSProfiles `internal:"true"`
 }
 
 // DB is databases needed for the admin server.
@@ -49,7 +50,8 @@ type Server struct {
 	server   http.Server
 	mux      *mux.Router
 
-	db DB
+	db         DB
+	rsProfiles metainfo.RSProfiles
 }
 
 // NewServer returns a new debug.Server.
@@ -59,6 +61,7 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, config Config) *Se
 	}
 
 	server.db = db
+	server.rsProfiles = config.RSProfiles
 	server.listener = listener
 	server.mux = mux.NewRouter()
 	server.server.Handler = &protectedServer{
@@ -75,8 +78,22 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, config Config) *Se
 	server.mux.HandleFunc("/api/coupon/{couponid}", server.deleteCoupon).Methods("DELETE")
 	server.mux.HandleFunc("/api/project/{project}/limit", server.getProjectLimit).Methods("GET")
 	server.mux.HandleFunc("/api/project/{project}/limit", server.putProjectLimit).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/placement", server.getBucketPlacement).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/placement", server.putBucketPlacement).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/limit", server.getBucketLimit).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/limit", server.putBucketLimit).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/lifecycle", server.getBucketLifecycle).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/lifecycle", server.putBucketLifecycle).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/rsprofile", server.getBucketRSProfile).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/rsprofile", server.putBucketRSProfile).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/versioning", server.getBucketVersioning).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/versioning", server.putBucketVersioning).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/retention", server.getBucketRetention).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/retention", server.putBucketRetention).Methods("PUT", "POST")
 	server.mux.HandleFunc("/api/project/{project}", server.deleteProject).Methods("DELETE")
 	server.mux.HandleFunc("/api/project", server.addProject).Methods("POST")
+	server.mux.HandleFunc("/api/apikey/{apikey}/limit", server.getAPIKeyLimit).Methods("GET")
+	server.mux.HandleFunc("/api/apikey/{apikey}/limit", server.putAPIKeyLimit).Methods("PUT", "POST")
 
 	return server
 }
@@ -27,6 +27,7 @@ import (
 	"storj.io/private/version"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/private/lifecycle"
+	"storj.io/storj/private/nodetags"
 	"storj.io/storj/private/post"
 	"storj.io/storj/private/post/oauth2"
 	"storj.io/storj/private/version/checker"
@@ -101,10 +102,11 @@ type API struct {
 	}
 
 	Metainfo struct {
-		Database      metainfo.PointerDB
-		Service       *metainfo.Service
-		PieceDeletion *piecedeletion.Service
-		Endpoint2     *metainfo.Endpoint
+		Database       metainfo.PointerDB
+		Service        *metainfo.Service
+		PieceDeletion  *piecedeletion.Service
+		Endpoint2      *metainfo.Endpoint
+		RequestCounter *metainfo.RequestCounter
 	}
 
 	Inspector struct {
@@ -249,7 +251,10 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 	{ // setup overlay
 		peer.Overlay.DB = peer.DB.OverlayCache()
 
-		peer.Overlay.Service = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		peer.Overlay.Service, err = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "overlay",
 			Close: peer.Overlay.Service.Close,
@@ -287,6 +292,9 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		if err := pb.DRPCRegisterNode(peer.Server.DRPC(), peer.Contact.Endpoint); err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		if err := nodetags.DRPCRegisterNodeTags(peer.Server.DRPC(), peer.Contact.Endpoint); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 
 		peer.Services.Add(lifecycle.Item{
 			Name:  "contact:service",
@@ -388,6 +396,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		peer.Metainfo.Service = metainfo.NewService(peer.Log.Named("metainfo:service"),
 			peer.Metainfo.Database,
 			peer.DB.Buckets(),
+			peer.DB.SegmentReferences(),
 		)
 
 		peer.Metainfo.PieceDeletion, err = piecedeletion.NewService(
@@ -404,6 +413,19 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			Close: peer.Metainfo.PieceDeletion.Close,
 		})
 
+		peer.Metainfo.RequestCounter = metainfo.NewRequestCounter(
+			peer.Log.Named("metainfo:requestcounter"),
+			peer.DB.Console().APIKeys(),
+			config.Metainfo.RequestCounter,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "metainfo:requestcounter",
+			Run:   peer.Metainfo.RequestCounter.Run,
+			Close: peer.Metainfo.RequestCounter.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Metainfo Request Counter", peer.Metainfo.RequestCounter.Loop))
+
 		peer.Metainfo.Endpoint2, err = metainfo.NewEndpoint(
 			peer.Log.Named("metainfo:endpoint"),
 			peer.Metainfo.Service,
@@ -414,6 +436,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Marketing.PartnersService,
 			peer.DB.PeerIdentities(),
 			peer.DB.Console().APIKeys(),
+			peer.Metainfo.RequestCounter,
 			peer.Accounting.ProjectUsage,
 			peer.DB.Console().Projects(),
 			signing.SignerFromFullIdentity(peer.Identity),
@@ -426,6 +449,9 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		if err := pb.DRPCRegisterMetainfo(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		if err := metainfo.DRPCRegisterObjects(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:endpoint",
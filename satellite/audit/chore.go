@@ -19,22 +19,24 @@ import (
 //
 // architecture: Chore
 type Chore struct {
-	log   *zap.Logger
-	rand  *rand.Rand
-	queue *Queue
-	Loop  *sync2.Cycle
+	log       *zap.Logger
+	rand      *rand.Rand
+	queue     *Queue
+	scheduler *Scheduler
+	Loop      *sync2.Cycle
 
 	metainfoLoop *metainfo.Loop
 	config       Config
 }
 
 // NewChore instantiates Chore.
-func NewChore(log *zap.Logger, queue *Queue, metaLoop *metainfo.Loop, config Config) *Chore {
+func NewChore(log *zap.Logger, queue *Queue, scheduler *Scheduler, metaLoop *metainfo.Loop, config Config) *Chore {
 	return &Chore{
-		log:   log,
-		rand:  rand.New(rand.NewSource(time.Now().Unix())),
-		queue: queue,
-		Loop:  sync2.NewCycle(config.ChoreInterval),
+		log:       log,
+		rand:      rand.New(rand.NewSource(time.Now().Unix())),
+		queue:     queue,
+		scheduler: scheduler,
+		Loop:      sync2.NewCycle(config.ChoreInterval),
 
 		metainfoLoop: metaLoop,
 		config:       config,
@@ -47,6 +49,24 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
 		defer mon.Task()(&ctx)(&err)
 
+		if chore.scheduler != nil {
+			err = chore.scheduler.Refresh(ctx)
+			if err != nil {
+				chore.log.Error("error refreshing node states", zap.Error(err))
+				return nil
+			}
+
+			pathCollector := NewScheduledPathCollector(chore.scheduler, chore.rand)
+			err = chore.metainfoLoop.Join(ctx, pathCollector)
+			if err != nil {
+				chore.log.Error("error joining metainfoloop", zap.Error(err))
+				return nil
+			}
+
+			chore.queue.Swap(chore.scheduler.Queue(pathCollector.Reservoirs))
+			return nil
+		}
+
 		pathCollector := NewPathCollector(chore.config.Slots, chore.rand)
 		err = chore.metainfoLoop.Join(ctx, pathCollector)
 		if err != nil {
@@ -0,0 +1,254 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/pb"
+	"storj.io/common/rpc"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/storj/private/pieceproof"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/uplink/private/eestream"
+)
+
+var (
+	// ErrNoPieceProof is the errs class for when a node doesn't support range proofs.
+	ErrNoPieceProof = errs.Class("node did not send a piece proof")
+	// ErrInvalidPieceProof is the errs class for when the range proof of a node is invalid.
+	ErrInvalidPieceProof = errs.Class("invalid piece proof")
+)
+
+// VerifyExistence checks that the nodes of a segment still store their pieces, without
+// downloading enough pieces to reconstruct a stripe. Every node is asked for a range proof
+// of its piece at a random offset: the state of the piece hash up to the offset and the
+// piece after it, signed by the node. A node passes when finishing the hash gives the
+// piece hash signed by the uplink.
+//
+// Nodes which don't support range proofs are reported as unknown.
+func (verifier *Verifier) VerifyExistence(ctx context.Context, path storj.Path, skip map[storj.NodeID]bool) (report Report, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointerBytes, pointer, err := verifier.metainfo.GetWithBytes(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return Report{}, ErrSegmentDeleted.New("%q", path)
+		}
+		return Report{}, err
+	}
+	if metainfo.IsExpired(pointer, time.Now()) {
+		return Report{}, ErrSegmentExpired.New("segment expired before VerifyExistence")
+	}
+
+	defer func() {
+		// if piece hashes have not been verified for this segment, do not mark nodes as failing audit
+		if !pointer.PieceHashesVerified {
+			report.Fails = nil
+		}
+	}()
+
+	redundancy, err := eestream.NewRedundancyStrategyFromProto(pointer.GetRemote().GetRedundancy())
+	if err != nil {
+		return Report{}, Error.Wrap(err)
+	}
+	pieceSize := eestream.CalcPieceSize(pointer.GetSegmentSize(), redundancy)
+
+	orderLimits, privateKey, err := verifier.orders.CreateAuditPieceOrderLimits(ctx, createBucketID(path), pointer, skip, pieceSize)
+	if err != nil {
+		return Report{}, err
+	}
+
+	offlineNodes := getOfflineNodes(pointer, orderLimits, skip)
+
+	type result struct {
+		nodeID storj.NodeID
+		err    error
+	}
+
+	rnd := rand.New(cryptoSource{})
+	ch := make(chan result, len(orderLimits))
+	pending := 0
+	for i, limit := range orderLimits {
+		if limit == nil {
+			continue
+		}
+		pending++
+
+		// every node gets its own offset, so they can't share proofs.
+		offset := rnd.Int63n(pieceSize/pieceproof.BlockSize+1) * pieceproof.BlockSize
+		go func(pieceNum int32, limit *pb.AddressedOrderLimit, offset int64) {
+			pieceID := pointer.GetRemote().RootPieceId.Derive(limit.GetLimit().StorageNodeId, pieceNum)
+			err := verifier.GetPieceProof(ctx, limit, privateKey, pieceID, pieceSize, offset)
+			ch <- result{nodeID: limit.GetLimit().StorageNodeId, err: err}
+		}(int32(i), limit, offset)
+	}
+
+	var successNodes, failedNodes, unknownNodes storj.NodeIDList
+	for ; pending > 0; pending-- {
+		result := <-ch
+		log := verifier.log.With(zap.Bool("Piece Hash Verified", pointer.PieceHashesVerified), zap.Stringer("Node ID", result.nodeID))
+
+		switch {
+		case result.err == nil:
+			successNodes = append(successNodes, result.nodeID)
+		case ErrNoPieceProof.Has(result.err):
+			unknownNodes = append(unknownNodes, result.nodeID)
+			mon.Meter("audit_existence_no_proof").Mark(1)
+			log.Debug("VerifyExistence: range proofs not supported (unknown)", zap.Error(result.err))
+		case ErrInvalidPieceProof.Has(result.err):
+			failedNodes = append(failedNodes, result.nodeID)
+			log.Info("VerifyExistence: invalid piece proof (audit failed)", zap.Error(result.err))
+		case rpc.Error.Has(result.err):
+			if errs.Is(result.err, context.DeadlineExceeded) || errs2.IsRPC(result.err, rpcstatus.Unknown) {
+				offlineNodes = append(offlineNodes, result.nodeID)
+				log.Debug("VerifyExistence: dial failed (offline)", zap.Error(result.err))
+				continue
+			}
+			unknownNodes = append(unknownNodes, result.nodeID)
+			log.Info("VerifyExistence: unknown transport error (skipped)", zap.Error(result.err))
+		case errs2.IsRPC(result.err, rpcstatus.NotFound), errs2.IsRPC(result.err, rpcstatus.DataLoss):
+			failedNodes = append(failedNodes, result.nodeID)
+			log.Info("VerifyExistence: piece not found or corrupted (audit failed)", zap.Error(result.err))
+		default:
+			// timeouts are unknown as well, there's no stripe to reverify a contained
+			// node with.
+			unknownNodes = append(unknownNodes, result.nodeID)
+			log.Info("VerifyExistence: unknown error (skipped)", zap.Error(result.err))
+		}
+	}
+
+	err = verifier.checkIfSegmentAltered(ctx, path, pointer, pointerBytes)
+	if err != nil {
+		return Report{
+			Offlines: offlineNodes,
+		}, err
+	}
+
+	mon.IntVal("audit_existence_success_nodes").Observe(int64(len(successNodes)))
+	mon.IntVal("audit_existence_fail_nodes").Observe(int64(len(failedNodes)))
+	mon.IntVal("audit_existence_offline_nodes").Observe(int64(len(offlineNodes)))
+	mon.IntVal("audit_existence_unknown_nodes").Observe(int64(len(unknownNodes)))
+
+	return Report{
+		Successes: successNodes,
+		Fails:     failedNodes,
+		Offlines:  offlineNodes,
+		Unknown:   unknownNodes,
+	}, nil
+}
+
+// GetPieceProof asks a node for a range proof of its piece at offset, and verifies it
+// against the piece hash signed by the uplink.
+func (verifier *Verifier) GetPieceProof(ctx context.Context, limit *pb.AddressedOrderLimit, piecePrivateKey storj.PiecePrivateKey, pieceID storj.PieceID, pieceSize, offset int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the node sends the piece after the offset, and hashes the whole piece.
+	timedCtx := ctx
+	if verifier.minBytesPerSecond > 0 {
+		maxTransferTime := time.Duration(int64(time.Second) * pieceSize / verifier.minBytesPerSecond.Int64())
+		if maxTransferTime < verifier.minDownloadTimeout {
+			maxTransferTime = verifier.minDownloadTimeout
+		}
+		var cancel func()
+		timedCtx, cancel = context.WithTimeout(ctx, maxTransferTime)
+		defer cancel()
+	}
+
+	nodeurl := storj.NodeURL{
+		ID:      limit.GetLimit().StorageNodeId,
+		Address: limit.GetStorageNodeAddress().Address,
+	}
+	conn, err := verifier.dialer.DialNodeURL(timedCtx, nodeurl)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err := conn.Close()
+		if err != nil {
+			verifier.log.Error("audit verifier failed to close conn to node: %+v", zap.Error(err))
+		}
+	}()
+
+	peer, err := conn.PeerIdentity()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	order, err := signing.SignUplinkOrder(ctx, piecePrivateKey, &pb.Order{
+		SerialNumber: limit.GetLimit().SerialNumber,
+		Amount:       pieceSize - offset,
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	proof, err := pieceproof.NewDRPCPieceProofsClient(conn).ProveRange(timedCtx, &pieceproof.ProveRangeRequest{
+		Limit:  limit.GetLimit(),
+		Order:  order,
+		Offset: offset,
+	})
+	if err != nil {
+		if errs2.IsRPC(err, rpcstatus.Unimplemented) {
+			return ErrNoPieceProof.New("%s", nodeurl.ID)
+		}
+		return err
+	}
+
+	if proof.Hash == nil || proof.Limit == nil {
+		return ErrInvalidPieceProof.New("missing piece hash or order limit")
+	}
+	if err := pieceproof.VerifySignature(ctx, signing.SigneeFromPeerIdentity(peer), limit.GetLimit().SerialNumber, proof); err != nil {
+		return ErrInvalidPieceProof.New("invalid node signature: %v", err)
+	}
+	if proof.Offset != offset || int64(len(proof.Data)) != pieceSize-offset {
+		return ErrInvalidPieceProof.New("proof is for offset %d with %d bytes, expected offset %d", proof.Offset, len(proof.Data), offset)
+	}
+
+	err = verifier.verifyPieceProof(ctx, nodeurl.ID, pieceID, pieceSize, proof.Hash, proof.Limit)
+	if err != nil {
+		return err
+	}
+
+	hash, err := pieceproof.FinishHash(proof.State, proof.Data)
+	if err != nil {
+		return ErrInvalidPieceProof.Wrap(err)
+	}
+	if !bytes.Equal(hash, proof.Hash.Hash) {
+		return ErrInvalidPieceProof.New("piece data doesn't match the piece hash")
+	}
+	return nil
+}
+
+// verifyPieceProof verifies that the piece hash and original order limit belong to the
+// piece of the node, and that the piece has the expected size.
+func (verifier *Verifier) verifyPieceProof(ctx context.Context, nodeID storj.NodeID, pieceID storj.PieceID, pieceSize int64, hash *pb.PieceHash, originalLimit *pb.OrderLimit) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := signing.VerifyOrderLimitSignature(ctx, signing.SigneeFromPeerIdentity(verifier.auditor), originalLimit); err != nil {
+		return ErrInvalidPieceProof.New("invalid order limit signature: %v", err)
+	}
+	if originalLimit.StorageNodeId != nodeID {
+		return ErrInvalidPieceProof.New("order limit is for node %s", originalLimit.StorageNodeId)
+	}
+	if originalLimit.PieceId != pieceID || hash.PieceId != pieceID {
+		return ErrInvalidPieceProof.New("piece id doesn't match %s", pieceID)
+	}
+	if err := signing.VerifyUplinkPieceHashSignature(ctx, originalLimit.UplinkPublicKey, hash); err != nil {
+		return ErrInvalidPieceProof.New("invalid piece hash signature: %v", err)
+	}
+	if hash.PieceSize != pieceSize {
+		return ErrInvalidPieceProof.New("piece size is %d, expected %d", hash.PieceSize, pieceSize)
+	}
+	return nil
+}
@@ -12,7 +12,7 @@ import (
 	"storj.io/storj/satellite/metainfo"
 )
 
-var _ metainfo.Observer = (*PathCollector)(nil)
+var _ metainfo.PartialObserver = (*PathCollector)(nil)
 
 // PathCollector uses the metainfo loop to add paths to node reservoirs
 //
@@ -20,6 +20,7 @@ var _ metainfo.Observer = (*PathCollector)(nil)
 type PathCollector struct {
 	Reservoirs map[storj.NodeID]*Reservoir
 	slotCount  int
+	scheduler  *Scheduler
 	rand       *rand.Rand
 }
 
@@ -32,11 +33,25 @@ func NewPathCollector(reservoirSlots int, r *rand.Rand) *PathCollector {
 	}
 }
 
+// NewScheduledPathCollector instantiates a path collector, which uses the
+// number of reservoir slots assigned to the nodes by the scheduler.
+func NewScheduledPathCollector(scheduler *Scheduler, r *rand.Rand) *PathCollector {
+	return &PathCollector{
+		Reservoirs: make(map[storj.NodeID]*Reservoir),
+		scheduler:  scheduler,
+		rand:       r,
+	}
+}
+
 // RemoteSegment takes a remote segment found in metainfo and creates a reservoir for it if it doesn't exist already
 func (collector *PathCollector) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	for _, piece := range pointer.GetRemote().GetRemotePieces() {
 		if _, ok := collector.Reservoirs[piece.NodeId]; !ok {
-			collector.Reservoirs[piece.NodeId] = NewReservoir(collector.slotCount)
+			slotCount := collector.slotCount
+			if collector.scheduler != nil {
+				slotCount = collector.scheduler.Slots(piece.NodeId)
+			}
+			collector.Reservoirs[piece.NodeId] = NewReservoir(slotCount)
 		}
 		collector.Reservoirs[piece.NodeId].Sample(collector.rand, path.Raw)
 	}
@@ -52,3 +67,31 @@ func (collector *PathCollector) Object(ctx context.Context, path metainfo.Scoped
 func (collector *PathCollector) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	return nil
 }
+
+// Fork returns a path collector for a range of the metainfo loop.
+func (collector *PathCollector) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return &PathCollector{
+		Reservoirs: make(map[storj.NodeID]*Reservoir),
+		slotCount:  collector.slotCount,
+		scheduler:  collector.scheduler,
+		rand:       rand.New(rand.NewSource(collector.rand.Int63())),
+	}, nil
+}
+
+// Merge merges the reservoirs of a forked path collector.
+func (collector *PathCollector) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*PathCollector)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+
+	for nodeID, reservoir := range fork.Reservoirs {
+		existing, ok := collector.Reservoirs[nodeID]
+		if !ok {
+			collector.Reservoirs[nodeID] = reservoir
+			continue
+		}
+		existing.Merge(collector.rand, reservoir)
+	}
+	return nil
+}
@@ -27,7 +27,7 @@ import (
 //
 // Then for every node in testplanet:
 //    - expect that there is a reservoir for that node on the audit observer
-//    - that the reservoir size is <= 2 (the maxReservoirSize)
+//    - that the reservoir size is the number of slots of the collector
 //    - that every item in the reservoir is unique
 func TestAuditPathCollector(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
@@ -59,9 +59,8 @@ func TestAuditPathCollector(t *testing.T) {
 			require.NotNil(t, observer.Reservoirs[node.ID()])
 			require.True(t, len(observer.Reservoirs[node.ID()].Paths) > 1)
 
-			// Require that len paths are <= 3 even though the PathCollector was instantiated with 4
-			// because the maxReservoirSize is currently 3.
-			require.True(t, len(observer.Reservoirs[node.ID()].Paths) <= 3)
+			// Require that the reservoir has as many slots as the PathCollector was instantiated with.
+			require.Len(t, observer.Reservoirs[node.ID()].Paths, 4)
 
 			repeats := make(map[storj.Path]bool)
 			for _, path := range observer.Reservoirs[node.ID()].Paths {
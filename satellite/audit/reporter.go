@@ -23,6 +23,7 @@ type Reporter struct {
 	log              *zap.Logger
 	overlay          *overlay.Service
 	containment      Containment
+	scheduler        *Scheduler
 	maxRetries       int
 	maxReverifyCount int32
 }
@@ -36,12 +37,13 @@ type Report struct {
 	Unknown       storj.NodeIDList
 }
 
-// NewReporter instantiates a reporter
-func NewReporter(log *zap.Logger, overlay *overlay.Service, containment Containment, maxRetries int, maxReverifyCount int32) *Reporter {
+// NewReporter instantiates a reporter, failed audits are reported to scheduler, when it's not nil.
+func NewReporter(log *zap.Logger, overlay *overlay.Service, containment Containment, scheduler *Scheduler, maxRetries int, maxReverifyCount int32) *Reporter {
 	return &Reporter{
 		log:              log,
 		overlay:          overlay,
 		containment:      containment,
+		scheduler:        scheduler,
 		maxRetries:       maxRetries,
 		maxReverifyCount: maxReverifyCount}
 }
@@ -66,6 +68,8 @@ func (reporter *Reporter) RecordAudits(ctx context.Context, req Report, path sto
 		zap.Int("pending", len(pendingAudits)),
 	)
 
+	reporter.scheduler.RecordFailures(fails)
+
 	var errlist errs.Group
 
 	tries := 0
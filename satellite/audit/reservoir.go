@@ -9,12 +9,10 @@ import (
 	"storj.io/common/storj"
 )
 
-const maxReservoirSize = 3
-
 // Reservoir holds a certain number of segments to reflect a random sample
 type Reservoir struct {
-	Paths [maxReservoirSize]storj.Path
-	size  int8
+	Paths []storj.Path
+	size  int
 	index int64
 }
 
@@ -22,15 +20,64 @@ type Reservoir struct {
 func NewReservoir(size int) *Reservoir {
 	if size < 1 {
 		size = 1
-	} else if size > maxReservoirSize {
-		size = maxReservoirSize
 	}
 	return &Reservoir{
-		size:  int8(size),
+		Paths: make([]storj.Path, size),
+		size:  size,
 		index: 0,
 	}
 }
 
+// Merge merges the sample of another reservoir of the same size, as if the
+// reservoir had sampled the segments of both. The segments of each reservoir
+// are picked with a probability proportional to the number of segments it
+// has seen.
+func (reservoir *Reservoir) Merge(r *rand.Rand, other *Reservoir) {
+	paths, otherPaths := reservoir.paths(), other.paths()
+	if len(otherPaths) == 0 {
+		reservoir.index += other.index
+		return
+	}
+	if len(paths) == 0 {
+		index := reservoir.index
+		*reservoir = *other
+		reservoir.index += index
+		return
+	}
+
+	// every remaining path represents the same number of the segments seen by its reservoir.
+	weight := float64(reservoir.index) / float64(len(paths))
+	otherWeight := float64(other.index) / float64(len(otherPaths))
+
+	merged := make([]storj.Path, reservoir.size)
+	for i := 0; i < reservoir.size && len(paths)+len(otherPaths) > 0; i++ {
+		total := weight*float64(len(paths)) + otherWeight*float64(len(otherPaths))
+		if len(otherPaths) == 0 || len(paths) > 0 && r.Float64()*total < weight*float64(len(paths)) {
+			k := r.Intn(len(paths))
+			merged[i] = paths[k]
+			paths = append(paths[:k], paths[k+1:]...)
+		} else {
+			k := r.Intn(len(otherPaths))
+			merged[i] = otherPaths[k]
+			otherPaths = append(otherPaths[:k], otherPaths[k+1:]...)
+		}
+	}
+
+	reservoir.Paths = merged
+	reservoir.index += other.index
+}
+
+// paths returns the sampled paths.
+func (reservoir *Reservoir) paths() []storj.Path {
+	paths := make([]storj.Path, 0, len(reservoir.Paths))
+	for _, path := range reservoir.Paths {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // Sample makes sure that for every segment in metainfo from index i=size..n-1,
 // pick a random number r = rand(0..i), and if r < size, replace reservoir.Segments[r] with segment
 func (reservoir *Reservoir) Sample(r *rand.Rand, path storj.Path) {
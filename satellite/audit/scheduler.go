@@ -0,0 +1,268 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/overlay"
+)
+
+// NodeState is the state of a node, which determines its audit budget.
+type NodeState int
+
+const (
+	// NodeVetted is a node without any of the other states.
+	NodeVetted NodeState = iota
+	// NodeUnvetted is a node which hasn't been audited enough to be vetted.
+	NodeUnvetted
+	// NodeSuspended is a node suspended for too many unknown audit errors.
+	NodeSuspended
+	// NodeFailed is a node with a failed audit within the failure window.
+	NodeFailed
+	// NodeContained is a node with a pending reverification.
+	NodeContained
+)
+
+var nodeStateNames = map[NodeState]string{
+	NodeVetted:    "vetted",
+	NodeUnvetted:  "unvetted",
+	NodeSuspended: "suspended",
+	NodeFailed:    "failed",
+	NodeContained: "contained",
+}
+
+// String returns the name of the state.
+func (state NodeState) String() string {
+	if name, ok := nodeStateNames[state]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ScheduleConfig contains the policy for allocating the audit budget by node state.
+type ScheduleConfig struct {
+	Priority       string        `help:"comma separated order in which nodes are audited by state, a node gets the first matching state of contained, failed, suspended, unvetted and vetted" default:"contained,failed,suspended,unvetted,vetted"`
+	UnvettedSlots  int           `help:"number of reservoir slots allotted for unvetted nodes" default:"6"`
+	SuspendedSlots int           `help:"number of reservoir slots allotted for suspended nodes" default:"3"`
+	FailedSlots    int           `help:"number of reservoir slots allotted for nodes with recently failed audits" default:"6"`
+	ContainedSlots int           `help:"number of reservoir slots allotted for contained nodes" default:"1"`
+	FailureWindow  time.Duration `help:"how long a node is prioritized after failing an audit" default:"24h"`
+}
+
+// ParsePriority parses the comma separated order of node states.
+// States missing from the list are audited after the listed states.
+func ParsePriority(priority string) ([]NodeState, error) {
+	byName := make(map[string]NodeState, len(nodeStateNames))
+	for state, name := range nodeStateNames {
+		byName[name] = state
+	}
+
+	var states []NodeState
+	seen := map[NodeState]bool{}
+	for _, name := range strings.Split(priority, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		state, ok := byName[name]
+		if !ok {
+			return nil, Error.New("unknown node state %q in audit priority", name)
+		}
+		if seen[state] {
+			return nil, Error.New("duplicate node state %q in audit priority", name)
+		}
+		seen[state] = true
+		states = append(states, state)
+	}
+
+	// keep the missing states in the default order.
+	for _, state := range []NodeState{NodeContained, NodeFailed, NodeSuspended, NodeUnvetted, NodeVetted} {
+		if !seen[state] {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+// Scheduler decides how many segments of a node are audited and in which order,
+// based on the state of the node.
+//
+// architecture: Service
+type Scheduler struct {
+	overlay  *overlay.Service
+	priority []NodeState
+	slots    map[NodeState]int
+	window   time.Duration
+
+	mu          sync.Mutex
+	failures    map[storj.NodeID]time.Time
+	states      map[storj.NodeID]NodeState
+	vettedSince time.Time
+}
+
+// NewScheduler creates a new audit scheduler, vettedSlots is the number of
+// reservoir slots of vetted nodes.
+func NewScheduler(overlay *overlay.Service, config ScheduleConfig, vettedSlots int) (*Scheduler, error) {
+	priority, err := ParsePriority(config.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		overlay:  overlay,
+		priority: priority,
+		slots: map[NodeState]int{
+			NodeVetted:    vettedSlots,
+			NodeUnvetted:  config.UnvettedSlots,
+			NodeSuspended: config.SuspendedSlots,
+			NodeFailed:    config.FailedSlots,
+			NodeContained: config.ContainedSlots,
+		},
+		window: config.FailureWindow,
+
+		failures:    map[storj.NodeID]time.Time{},
+		states:      map[storj.NodeID]NodeState{},
+		vettedSince: time.Now(),
+	}, nil
+}
+
+// RecordFailures marks the nodes as recently failed.
+func (scheduler *Scheduler) RecordFailures(nodeIDs storj.NodeIDList) {
+	if scheduler == nil || len(nodeIDs) == 0 {
+		return
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	now := time.Now()
+	for _, nodeID := range nodeIDs {
+		scheduler.failures[nodeID] = now
+	}
+}
+
+// Refresh loads the states of the nodes.
+func (scheduler *Scheduler) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	vettedSince := time.Now()
+	states, err := scheduler.overlay.AuditStates(ctx, scheduler.vettedSince)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, duration := range states.TimeToVetting {
+		mon.FloatVal("audit_time_to_vetting").Observe(duration.Hours())
+	}
+
+	scheduler.update(states, vettedSince)
+	return nil
+}
+
+// update assigns the states of the nodes.
+func (scheduler *Scheduler) update(states *overlay.AuditStates, vettedSince time.Time) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	scheduler.vettedSince = vettedSince
+
+	failed := storj.NodeIDList{}
+	for nodeID, failedAt := range scheduler.failures {
+		if time.Since(failedAt) > scheduler.window {
+			delete(scheduler.failures, nodeID)
+			continue
+		}
+		failed = append(failed, nodeID)
+	}
+
+	nodesByState := map[NodeState]storj.NodeIDList{
+		NodeUnvetted:  states.Unvetted,
+		NodeSuspended: states.Suspended,
+		NodeFailed:    failed,
+		NodeContained: states.Contained,
+	}
+
+	// a node gets the state with the highest priority, so assign the
+	// states starting with the lowest priority.
+	scheduler.states = map[storj.NodeID]NodeState{}
+	for i := len(scheduler.priority) - 1; i >= 0; i-- {
+		state := scheduler.priority[i]
+		for _, nodeID := range nodesByState[state] {
+			scheduler.states[nodeID] = state
+		}
+	}
+
+	counts := map[NodeState]int64{}
+	for _, state := range scheduler.states {
+		counts[state]++
+	}
+	for _, state := range scheduler.priority {
+		if state != NodeVetted {
+			mon.IntValf("audit_scheduled_nodes_%s", state).Observe(counts[state])
+		}
+	}
+}
+
+// State returns the state of the node from the last refresh.
+func (scheduler *Scheduler) State(nodeID storj.NodeID) NodeState {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	return scheduler.states[nodeID]
+}
+
+// Slots returns the number of reservoir slots of the node.
+func (scheduler *Scheduler) Slots(nodeID storj.NodeID) int {
+	return scheduler.slots[scheduler.State(nodeID)]
+}
+
+// Queue returns the paths of the reservoirs in the order they should be audited.
+// Nodes are ordered by the priority of their state, paths of nodes with the same state
+// are interleaved so that every node gets its first audit before any gets a second one.
+func (scheduler *Scheduler) Queue(reservoirs map[storj.NodeID]*Reservoir) []storj.Path {
+	byState := map[NodeState][]storj.NodeID{}
+	for nodeID := range reservoirs {
+		state := scheduler.State(nodeID)
+		byState[state] = append(byState[state], nodeID)
+	}
+
+	var queue []storj.Path
+	queued := make(map[storj.Path]struct{})
+	for _, state := range scheduler.priority {
+		nodeIDs := byState[state]
+		// iterate in a stable order, the paths in the reservoirs are random.
+		sort.Sort(storj.NodeIDList(nodeIDs))
+
+		slots := 0
+		for _, nodeID := range nodeIDs {
+			if len(reservoirs[nodeID].Paths) > slots {
+				slots = len(reservoirs[nodeID].Paths)
+			}
+		}
+
+		queuedBefore := len(queue)
+		for i := 0; i < slots; i++ {
+			for _, nodeID := range nodeIDs {
+				paths := reservoirs[nodeID].Paths
+				if len(paths) <= i || paths[i] == "" {
+					continue
+				}
+				path := paths[i]
+				if _, ok := queued[path]; !ok {
+					queue = append(queue, path)
+					queued[path] = struct{}{}
+				}
+			}
+		}
+		mon.IntValf("audit_queued_paths_%s", state).Observe(int64(len(queue) - queuedBefore))
+	}
+
+	return queue
+}
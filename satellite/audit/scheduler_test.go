@@ -0,0 +1,127 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestParsePriority(t *testing.T) {
+	priority, err := ParsePriority("unvetted, failed")
+	require.NoError(t, err)
+	require.Equal(t, []NodeState{NodeUnvetted, NodeFailed, NodeContained, NodeSuspended, NodeVetted}, priority)
+
+	_, err = ParsePriority("unvetted,unknown")
+	require.Error(t, err)
+
+	_, err = ParsePriority("failed,failed")
+	require.Error(t, err)
+}
+
+func TestScheduler(t *testing.T) {
+	scheduler, err := NewScheduler(nil, ScheduleConfig{
+		Priority:       "contained,failed,suspended,unvetted,vetted",
+		UnvettedSlots:  3,
+		SuspendedSlots: 2,
+		FailedSlots:    3,
+		ContainedSlots: 1,
+		FailureWindow:  time.Hour,
+	}, 1)
+	require.NoError(t, err)
+
+	vetted, unvetted, suspended, failed, contained := testrand.NodeID(), testrand.NodeID(), testrand.NodeID(), testrand.NodeID(), testrand.NodeID()
+
+	scheduler.RecordFailures(storj.NodeIDList{failed, contained})
+	scheduler.update(&overlay.AuditStates{
+		Unvetted:  storj.NodeIDList{unvetted, failed},
+		Suspended: storj.NodeIDList{suspended},
+		Contained: storj.NodeIDList{contained},
+	}, time.Now())
+
+	// a node gets the state with the highest priority.
+	require.Equal(t, NodeVetted, scheduler.State(vetted))
+	require.Equal(t, NodeUnvetted, scheduler.State(unvetted))
+	require.Equal(t, NodeSuspended, scheduler.State(suspended))
+	require.Equal(t, NodeFailed, scheduler.State(failed))
+	require.Equal(t, NodeContained, scheduler.State(contained))
+
+	require.Equal(t, 1, scheduler.Slots(vetted))
+	require.Equal(t, 3, scheduler.Slots(unvetted))
+	require.Equal(t, 2, scheduler.Slots(suspended))
+
+	reservoir := func(paths ...storj.Path) *Reservoir {
+		res := NewReservoir(len(paths))
+		copy(res.Paths, paths)
+		return res
+	}
+	queue := scheduler.Queue(map[storj.NodeID]*Reservoir{
+		vetted:    reservoir("vetted"),
+		unvetted:  reservoir("unvetted-1", "unvetted-2"),
+		suspended: reservoir("suspended", "shared"),
+		failed:    reservoir("failed"),
+		contained: reservoir("contained", "shared"),
+	})
+	require.Equal(t, []storj.Path{"contained", "shared", "failed", "suspended", "unvetted-1", "unvetted-2", "vetted"}, queue)
+
+	// failures expire after the failure window.
+	scheduler.failures[failed] = time.Now().Add(-2 * time.Hour)
+	scheduler.update(&overlay.AuditStates{}, time.Now())
+	require.Equal(t, NodeVetted, scheduler.State(failed))
+	require.Empty(t, scheduler.failures[failed])
+}
+
+func TestScheduler_UnvettedNodesGetMoreAudits(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	scheduler, err := NewScheduler(nil, ScheduleConfig{
+		Priority:       "contained,failed,suspended,unvetted,vetted",
+		UnvettedSlots:  6,
+		SuspendedSlots: 3,
+		FailedSlots:    6,
+		ContainedSlots: 1,
+		FailureWindow:  time.Hour,
+	}, 3)
+	require.NoError(t, err)
+
+	vetted, unvetted := testrand.NodeID(), testrand.NodeID()
+	scheduler.update(&overlay.AuditStates{
+		Unvetted: storj.NodeIDList{unvetted},
+	}, time.Now())
+
+	collector := NewScheduledPathCollector(scheduler, rand.New(rand.NewSource(time.Now().Unix())))
+	owner := map[storj.Path]storj.NodeID{}
+	for _, nodeID := range []storj.NodeID{vetted, unvetted} {
+		for i := 0; i < 20; i++ {
+			path := nodeID.String() + "/" + strconv.Itoa(i)
+			owner[path] = nodeID
+			err := collector.RemoteSegment(ctx, metainfo.ScopedPath{Raw: path}, &pb.Pointer{
+				Remote: &pb.RemoteSegment{
+					RemotePieces: []*pb.RemotePiece{{NodeId: nodeID}},
+				},
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	audits := map[storj.NodeID]int{}
+	for _, path := range scheduler.Queue(collector.Reservoirs) {
+		audits[owner[path]]++
+	}
+
+	require.LessOrEqual(t, audits[vetted], 3)
+	require.Greater(t, audits[unvetted], audits[vetted])
+}
@@ -95,7 +95,7 @@ func (verifier *Verifier) Verify(ctx context.Context, path storj.Path, skip map[
 		}
 		return Report{}, err
 	}
-	if pointer.ExpirationDate != (time.Time{}) && pointer.ExpirationDate.Before(time.Now()) {
+	if metainfo.IsExpired(pointer, time.Now()) {
 		errDelete := verifier.metainfo.Delete(ctx, path, pointerBytes)
 		if errDelete != nil {
 			return Report{}, Error.Wrap(errDelete)
@@ -374,7 +374,7 @@ func (verifier *Verifier) Reverify(ctx context.Context, path storj.Path) (report
 		}
 		return Report{}, err
 	}
-	if pointer.ExpirationDate != (time.Time{}) && pointer.ExpirationDate.Before(time.Now()) {
+	if metainfo.IsExpired(pointer, time.Now()) {
 		errDelete := verifier.metainfo.Delete(ctx, path, pointerBytes)
 		if errDelete != nil {
 			return Report{}, Error.Wrap(errDelete)
@@ -448,7 +448,7 @@ func (verifier *Verifier) Reverify(ctx context.Context, path storj.Path) (report
 				verifier.log.Debug("Reverify: error getting pending pointer from metainfo", zap.Stringer("Node ID", pending.NodeID), zap.Error(err))
 				return
 			}
-			if pendingPointer.ExpirationDate != (time.Time{}) && pendingPointer.ExpirationDate.Before(time.Now().UTC()) {
+			if metainfo.IsExpired(pendingPointer, time.Now().UTC()) {
 				errDelete := verifier.metainfo.Delete(ctx, pending.Path, pendingPointerBytes)
 				if errDelete != nil {
 					verifier.log.Debug("Reverify: error deleting expired segment", zap.Stringer("Node ID", pending.NodeID), zap.Error(errDelete))
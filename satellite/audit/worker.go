@@ -5,6 +5,7 @@ package audit
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -27,8 +28,11 @@ type Config struct {
 
 	ChoreInterval     time.Duration `help:"how often to run the reservoir chore" releaseDefault:"24h" devDefault:"1m"`
 	QueueInterval     time.Duration `help:"how often to recheck an empty audit queue" releaseDefault:"1h" devDefault:"1m"`
-	Slots             int           `help:"number of reservoir slots allotted for nodes, currently capped at 3" default:"3"`
+	Slots             int           `help:"number of reservoir slots allotted for nodes" default:"3"`
 	WorkerConcurrency int           `help:"number of workers to run audits on paths" default:"2"`
+	ExistenceRatio    float64       `help:"fraction of segments audited with range proofs of the pieces instead of downloading a stripe" default:"0"`
+
+	Schedule ScheduleConfig
 }
 
 // Worker contains information for populating audit queue and processing audits.
@@ -39,6 +43,8 @@ type Worker struct {
 	reporter *Reporter
 	Loop     *sync2.Cycle
 	limiter  *sync2.Limiter
+
+	existenceRatio float64
 }
 
 // NewWorker instantiates Worker.
@@ -51,6 +57,8 @@ func NewWorker(log *zap.Logger, queue *Queue, verifier *Verifier, reporter *Repo
 		reporter: reporter,
 		Loop:     sync2.NewCycle(config.QueueInterval),
 		limiter:  sync2.NewLimiter(config.WorkerConcurrency),
+
+		existenceRatio: config.ExistenceRatio,
 	}, nil
 }
 
@@ -136,7 +144,13 @@ func (worker *Worker) work(ctx context.Context, path storj.Path) (err error) {
 	}
 
 	// Next, audit the the remaining nodes that are not in containment mode.
-	report, err = worker.verifier.Verify(ctx, path, skip)
+	// Some segments get a piece-existence audit instead, which checks range proofs
+	// of the pieces against the piece hashes signed by the uplink.
+	if worker.existenceRatio > 0 && rand.Float64() < worker.existenceRatio {
+		report, err = worker.verifier.VerifyExistence(ctx, path, skip)
+	} else {
+		report, err = worker.verifier.Verify(ctx, path, skip)
+	}
 	if err != nil {
 		errlist.Add(err)
 	}
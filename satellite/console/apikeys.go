@@ -28,6 +28,10 @@ type APIKeys interface {
 	Update(ctx context.Context, key APIKeyInfo) error
 	// Delete deletes APIKeyInfo from store
 	Delete(ctx context.Context, id uuid.UUID) error
+	// UpdateRateLimit updates the request rate limit of the api key, 0 removes the limit
+	UpdateRateLimit(ctx context.Context, id uuid.UUID, newLimit int) error
+	// IncrementRequestCounts adds the given amounts to the request counts of api keys
+	IncrementRequestCounts(ctx context.Context, counts map[uuid.UUID]int64) error
 }
 
 // APIKeyInfo describing api key model in the database
@@ -38,6 +42,9 @@ type APIKeyInfo struct {
 	Name      string    `json:"name"`
 	Secret    []byte    `json:"-"`
 	CreatedAt time.Time `json:"createdAt"`
+	RateLimit *int      `json:"rateLimit"`
+
+	RequestCount int64 `json:"requestCount"`
 }
 
 // APIKeyCursor holds info for api keys cursor pagination
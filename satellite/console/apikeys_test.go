@@ -11,6 +11,8 @@ import (
 
 	"storj.io/common/macaroon"
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/common/uuid"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
@@ -117,6 +119,64 @@ func TestApiKeysRepository(t *testing.T) {
 			assert.NoError(t, err)
 		})
 
+		t.Run("UpdateRateLimit success", func(t *testing.T) {
+			cursor := console.APIKeyCursor{
+				Page:   1,
+				Limit:  10,
+				Search: "",
+			}
+			page, err := apikeys.GetPagedByProjectID(ctx, project.ID, cursor)
+			assert.NotNil(t, page)
+			assert.NoError(t, err)
+
+			keyID := page.APIKeys[0].ID
+
+			err = apikeys.UpdateRateLimit(ctx, keyID, 10)
+			assert.NoError(t, err)
+
+			key, err := apikeys.Get(ctx, keyID)
+			assert.NoError(t, err)
+			if assert.NotNil(t, key.RateLimit) {
+				assert.Equal(t, 10, *key.RateLimit)
+			}
+
+			err = apikeys.UpdateRateLimit(ctx, keyID, 0)
+			assert.NoError(t, err)
+
+			key, err = apikeys.Get(ctx, keyID)
+			assert.NoError(t, err)
+			assert.Nil(t, key.RateLimit)
+		})
+
+		t.Run("IncrementRequestCounts success", func(t *testing.T) {
+			cursor := console.APIKeyCursor{
+				Page:   1,
+				Limit:  10,
+				Search: "",
+			}
+			page, err := apikeys.GetPagedByProjectID(ctx, project.ID, cursor)
+			assert.NotNil(t, page)
+			assert.NoError(t, err)
+
+			first, second := page.APIKeys[0].ID, page.APIKeys[1].ID
+
+			err = apikeys.IncrementRequestCounts(ctx, map[uuid.UUID]int64{first: 3, second: 1})
+			assert.NoError(t, err)
+			err = apikeys.IncrementRequestCounts(ctx, map[uuid.UUID]int64{first: 2, testrand.UUID(): 5})
+			assert.NoError(t, err)
+
+			page, err = apikeys.GetPagedByProjectID(ctx, project.ID, cursor)
+			assert.NoError(t, err)
+
+			counts := map[uuid.UUID]int64{}
+			for _, key := range page.APIKeys {
+				counts[key.ID] = key.RequestCount
+			}
+			assert.Equal(t, int64(5), counts[first])
+			assert.Equal(t, int64(1), counts[second])
+			assert.Equal(t, int64(0), counts[page.APIKeys[2].ID])
+		})
+
 		t.Run("Delete success", func(t *testing.T) {
 			cursor := console.APIKeyCursor{
 				Page:   1,
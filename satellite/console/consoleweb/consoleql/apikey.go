@@ -17,6 +17,10 @@ const (
 	CreateAPIKeyType = "graphqlCreateAPIKey"
 	// FieldKey is field name for the actual key in createAPIKey
 	FieldKey = "key"
+	// FieldRateLimit is field name for the request rate limit of the api key
+	FieldRateLimit = "rateLimit"
+	// FieldRequestCount is field name for the number of requests made with the api key
+	FieldRequestCount = "requestCount"
 )
 
 // graphqlAPIKeyInfo creates satellite.APIKeyInfo graphql object
@@ -39,6 +43,12 @@ func graphqlAPIKeyInfo() *graphql.Object {
 			FieldPartnerID: &graphql.Field{
 				Type: graphql.String,
 			},
+			FieldRateLimit: &graphql.Field{
+				Type: graphql.Int,
+			},
+			FieldRequestCount: &graphql.Field{
+				Type: graphql.Float,
+			},
 		},
 	})
 }
@@ -62,7 +62,7 @@ func TestGrapqhlMutation(t *testing.T) {
 		require.NoError(t, err)
 		defer ctx.Check(redis.Close)
 
-		cache, err := live.NewCache(log.Named("cache"), live.Config{StorageBackend: "redis://" + redis.Addr() + "?db=0"})
+		cache, err := live.NewCache(log.Named("cache"), nil, live.Config{StorageBackend: "redis://" + redis.Addr() + "?db=0"})
 		require.NoError(t, err)
 
 		projectUsage := accounting.NewService(db.ProjectAccounting(), cache, 0, 0)
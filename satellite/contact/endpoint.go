@@ -14,6 +14,7 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/storj"
+	"storj.io/storj/private/nodetags"
 	"storj.io/storj/satellite/overlay"
 )
 
@@ -104,6 +105,30 @@ func (endpoint *Endpoint) CheckIn(ctx context.Context, req *pb.CheckInRequest) (
 	}, nil
 }
 
+// SetTags replaces the region tags of the node, which are used for placement
+// when the operator maintained ip ranges don't contain the address of the node.
+func (endpoint *Endpoint) SetTags(ctx context.Context, req *nodetags.SetTagsRequest) (_ *nodetags.SetTagsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peerID, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		endpoint.log.Info("failed to get node ID from context", zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, errCheckInIdentity.New("failed to get ID from context: %v", err).Error())
+	}
+
+	tags, err := nodetags.Normalize(req.Tags)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	err = endpoint.service.overlay.UpdateNodeTags(ctx, peerID.ID, tags)
+	if err != nil {
+		endpoint.log.Info("failed to update node tags", zap.Stringer("Node ID", peerID.ID), zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, Error.Wrap(err).Error())
+	}
+	return &nodetags.SetTagsResponse{}, nil
+}
+
 // GetTime returns current timestamp
 func (endpoint *Endpoint) GetTime(ctx context.Context, req *pb.GetTimeRequest) (_ *pb.GetTimeResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -35,7 +35,10 @@ import (
 	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/gracefulexit"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/bucketlifecycle"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
+	"storj.io/storj/satellite/metainfo/piecedeletion"
+	"storj.io/storj/satellite/metainfo/zombiedeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -94,11 +97,12 @@ type Core struct {
 		Checker *checker.Checker
 	}
 	Audit struct {
-		Queue    *audit.Queue
-		Worker   *audit.Worker
-		Chore    *audit.Chore
-		Verifier *audit.Verifier
-		Reporter *audit.Reporter
+		Queue     *audit.Queue
+		Worker    *audit.Worker
+		Chore     *audit.Chore
+		Verifier  *audit.Verifier
+		Reporter  *audit.Reporter
+		Scheduler *audit.Scheduler
 	}
 
 	GarbageCollection struct {
@@ -109,6 +113,15 @@ type Core struct {
 		Chore *expireddeletion.Chore
 	}
 
+	BucketLifecycle struct {
+		PieceDeletion *piecedeletion.Service
+		Chore         *bucketlifecycle.Chore
+	}
+
+	ZombieDeletion struct {
+		Chore *zombiedeletion.Chore
+	}
+
 	DBCleanup struct {
 		Chore *dbcleanup.Chore
 	}
@@ -232,7 +245,10 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 	{ // setup overlay
 		peer.Overlay.DB = peer.DB.OverlayCache()
-		peer.Overlay.Service = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		peer.Overlay.Service, err = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "overlay",
 			Close: peer.Overlay.Service.Close,
@@ -280,8 +296,9 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 		peer.Metainfo.Service = metainfo.NewService(peer.Log.Named("metainfo:service"),
 			peer.Metainfo.Database,
 			peer.DB.Buckets(),
+			peer.DB.SegmentReferences(),
 		)
-		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database)
+		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database, peer.DB.MetainfoLoopRanges())
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:loop",
 			Run:   peer.Metainfo.Loop.Run,
@@ -298,6 +315,8 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Metainfo.Service,
 			peer.Metainfo.Loop,
 			peer.Overlay.Service,
+			peer.DB.DowntimeTracking(),
+			config.Metainfo.RS.Profiles,
 			config.Checker)
 		peer.Services.Add(lifecycle.Item{
 			Name:  "repair:checker",
@@ -315,6 +334,11 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 		peer.Audit.Queue = &audit.Queue{}
 
+		peer.Audit.Scheduler, err = audit.NewScheduler(peer.Overlay.Service, config.Schedule, config.Slots)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
 		peer.Audit.Verifier = audit.NewVerifier(log.Named("audit:verifier"),
 			peer.Metainfo.Service,
 			peer.Dialer,
@@ -329,6 +353,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 		peer.Audit.Reporter = audit.NewReporter(log.Named("audit:reporter"),
 			peer.Overlay.Service,
 			peer.DB.Containment(),
+			peer.Audit.Scheduler,
 			config.MaxRetriesStatDB,
 			int32(config.MaxReverifyCount),
 		)
@@ -353,6 +378,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 		peer.Audit.Chore = audit.NewChore(peer.Log.Named("audit:chore"),
 			peer.Audit.Queue,
+			peer.Audit.Scheduler,
 			peer.Metainfo.Loop,
 			config,
 		)
@@ -373,6 +399,8 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 				peer.Dialer,
 				peer.Overlay.DB,
 				peer.Metainfo.Loop,
+				peer.Metainfo.Database,
+				peer.DB.SegmentReferences(),
 			)
 			peer.Services.Add(lifecycle.Item{
 				Name: "core-garbage-collection",
@@ -398,6 +426,56 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			debug.Cycle("Expired Segments Chore", peer.ExpiredDeletion.Chore.Loop))
 	}
 
+	{ // setup bucket lifecycle rules
+		peer.BucketLifecycle.PieceDeletion, err = piecedeletion.NewService(
+			peer.Log.Named("bucket-lifecycle:piecedeletion"),
+			peer.Dialer,
+			config.Metainfo.PieceDeletion,
+		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		peer.Services.Add(lifecycle.Item{
+			Name:  "bucket-lifecycle:piecedeletion",
+			Run:   peer.BucketLifecycle.PieceDeletion.Run,
+			Close: peer.BucketLifecycle.PieceDeletion.Close,
+		})
+
+		peer.BucketLifecycle.Chore = bucketlifecycle.NewChore(
+			peer.Log.Named("core-bucket-lifecycle"),
+			config.BucketLifecycle,
+			peer.DB.Buckets(),
+			peer.Metainfo.Service,
+			peer.Metainfo.Loop,
+			peer.Overlay.Service,
+			peer.BucketLifecycle.PieceDeletion,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "bucketlifecycle:chore",
+			Run:   peer.BucketLifecycle.Chore.Run,
+			Close: peer.BucketLifecycle.Chore.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Bucket Lifecycle Chore", peer.BucketLifecycle.Chore.Loop))
+	}
+
+	{ // setup zombie segment cleanup
+		peer.ZombieDeletion.Chore = zombiedeletion.NewChore(
+			peer.Log.Named("core-zombie-deletion"),
+			config.ZombieDeletion,
+			config.Metainfo.MaxCommitInterval,
+			peer.Metainfo.Service,
+			peer.Metainfo.Loop,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "zombiedeletion:chore",
+			Run:   peer.ZombieDeletion.Chore.Run,
+			Close: peer.ZombieDeletion.Chore.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Zombie Segments Chore", peer.ZombieDeletion.Chore.Loop))
+	}
+
 	{ // setup db cleanup
 		peer.DBCleanup.Chore = dbcleanup.NewChore(peer.Log.Named("dbcleanup"), peer.DB.Orders(), config.DBCleanup)
 		peer.Services.Add(lifecycle.Item{
@@ -28,4 +28,7 @@ type DB interface {
 	// An offline time window that began before `begin` but that overlaps with the provided time window is not included.
 	// An offline time window that begins within the provided time window, but that extends beyond `end` is included.
 	GetOfflineTime(ctx context.Context, nodeID storj.NodeID, begin, end time.Time) (time.Duration, error)
+	// GetTotalOfflineTime gets the sum of the offline time of all nodes within a certain timeframe,
+	// with the same definition of offline time windows as GetOfflineTime.
+	GetTotalOfflineTime(ctx context.Context, begin, end time.Time) (time.Duration, error)
 }
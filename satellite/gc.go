@@ -140,7 +140,8 @@ func NewGarbageCollection(log *zap.Logger, full *identity.FullIdentity, db DB,
 		// GC runs infrequently, this shouldn'tt add too much extra load on the metainfo db.
 		// As long as garbage collection is the only observer joining the metainfo loop, then by default
 		// the metainfo loop will only run when the garbage collection joins (which happens every GarbageCollection.Interval)
-		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database)
+		// The ranges aren't shared with other processes, because the bloom filters need every segment.
+		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database, nil)
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:loop",
 			Run:   peer.Metainfo.Loop.Run,
@@ -155,6 +156,8 @@ func NewGarbageCollection(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Dialer,
 			peer.Overlay.DB,
 			peer.Metainfo.Loop,
+			peer.Metainfo.Database,
+			peer.DB.SegmentReferences(),
 		)
 		peer.Services.Add(lifecycle.Item{
 			Name: "garbage-collection",
@@ -16,6 +16,10 @@ The gc.Service will send that request to the storagenode after a full metaloop
 iteration, and the storage node will use that request to delete the "garbage" pieces
 that are not in the bloom filter.
 
+The filters can also be built offline from a snapshot of the pointer database with
+gc.Generate, stored with gc.WriteRetainInfos and sent later with gc.Service.SendRetainInfos,
+which is what the "satellite garbage-collection generate" and "send" commands do.
+
 See storj/docs/design/garbage-collection.md for more info.
 */
 package gc
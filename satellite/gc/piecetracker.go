@@ -5,6 +5,7 @@ package gc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,7 +17,7 @@ import (
 	"storj.io/storj/satellite/metainfo"
 )
 
-var _ metainfo.Observer = (*PieceTracker)(nil)
+var _ metainfo.PartialObserver = (*PieceTracker)(nil)
 
 // PieceTracker implements the metainfo loop observer interface for garbage collection
 //
@@ -28,6 +29,9 @@ type PieceTracker struct {
 	// TODO: should we use int or int64 consistently for piece count (db type is int64)?
 	pieceCounts map[storj.NodeID]int
 
+	// mu guards the bloom filters, which are shared with the forks of the piece
+	// tracker instead of merged, because the filters of a node use a random seed.
+	mu          sync.Mutex
 	retainInfos map[storj.NodeID]*RetainInfo
 }
 
@@ -67,8 +71,21 @@ func (pieceTracker *PieceTracker) InlineSegment(ctx context.Context, path metain
 	return nil
 }
 
+// Fork returns the piece tracker itself, because it's safe for concurrent use.
+func (pieceTracker *PieceTracker) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return pieceTracker, nil
+}
+
+// Merge does nothing, because the forks add the pieces to the same bloom filters.
+func (pieceTracker *PieceTracker) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	return nil
+}
+
 // adds a pieceID to the relevant node's RetainInfo
 func (pieceTracker *PieceTracker) add(nodeID storj.NodeID, pieceID storj.PieceID) {
+	pieceTracker.mu.Lock()
+	defer pieceTracker.mu.Unlock()
+
 	if _, ok := pieceTracker.retainInfos[nodeID]; !ok {
 		// If we know how many pieces a node should be storing, use that number. Otherwise use default.
 		numPieces := pieceTracker.config.InitialPieces
@@ -76,7 +93,7 @@ func (pieceTracker *PieceTracker) add(nodeID storj.NodeID, pieceID storj.PieceID
 			numPieces = pieceTracker.pieceCounts[nodeID]
 		}
 		// limit size of bloom filter to ensure we are under the limit for RPC
-		filter := bloomfilter.NewOptimalMaxSize(numPieces, pieceTracker.config.FalsePositiveRate, 2*memory.MiB)
+		filter := bloomfilter.NewOptimalMaxSize(numPieces, falsePositiveRate(pieceTracker.config, numPieces), 2*memory.MiB)
 		pieceTracker.retainInfos[nodeID] = &RetainInfo{
 			Filter:       filter,
 			CreationDate: pieceTracker.creationDate,
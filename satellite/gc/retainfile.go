@@ -0,0 +1,119 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/common/storj"
+)
+
+// RetainFileExt is the extension of files containing retain infos.
+const RetainFileExt = ".retain"
+
+// retainFile is the stored form of the retain info of a node.
+type retainFile struct {
+	NodeID       storj.NodeID `json:"nodeID"`
+	CreationDate time.Time    `json:"creationDate"`
+	Count        int          `json:"count"`
+	Filter       []byte       `json:"filter"`
+}
+
+// RetainFileName returns the name of the file containing the retain info of the node.
+func RetainFileName(nodeID storj.NodeID, creationDate time.Time) string {
+	return nodeID.String() + "-" + creationDate.UTC().Format("20060102T150405Z") + RetainFileExt
+}
+
+// WriteRetainInfos writes the retain info of every node to a separate file in dir.
+func WriteRetainInfos(dir string, retainInfos map[storj.NodeID]*RetainInfo) (err error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Error.Wrap(err)
+	}
+
+	for nodeID, info := range retainInfos {
+		data, err := json.Marshal(retainFile{
+			NodeID:       nodeID,
+			CreationDate: info.CreationDate,
+			Count:        info.Count,
+			Filter:       info.Filter.Bytes(),
+		})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		if err := writeFile(filepath.Join(dir, RetainFileName(nodeID, info.CreationDate)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces the file at path with data.
+func writeFile(path string, data []byte) (err error) {
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, os.Remove(file.Name()))
+		}
+	}()
+
+	_, err = file.Write(data)
+	err = errs.Combine(err, file.Close())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(file.Name(), path))
+}
+
+// ReadRetainInfos reads the retain infos stored in dir. When there are several files
+// for a node, the one with the latest creation date is used.
+func ReadRetainInfos(dir string) (_ map[storj.NodeID]*RetainInfo, err error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	retainInfos := make(map[storj.NodeID]*RetainInfo)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), RetainFileExt) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		var stored retainFile
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, Error.New("invalid retain file %q: %v", entry.Name(), err)
+		}
+
+		filter, err := bloomfilter.NewFromBytes(stored.Filter)
+		if err != nil {
+			return nil, Error.New("invalid filter in retain file %q: %v", entry.Name(), err)
+		}
+
+		if existing, ok := retainInfos[stored.NodeID]; ok && !existing.CreationDate.Before(stored.CreationDate) {
+			continue
+		}
+		retainInfos[stored.NodeID] = &RetainInfo{
+			Filter:       filter,
+			CreationDate: stored.CreationDate,
+			Count:        stored.Count,
+		}
+	}
+	return retainInfos, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/gc"
+)
+
+func TestRetainInfosRoundTrip(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("filters")
+
+	older := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	nodeA, nodeB := testrand.NodeID(), testrand.NodeID()
+	pieceA, pieceB := testrand.PieceID(), testrand.PieceID()
+
+	newInfo := func(creationDate time.Time, pieces ...storj.PieceID) *gc.RetainInfo {
+		info := &gc.RetainInfo{
+			Filter:       bloomfilter.NewOptimal(10, 0.01),
+			CreationDate: creationDate,
+		}
+		for _, pieceID := range pieces {
+			info.Filter.Add(pieceID)
+			info.Count++
+		}
+		return info
+	}
+
+	require.NoError(t, gc.WriteRetainInfos(dir, map[storj.NodeID]*gc.RetainInfo{
+		nodeA: newInfo(older, pieceA),
+		nodeB: newInfo(older, pieceB),
+	}))
+	require.NoError(t, gc.WriteRetainInfos(dir, map[storj.NodeID]*gc.RetainInfo{
+		nodeA: newInfo(newer, pieceA, pieceB),
+	}))
+
+	// files which aren't retain files are ignored
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("filters"), 0600))
+
+	infos, err := gc.ReadRetainInfos(dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+
+	// the newest filter of a node is used
+	require.True(t, infos[nodeA].CreationDate.Equal(newer))
+	require.Equal(t, 2, infos[nodeA].Count)
+	require.True(t, infos[nodeA].Filter.Contains(pieceA))
+	require.True(t, infos[nodeA].Filter.Contains(pieceB))
+
+	require.True(t, infos[nodeB].CreationDate.Equal(older))
+	require.Equal(t, 1, infos[nodeB].Count)
+	require.True(t, infos[nodeB].Filter.Contains(pieceB))
+}
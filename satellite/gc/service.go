@@ -5,6 +5,7 @@ package gc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -18,6 +19,7 @@ import (
 	"storj.io/common/sync2"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/storage"
 	"storj.io/uplink/private/piecestore"
 )
 
@@ -34,10 +36,12 @@ type Config struct {
 	SkipFirst bool          `help:"if true, skip the first run of GC" releaseDefault:"true" devDefault:"false"`
 	RunInCore bool          `help:"if true, run garbage collection as part of the core" releaseDefault:"false" devDefault:"false"`
 	// value for InitialPieces currently based on average pieces per node
-	InitialPieces     int           `help:"the initial number of pieces expected for a storage node to have, used for creating a filter" releaseDefault:"400000" devDefault:"10"`
-	FalsePositiveRate float64       `help:"the false positive rate used for creating a garbage collection bloom filter" releaseDefault:"0.1" devDefault:"0.1"`
-	ConcurrentSends   int           `help:"the number of nodes to concurrently send garbage collection bloom filters to" releaseDefault:"1" devDefault:"1"`
-	RetainSendTimeout time.Duration `help:"the amount of time to allow a node to handle a retain request" default:"1m"`
+	InitialPieces     int     `help:"the initial number of pieces expected for a storage node to have, used for creating a filter" releaseDefault:"400000" devDefault:"10"`
+	FalsePositiveRate float64 `help:"the false positive rate used for creating a garbage collection bloom filter" releaseDefault:"0.1" devDefault:"0.1"`
+	// value for RetainedGarbageBudget lowers the false positive rate for nodes with more than 100000 pieces
+	RetainedGarbageBudget int           `help:"the number of garbage pieces a node may keep because of false positives, assuming it doesn't have more garbage than pieces, used for lowering the false positive rate of large nodes (0 means no budget)" default:"10000"`
+	ConcurrentSends       int           `help:"the number of nodes to concurrently send garbage collection bloom filters to" releaseDefault:"1" devDefault:"1"`
+	RetainSendTimeout     time.Duration `help:"the amount of time to allow a node to handle a retain request" default:"1m"`
 }
 
 // Service implements the garbage collection service
@@ -51,6 +55,8 @@ type Service struct {
 	dialer       rpc.Dialer
 	overlay      overlay.DB
 	metainfoLoop *metainfo.Loop
+	pointerDB    metainfo.PointerDB
+	references   metainfo.SegmentReferencesDB
 }
 
 // RetainInfo contains info needed for a storage node to retain important data and delete garbage data
@@ -61,7 +67,7 @@ type RetainInfo struct {
 }
 
 // NewService creates a new instance of the gc service
-func NewService(log *zap.Logger, config Config, dialer rpc.Dialer, overlay overlay.DB, loop *metainfo.Loop) *Service {
+func NewService(log *zap.Logger, config Config, dialer rpc.Dialer, overlay overlay.DB, loop *metainfo.Loop, pointerDB metainfo.PointerDB, references metainfo.SegmentReferencesDB) *Service {
 	return &Service{
 		log:          log,
 		config:       config,
@@ -69,6 +75,8 @@ func NewService(log *zap.Logger, config Config, dialer rpc.Dialer, overlay overl
 		dialer:       dialer,
 		overlay:      overlay,
 		metainfoLoop: loop,
+		pointerDB:    pointerDB,
+		references:   references,
 	}
 }
 
@@ -109,6 +117,12 @@ func (service *Service) Run(ctx context.Context) (err error) {
 			return nil
 		}
 
+		err = service.addRelocatedSegments(ctx, pieceTracker)
+		if err != nil {
+			service.log.Error("error adding relocated segments", zap.Error(err))
+			return nil
+		}
+
 		// save piece counts in memory for next iteration
 		for id := range lastPieceCounts {
 			delete(lastPieceCounts, id)
@@ -117,33 +131,127 @@ func (service *Service) Run(ctx context.Context) (err error) {
 			lastPieceCounts[id] = info.Count
 		}
 
-		// save piece counts to db for next satellite restart
-		err = service.overlay.UpdatePieceCounts(ctx, lastPieceCounts)
+		err = service.SendRetainInfos(ctx, pieceTracker.retainInfos)
 		if err != nil {
-			service.log.Error("error updating piece counts", zap.Error(err))
+			service.log.Warn("error sending retain infos", zap.Error(err))
 		}
 
-		// monitor information
-		for _, info := range pieceTracker.retainInfos {
-			mon.IntVal("node_piece_count").Observe(int64(info.Count))
-			mon.IntVal("retain_filter_size_bytes").Observe(info.Filter.Size())
+		return nil
+	})
+}
+
+// addRelocatedSegments adds the pieces of the segments which were copied or
+// moved since the loop started, the loop may have missed them when they were
+// created behind its position.
+func (service *Service) addRelocatedSegments(ctx context.Context, pieceTracker *PieceTracker) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if service.references == nil {
+		return nil
+	}
+
+	paths, err := service.references.ListRelocated(ctx, pieceTracker.creationDate)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	mon.IntVal("relocated_segments").Observe(int64(len(paths)))
+
+	for _, path := range paths {
+		pointerBytes, err := service.pointerDB.Get(ctx, storage.Key(path))
+		if err != nil {
+			if storage.ErrKeyNotFound.Has(err) {
+				// moving the segment again records the new path.
+				continue
+			}
+			return Error.Wrap(err)
 		}
 
-		// send retain requests
-		limiter := sync2.NewLimiter(service.config.ConcurrentSends)
-		for id, info := range pieceTracker.retainInfos {
-			id, info := id, info
-			limiter.Go(ctx, func() {
-				err := service.sendRetainRequest(ctx, id, info)
-				if err != nil {
-					service.log.Warn("error sending retain info to node", zap.Stringer("Node ID", id), zap.Error(err))
-				}
-			})
+		pointer := &pb.Pointer{}
+		if err := pb.Unmarshal(pointerBytes, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+		if pointer.Type != pb.Pointer_REMOTE {
+			continue
 		}
-		limiter.Wait()
 
-		return nil
-	})
+		err = pieceTracker.RemoteSegment(ctx, metainfo.ScopedPath{Raw: path}, pointer)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	// the loop has seen the segments relocated before it started.
+	err = service.references.DeleteRelocatedBefore(ctx, pieceTracker.creationDate)
+	if err != nil {
+		service.log.Warn("error deleting relocated segments", zap.Error(err))
+	}
+	return nil
+}
+
+// SendRetainInfos stores the piece counts of the nodes for sizing the next filters and
+// sends the retain requests to the nodes.
+func (service *Service) SendRetainInfos(ctx context.Context, retainInfos map[storj.NodeID]*RetainInfo) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pieceCounts := make(map[storj.NodeID]int, len(retainInfos))
+	for id, info := range retainInfos {
+		pieceCounts[id] = info.Count
+	}
+
+	// save piece counts to db for next satellite restart
+	err = service.overlay.UpdatePieceCounts(ctx, pieceCounts)
+	if err != nil {
+		service.log.Error("error updating piece counts", zap.Error(err))
+	}
+
+	// monitor information
+	for _, info := range retainInfos {
+		mon.IntVal("node_piece_count").Observe(int64(info.Count))
+		mon.IntVal("retain_filter_size_bytes").Observe(info.Filter.Size())
+	}
+
+	// send retain requests
+	var mu sync.Mutex
+	var failed int
+	limiter := sync2.NewLimiter(service.config.ConcurrentSends)
+	for id, info := range retainInfos {
+		id, info := id, info
+		limiter.Go(ctx, func() {
+			err := service.sendRetainRequest(ctx, id, info)
+			if err != nil {
+				service.log.Warn("error sending retain info to node", zap.Stringer("Node ID", id), zap.Error(err))
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+			mon.FloatVal("retain_filter_false_positive_rate").Observe(EstimateFalsePositiveRate(info.Filter))
+		})
+	}
+	limiter.Wait()
+
+	if failed > 0 {
+		return Error.New("failed to send retain info to %d of %d nodes", failed, len(retainInfos))
+	}
+	return nil
+}
+
+// Generate builds the retain infos of all nodes with a single iteration over db,
+// which can be a snapshot or a backup of the pointer database.
+//
+// Nodes keep pieces created after creationDate regardless of the filter, so it must
+// not be later than the time the snapshot was taken.
+func Generate(ctx context.Context, log *zap.Logger, config Config, db metainfo.PointerDB, rateLimit float64, pieceCounts map[storj.NodeID]int, creationDate time.Time) (_ map[storj.NodeID]*RetainInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pieceTracker := NewPieceTracker(log, config, pieceCounts)
+	pieceTracker.creationDate = creationDate.UTC()
+
+	err = metainfo.IterateDatabase(ctx, rateLimit, db, pieceTracker)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pieceTracker.retainInfos, nil
 }
 
 func (service *Service) sendRetainRequest(ctx context.Context, id storj.NodeID, info *RetainInfo) (err error) {
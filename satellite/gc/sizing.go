@@ -0,0 +1,54 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"math"
+	"math/bits"
+
+	"storj.io/common/bloomfilter"
+)
+
+// falsePositiveRate returns the false positive rate for the filter of a node expected
+// to store numPieces pieces.
+//
+// The garbage a node keeps because of false positives is the false positive rate times
+// the amount of garbage it has. Assuming a node doesn't have more garbage than pieces,
+// the rate is lowered for large nodes so that they keep at most RetainedGarbageBudget
+// garbage pieces, while small nodes use FalsePositiveRate.
+func falsePositiveRate(config Config, numPieces int) float64 {
+	rate := config.FalsePositiveRate
+	if config.RetainedGarbageBudget > 0 && numPieces > 0 {
+		budgetRate := float64(config.RetainedGarbageBudget) / float64(numPieces)
+		if budgetRate < rate {
+			rate = budgetRate
+		}
+	}
+	return rate
+}
+
+// EstimateFalsePositiveRate estimates the false positive rate of a filter from the share
+// of its bits that are set, which also accounts for filters that were capped in size or
+// contain more pieces than expected.
+func EstimateFalsePositiveRate(filter *bloomfilter.Filter) float64 {
+	hashCount, size := filter.Parameters()
+	if size == 0 {
+		return 1
+	}
+
+	set := 0
+	for _, b := range filterTable(filter) {
+		set += bits.OnesCount8(b)
+	}
+
+	return math.Pow(float64(set)/float64(8*size), float64(hashCount))
+}
+
+// filterTable returns the bit table of the filter, which follows the header in the
+// serialized filter.
+func filterTable(filter *bloomfilter.Filter) []byte {
+	_, size := filter.Parameters()
+	data := filter.Bytes()
+	return data[len(data)-size:]
+}
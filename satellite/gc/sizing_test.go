@@ -0,0 +1,71 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/common/memory"
+	"storj.io/common/testrand"
+)
+
+func TestFalsePositiveRate(t *testing.T) {
+	config := Config{FalsePositiveRate: 0.1, RetainedGarbageBudget: 10000}
+
+	// small nodes use the configured rate
+	require.Equal(t, 0.1, falsePositiveRate(config, 1000))
+	require.Equal(t, 0.1, falsePositiveRate(config, 100000))
+	// large nodes get a rate within the budget
+	require.Equal(t, 0.01, falsePositiveRate(config, 1000000))
+
+	config.RetainedGarbageBudget = 0
+	require.Equal(t, 0.1, falsePositiveRate(config, 1000000))
+}
+
+func TestEstimateFalsePositiveRate(t *testing.T) {
+	const pieces = 10000
+
+	filter := bloomfilter.NewOptimal(pieces, 0.1)
+	require.Equal(t, float64(0), EstimateFalsePositiveRate(filter))
+
+	for i := 0; i < pieces; i++ {
+		filter.Add(testrand.PieceID())
+	}
+	require.InDelta(t, 0.1, EstimateFalsePositiveRate(filter), 0.03)
+
+	// a filter capped in size has a higher rate than requested
+	capped := bloomfilter.NewOptimalMaxSize(pieces, 0.1, 2*memory.KiB)
+	for i := 0; i < pieces; i++ {
+		capped.Add(testrand.PieceID())
+	}
+	require.Greater(t, EstimateFalsePositiveRate(capped), 0.2)
+}
+
+func TestFilterTable(t *testing.T) {
+	filter := bloomfilter.NewOptimal(1000, 0.1)
+	hashCount, size := filter.Parameters()
+	require.Len(t, filterTable(filter), size)
+
+	// an empty filter has no bits set, so the header isn't part of the table.
+	for _, b := range filterTable(filter) {
+		require.Zero(t, b)
+	}
+
+	// adding a piece sets at most hash count bits of the table.
+	filter.Add(testrand.PieceID())
+	set := 0
+	for _, b := range filterTable(filter) {
+		set += bits.OnesCount8(b)
+	}
+	require.True(t, set >= 1 && set <= hashCount)
+
+	// the table is what a node reads back from the serialized filter.
+	parsed, err := bloomfilter.NewFromBytes(filter.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, filterTable(filter), filterTable(parsed))
+}
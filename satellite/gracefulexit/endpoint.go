@@ -366,18 +366,29 @@ func (endpoint *Endpoint) processIncomplete(ctx context.Context, stream pb.DRPCS
 		return Error.Wrap(err)
 	}
 
-	// populate excluded node IDs
+	// populate excluded node IDs, the pieces of the other nodes are kept.
 	remote := pointer.GetRemote()
 	pieces := remote.RemotePieces
 	excludedIDs := make([]storj.NodeID, len(pieces))
+	var retainedIDs []storj.NodeID
 	for i, piece := range pieces {
 		excludedIDs[i] = piece.NodeId
+		if piece.NodeId != nodeID {
+			retainedIDs = append(retainedIDs, piece.NodeId)
+		}
+	}
+
+	placement, err := endpoint.metainfo.SegmentPlacement(ctx, string(incomplete.Path))
+	if err != nil {
+		return Error.Wrap(err)
 	}
 
 	// get replacement node
 	request := &overlay.FindStorageNodesRequest{
 		RequestedCount: 1,
 		ExcludedIDs:    excludedIDs,
+		Placement:      placement,
+		RetainedIDs:    retainedIDs,
 	}
 
 	newNodes, err := endpoint.overlay.FindStorageNodesForGracefulExit(ctx, *request)
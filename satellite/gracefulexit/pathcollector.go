@@ -16,7 +16,7 @@ import (
 	"storj.io/uplink/private/eestream"
 )
 
-var _ metainfo.Observer = (*PathCollector)(nil)
+var _ metainfo.PartialObserver = (*PathCollector)(nil)
 
 // PathCollector uses the metainfo loop to add paths to node reservoirs
 //
@@ -108,6 +108,39 @@ func (collector *PathCollector) InlineSegment(ctx context.Context, path metainfo
 	return nil
 }
 
+// Fork returns a path collector for a range of the metainfo loop.
+func (collector *PathCollector) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	collector.nodeIDMutex.Lock()
+	defer collector.nodeIDMutex.Unlock()
+
+	nodeIDs := make(storj.NodeIDList, 0, len(collector.nodeIDStorage))
+	for nodeID := range collector.nodeIDStorage {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return NewPathCollector(collector.db, nodeIDs, collector.log, collector.batchSize), nil
+}
+
+// Merge persists the remaining items of a forked path collector and adds up
+// the bytes to transfer.
+func (collector *PathCollector) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*PathCollector)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+
+	if err := fork.Flush(ctx); err != nil {
+		return err
+	}
+
+	collector.nodeIDMutex.Lock()
+	defer collector.nodeIDMutex.Unlock()
+
+	for nodeID, bytes := range fork.nodeIDStorage {
+		collector.nodeIDStorage[nodeID] += bytes
+	}
+	return nil
+}
+
 func (collector *PathCollector) flush(ctx context.Context, limit int) (err error) {
 	if len(collector.buffer) >= limit {
 		err = collector.db.Enqueue(ctx, collector.buffer)
@@ -0,0 +1,103 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/uplink/private/testuplink"
+)
+
+// TestBucketLifecycle does the following:
+// * Upload objects to a bucket with an expiration rule and to a bucket without rules
+// * Leave an upload without its last segment in a bucket with an abort rule
+// * Run the chore as if two days have passed
+// * Verify that only the expired objects and the incomplete upload were deleted
+func TestBucketLifecycle(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+		projectID := upl.Projects[0].ID
+
+		chore := satellite.Core.BucketLifecycle.Chore
+		chore.Loop.Pause()
+
+		uploadCtx := testuplink.WithMaxSegmentSize(ctx, 5*memory.KiB)
+
+		// incomplete upload, the last segment is removed to leave only the first segment
+		require.NoError(t, upl.Upload(uploadCtx, satellite, "incomplete", "partial", testrand.Bytes(8*memory.KiB)))
+		segments := listSegments(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, segments["incomplete"], 2)
+		for _, path := range segments["incomplete"] {
+			if storj.SplitPath(path)[1] == "l" {
+				require.NoError(t, satellite.Metainfo.Service.UnsynchronizedDelete(ctx, path))
+			}
+		}
+
+		require.NoError(t, upl.Upload(uploadCtx, satellite, "incomplete", "complete", testrand.Bytes(8*memory.KiB)))
+		require.NoError(t, upl.Upload(ctx, satellite, "expiring", "remote", testrand.Bytes(8*memory.KiB)))
+		require.NoError(t, upl.Upload(ctx, satellite, "expiring", "inline", testrand.Bytes(1*memory.KiB)))
+		require.NoError(t, upl.Upload(ctx, satellite, "kept", "remote", testrand.Bytes(8*memory.KiB)))
+
+		buckets := satellite.DB.Buckets()
+		require.NoError(t, buckets.UpdateBucketLifecycle(ctx, []byte("expiring"), projectID, metainfo.LifecycleRules{
+			{ExpirationDays: 1},
+		}))
+		require.NoError(t, buckets.UpdateBucketLifecycle(ctx, []byte("incomplete"), projectID, metainfo.LifecycleRules{
+			{AbortIncompleteUploadHours: 1},
+		}))
+
+		// nothing is old enough yet
+		require.NoError(t, chore.RunOnce(ctx))
+
+		segments = listSegments(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, segments["incomplete"], 3)
+		require.Len(t, segments["expiring"], 2)
+		require.Len(t, segments["kept"], 1)
+
+		chore.SetNow(func() time.Time {
+			return time.Now().Add(48 * time.Hour)
+		})
+		require.NoError(t, chore.RunOnce(ctx))
+
+		segments = listSegments(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, segments["incomplete"], 2)
+		require.Len(t, segments["expiring"], 0)
+		require.Len(t, segments["kept"], 1)
+
+		rules, err := buckets.GetBucketLifecycle(ctx, []byte("kept"), projectID)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
+
+// listSegments returns the segment paths in metainfo grouped by bucket name.
+func listSegments(ctx context.Context, t *testing.T, db metainfo.PointerDB) map[string][]string {
+	segments := make(map[string][]string)
+	err := db.Iterate(ctx, storage.IterateOptions{Recurse: true},
+		func(ctx context.Context, it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(ctx, &item) {
+				parts := storj.SplitPath(item.Key.String())
+				require.True(t, len(parts) >= 4)
+				segments[parts[2]] = append(segments[parts[2]], item.Key.String())
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	return segments
+}
@@ -0,0 +1,249 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/piecedeletion"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/storage"
+)
+
+var (
+	// Error defines the bucketlifecycle chore errors class
+	Error = errs.Class("bucketlifecycle chore error")
+	mon   = monkit.Package()
+)
+
+const (
+	// maxPiecesPerBatch is the number of pieces collected before they are sent to the piece deletion service.
+	maxPiecesPerBatch = 10000
+	// deletePiecesSuccessThreshold is the fraction of piece deletion requests waited for.
+	deletePiecesSuccessThreshold = 0.75
+)
+
+// Config contains configurable values for executing bucket lifecycle rules
+type Config struct {
+	Interval   time.Duration `help:"the time between each evaluation of the bucket lifecycle rules" releaseDefault:"24h" devDefault:"10m"`
+	Enabled    bool          `help:"set if bucket lifecycle rules are executed" releaseDefault:"true" devDefault:"true"`
+	MaxObjects int           `help:"maximum number of objects and incomplete uploads deleted in a single run" default:"100000"`
+	DeleteRate float64       `help:"maximum number of segments deleted per second" default:"100"`
+}
+
+// Chore executes the bucket lifecycle rules
+//
+// architecture: Chore
+type Chore struct {
+	log    *zap.Logger
+	audit  *zap.Logger
+	config Config
+	Loop   *sync2.Cycle
+
+	buckets      metainfo.BucketsDB
+	metainfo     *metainfo.Service
+	metainfoLoop *metainfo.Loop
+	overlay      *overlay.Service
+	deletePieces *piecedeletion.Service
+
+	nowFn func() time.Time
+}
+
+// NewChore creates a new instance of the bucketlifecycle chore
+func NewChore(log *zap.Logger, config Config, buckets metainfo.BucketsDB, meta *metainfo.Service, loop *metainfo.Loop, overlay *overlay.Service, deletePieces *piecedeletion.Service) *Chore {
+	return &Chore{
+		log:          log,
+		audit:        log.Named("audit"),
+		config:       config,
+		Loop:         sync2.NewCycle(config.Interval),
+		buckets:      buckets,
+		metainfo:     meta,
+		metainfoLoop: loop,
+		overlay:      overlay,
+		deletePieces: deletePieces,
+		nowFn:        time.Now,
+	}
+}
+
+// Run starts the bucketlifecycle loop service
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !chore.config.Enabled {
+		return nil
+	}
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		err := chore.RunOnce(ctx)
+		if err != nil {
+			chore.log.Error("error executing bucket lifecycle rules", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the bucketlifecycle loop service
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
+
+// SetNow allows tests to have the Chore act as if the current time is whatever
+// they want. This avoids races and sleeping, making tests more reliable and efficient.
+func (chore *Chore) SetNow(now func() time.Time) {
+	chore.nowFn = now
+}
+
+// RunOnce evaluates the lifecycle rules of all buckets and deletes the selected segments.
+func (chore *Chore) RunOnce(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lifecycles, err := chore.buckets.ListBucketLifecycles(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if len(lifecycles) == 0 {
+		return nil
+	}
+
+	obs := newObserver(lifecycles, chore.nowFn(), chore.config.MaxObjects)
+	err = chore.metainfoLoop.Join(ctx, obs)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return chore.deleteSegments(ctx, obs.segments)
+}
+
+// deleteSegments deletes the segments from metainfo and sends their pieces
+// to the piece deletion service.
+func (chore *Chore) deleteSegments(ctx context.Context, segments []segment) (err error) {
+	defer mon.Task()(&ctx, len(segments))(&err)
+
+	limiter := rate.NewLimiter(rate.Limit(chore.config.DeleteRate), 1)
+
+	nodesPieces := make(map[storj.NodeID][]storj.PieceID)
+	pieceCount := 0
+
+	for _, segment := range segments {
+		if err := limiter.Wait(ctx); err != nil {
+			return Error.Wrap(err)
+		}
+
+		deleted, piecesShared, err := chore.deleteSegment(ctx, segment)
+		if err != nil {
+			chore.log.Warn("unable to delete segment",
+				zap.String("Path", segment.path.Raw),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !deleted {
+			continue
+		}
+
+		chore.audit.Info("deleted segment",
+			zap.Stringer("Project ID", segment.path.ProjectID),
+			zap.String("Bucket", segment.path.BucketName),
+			zap.String("Encrypted Path", segment.path.EncryptedObjectPath),
+			zap.String("Segment", segment.path.Segment),
+			zap.Time("Created", segment.pointer.CreationDate),
+			zap.String("Reason", segment.reason),
+		)
+		mon.Meter("bucket_lifecycle_deleted_segments").Mark(1)
+		mon.Meter("bucket_lifecycle_deleted_bytes").Mark64(segment.pointer.SegmentSize)
+
+		remote := segment.pointer.GetRemote()
+		if segment.pointer.Type != pb.Pointer_REMOTE || remote == nil || piecesShared {
+			continue
+		}
+		for _, piece := range remote.GetRemotePieces() {
+			nodesPieces[piece.NodeId] = append(nodesPieces[piece.NodeId], remote.RootPieceId.Derive(piece.NodeId, piece.PieceNum))
+			pieceCount++
+		}
+
+		if pieceCount >= maxPiecesPerBatch {
+			chore.deletePiecesFromNodes(ctx, nodesPieces)
+			nodesPieces = make(map[storj.NodeID][]storj.PieceID)
+			pieceCount = 0
+		}
+	}
+
+	chore.deletePiecesFromNodes(ctx, nodesPieces)
+	return nil
+}
+
+// deleteSegment deletes the segment from metainfo, unless it was changed
+// since it was selected. piecesShared is set when the pieces of the segment
+// are still used by its copies.
+func (chore *Chore) deleteSegment(ctx context.Context, segment segment) (deleted, piecesShared bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointerBytes, err := pb.Marshal(segment.pointer)
+	if err != nil {
+		return false, false, Error.Wrap(err)
+	}
+
+	piecesShared, err = chore.metainfo.DeleteSegment(ctx, segment.path.Raw, pointerBytes)
+	if storj.ErrObjectNotFound.Has(err) || storage.ErrValueChanged.Has(err) {
+		// segment was already deleted or replaced
+		return false, false, nil
+	}
+	if metainfo.ErrObjectLocked.Has(err) {
+		// segment is kept until its lock ends
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, Error.Wrap(err)
+	}
+	return true, piecesShared, nil
+}
+
+// deletePiecesFromNodes sends delete requests for the pieces, pieces which
+// couldn't be deleted are collected by the garbage collection.
+func (chore *Chore) deletePiecesFromNodes(ctx context.Context, nodesPieces map[storj.NodeID][]storj.PieceID) {
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	if len(nodesPieces) == 0 {
+		return
+	}
+
+	nodeIDs := make(storj.NodeIDList, 0, len(nodesPieces))
+	for nodeID := range nodesPieces {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	nodes, err := chore.overlay.KnownReliable(ctx, nodeIDs)
+	if err != nil {
+		chore.log.Warn("unable to look up nodes from overlay", zap.Error(err))
+		return
+	}
+
+	requests := make([]piecedeletion.Request, 0, len(nodes))
+	for _, node := range nodes {
+		requests = append(requests, piecedeletion.Request{
+			Node: storj.NodeURL{
+				ID:      node.Id,
+				Address: node.Address.Address,
+			},
+			Pieces: nodesPieces[node.Id],
+		})
+	}
+
+	err = chore.deletePieces.Delete(ctx, requests, deletePiecesSuccessThreshold)
+	if err != nil {
+		chore.log.Warn("unable to delete pieces", zap.Error(err))
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package bucketlifecycle contains the functions needed to execute bucket lifecycle rules.
+
+The bucketlifecycle.observer implements the metainfo loop Observer interface
+and selects the segments of objects which are older than the expiration of a
+matching rule, as well as the segments of uploads which were not committed
+within the abort age of a matching rule.
+
+The bucketlifecycle chore loads the rules of all buckets, subscribes the
+observer to the metainfo loop and deletes the selected segments from metainfo
+and their pieces through the piece deletion service. Every deleted segment is
+written to the audit log.
+*/
+package bucketlifecycle
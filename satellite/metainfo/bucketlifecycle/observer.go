@@ -0,0 +1,186 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"storj.io/common/pb"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+)
+
+const (
+	reasonExpired          = "expired"
+	reasonIncompleteUpload = "incomplete upload"
+)
+
+var _ metainfo.PartialObserver = (*observer)(nil)
+var _ metainfo.DistributedObserver = (*observer)(nil)
+
+// segment is a segment selected for deletion by a lifecycle rule.
+type segment struct {
+	path    metainfo.ScopedPath
+	pointer *pb.Pointer
+	reason  string
+}
+
+// observer implements the metainfo loop observer interface for selecting
+// the segments affected by bucket lifecycle rules.
+//
+// architecture: Observer
+type observer struct {
+	rules      map[string]metainfo.LifecycleRules
+	now        time.Time
+	maxObjects int
+
+	// the loop visits the last segments of a project before its other segments,
+	// so these only need to hold the objects of the current project.
+	project   uuid.UUID
+	expired   map[string]struct{}
+	committed map[string]struct{}
+
+	// objects counts the selected objects, it's shared with the forks.
+	objects  *int64
+	segments []segment
+}
+
+// newObserver returns an observer for the lifecycle rules.
+func newObserver(lifecycles []metainfo.BucketLifecycle, now time.Time, maxObjects int) *observer {
+	rules := make(map[string]metainfo.LifecycleRules, len(lifecycles))
+	for _, lifecycle := range lifecycles {
+		rules[bucketKey(lifecycle.ProjectID, string(lifecycle.BucketName))] = lifecycle.Rules
+	}
+	return &observer{
+		rules:      rules,
+		now:        now,
+		maxObjects: maxObjects,
+		expired:    make(map[string]struct{}),
+		committed:  make(map[string]struct{}),
+		objects:    new(int64),
+	}
+}
+
+// RemoteSegment selects the segment if a lifecycle rule applies to it.
+func (obs *observer) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return obs.processSegment(path, pointer)
+}
+
+// InlineSegment selects the segment if a lifecycle rule applies to it.
+func (obs *observer) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return obs.processSegment(path, pointer)
+}
+
+// Object returns nil because the last segment is handled with the other segments.
+func (obs *observer) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+// Fork returns an observer for a range of the metainfo loop, which shares
+// the limit of selected objects with the observer.
+func (obs *observer) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return &observer{
+		rules:      obs.rules,
+		now:        obs.now,
+		maxObjects: obs.maxObjects,
+		expired:    make(map[string]struct{}),
+		committed:  make(map[string]struct{}),
+		objects:    obs.objects,
+	}, nil
+}
+
+// Merge adds the segments selected by a forked observer.
+func (obs *observer) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*observer)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+	obs.segments = append(obs.segments, fork.segments...)
+	return nil
+}
+
+// Distributed returns true, because the selected segments are deleted by the
+// process which selected them.
+func (obs *observer) Distributed() bool {
+	return true
+}
+
+// DistributedName returns the name of the observer for claiming the ranges.
+func (obs *observer) DistributedName() string {
+	return "bucket-lifecycle"
+}
+
+func (obs *observer) processSegment(path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	rules, ok := obs.rules[bucketKey(path.ProjectID, path.BucketName)]
+	if !ok {
+		return nil
+	}
+
+	if path.ProjectID != obs.project {
+		obs.project = path.ProjectID
+		obs.expired = make(map[string]struct{})
+		obs.committed = make(map[string]struct{})
+	}
+
+	key := path.BucketName + "/" + path.EncryptedObjectPath
+	age := obs.now.Sub(pointer.CreationDate)
+	locked := metainfo.IsLocked(pointer, obs.now)
+
+	if path.Segment == "l" {
+		if rules.HasAbortIncompleteUpload() {
+			obs.committed[key] = struct{}{}
+		}
+		if locked {
+			// locked objects are kept until the lock ends.
+			return nil
+		}
+
+		expiration := rules.Expiration(path.EncryptedObjectPath)
+		if expiration > 0 && age > expiration && obs.selectObject() {
+			obs.expired[key] = struct{}{}
+			obs.add(path, pointer, reasonExpired)
+		}
+		return nil
+	}
+
+	// the remaining segments of selected objects are always deleted, otherwise
+	// they would be left behind without a last segment.
+	if _, ok := obs.expired[key]; ok {
+		obs.add(path, pointer, reasonExpired)
+		return nil
+	}
+
+	if _, ok := obs.committed[key]; ok || locked {
+		return nil
+	}
+
+	abort := rules.AbortIncompleteUpload(path.EncryptedObjectPath)
+	if abort > 0 && age > abort && obs.selectObject() {
+		obs.add(path, pointer, reasonIncompleteUpload)
+	}
+	return nil
+}
+
+// selectObject returns whether another object can be selected within the limit.
+func (obs *observer) selectObject() bool {
+	if atomic.AddInt64(obs.objects, 1) > int64(obs.maxObjects) {
+		atomic.AddInt64(obs.objects, -1)
+		return false
+	}
+	return true
+}
+
+func (obs *observer) add(path metainfo.ScopedPath, pointer *pb.Pointer, reason string) {
+	obs.segments = append(obs.segments, segment{
+		path:    path,
+		pointer: pointer,
+		reason:  reason,
+	})
+}
+
+func bucketKey(projectID uuid.UUID, bucketName string) string {
+	return projectID.String() + "/" + bucketName
+}
@@ -32,6 +32,8 @@ type RSConfig struct {
 	SuccessThreshold int         `help:"the desired total pieces for a segment. o." releaseDefault:"80" devDefault:"8"`
 	TotalThreshold   int         `help:"the largest amount of pieces to encode to. n." releaseDefault:"110" devDefault:"10"`
 
+	Profiles RSProfiles `help:"comma separated list of named redundancy scheme profiles which buckets can use instead of the default, as name:k/m/o/n" default:""`
+
 	// TODO left for validation until we will remove CreateSegmentOld
 	MinTotalThreshold int  `help:"the largest amount of pieces to encode to. n (lower bound for validation)." releaseDefault:"95" devDefault:"10"`
 	MaxTotalThreshold int  `help:"the largest amount of pieces to encode to. n (upper bound for validation)." releaseDefault:"130" devDefault:"10"`
@@ -42,8 +44,18 @@ type RSConfig struct {
 type RateLimiterConfig struct {
 	Enabled         bool          `help:"whether rate limiting is enabled." releaseDefault:"true" devDefault:"true"`
 	Rate            float64       `help:"request rate per project per second." releaseDefault:"1000" devDefault:"100"`
+	KeyRate         float64       `help:"request rate per api key per second, 0 means keys are limited only by the project rate." default:"0"`
 	CacheCapacity   int           `help:"number of projects to cache." releaseDefault:"10000" devDefault:"10"`
 	CacheExpiration time.Duration `help:"how long to cache the projects limiter." releaseDefault:"10m" devDefault:"10s"`
+
+	KeyCacheCapacity   int           `help:"number of api keys to cache." releaseDefault:"10000" devDefault:"10"`
+	KeyCacheExpiration time.Duration `help:"how long to cache the api keys limiter." releaseDefault:"10m" devDefault:"10s"`
+}
+
+// BucketCacheConfig is a configuration struct for caching the settings of buckets
+type BucketCacheConfig struct {
+	Capacity   int           `help:"number of buckets to cache, 0 disables the cache." releaseDefault:"10000" devDefault:"10"`
+	Expiration time.Duration `help:"how long to cache the settings of a bucket, changes to the settings may take as long to apply." releaseDefault:"1m" devDefault:"10s"`
 }
 
 // Config is a configuration struct that is everything you need to start a metainfo
@@ -58,6 +70,8 @@ type Config struct {
 	RS                   RSConfig             `help:"redundancy scheme configuration"`
 	Loop                 LoopConfig           `help:"loop configuration"`
 	RateLimiter          RateLimiterConfig    `help:"rate limiter configuration"`
+	BucketCache          BucketCacheConfig    `help:"bucket settings cache configuration"`
+	RequestCounter       RequestCounterConfig `help:"api key request counter configuration"`
 	PieceDeletion        piecedeletion.Config `help:"piece deletion configuration"`
 }
 
@@ -0,0 +1,484 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/storage"
+)
+
+var (
+	// ErrObjectExists is returned when the destination of a copy or move already exists.
+	ErrObjectExists = errs.Class("object already exists")
+	// ErrObjectChanged is returned when the object was modified during a copy or move.
+	ErrObjectChanged = errs.Class("object changed")
+)
+
+// objectSegment is a segment of an object which is copied or moved.
+type objectSegment struct {
+	index   int64
+	path    storj.Path
+	newPath storj.Path
+
+	pointerBytes    []byte
+	pointer         *pb.Pointer
+	newPointerBytes []byte
+}
+
+// CopyObject copies the segments of an object to a new path, the copies share
+// the remote pieces of the original segments, so no data is transferred. The
+// segments are tracked in the segment references, so the pieces are deleted
+// only together with the last segment using them.
+//
+// newMetadata replaces the metadata of the copied segments by segment index,
+// where the last segment has index -1, since clients have to encrypt the keys
+// stored in the metadata for the new path.
+func (s *Service) CopyObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath, newBucket, newEncryptedPath []byte, newMetadata map[int64][]byte) (size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := s.objectSegments(ctx, projectID, bucket, encryptedPath, newBucket, newEncryptedPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkNotExists(ctx, segments); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i := range segments {
+		segment := &segments[i]
+
+		pointer := &pb.Pointer{}
+		if err := pb.Unmarshal(segment.pointerBytes, pointer); err != nil {
+			return 0, Error.Wrap(err)
+		}
+		pointer.CreationDate = now
+		if metadata, ok := newMetadata[segment.index]; ok {
+			pointer.Metadata = metadata
+		}
+
+		segment.newPointerBytes, err = pb.Marshal(pointer)
+		if err != nil {
+			return 0, Error.Wrap(err)
+		}
+		size += pointer.SegmentSize
+	}
+
+	// the last segment is copied last, so the copy is listed only when it's complete.
+	for i, segment := range segments {
+		// the references are added before the copy exists, so deleting the copy
+		// right away can't delete the pieces of the original.
+		if segment.pointer.Type == pb.Pointer_REMOTE && segment.pointer.Remote != nil {
+			err = s.references.Add(ctx, segment.pointer.Remote.RootPieceId, segment.path, segment.newPath)
+			if err != nil {
+				s.rollbackCopy(ctx, segments[:i])
+				return 0, Error.Wrap(err)
+			}
+		}
+
+		err = s.db.CompareAndSwap(ctx, []byte(segment.newPath), nil, segment.newPointerBytes)
+		if err != nil {
+			s.rollbackCopy(ctx, segments[:i])
+			if storage.ErrValueChanged.Has(err) {
+				return 0, ErrObjectExists.New("%s", newEncryptedPath)
+			}
+			return 0, Error.Wrap(err)
+		}
+	}
+
+	// the original may have been deleted before its references were added, in
+	// which case its pieces may be deleted already.
+	for _, segment := range segments {
+		pointerBytes, err := s.db.Get(ctx, []byte(segment.path))
+		if err != nil || !bytes.Equal(pointerBytes, segment.pointerBytes) {
+			s.rollbackCopy(ctx, segments)
+			if err != nil && !storage.ErrKeyNotFound.Has(err) {
+				return 0, Error.Wrap(err)
+			}
+			return 0, ErrObjectChanged.New("%s", encryptedPath)
+		}
+	}
+
+	return size, s.addRelocated(ctx, segments)
+}
+
+// MoveObject atomically moves the segments of an object to a new path.
+//
+// newMetadata replaces the metadata of the moved segments by segment index,
+// where the last segment has index -1, since clients have to encrypt the keys
+// stored in the metadata for the new path. Locked objects aren't moved and
+// ErrObjectLocked is returned.
+func (s *Service) MoveObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath, newBucket, newEncryptedPath []byte, newMetadata map[int64][]byte) (size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	mover, ok := s.db.(storage.KeyMover)
+	if !ok {
+		return 0, Error.New("pointer database doesn't support moving objects")
+	}
+
+	segments, err := s.objectSegments(ctx, projectID, bucket, encryptedPath, newBucket, newEncryptedPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkNotExists(ctx, segments); err != nil {
+		return 0, err
+	}
+	// moving deletes the object at its path, which locked objects don't allow.
+	// the moves only succeed when the checked segments are unchanged.
+	now := time.Now()
+	for _, segment := range segments {
+		if IsLocked(segment.pointer, now) {
+			return 0, ErrObjectLocked.New("%s", segment.path)
+		}
+	}
+
+	moves := make([]storage.Move, 0, len(segments))
+	for _, segment := range segments {
+		newPointerBytes := segment.pointerBytes
+		if metadata, ok := newMetadata[segment.index]; ok {
+			pointer := &pb.Pointer{}
+			if err := pb.Unmarshal(segment.pointerBytes, pointer); err != nil {
+				return 0, Error.Wrap(err)
+			}
+			pointer.Metadata = metadata
+
+			newPointerBytes, err = pb.Marshal(pointer)
+			if err != nil {
+				return 0, Error.Wrap(err)
+			}
+		}
+		size += segment.pointer.SegmentSize
+
+		moves = append(moves, storage.Move{
+			From:     storage.Key(segment.path),
+			To:       storage.Key(segment.newPath),
+			OldValue: segment.pointerBytes,
+			NewValue: newPointerBytes,
+		})
+	}
+
+	err = mover.MoveKeys(ctx, moves)
+	if err != nil {
+		if storage.ErrValueChanged.Has(err) {
+			// the destination was checked before, most likely the object was modified.
+			return 0, ErrObjectChanged.New("%s", encryptedPath)
+		}
+		return 0, Error.Wrap(err)
+	}
+
+	for _, segment := range segments {
+		if segment.pointer.Type != pb.Pointer_REMOTE || segment.pointer.Remote == nil {
+			continue
+		}
+		err = s.references.Rename(ctx, segment.pointer.Remote.RootPieceId, segment.path, segment.newPath)
+		if err != nil {
+			// the stale reference keeps the pieces when the last copy is deleted,
+			// which is safe since garbage collection deletes them afterwards.
+			s.logger.Warn("unable to rename segment reference", zap.String("path", segment.newPath), zap.Error(err))
+		}
+	}
+
+	return size, s.addRelocated(ctx, segments)
+}
+
+// objectSegments returns the segments of an object, the last segment is
+// returned last.
+func (s *Service) objectSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath, newBucket, newEncryptedPath []byte) (segments []objectSegment, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	getSegment := func(index int64) (segment objectSegment, err error) {
+		segment.index = index
+		segment.path, err = CreatePath(ctx, projectID, index, bucket, encryptedPath)
+		if err != nil {
+			return segment, Error.Wrap(err)
+		}
+		segment.newPath, err = CreatePath(ctx, projectID, index, newBucket, newEncryptedPath)
+		if err != nil {
+			return segment, Error.Wrap(err)
+		}
+		segment.pointerBytes, segment.pointer, err = s.GetWithBytes(ctx, segment.path)
+		return segment, err
+	}
+
+	last, err := getSegment(lastSegment)
+	if err != nil {
+		return nil, err
+	}
+
+	streamMeta := &pb.StreamMeta{}
+	if err := pb.Unmarshal(last.pointer.Metadata, streamMeta); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for index := int64(0); streamMeta.NumberOfSegments == 0 || index < streamMeta.NumberOfSegments-1; index++ {
+		segment, err := getSegment(index)
+		if err != nil {
+			if streamMeta.NumberOfSegments == 0 && storj.ErrObjectNotFound.Has(err) {
+				// old objects don't store the number of segments.
+				break
+			}
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+
+	return append(segments, last), nil
+}
+
+// checkNotExists returns an error when any segment exists at the new path.
+func (s *Service) checkNotExists(ctx context.Context, segments []objectSegment) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, segment := range segments {
+		_, err := s.db.Get(ctx, []byte(segment.newPath))
+		if err == nil {
+			return ErrObjectExists.New("%s", segment.newPath)
+		}
+		if !storage.ErrKeyNotFound.Has(err) {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// rollbackCopy deletes the copied segments and their references.
+func (s *Service) rollbackCopy(ctx context.Context, segments []objectSegment) {
+	defer mon.Task()(&ctx)(nil)
+
+	for _, segment := range segments {
+		err := s.db.CompareAndSwap(ctx, []byte(segment.newPath), segment.newPointerBytes, nil)
+		if err != nil && !storage.ErrKeyNotFound.Has(err) && !storage.ErrValueChanged.Has(err) {
+			s.logger.Warn("unable to delete copied segment", zap.String("path", segment.newPath), zap.Error(err))
+			continue
+		}
+		s.ReleasePieces(ctx, segment.newPath, segment.pointer)
+	}
+}
+
+// addRelocated records the new paths of the segments for garbage collection.
+func (s *Service) addRelocated(ctx context.Context, segments []objectSegment) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	paths := make([]storj.Path, 0, len(segments))
+	for _, segment := range segments {
+		if segment.pointer.Type == pb.Pointer_REMOTE {
+			paths = append(paths, segment.newPath)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return Error.Wrap(s.references.AddRelocated(ctx, time.Now(), paths...))
+}
+
+// CopyObjectRequest is the request for copying an object.
+type CopyObjectRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3"`
+
+	Bucket        []byte `protobuf:"bytes,1,opt,name=bucket,proto3"`
+	EncryptedPath []byte `protobuf:"bytes,2,opt,name=encrypted_path,proto3"`
+
+	NewBucket        []byte `protobuf:"bytes,3,opt,name=new_bucket,proto3"`
+	NewEncryptedPath []byte `protobuf:"bytes,4,opt,name=new_encrypted_path,proto3"`
+	// NewSegmentMetadata replaces the metadata of the segments by segment index,
+	// where the last segment has index -1.
+	NewSegmentMetadata map[int64][]byte `protobuf:"bytes,5,rep,name=new_segment_metadata,proto3" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+// CopyObjectResponse is the response for copying an object.
+type CopyObjectResponse struct{}
+
+// MoveObjectRequest is the request for moving an object.
+type MoveObjectRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3"`
+
+	Bucket        []byte `protobuf:"bytes,1,opt,name=bucket,proto3"`
+	EncryptedPath []byte `protobuf:"bytes,2,opt,name=encrypted_path,proto3"`
+
+	NewBucket        []byte `protobuf:"bytes,3,opt,name=new_bucket,proto3"`
+	NewEncryptedPath []byte `protobuf:"bytes,4,opt,name=new_encrypted_path,proto3"`
+	// NewSegmentMetadata replaces the metadata of the segments by segment index,
+	// where the last segment has index -1.
+	NewSegmentMetadata map[int64][]byte `protobuf:"bytes,5,rep,name=new_segment_metadata,proto3" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+// MoveObjectResponse is the response for moving an object.
+type MoveObjectResponse struct{}
+
+// CopyObject copies an object to a new path on the satellite, without
+// transferring its data.
+func (endpoint *Endpoint) CopyObject(ctx context.Context, req *CopyObjectRequest) (resp *CopyObjectResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionWrite,
+		Bucket:        req.NewBucket,
+		EncryptedPath: req.NewEncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := endpoint.validateRelocation(ctx, keyInfo.ProjectID, req.Bucket, req.NewBucket, req.NewEncryptedPath); err != nil {
+		return nil, err
+	}
+
+	exceeded, limit, err := endpoint.projectUsage.ExceedsStorageUsage(ctx, keyInfo.ProjectID)
+	if err != nil {
+		endpoint.log.Error("Retrieving project storage totals failed.", zap.Error(err))
+	}
+	if exceeded {
+		endpoint.log.Error("Monthly storage limit exceeded.",
+			zap.Stringer("Limit", limit),
+			zap.Stringer("Project ID", keyInfo.ProjectID),
+		)
+		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
+	}
+	if err := endpoint.checkBucketStorageLimits(ctx, keyInfo.ProjectID, req.NewBucket, true); err != nil {
+		return nil, err
+	}
+
+	size, err := endpoint.metainfo.CopyObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.NewBucket, req.NewEncryptedPath, req.NewSegmentMetadata)
+	if err != nil {
+		return nil, endpoint.convertRelocationError(err)
+	}
+
+	if err := endpoint.projectUsage.AddProjectStorageUsage(ctx, keyInfo.ProjectID, size); err != nil {
+		endpoint.log.Error("Could not track new storage usage by project",
+			zap.Stringer("Project ID", keyInfo.ProjectID),
+			zap.Error(err),
+		)
+	}
+	endpoint.addBucketUsage(ctx, keyInfo.ProjectID, req.NewBucket, accounting.BucketLiveUsage{Storage: size, Objects: 1})
+
+	endpoint.log.Info("Object Copy", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "copy"), zap.String("type", "object"))
+	mon.Meter("req_copy_object").Mark(1)
+
+	return &CopyObjectResponse{}, nil
+}
+
+// MoveObject atomically moves an object to a new path on the satellite,
+// without transferring its data.
+func (endpoint *Endpoint) MoveObject(ctx context.Context, req *MoveObjectRequest) (resp *MoveObjectResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionDelete,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionWrite,
+		Bucket:        req.NewBucket,
+		EncryptedPath: req.NewEncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := endpoint.validateRelocation(ctx, keyInfo.ProjectID, req.Bucket, req.NewBucket, req.NewEncryptedPath); err != nil {
+		return nil, err
+	}
+
+	crossBucket := !bytes.Equal(req.Bucket, req.NewBucket)
+	if crossBucket {
+		if err := endpoint.checkBucketStorageLimits(ctx, keyInfo.ProjectID, req.NewBucket, true); err != nil {
+			return nil, err
+		}
+	}
+
+	size, err := endpoint.metainfo.MoveObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.NewBucket, req.NewEncryptedPath, req.NewSegmentMetadata)
+	if err != nil {
+		return nil, endpoint.convertRelocationError(err)
+	}
+
+	if crossBucket {
+		endpoint.addBucketUsage(ctx, keyInfo.ProjectID, req.NewBucket, accounting.BucketLiveUsage{Storage: size, Objects: 1})
+	}
+
+	endpoint.log.Info("Object Move", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "move"), zap.String("type", "object"))
+	mon.Meter("req_move_object").Mark(1)
+
+	return &MoveObjectResponse{}, nil
+}
+
+// validateRelocation validates the buckets and the new path of a copy or move.
+func (endpoint *Endpoint) validateRelocation(ctx context.Context, projectID uuid.UUID, bucket, newBucket, newEncryptedPath []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := endpoint.validateBucket(ctx, bucket); err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+	if err := endpoint.validateBucket(ctx, newBucket); err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+	if len(newEncryptedPath) == 0 {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, "missing new encrypted path")
+	}
+
+	_, err = endpoint.metainfo.GetBucket(ctx, newBucket, projectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		endpoint.log.Error("unable to check bucket", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
+
+// convertRelocationError converts the errors of a copy or move to rpc errors.
+func (endpoint *Endpoint) convertRelocationError(err error) error {
+	switch {
+	case storj.ErrObjectNotFound.Has(err):
+		return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+	case ErrObjectExists.Has(err):
+		return rpcstatus.Error(rpcstatus.AlreadyExists, err.Error())
+	case ErrObjectChanged.Has(err):
+		return rpcstatus.Error(rpcstatus.Aborted, err.Error())
+	case ErrObjectLocked.Has(err):
+		return rpcstatus.Error(rpcstatus.PermissionDenied, err.Error())
+	default:
+		endpoint.log.Error("unable to relocate object", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+}
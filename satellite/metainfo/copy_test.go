@@ -0,0 +1,344 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/common/uuid"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/uplink/private/testuplink"
+)
+
+func TestCopyObject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		endpoint := satellite.Metainfo.Endpoint2
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+
+		data := testrand.Bytes(30 * memory.KiB)
+		uploadCtx := testuplink.WithMaxSegmentSize(ctx, 13*memory.KiB)
+		require.NoError(t, uplnk.Upload(uploadCtx, satellite, "testbucket", "object", data))
+
+		projectID, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+		usedSpace := storageNodesUsedSpace(ctx, t, planet)
+
+		_, err := endpoint.CopyObject(ctx, &metainfo.CopyObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    encryptedPath,
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: []byte("copy"),
+		})
+		require.NoError(t, err)
+
+		_, err = endpoint.CopyObject(ctx, &metainfo.CopyObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    encryptedPath,
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: []byte("copy"),
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.AlreadyExists), err)
+
+		// the copy shares the pieces of the original
+		originals := objectPointers(ctx, t, satellite, projectID, "testbucket", encryptedPath)
+		copies := objectPointers(ctx, t, satellite, projectID, "testbucket", []byte("copy"))
+		require.Len(t, originals, 3)
+		require.Len(t, copies, 3)
+		for path, pointer := range originals {
+			require.Equal(t, pb.Pointer_REMOTE, pointer.Type)
+
+			paths, err := satellite.DB.SegmentReferences().Get(ctx, pointer.Remote.RootPieceId)
+			require.NoError(t, err)
+			require.Len(t, paths, 2)
+			require.Contains(t, paths, path)
+		}
+		require.Equal(t, usedSpace, storageNodesUsedSpace(ctx, t, planet))
+
+		t.Run("repair updates copies", func(t *testing.T) {
+			path, err := metainfo.CreatePath(ctx, projectID, -1, []byte("testbucket"), encryptedPath)
+			require.NoError(t, err)
+			pointer := originals[path]
+			removed := pointer.Remote.RemotePieces[0]
+
+			_, err = satellite.Metainfo.Service.UpdatePieces(ctx, path, pointer, nil, []*pb.RemotePiece{removed})
+			require.NoError(t, err)
+
+			copyPath, err := metainfo.CreatePath(ctx, projectID, -1, []byte("testbucket"), []byte("copy"))
+			require.NoError(t, err)
+			copyPointer, err := satellite.Metainfo.Service.Get(ctx, copyPath)
+			require.NoError(t, err)
+			require.Len(t, copyPointer.Remote.RemotePieces, len(pointer.Remote.RemotePieces)-1)
+			for _, piece := range copyPointer.Remote.RemotePieces {
+				require.NotEqual(t, removed.PieceNum, piece.PieceNum)
+			}
+		})
+
+		// deleting the original keeps the pieces used by the copy
+		require.NoError(t, uplnk.DeleteObject(ctx, satellite, "testbucket", "object"))
+		planet.WaitForStorageNodeDeleters(ctx)
+		require.Equal(t, usedSpace, storageNodesUsedSpace(ctx, t, planet))
+
+		for _, pointer := range copies {
+			paths, err := satellite.DB.SegmentReferences().Get(ctx, pointer.Remote.RootPieceId)
+			require.NoError(t, err)
+			require.Empty(t, paths)
+		}
+
+		// the copy is moved back to the original path to be able to decrypt it
+		_, err = endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    []byte("copy"),
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+
+		downloaded, err := uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+
+		// deleting the last object using the pieces deletes them
+		require.NoError(t, uplnk.DeleteObject(ctx, satellite, "testbucket", "object"))
+		planet.WaitForStorageNodeDeleters(ctx)
+		require.Less(t, storageNodesUsedSpace(ctx, t, planet), usedSpace)
+	})
+}
+
+func TestMoveObject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		endpoint := satellite.Metainfo.Endpoint2
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+
+		data := testrand.Bytes(30 * memory.KiB)
+		uploadCtx := testuplink.WithMaxSegmentSize(ctx, 13*memory.KiB)
+		require.NoError(t, uplnk.Upload(uploadCtx, satellite, "testbucket", "object", data))
+		require.NoError(t, uplnk.CreateBucket(ctx, satellite, "otherbucket"))
+
+		projectID, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+		originals := objectPointers(ctx, t, satellite, projectID, "testbucket", encryptedPath)
+		require.Len(t, originals, 3)
+
+		started := time.Now()
+		_, err := endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    encryptedPath,
+			NewBucket:        []byte("otherbucket"),
+			NewEncryptedPath: []byte("moved"),
+		})
+		require.NoError(t, err)
+
+		require.Empty(t, objectPointers(ctx, t, satellite, projectID, "testbucket", encryptedPath))
+		moved := objectPointers(ctx, t, satellite, projectID, "otherbucket", []byte("moved"))
+		require.Len(t, moved, 3)
+
+		// garbage collection adds the pieces of segments moved during its loop
+		relocated, err := satellite.DB.SegmentReferences().ListRelocated(ctx, started)
+		require.NoError(t, err)
+		require.Len(t, relocated, 3)
+		for _, path := range relocated {
+			require.Contains(t, moved, path)
+		}
+
+		t.Run("existing destination", func(t *testing.T) {
+			require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "other", testrand.Bytes(10*memory.KiB)))
+			otherPaths := objectPointers(ctx, t, satellite, projectID, "testbucket", nil)
+			require.Len(t, otherPaths, 1)
+
+			var otherPath []byte
+			for path := range otherPaths {
+				otherPath = []byte(storj.SplitPath(path)[3])
+			}
+
+			_, err := endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+				Header:           header,
+				Bucket:           []byte("otherbucket"),
+				EncryptedPath:    []byte("moved"),
+				NewBucket:        []byte("testbucket"),
+				NewEncryptedPath: otherPath,
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.AlreadyExists), err)
+			require.Len(t, objectPointers(ctx, t, satellite, projectID, "otherbucket", []byte("moved")), 3)
+		})
+
+		t.Run("missing destination bucket", func(t *testing.T) {
+			_, err := endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+				Header:           header,
+				Bucket:           []byte("otherbucket"),
+				EncryptedPath:    []byte("moved"),
+				NewBucket:        []byte("missingbucket"),
+				NewEncryptedPath: []byte("moved"),
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.NotFound), err)
+		})
+
+		_, err = endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+			Header:           header,
+			Bucket:           []byte("otherbucket"),
+			EncryptedPath:    []byte("moved"),
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+		require.Equal(t, originals, objectPointers(ctx, t, satellite, projectID, "testbucket", encryptedPath))
+
+		downloaded, err := uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
+
+func TestRepairCopies(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 8, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				testplanet.ReconfigureRS(2, 3, 4, 4)(log, index, config)
+				config.Repairer.MaxRepair = 2
+				config.Repairer.InMemoryRepair = true
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		endpoint := satellite.Metainfo.Endpoint2
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+
+		satellite.Audit.Worker.Loop.Pause()
+		satellite.Repair.Checker.Loop.Pause()
+		satellite.Repair.Repairer.Loop.Pause()
+
+		data := testrand.Bytes(10 * memory.KiB)
+		require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "object", data))
+
+		projectID, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+		_, err := endpoint.CopyObject(ctx, &metainfo.CopyObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    encryptedPath,
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: []byte("copy"),
+		})
+		require.NoError(t, err)
+
+		path, err := metainfo.CreatePath(ctx, projectID, -1, []byte("testbucket"), encryptedPath)
+		require.NoError(t, err)
+		copyPath, err := metainfo.CreatePath(ctx, projectID, -1, []byte("testbucket"), []byte("copy"))
+		require.NoError(t, err)
+
+		pointer, err := satellite.Metainfo.Service.Get(ctx, path)
+		require.NoError(t, err)
+
+		// both segments need repair, they would derive the same piece ids on the new nodes.
+		lost := pointer.Remote.RemotePieces[:2]
+		for _, piece := range lost {
+			require.NoError(t, planet.StopNodeAndUpdate(ctx, planet.FindNode(piece.NodeId)))
+		}
+
+		satellite.Repair.Checker.Loop.Restart()
+		satellite.Repair.Checker.Loop.TriggerWait()
+		satellite.Repair.Checker.Loop.Pause()
+		satellite.Repair.Repairer.Loop.Restart()
+		satellite.Repair.Repairer.Loop.TriggerWait()
+		satellite.Repair.Repairer.Loop.Pause()
+		satellite.Repair.Repairer.WaitForPendingRepairs()
+
+		count, err := satellite.DB.RepairQueue().Count(ctx)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		repaired, err := satellite.Metainfo.Service.Get(ctx, path)
+		require.NoError(t, err)
+		repairedCopy, err := satellite.Metainfo.Service.Get(ctx, copyPath)
+		require.NoError(t, err)
+
+		require.Len(t, repaired.Remote.RemotePieces, 4)
+		require.ElementsMatch(t, repaired.Remote.RemotePieces, repairedCopy.Remote.RemotePieces)
+
+		pieceNums := make(map[int32]bool)
+		for _, piece := range repaired.Remote.RemotePieces {
+			for _, lostPiece := range lost {
+				require.NotEqual(t, lostPiece.NodeId, piece.NodeId)
+			}
+			require.False(t, pieceNums[piece.PieceNum], "piece %d is stored twice", piece.PieceNum)
+			pieceNums[piece.PieceNum] = true
+
+			node := planet.FindNode(piece.NodeId)
+			reader, err := node.Storage2.Store.ReaderWithWiscKey(ctx, satellite.ID(), repaired.Remote.RootPieceId.Derive(piece.NodeId, piece.PieceNum))
+			require.NoError(t, err)
+			require.NoError(t, reader.CloseWithWiscKey())
+		}
+
+		downloaded, err := uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
+
+// objectPointers returns the pointers of the segments of the object by path,
+// all objects in the bucket are returned when encryptedPath is nil.
+func objectPointers(ctx context.Context, t *testing.T, satellite *testplanet.Satellite, projectID uuid.UUID, bucket string, encryptedPath []byte) map[string]*pb.Pointer {
+	t.Helper()
+
+	pointers := make(map[string]*pb.Pointer)
+	keys, err := satellite.Metainfo.Database.List(ctx, nil, 0)
+	require.NoError(t, err)
+
+	for _, key := range keys {
+		parts := storj.SplitPath(key.String())
+		if len(parts) != 4 || parts[0] != projectID.String() || parts[2] != bucket {
+			continue
+		}
+		if encryptedPath != nil && parts[3] != string(encryptedPath) {
+			continue
+		}
+
+		pointer, err := satellite.Metainfo.Service.Get(ctx, key.String())
+		require.NoError(t, err)
+		pointers[key.String()] = pointer
+	}
+	return pointers
+}
+
+// storageNodesUsedSpace returns the space used for pieces by all storage nodes.
+func storageNodesUsedSpace(ctx context.Context, t *testing.T, planet *testplanet.Planet) (total int64) {
+	t.Helper()
+
+	for _, node := range planet.StorageNodes {
+		piecesTotal, _, err := node.Storage2.Store.SpaceUsedForPieces(ctx)
+		require.NoError(t, err)
+		total += piecesTotal
+	}
+	return total
+}
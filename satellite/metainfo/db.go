@@ -5,10 +5,13 @@ package metainfo
 
 import (
 	"context"
+	"time"
 
 	"storj.io/common/macaroon"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/nodeselection"
 )
 
 // BucketsDB is the interface for the database to interact with buckets
@@ -25,4 +28,82 @@ type BucketsDB interface {
 	DeleteBucket(ctx context.Context, bucketName []byte, projectID uuid.UUID) (err error)
 	// List returns all buckets for a project
 	ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts storj.BucketListOptions, allowedBuckets macaroon.AllowedBuckets) (bucketList storj.BucketList, err error)
+	// GetBucketSettings returns the settings of a bucket, which apply to the objects uploaded to it
+	GetBucketSettings(ctx context.Context, bucketName []byte, projectID uuid.UUID) (settings BucketSettings, err error)
+	// GetBucketPlacement returns the placement of a bucket
+	GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (placement nodeselection.Placement, err error)
+	// UpdateBucketPlacement sets the placement of a bucket, an empty placement allows all nodes
+	UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement nodeselection.Placement) (err error)
+	// GetBucketLimits returns the usage limits of a bucket
+	GetBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID) (limits accounting.BucketLimits, err error)
+	// UpdateBucketLimits sets the usage limits of a bucket, zero limits are removed
+	UpdateBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID, limits accounting.BucketLimits) (err error)
+	// GetBucketLifecycle returns the lifecycle rules of a bucket
+	GetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID) (rules LifecycleRules, err error)
+	// UpdateBucketLifecycle sets the lifecycle rules of a bucket, empty rules are removed
+	UpdateBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID, rules LifecycleRules) (err error)
+	// ListBucketLifecycles returns the lifecycle rules of all buckets which have any
+	ListBucketLifecycles(ctx context.Context) (lifecycles []BucketLifecycle, err error)
+	// GetBucketRSProfile returns the name of the redundancy scheme profile of a bucket, empty means the default
+	GetBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID) (profile string, err error)
+	// UpdateBucketRSProfile sets the redundancy scheme profile of a bucket, an empty name sets the default
+	UpdateBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID, profile string) (err error)
+	// GetBucketVersioning returns whether versioning is enabled for a bucket
+	GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (enabled bool, err error)
+	// UpdateBucketVersioning enables or disables versioning for a bucket
+	UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, enabled bool) (err error)
+	// GetBucketDefaultRetention returns the number of days new objects of a bucket are locked for, zero means they aren't locked
+	GetBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID) (days int, err error)
+	// UpdateBucketDefaultRetention sets the number of days new objects of a bucket are locked for, zero disables it
+	UpdateBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID, days int) (err error)
+}
+
+// BucketSettings are the settings of a bucket, which apply to the objects uploaded to it.
+type BucketSettings struct {
+	Placement nodeselection.Placement
+	Limits    accounting.BucketLimits
+	// RSProfile is the name of the redundancy scheme profile, empty means the default
+	RSProfile  string
+	Versioning bool
+	// DefaultRetentionDays is the number of days new objects are locked for, zero means they aren't locked
+	DefaultRetentionDays int
+}
+
+// SegmentReferencesDB tracks the paths of segments which share the same remote
+// pieces after a server-side copy.
+//
+// architecture: Database
+type SegmentReferencesDB interface {
+	// Add records that the segments at paths share the pieces of rootPieceID
+	Add(ctx context.Context, rootPieceID storj.PieceID, paths ...storj.Path) (err error)
+	// Get returns the paths of the segments sharing the pieces of rootPieceID, empty when they aren't shared
+	Get(ctx context.Context, rootPieceID storj.PieceID) (paths []storj.Path, err error)
+	// Rename replaces the path of a segment sharing the pieces of rootPieceID
+	Rename(ctx context.Context, rootPieceID storj.PieceID, oldPath, newPath storj.Path) (err error)
+	// Remove removes the segment at path from the segments sharing the pieces of rootPieceID
+	// and returns whether the pieces are still used by other segments
+	Remove(ctx context.Context, rootPieceID storj.PieceID, path storj.Path) (shared bool, err error)
+
+	// AddRelocated records that the segments at paths were created by a copy or move at relocatedAt
+	AddRelocated(ctx context.Context, relocatedAt time.Time, paths ...storj.Path) (err error)
+	// ListRelocated returns the paths of the segments created by a copy or move since the given time
+	ListRelocated(ctx context.Context, since time.Time) (paths []storj.Path, err error)
+	// DeleteRelocatedBefore removes the records of the segments created by a copy or move before the given time
+	DeleteRelocatedBefore(ctx context.Context, before time.Time) (err error)
+}
+
+// LoopRangesDB coordinates the ranges of the metainfo loop between satellite
+// processes, so every range is observed by a single process during a pass.
+// The ranges of every observer are claimed separately, because the processes
+// may run different observers.
+//
+// architecture: Database
+type LoopRangesDB interface {
+	// Claim leases the range of the observer to owner, unless another owner holds
+	// an unexpired lease or the range was finished after passStarted
+	Claim(ctx context.Context, observer string, index int, owner uuid.UUID, passStarted time.Time, lease time.Duration) (claimed bool, err error)
+	// Finish marks the range of the observer as finished by owner and releases the lease
+	Finish(ctx context.Context, observer string, index int, owner uuid.UUID, finishedAt time.Time) (err error)
+	// Release releases the lease of owner without marking the range of the observer as finished
+	Release(ctx context.Context, observer string, index int, owner uuid.UUID) (err error)
 }
@@ -5,6 +5,7 @@ package metainfo_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -194,3 +195,64 @@ func TestListBucketsNotAllowed(t *testing.T) {
 		}
 	})
 }
+
+func TestLoopRangesDB(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		rangesDB := db.MetainfoLoopRanges()
+
+		owner1, owner2 := testrand.UUID(), testrand.UUID()
+		passStarted := time.Now()
+
+		// the first owner claims the range.
+		claimed, err := rangesDB.Claim(ctx, "checker", 3, owner1, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// the lease of the first owner hasn't expired.
+		claimed, err = rangesDB.Claim(ctx, "checker", 3, owner2, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		// the range can be claimed for another observer.
+		claimed, err = rangesDB.Claim(ctx, "audit", 3, owner2, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// another range can be claimed.
+		claimed, err = rangesDB.Claim(ctx, "checker", 4, owner2, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// a released range can be claimed by another owner.
+		require.NoError(t, rangesDB.Release(ctx, "checker", 3, owner1))
+		claimed, err = rangesDB.Claim(ctx, "checker", 3, owner2, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// only the owner of the lease can finish the range.
+		require.NoError(t, rangesDB.Finish(ctx, "checker", 3, owner1, time.Now()))
+		claimed, err = rangesDB.Claim(ctx, "checker", 3, owner1, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		// a finished range can't be claimed again in the same pass.
+		finishedAt := time.Now()
+		require.NoError(t, rangesDB.Finish(ctx, "checker", 3, owner2, finishedAt))
+		claimed, err = rangesDB.Claim(ctx, "checker", 3, owner1, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		// a finished range can be claimed in the next pass.
+		claimed, err = rangesDB.Claim(ctx, "checker", 3, owner1, finishedAt.Add(time.Second), time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// an expired lease can be claimed by another owner.
+		claimed, err = rangesDB.Claim(ctx, "checker", 5, owner1, passStarted, -time.Minute)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		claimed, err = rangesDB.Claim(ctx, "checker", 5, owner2, passStarted, time.Hour)
+		require.NoError(t, err)
+		require.True(t, claimed)
+	})
+}
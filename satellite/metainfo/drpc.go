@@ -0,0 +1,219 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"fmt"
+
+	"storj.io/drpc"
+)
+
+// DRPCObjectsServer is the server of the object operations, which aren't part
+// of the Metainfo service yet.
+type DRPCObjectsServer interface {
+	CopyObject(context.Context, *CopyObjectRequest) (*CopyObjectResponse, error)
+	MoveObject(context.Context, *MoveObjectRequest) (*MoveObjectResponse, error)
+	ListObjectVersions(context.Context, *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error)
+	GetObjectLock(context.Context, *GetObjectLockRequest) (*GetObjectLockResponse, error)
+	SetObjectLock(context.Context, *SetObjectLockRequest) (*SetObjectLockResponse, error)
+}
+
+// DRPCObjectsDescription describes the object operations for registering them on a drpc mux.
+type DRPCObjectsDescription struct{}
+
+// NumMethods returns the number of object operations.
+func (DRPCObjectsDescription) NumMethods() int { return 5 }
+
+// Method returns the nth object operation.
+func (DRPCObjectsDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/metainfo.Objects/CopyObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectsServer).CopyObject(ctx, in1.(*CopyObjectRequest))
+			}, DRPCObjectsServer.CopyObject, true
+	case 1:
+		return "/metainfo.Objects/MoveObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectsServer).MoveObject(ctx, in1.(*MoveObjectRequest))
+			}, DRPCObjectsServer.MoveObject, true
+	case 2:
+		return "/metainfo.Objects/ListObjectVersions",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectsServer).ListObjectVersions(ctx, in1.(*ListObjectVersionsRequest))
+			}, DRPCObjectsServer.ListObjectVersions, true
+	case 3:
+		return "/metainfo.Objects/GetObjectLock",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectsServer).GetObjectLock(ctx, in1.(*GetObjectLockRequest))
+			}, DRPCObjectsServer.GetObjectLock, true
+	case 4:
+		return "/metainfo.Objects/SetObjectLock",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectsServer).SetObjectLock(ctx, in1.(*SetObjectLockRequest))
+			}, DRPCObjectsServer.SetObjectLock, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterObjects registers the object operations of impl on the mux.
+func DRPCRegisterObjects(mux drpc.Mux, impl DRPCObjectsServer) error {
+	return mux.Register(impl, DRPCObjectsDescription{})
+}
+
+// DRPCObjectsClient is the client of the object operations.
+type DRPCObjectsClient interface {
+	DRPCConn() drpc.Conn
+
+	CopyObject(ctx context.Context, in *CopyObjectRequest) (*CopyObjectResponse, error)
+	MoveObject(ctx context.Context, in *MoveObjectRequest) (*MoveObjectResponse, error)
+	ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error)
+	GetObjectLock(ctx context.Context, in *GetObjectLockRequest) (*GetObjectLockResponse, error)
+	SetObjectLock(ctx context.Context, in *SetObjectLockRequest) (*SetObjectLockResponse, error)
+}
+
+type drpcObjectsClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCObjectsClient returns a client of the object operations on the connection.
+func NewDRPCObjectsClient(cc drpc.Conn) DRPCObjectsClient {
+	return &drpcObjectsClient{cc}
+}
+
+func (c *drpcObjectsClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcObjectsClient) CopyObject(ctx context.Context, in *CopyObjectRequest) (*CopyObjectResponse, error) {
+	out := new(CopyObjectResponse)
+	if err := c.cc.Invoke(ctx, "/metainfo.Objects/CopyObject", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectsClient) MoveObject(ctx context.Context, in *MoveObjectRequest) (*MoveObjectResponse, error) {
+	out := new(MoveObjectResponse)
+	if err := c.cc.Invoke(ctx, "/metainfo.Objects/MoveObject", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectsClient) ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error) {
+	out := new(ListObjectVersionsResponse)
+	if err := c.cc.Invoke(ctx, "/metainfo.Objects/ListObjectVersions", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectsClient) GetObjectLock(ctx context.Context, in *GetObjectLockRequest) (*GetObjectLockResponse, error) {
+	out := new(GetObjectLockResponse)
+	if err := c.cc.Invoke(ctx, "/metainfo.Objects/GetObjectLock", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectsClient) SetObjectLock(ctx context.Context, in *SetObjectLockRequest) (*SetObjectLockResponse, error) {
+	out := new(SetObjectLockResponse)
+	if err := c.cc.Invoke(ctx, "/metainfo.Objects/SetObjectLock", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// The requests and responses are encoded using the protobuf tags of their fields.
+
+// Reset resets the request.
+func (m *CopyObjectRequest) Reset() { *m = CopyObjectRequest{} }
+
+// String formats the request.
+func (m *CopyObjectRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*CopyObjectRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *CopyObjectResponse) Reset() { *m = CopyObjectResponse{} }
+
+// String formats the response.
+func (m *CopyObjectResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*CopyObjectResponse) ProtoMessage() {}
+
+// Reset resets the request.
+func (m *MoveObjectRequest) Reset() { *m = MoveObjectRequest{} }
+
+// String formats the request.
+func (m *MoveObjectRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*MoveObjectRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *MoveObjectResponse) Reset() { *m = MoveObjectResponse{} }
+
+// String formats the response.
+func (m *MoveObjectResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*MoveObjectResponse) ProtoMessage() {}
+
+// Reset resets the request.
+func (m *ListObjectVersionsRequest) Reset() { *m = ListObjectVersionsRequest{} }
+
+// String formats the request.
+func (m *ListObjectVersionsRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*ListObjectVersionsRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *ListObjectVersionsResponse) Reset() { *m = ListObjectVersionsResponse{} }
+
+// String formats the response.
+func (m *ListObjectVersionsResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*ListObjectVersionsResponse) ProtoMessage() {}
+
+// Reset resets the request.
+func (m *GetObjectLockRequest) Reset() { *m = GetObjectLockRequest{} }
+
+// String formats the request.
+func (m *GetObjectLockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*GetObjectLockRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *GetObjectLockResponse) Reset() { *m = GetObjectLockResponse{} }
+
+// String formats the response.
+func (m *GetObjectLockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*GetObjectLockResponse) ProtoMessage() {}
+
+// Reset resets the request.
+func (m *SetObjectLockRequest) Reset() { *m = SetObjectLockRequest{} }
+
+// String formats the request.
+func (m *SetObjectLockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the request as a protobuf message.
+func (*SetObjectLockRequest) ProtoMessage() {}
+
+// Reset resets the response.
+func (m *SetObjectLockResponse) Reset() { *m = SetObjectLockResponse{} }
+
+// String formats the response.
+func (m *SetObjectLockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the response as a protobuf message.
+func (*SetObjectLockResponse) ProtoMessage() {}
@@ -0,0 +1,119 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/drpc/drpcmux"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestObjectsDRPCEncoding(t *testing.T) {
+	require.NoError(t, metainfo.DRPCRegisterObjects(drpcmux.New(), &metainfo.Endpoint{}))
+
+	copyReq := &metainfo.CopyObjectRequest{
+		Header:             &pb.RequestHeader{ApiKey: []byte("key")},
+		Bucket:             []byte("bucket"),
+		EncryptedPath:      []byte("path"),
+		NewBucket:          []byte("new-bucket"),
+		NewEncryptedPath:   []byte("new-path"),
+		NewSegmentMetadata: map[int64][]byte{-1: []byte("last"), 0: []byte("first")},
+	}
+	data, err := pb.Marshal(copyReq)
+	require.NoError(t, err)
+	var decodedCopy metainfo.CopyObjectRequest
+	require.NoError(t, pb.Unmarshal(data, &decodedCopy))
+	require.Equal(t, copyReq.Bucket, decodedCopy.Bucket)
+	require.Equal(t, copyReq.NewEncryptedPath, decodedCopy.NewEncryptedPath)
+	require.Equal(t, copyReq.NewSegmentMetadata, decodedCopy.NewSegmentMetadata)
+	require.Equal(t, copyReq.Header.ApiKey, decodedCopy.Header.ApiKey)
+
+	lockReq := &metainfo.SetObjectLockRequest{
+		Bucket:      []byte("bucket"),
+		Version:     3,
+		RetainUntil: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		LegalHold:   true,
+	}
+	data, err = pb.Marshal(lockReq)
+	require.NoError(t, err)
+	var decodedLock metainfo.SetObjectLockRequest
+	require.NoError(t, pb.Unmarshal(data, &decodedLock))
+	require.EqualValues(t, 3, decodedLock.Version)
+	require.True(t, lockReq.RetainUntil.Equal(decodedLock.RetainUntil))
+	require.True(t, decodedLock.LegalHold)
+}
+
+func TestObjectsDRPC(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+
+		require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "object", testrand.Bytes(10*memory.KiB)))
+		_, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+
+		conn, err := uplnk.Dialer.DialNodeURL(ctx, satellite.NodeURL())
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+		client := metainfo.NewDRPCObjectsClient(conn.Raw())
+
+		_, err = client.CopyObject(ctx, &metainfo.CopyObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    encryptedPath,
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: []byte("copy"),
+		})
+		require.NoError(t, err)
+
+		_, err = client.MoveObject(ctx, &metainfo.MoveObjectRequest{
+			Header:           header,
+			Bucket:           []byte("testbucket"),
+			EncryptedPath:    []byte("copy"),
+			NewBucket:        []byte("testbucket"),
+			NewEncryptedPath: []byte("moved"),
+		})
+		require.NoError(t, err)
+
+		retainUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+		_, err = client.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+			Header:        header,
+			Bucket:        []byte("testbucket"),
+			EncryptedPath: []byte("moved"),
+			RetainUntil:   retainUntil,
+		})
+		require.NoError(t, err)
+
+		lock, err := client.GetObjectLock(ctx, &metainfo.GetObjectLockRequest{
+			Header:        header,
+			Bucket:        []byte("testbucket"),
+			EncryptedPath: []byte("moved"),
+		})
+		require.NoError(t, err)
+		require.True(t, retainUntil.Equal(lock.RetainUntil))
+		require.False(t, lock.LegalHold)
+
+		versions, err := client.ListObjectVersions(ctx, &metainfo.ListObjectVersionsRequest{
+			Header:        header,
+			Bucket:        []byte("testbucket"),
+			EncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+		require.Empty(t, versions.Items)
+	})
+}
@@ -15,7 +15,8 @@ import (
 	"storj.io/storj/storage"
 )
 
-var _ metainfo.Observer = (*expiredDeleter)(nil)
+var _ metainfo.PartialObserver = (*expiredDeleter)(nil)
+var _ metainfo.DistributedObserver = (*expiredDeleter)(nil)
 
 // expiredDeleter implements the metainfo loop observer interface for expired segment cleanup
 //
@@ -44,9 +45,30 @@ func (ed *expiredDeleter) Object(ctx context.Context, path metainfo.ScopedPath,
 	return nil
 }
 
+// Fork returns the expired deleter itself, because it doesn't collect anything.
+func (ed *expiredDeleter) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return ed, nil
+}
+
+// Merge does nothing, because the expired segments are deleted while observing them.
+func (ed *expiredDeleter) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	return nil
+}
+
+// Distributed returns true, because every expired segment needs to be deleted only once.
+func (ed *expiredDeleter) Distributed() bool {
+	return true
+}
+
+// DistributedName returns the name of the observer for claiming the ranges.
+func (ed *expiredDeleter) DistributedName() string {
+	return "expired-deletion"
+}
+
 func (ed *expiredDeleter) deleteSegmentIfExpired(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
-	// delete segment if expired
-	if !pointer.ExpirationDate.IsZero() && pointer.ExpirationDate.Before(time.Now().UTC()) {
+	// delete segment if expired, locked segments are kept until the lock ends
+	now := time.Now().UTC()
+	if metainfo.IsExpired(pointer, now) && !metainfo.IsLocked(pointer, now) {
 		pointerBytes, err := pb.Marshal(pointer)
 		if err != nil {
 			return err
@@ -0,0 +1,123 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+)
+
+// ErrLifecycle is the error class for invalid lifecycle rules.
+var ErrLifecycle = errs.Class("lifecycle")
+
+// LifecycleRule describes an action which is applied to the objects of a bucket
+// after they reach a certain age.
+//
+// Prefix is matched against the object path as it is stored by the satellite,
+// for buckets with path encryption it must be the encrypted path prefix.
+type LifecycleRule struct {
+	Prefix string `json:"prefix,omitempty"`
+	// ExpirationDays deletes committed objects older than the given number of days.
+	ExpirationDays int `json:"expirationDays,omitempty"`
+	// AbortIncompleteUploadHours deletes the segments of objects which were
+	// not committed within the given number of hours.
+	AbortIncompleteUploadHours int `json:"abortIncompleteUploadHours,omitempty"`
+}
+
+// Expiration returns the age after which committed objects are deleted, zero means never.
+func (rule LifecycleRule) Expiration() time.Duration {
+	return time.Duration(rule.ExpirationDays) * 24 * time.Hour
+}
+
+// AbortIncompleteUpload returns the age after which uncommitted segments are deleted, zero means never.
+func (rule LifecycleRule) AbortIncompleteUpload() time.Duration {
+	return time.Duration(rule.AbortIncompleteUploadHours) * time.Hour
+}
+
+// Matches returns whether the rule applies to the encrypted object path.
+func (rule LifecycleRule) Matches(encryptedPath string) bool {
+	return strings.HasPrefix(encryptedPath, rule.Prefix)
+}
+
+// LifecycleRules is the list of lifecycle rules of a bucket.
+type LifecycleRules []LifecycleRule
+
+// Validate checks that all rules have an action and no negative ages.
+func (rules LifecycleRules) Validate() error {
+	for i, rule := range rules {
+		if rule.ExpirationDays < 0 || rule.AbortIncompleteUploadHours < 0 {
+			return ErrLifecycle.New("rule %d has a negative age", i)
+		}
+		if rule.ExpirationDays == 0 && rule.AbortIncompleteUploadHours == 0 {
+			return ErrLifecycle.New("rule %d has no action", i)
+		}
+	}
+	return nil
+}
+
+// Expiration returns the shortest expiration age of the rules matching the path,
+// zero means the object doesn't expire.
+func (rules LifecycleRules) Expiration(encryptedPath string) (age time.Duration) {
+	for _, rule := range rules {
+		if expiration := rule.Expiration(); expiration > 0 && rule.Matches(encryptedPath) {
+			if age == 0 || expiration < age {
+				age = expiration
+			}
+		}
+	}
+	return age
+}
+
+// AbortIncompleteUpload returns the shortest age after which uncommitted segments of the path
+// are deleted, zero means they are kept.
+func (rules LifecycleRules) AbortIncompleteUpload(encryptedPath string) (age time.Duration) {
+	for _, rule := range rules {
+		if abort := rule.AbortIncompleteUpload(); abort > 0 && rule.Matches(encryptedPath) {
+			if age == 0 || abort < age {
+				age = abort
+			}
+		}
+	}
+	return age
+}
+
+// HasAbortIncompleteUpload returns whether any rule deletes uncommitted segments.
+func (rules LifecycleRules) HasAbortIncompleteUpload() bool {
+	for _, rule := range rules {
+		if rule.AbortIncompleteUploadHours > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalLifecycleRules encodes the rules for storing them in the database.
+func MarshalLifecycleRules(rules LifecycleRules) ([]byte, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(rules)
+	return data, ErrLifecycle.Wrap(err)
+}
+
+// UnmarshalLifecycleRules decodes rules stored in the database.
+func UnmarshalLifecycleRules(data []byte) (rules LifecycleRules, err error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	err = json.Unmarshal(data, &rules)
+	return rules, ErrLifecycle.Wrap(err)
+}
+
+// BucketLifecycle holds the lifecycle rules of a single bucket.
+type BucketLifecycle struct {
+	ProjectID  uuid.UUID
+	BucketName []byte
+	Rules      LifecycleRules
+}
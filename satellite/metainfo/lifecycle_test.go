@@ -0,0 +1,43 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestLifecycleRules(t *testing.T) {
+	rules := metainfo.LifecycleRules{
+		{Prefix: "logs/", ExpirationDays: 30},
+		{Prefix: "logs/debug/", ExpirationDays: 2},
+		{AbortIncompleteUploadHours: 24},
+	}
+	require.NoError(t, rules.Validate())
+
+	require.Equal(t, 30*24*time.Hour, rules.Expiration("logs/app"))
+	require.Equal(t, 2*24*time.Hour, rules.Expiration("logs/debug/app"))
+	require.Equal(t, time.Duration(0), rules.Expiration("data/app"))
+
+	require.Equal(t, 24*time.Hour, rules.AbortIncompleteUpload("data/app"))
+	require.True(t, rules.HasAbortIncompleteUpload())
+	require.False(t, rules[:2].HasAbortIncompleteUpload())
+
+	data, err := metainfo.MarshalLifecycleRules(rules)
+	require.NoError(t, err)
+	decoded, err := metainfo.UnmarshalLifecycleRules(data)
+	require.NoError(t, err)
+	require.Equal(t, rules, decoded)
+
+	data, err = metainfo.MarshalLifecycleRules(nil)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	require.Error(t, metainfo.LifecycleRules{{Prefix: "logs/"}}.Validate())
+	require.Error(t, metainfo.LifecycleRules{{ExpirationDays: -1}}.Validate())
+}
@@ -6,6 +6,8 @@ package metainfo
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -15,7 +17,6 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
-	"storj.io/storj/storage"
 )
 
 var (
@@ -53,6 +54,16 @@ func (NullObserver) InlineSegment(context.Context, ScopedPath, *pb.Pointer) erro
 	return nil
 }
 
+// Fork implements the PartialObserver interface
+func (NullObserver) Fork(context.Context) (Observer, error) {
+	return NullObserver{}, nil
+}
+
+// Merge implements the PartialObserver interface
+func (NullObserver) Merge(context.Context, Observer) error {
+	return nil
+}
+
 // ScopedPath contains full expanded information about the path.
 type ScopedPath struct {
 	ProjectID           uuid.UUID
@@ -69,13 +80,21 @@ type ScopedPath struct {
 
 type observerContext struct {
 	observer Observer
+	// parent is the joined observer when the observer is a fork for a single range.
+	parent *observerContext
 
-	ctx  context.Context
-	done chan error
+	ctx    context.Context
+	done   chan error
+	failed int32
 
-	object *monkit.DurationDist
-	remote *monkit.DurationDist
-	inline *monkit.DurationDist
+	// partial serializes forking and merging the observer.
+	partial sync.Mutex
+
+	// durations guards the distributions, which are shared with the forks.
+	durations *sync.Mutex
+	object    *monkit.DurationDist
+	remote    *monkit.DurationDist
+	inline    *monkit.DurationDist
 }
 
 func newObserverContext(ctx context.Context, obs Observer) *observerContext {
@@ -88,42 +107,95 @@ func newObserverContext(ctx context.Context, obs Observer) *observerContext {
 		ctx:  ctx,
 		done: make(chan error),
 
-		object: monkit.NewDurationDist(key.WithTag("pointer_type", "object")),
-		inline: monkit.NewDurationDist(key.WithTag("pointer_type", "inline")),
-		remote: monkit.NewDurationDist(key.WithTag("pointer_type", "remote")),
+		durations: &sync.Mutex{},
+		object:    monkit.NewDurationDist(key.WithTag("pointer_type", "object")),
+		inline:    monkit.NewDurationDist(key.WithTag("pointer_type", "inline")),
+		remote:    monkit.NewDurationDist(key.WithTag("pointer_type", "remote")),
 	}
 }
 
 func (observer *observerContext) Object(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
 	start := time.Now()
-	defer func() { observer.object.Insert(time.Since(start)) }()
+	defer func() { observer.insert(observer.object, time.Since(start)) }()
 
 	return observer.observer.Object(ctx, path, pointer)
 }
 
 func (observer *observerContext) RemoteSegment(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
 	start := time.Now()
-	defer func() { observer.remote.Insert(time.Since(start)) }()
+	defer func() { observer.insert(observer.remote, time.Since(start)) }()
 
 	return observer.observer.RemoteSegment(ctx, path, pointer)
 }
 
 func (observer *observerContext) InlineSegment(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
 	start := time.Now()
-	defer func() { observer.inline.Insert(time.Since(start)) }()
+	defer func() { observer.insert(observer.inline, time.Since(start)) }()
 
 	return observer.observer.InlineSegment(ctx, path, pointer)
 }
 
+func (observer *observerContext) insert(dist *monkit.DurationDist, duration time.Duration) {
+	observer.durations.Lock()
+	defer observer.durations.Unlock()
+	dist.Insert(duration)
+}
+
+// HandleError finishes the joined observer with the error, the errors of
+// forks are handled by their parent. Only the first error is reported.
 func (observer *observerContext) HandleError(err error) bool {
 	if err != nil {
-		observer.done <- err
-		observer.Finish()
+		if observer.parent != nil {
+			return observer.parent.HandleError(err)
+		}
+		if atomic.CompareAndSwapInt32(&observer.failed, 0, 1) {
+			observer.done <- err
+			observer.Finish()
+		}
 		return true
 	}
 	return false
 }
 
+// Failed returns whether the joined observer was finished with an error.
+func (observer *observerContext) Failed() bool {
+	if observer.parent != nil {
+		return observer.parent.Failed()
+	}
+	return atomic.LoadInt32(&observer.failed) != 0
+}
+
+// fork returns a fork of the observer for observing a single range.
+func (observer *observerContext) fork(ctx context.Context) (*observerContext, error) {
+	observer.partial.Lock()
+	defer observer.partial.Unlock()
+
+	partial, err := observer.observer.(PartialObserver).Fork(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &observerContext{
+		observer: partial,
+		parent:   observer,
+
+		ctx: observer.ctx,
+
+		durations: observer.durations,
+		object:    observer.object,
+		inline:    observer.inline,
+		remote:    observer.remote,
+	}, nil
+}
+
+// merge merges the results of the fork into its parent.
+func (observer *observerContext) merge(ctx context.Context) error {
+	parent := observer.parent
+	parent.partial.Lock()
+	defer parent.partial.Unlock()
+
+	return parent.observer.(PartialObserver).Merge(ctx, observer.observer)
+}
+
 func (observer *observerContext) Finish() {
 	close(observer.done)
 
@@ -141,6 +213,9 @@ type LoopConfig struct {
 	CoalesceDuration time.Duration `help:"how long to wait for new observers before starting iteration" releaseDefault:"5s" devDefault:"5s"`
 	RateLimit        float64       `help:"rate limit (default is 0 which is unlimited segments per second)" default:"0"`
 	ListLimit        int           `help:"how many items to query in a batch" default:"10000"`
+	Ranges           int           `help:"how many ranges of projects the pointer database is split into, at most 256; the satellite processes share the ranges of observers which don't need to see every segment" default:"1"`
+	Parallelism      int           `help:"how many ranges are iterated concurrently when all observers can merge partial results" default:"1"`
+	RangeLease       time.Duration `help:"how long a range is leased to a satellite process before other processes can take it over" default:"1h"`
 }
 
 // Loop is a metainfo loop service.
@@ -149,15 +224,27 @@ type LoopConfig struct {
 type Loop struct {
 	config LoopConfig
 	db     PointerDB
+	ranges LoopRangesDB
+	owner  uuid.UUID
 	join   chan []*observerContext
 	done   chan struct{}
 }
 
-// NewLoop creates a new metainfo loop service.
-func NewLoop(config LoopConfig, db PointerDB) *Loop {
+// NewLoop creates a new metainfo loop service. The ranges are shared with
+// other satellite processes through rangesDB, which may be nil when the loop
+// doesn't need to coordinate with other processes.
+func NewLoop(config LoopConfig, db PointerDB, rangesDB LoopRangesDB) *Loop {
+	owner, err := uuid.New()
+	if err != nil {
+		// the random source is broken, don't share the ranges with a non-unique owner.
+		rangesDB = nil
+	}
+
 	return &Loop{
 		db:     db,
 		config: config,
+		ranges: rangesDB,
+		owner:  owner,
 		join:   make(chan []*observerContext),
 		done:   make(chan struct{}),
 	}
@@ -238,7 +325,19 @@ waitformore:
 			return ctx.Err()
 		}
 	}
-	return iterateDatabase(ctx, loop.db, observers, loop.config.ListLimit, rate.NewLimiter(rate.Limit(loop.config.RateLimit), 1))
+
+	pass := &loopPass{
+		db:          loop.db,
+		ranges:      splitKeyspace(loop.config.Ranges),
+		parallelism: loop.config.Parallelism,
+		limit:       loop.config.ListLimit,
+		rateLimiter: rate.NewLimiter(rate.Limit(loop.config.RateLimit), 1),
+
+		rangesDB: loop.ranges,
+		owner:    loop.owner,
+		lease:    loop.config.RangeLease,
+	}
+	return pass.run(ctx, observers)
 }
 
 // IterateDatabase iterates over PointerDB and notifies specified observers about results.
@@ -290,90 +389,20 @@ func (loop *Loop) Wait() {
 }
 
 func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerContext, limit int, rateLimiter *rate.Limiter) (err error) {
-	defer func() {
-		if err != nil {
-			for _, observer := range observers {
-				observer.HandleError(err)
-			}
-			return
-		}
-		finishObservers(observers)
-	}()
-
-	err = db.IterateWithoutLookupLimit(ctx, storage.IterateOptions{
-		Recurse: true,
-		Limit:   limit,
-	}, func(ctx context.Context, it storage.Iterator) error {
-		var item storage.ListItem
-
-		// iterate over every segment in metainfo
-	nextSegment:
-		for it.Next(ctx, &item) {
-			if err := rateLimiter.Wait(ctx); err != nil {
-				// We don't really execute concurrent batches so we should never
-				// exceed the burst size of 1 and this should never happen.
-				// We can also enter here if the context is cancelled.
-				return LoopError.Wrap(err)
-			}
-
-			rawPath := item.Key.String()
-			pointer := &pb.Pointer{}
-
-			err := pb.Unmarshal(item.Value, pointer)
-			if err != nil {
-				return LoopError.New("unexpected error unmarshalling pointer %s", err)
-			}
-
-			pathElements := storj.SplitPath(rawPath)
-
-			if len(pathElements) < 4 {
-				// We skip this path because it belongs to bucket metadata, not to an
-				// actual object
-				continue nextSegment
-			}
-
-			isLastSegment := pathElements[1] == "l"
-
-			path := ScopedPath{
-				Raw:                 rawPath,
-				ProjectIDString:     pathElements[0],
-				Segment:             pathElements[1],
-				BucketName:          pathElements[2],
-				EncryptedObjectPath: storj.JoinPaths(pathElements[3:]...),
-			}
-
-			path.ProjectID, err = uuid.FromString(path.ProjectIDString)
-			if err != nil {
-				return LoopError.Wrap(err)
-			}
-
-			nextObservers := observers[:0]
-			for _, observer := range observers {
-				keepObserver := handlePointer(ctx, observer, path, isLastSegment, pointer)
-				if keepObserver {
-					nextObservers = append(nextObservers, observer)
-				}
-			}
-
-			observers = nextObservers
-			if len(observers) == 0 {
-				return nil
-			}
-
-			// if context has been canceled exit. Otherwise, continue
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-		}
-		return nil
-	})
-	return err
+	pass := &loopPass{
+		db:          db,
+		ranges:      splitKeyspace(1),
+		parallelism: 1,
+		limit:       limit,
+		rateLimiter: rateLimiter,
+	}
+	return pass.run(ctx, observers)
 }
 
 func finishObservers(observers []*observerContext) {
 	for _, observer := range observers {
-		observer.Finish()
+		if !observer.Failed() {
+			observer.Finish()
+		}
 	}
 }
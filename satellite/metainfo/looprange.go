@@ -0,0 +1,334 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/storage"
+)
+
+// maxLoopRanges is the number of distinct first bytes of the project IDs in
+// the keys of the pointer database.
+const maxLoopRanges = 256
+
+// PartialObserver is an observer which can observe the ranges of the
+// metainfo loop concurrently. The loop iterates the ranges concurrently only
+// when all joined observers are partial observers.
+//
+// Fork and Merge calls of an observer are never concurrent.
+type PartialObserver interface {
+	Observer
+
+	// Fork returns an observer for a single range. The segments of a project
+	// are always in the same range.
+	Fork(ctx context.Context) (Observer, error)
+	// Merge adds the results of an observer returned by Fork, after it
+	// observed its whole range.
+	Merge(ctx context.Context, partial Observer) error
+}
+
+// DistributedObserver is an observer which doesn't need to observe the ranges
+// observed by other satellite processes during the same pass, because its
+// results are only used for acting on the observed segments.
+//
+// The ranges are claimed separately for every name, because the processes
+// may run different observers.
+type DistributedObserver interface {
+	Observer
+
+	// Distributed returns whether the observer can skip the ranges observed by other processes.
+	Distributed() bool
+	// DistributedName returns the name of the observer, which is the same in all processes.
+	DistributedName() string
+}
+
+// loopRange is a range of the pointer database keys.
+type loopRange struct {
+	index int
+	// first is the first key of the range, nil for the first range.
+	first storage.Key
+	// end is the first key after the range, nil for the last range.
+	end storage.Key
+}
+
+// splitKeyspace splits the pointer database into ranges by the first byte of
+// the project IDs, so the keys of a project are always in the same range.
+func splitKeyspace(count int) []loopRange {
+	if count < 1 {
+		count = 1
+	} else if count > maxLoopRanges {
+		count = maxLoopRanges
+	}
+
+	ranges := make([]loopRange, count)
+	for i := range ranges {
+		ranges[i].index = i
+		if i > 0 {
+			ranges[i].first = storage.Key(fmt.Sprintf("%02x", i*maxLoopRanges/count))
+			ranges[i-1].end = ranges[i].first
+		}
+	}
+	return ranges
+}
+
+// contains returns whether the key belongs to the range.
+func (rng loopRange) contains(key storage.Key) bool {
+	return bytes.Compare(key, rng.first) >= 0 && (rng.end == nil || bytes.Compare(key, rng.end) < 0)
+}
+
+// loopPass is a single pass of the observers over the pointer database.
+type loopPass struct {
+	db          PointerDB
+	ranges      []loopRange
+	parallelism int
+	limit       int
+	rateLimiter *rate.Limiter
+
+	// rangesDB is used to share the ranges of distributed observers with other
+	// processes, it's nil when the ranges aren't shared.
+	rangesDB LoopRangesDB
+	owner    uuid.UUID
+	lease    time.Duration
+	started  time.Time
+
+	next int64
+}
+
+// run notifies the observers about all segments and finishes them.
+func (pass *loopPass) run(ctx context.Context, observers []*observerContext) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer func() {
+		if err != nil {
+			for _, observer := range observers {
+				observer.HandleError(err)
+			}
+			return
+		}
+		finishObservers(observers)
+	}()
+
+	pass.started = time.Now()
+
+	// the observers observe the ranges concurrently only when all of them
+	// can merge the results of the ranges.
+	parallelism := pass.parallelism
+	if parallelism > len(pass.ranges) {
+		parallelism = len(pass.ranges)
+	}
+	for _, observer := range observers {
+		if _, ok := observer.observer.(PartialObserver); !ok {
+			parallelism = 1
+		}
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	forked := parallelism > 1
+	mon.IntVal("metainfo_loop_parallelism").Observe(int64(parallelism))
+
+	group, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < parallelism; i++ {
+		group.Go(func() error {
+			for {
+				index := atomic.AddInt64(&pass.next, 1) - 1
+				if index >= int64(len(pass.ranges)) {
+					return nil
+				}
+				if err := pass.observeRange(ctx, pass.ranges[index], observers, forked); err != nil {
+					return err
+				}
+			}
+		})
+	}
+	return group.Wait()
+}
+
+// observeRange notifies the observers about the segments in the range. The
+// distributed observers observe the range only when this process claims it
+// for them.
+func (pass *loopPass) observeRange(ctx context.Context, rng loopRange, observers []*observerContext, forked bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// claims contains whether the range was claimed for the observer names.
+	claims := map[string]bool{}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for name, claimed := range claims {
+			if claimed {
+				// another process takes the range over once the lease is released.
+				err = errs.Combine(err, pass.rangesDB.Release(ctx, name, rng.index, pass.owner))
+			}
+		}
+	}()
+
+	active := make([]*observerContext, 0, len(observers))
+	for _, observer := range observers {
+		if observer.Failed() {
+			continue
+		}
+
+		if name, ok := pass.distributed(observer); ok {
+			claimed, checked := claims[name]
+			if !checked {
+				claimed, err = pass.rangesDB.Claim(ctx, name, rng.index, pass.owner, pass.started, pass.lease)
+				if err != nil {
+					return LoopError.Wrap(err)
+				}
+				claims[name] = claimed
+			}
+			if !claimed {
+				continue
+			}
+		}
+
+		if forked {
+			partial, err := observer.fork(ctx)
+			if observer.HandleError(err) {
+				continue
+			}
+			observer = partial
+		}
+		active = append(active, observer)
+	}
+
+	if len(active) > 0 {
+		err = iterateRange(ctx, pass.db, rng, active, pass.limit, pass.rateLimiter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if forked {
+		for _, partial := range active {
+			if !partial.Failed() {
+				partial.HandleError(partial.merge(ctx))
+			}
+		}
+	}
+
+	finishedAt := time.Now()
+	var group errs.Group
+	for name, claimed := range claims {
+		if claimed {
+			group.Add(pass.rangesDB.Finish(ctx, name, rng.index, pass.owner, finishedAt))
+		}
+	}
+	return LoopError.Wrap(group.Err())
+}
+
+// distributed returns the name of the observer, when it observes only the
+// ranges claimed by this process.
+func (pass *loopPass) distributed(observer *observerContext) (name string, ok bool) {
+	if pass.rangesDB == nil || len(pass.ranges) < 2 {
+		return "", false
+	}
+	distributed, ok := observer.observer.(DistributedObserver)
+	if !ok || !distributed.Distributed() {
+		return "", false
+	}
+	return distributed.DistributedName(), true
+}
+
+// iterateRange notifies the observers about the segments in the range. The
+// errors of the observers are handled by the observers.
+func iterateRange(ctx context.Context, db PointerDB, rng loopRange, observers []*observerContext, limit int, rateLimiter *rate.Limiter) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the observers which stop observing are removed from the copy.
+	observers = append([]*observerContext(nil), observers...)
+
+	return db.IterateWithoutLookupLimit(ctx, storage.IterateOptions{
+		First:   rng.first,
+		Recurse: true,
+		Limit:   limit,
+	}, func(ctx context.Context, it storage.Iterator) error {
+		var item storage.ListItem
+
+		// iterate over every segment in the range
+	nextSegment:
+		for it.Next(ctx, &item) {
+			if !rng.contains(item.Key) {
+				return nil
+			}
+
+			if err := rateLimiter.Wait(ctx); err != nil {
+				// The ranges share the limiter, but each of them waits for a
+				// single event, so we should never exceed the burst size of 1
+				// and this should never happen.
+				// We can also enter here if the context is cancelled.
+				return LoopError.Wrap(err)
+			}
+
+			rawPath := item.Key.String()
+			pointer := &pb.Pointer{}
+
+			err := pb.Unmarshal(item.Value, pointer)
+			if err != nil {
+				return LoopError.New("unexpected error unmarshalling pointer %s", err)
+			}
+
+			pathElements := storj.SplitPath(rawPath)
+
+			if len(pathElements) < 4 {
+				// We skip this path because it belongs to bucket metadata, not to an
+				// actual object
+				continue nextSegment
+			}
+
+			isLastSegment := pathElements[1] == "l"
+
+			path := ScopedPath{
+				Raw:                 rawPath,
+				ProjectIDString:     pathElements[0],
+				Segment:             pathElements[1],
+				BucketName:          pathElements[2],
+				EncryptedObjectPath: storj.JoinPaths(pathElements[3:]...),
+			}
+
+			path.ProjectID, err = uuid.FromString(path.ProjectIDString)
+			if err != nil {
+				return LoopError.Wrap(err)
+			}
+
+			nextObservers := observers[:0]
+			for _, observer := range observers {
+				// the observer may have failed in another range.
+				if observer.Failed() {
+					continue
+				}
+				keepObserver := handlePointer(ctx, observer, path, isLastSegment, pointer)
+				if keepObserver {
+					nextObservers = append(nextObservers, observer)
+				}
+			}
+
+			observers = nextObservers
+			if len(observers) == 0 {
+				return nil
+			}
+
+			// if context has been canceled exit. Otherwise, continue
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		return nil
+	})
+}
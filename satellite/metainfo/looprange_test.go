@@ -0,0 +1,524 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/errs2"
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+// TestLoopRanges does the following:
+// * put 2 segments of 4 objects in each of 32 projects spread over the keyspace
+// * run the metainfo loop with different numbers of ranges and parallelism
+// * expect that a partial observer sees every segment exactly once
+// * expect that the ranges are forked only when the pass is parallel
+// * expect that the pass is sequential when an observer isn't partial
+func TestLoopRanges(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	fillLoopRanges(ctx, t, db, 32, 4)
+
+	for _, tt := range []struct {
+		ranges      int
+		parallelism int
+	}{
+		{ranges: 1, parallelism: 1},
+		{ranges: 1, parallelism: 4},
+		{ranges: 16, parallelism: 1},
+		{ranges: 16, parallelism: 4},
+		{ranges: 256, parallelism: 8},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("ranges=%d,parallelism=%d", tt.ranges, tt.parallelism), func(t *testing.T) {
+			config := metainfo.LoopConfig{
+				ListLimit:   5,
+				Ranges:      tt.ranges,
+				Parallelism: tt.parallelism,
+			}
+
+			partial := newTestPartialObserver(nil)
+			runLoop(ctx, t, config, db, nil, partial)
+
+			assert.EqualValues(t, 128, partial.objectCount)
+			assert.EqualValues(t, 128, partial.remoteSegCount)
+			assert.EqualValues(t, 128, partial.inlineSegCount)
+			assert.EqualValues(t, 256, len(partial.uniquePaths))
+
+			if tt.ranges > 1 && tt.parallelism > 1 {
+				assert.EqualValues(t, tt.ranges, partial.forks())
+			} else {
+				assert.EqualValues(t, 0, partial.forks())
+			}
+
+			partial = newTestPartialObserver(nil)
+			sequential := newTestObserver(nil)
+			runLoop(ctx, t, config, db, nil, partial, sequential)
+
+			for _, obs := range []*testObserver{partial.testObserver, sequential} {
+				assert.EqualValues(t, 128, obs.objectCount)
+				assert.EqualValues(t, 128, obs.remoteSegCount)
+				assert.EqualValues(t, 128, obs.inlineSegCount)
+				assert.EqualValues(t, 256, len(obs.uniquePaths))
+			}
+			assert.EqualValues(t, 0, partial.forks())
+		})
+	}
+}
+
+// TestLoopRangesObserverError does the following:
+// * run a parallel pass with a partial observer which fails in one range
+// * expect that the failing observer gets the error
+// * expect that another partial observer sees every segment
+func TestLoopRangesObserverError(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	fillLoopRanges(ctx, t, db, 32, 4)
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{
+		ListLimit:   5,
+		Ranges:      16,
+		Parallelism: 4,
+	}, db, nil)
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var loopGroup errgroup.Group
+	loopGroup.Go(func() error {
+		err := metaLoop.Run(loopCtx)
+		if !errs2.IsCanceled(err) {
+			return errors.New("expected context canceled")
+		}
+		return nil
+	})
+
+	good := newTestPartialObserver(nil)
+	var once int64
+	failing := newTestPartialObserver(func(ctx context.Context) error {
+		if atomic.AddInt64(&once, 1) == 1 {
+			return errors.New("test error")
+		}
+		return nil
+	})
+
+	var group errgroup.Group
+	group.Go(func() error {
+		return metaLoop.Join(ctx, good)
+	})
+	group.Go(func() error {
+		err := metaLoop.Join(ctx, failing)
+		if err == nil {
+			return errors.New("got no error")
+		}
+		if err.Error() != "test error" {
+			return fmt.Errorf("expected test error, got %v", err)
+		}
+		return nil
+	})
+	require.NoError(t, group.Wait())
+
+	cancel()
+	require.NoError(t, loopGroup.Wait())
+	require.NoError(t, metaLoop.Close())
+
+	assert.EqualValues(t, 128, good.objectCount)
+	assert.EqualValues(t, 128, good.remoteSegCount)
+	assert.EqualValues(t, 128, good.inlineSegCount)
+}
+
+// TestLoopRangesDistributed does the following:
+// * run a parallel pass while another process holds the leases of the odd ranges
+// * expect that a distributed observer sees only the segments in the even ranges
+// * expect that other observers see every segment
+// * expect that the claimed ranges are finished
+func TestLoopRangesDistributed(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	fillLoopRanges(ctx, t, db, 32, 4)
+
+	rangesDB := newTestLoopRanges(func(index int) bool {
+		return index%2 == 0
+	})
+
+	config := metainfo.LoopConfig{
+		ListLimit:   5,
+		Ranges:      4,
+		Parallelism: 2,
+		RangeLease:  time.Hour,
+	}
+
+	distributed := newTestDistributedObserver("distributed")
+	partial := newTestPartialObserver(nil)
+	runLoop(ctx, t, config, db, rangesDB, distributed, partial)
+
+	// the projects are spread evenly, so the even ranges contain half of the segments.
+	assert.EqualValues(t, 64, distributed.objectCount)
+	assert.EqualValues(t, 64, distributed.remoteSegCount)
+	assert.EqualValues(t, 64, distributed.inlineSegCount)
+	for _, path := range distributed.uniquePaths {
+		first, err := strconv.ParseUint(path.ProjectIDString[:2], 16, 8)
+		require.NoError(t, err)
+		assert.True(t, first < 64 || (first >= 128 && first < 192), path.Raw)
+	}
+
+	assert.EqualValues(t, 128, partial.objectCount)
+	assert.EqualValues(t, 128, partial.remoteSegCount)
+	assert.EqualValues(t, 128, partial.inlineSegCount)
+
+	assert.Equal(t, []int{0, 2}, rangesDB.finishedRanges("distributed"))
+
+	// without a ranges database the distributed observer sees every segment.
+	distributed = newTestDistributedObserver("distributed")
+	runLoop(ctx, t, config, db, nil, distributed)
+	assert.EqualValues(t, 128, distributed.objectCount)
+}
+
+// TestLoopRangesDistributedObserverSets does the following:
+// * run the passes of two processes concurrently with different distributed observers
+// * the first process runs the "shared" and "single" observers, the second only "shared"
+// * expect that the "shared" observers see every segment once between the processes
+// * expect that the "single" observer sees every segment
+// * expect that every range is finished for both observers
+func TestLoopRangesDistributedObserverSets(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	fillLoopRanges(ctx, t, db, 32, 4)
+
+	rangesDB := newTestLoopRanges(func(index int) bool { return true })
+
+	config := metainfo.LoopConfig{
+		ListLimit:   5,
+		Ranges:      8,
+		Parallelism: 2,
+		RangeLease:  time.Hour,
+	}
+
+	first := metainfo.NewLoop(config, db, rangesDB)
+	second := metainfo.NewLoop(config, db, rangesDB)
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var group errgroup.Group
+	for _, metaLoop := range []*metainfo.Loop{first, second} {
+		metaLoop := metaLoop
+		group.Go(func() error {
+			err := metaLoop.Run(loopCtx)
+			if !errs2.IsCanceled(err) {
+				return errors.New("expected context canceled")
+			}
+			return nil
+		})
+	}
+
+	firstShared := newTestDistributedObserver("shared")
+	firstSingle := newTestDistributedObserver("single")
+	secondShared := newTestDistributedObserver("shared")
+
+	var joins errgroup.Group
+	joins.Go(func() error { return first.Join(ctx, firstShared, firstSingle) })
+	joins.Go(func() error { return second.Join(ctx, secondShared) })
+	require.NoError(t, joins.Wait())
+
+	cancel()
+	require.NoError(t, group.Wait())
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+
+	// the other process doesn't run the single observer, so it isn't skipped in any range.
+	assert.EqualValues(t, 128, firstSingle.objectCount)
+	assert.EqualValues(t, 128, firstSingle.remoteSegCount)
+	assert.EqualValues(t, 128, firstSingle.inlineSegCount)
+
+	assert.EqualValues(t, 128, firstShared.objectCount+secondShared.objectCount)
+	assert.EqualValues(t, 128, firstShared.remoteSegCount+secondShared.remoteSegCount)
+	assert.EqualValues(t, 128, firstShared.inlineSegCount+secondShared.inlineSegCount)
+	for raw := range secondShared.uniquePaths {
+		_, ok := firstShared.uniquePaths[raw]
+		assert.False(t, ok, "path %s observed by both processes", raw)
+	}
+
+	allRanges := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	assert.Equal(t, allRanges, rangesDB.finishedRanges("shared"))
+	assert.Equal(t, allRanges, rangesDB.finishedRanges("single"))
+}
+
+// BenchmarkLoopParallelism measures a pass of the metainfo loop over a
+// pointer database which has a latency for every batch of listed items.
+func BenchmarkLoopParallelism(b *testing.B) {
+	ctx := testcontext.New(b)
+	defer ctx.Cleanup()
+
+	db := &slowPointerDB{
+		PointerDB: teststore.New(),
+		latency:   time.Millisecond,
+	}
+	fillLoopRanges(ctx, b, db, 256, 16)
+
+	for _, parallelism := range []int{1, 2, 4, 8, 16} {
+		parallelism := parallelism
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			config := metainfo.LoopConfig{
+				ListLimit:   100,
+				Ranges:      64,
+				Parallelism: parallelism,
+			}
+
+			metaLoop := metainfo.NewLoop(config, db, nil)
+
+			loopCtx, cancel := context.WithCancel(ctx)
+			var loopGroup errgroup.Group
+			loopGroup.Go(func() error {
+				_ = metaLoop.Run(loopCtx)
+				return nil
+			})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := metaLoop.Join(ctx, metainfo.NullObserver{})
+				require.NoError(b, err)
+			}
+			b.StopTimer()
+
+			cancel()
+			require.NoError(b, loopGroup.Wait())
+			require.NoError(b, metaLoop.Close())
+		})
+	}
+}
+
+// runLoop runs a single pass of a new metainfo loop with the observers.
+func runLoop(ctx context.Context, t require.TestingT, config metainfo.LoopConfig, db metainfo.PointerDB, rangesDB metainfo.LoopRangesDB, observers ...metainfo.Observer) {
+	metaLoop := metainfo.NewLoop(config, db, rangesDB)
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var group errgroup.Group
+	group.Go(func() error {
+		err := metaLoop.Run(loopCtx)
+		if !errs2.IsCanceled(err) {
+			return errors.New("expected context canceled")
+		}
+		return nil
+	})
+
+	require.NoError(t, metaLoop.Join(ctx, observers...))
+
+	cancel()
+	require.NoError(t, group.Wait())
+	require.NoError(t, metaLoop.Close())
+}
+
+// fillLoopRanges puts an inline and a remote segment of objectCount objects
+// in each of projectCount projects, whose IDs are spread evenly over the
+// keyspace, and the metadata of their buckets.
+func fillLoopRanges(ctx context.Context, t require.TestingT, db metainfo.PointerDB, projectCount, objectCount int) {
+	remote, err := pb.Marshal(&pb.Pointer{Type: pb.Pointer_REMOTE})
+	require.NoError(t, err)
+	inline, err := pb.Marshal(&pb.Pointer{Type: pb.Pointer_INLINE})
+	require.NoError(t, err)
+
+	for i := 0; i < projectCount; i++ {
+		var projectID uuid.UUID
+		projectID[0] = byte(i * 256 / projectCount)
+		projectID[15] = byte(i)
+
+		// bucket metadata isn't passed to the observers.
+		err := db.Put(ctx, storage.Key(projectID.String()+"/l/bucket"), inline)
+		require.NoError(t, err)
+
+		for k := 0; k < objectCount; k++ {
+			object := fmt.Sprintf("bucket/object-%d", k)
+			err := db.Put(ctx, storage.Key(projectID.String()+"/s0/"+object), remote)
+			require.NoError(t, err)
+			err = db.Put(ctx, storage.Key(projectID.String()+"/l/"+object), inline)
+			require.NoError(t, err)
+		}
+	}
+}
+
+// testPartialObserver is a testObserver which can observe the ranges of the loop concurrently.
+type testPartialObserver struct {
+	*testObserver
+	forkCount *int64
+}
+
+func newTestPartialObserver(onSegment func(context.Context) error) *testPartialObserver {
+	return &testPartialObserver{
+		testObserver: newTestObserver(onSegment),
+		forkCount:    new(int64),
+	}
+}
+
+func (obs *testPartialObserver) forks() int64 { return atomic.LoadInt64(obs.forkCount) }
+
+func (obs *testPartialObserver) Fork(ctx context.Context) (metainfo.Observer, error) {
+	atomic.AddInt64(obs.forkCount, 1)
+	return &testPartialObserver{
+		testObserver: newTestObserver(obs.onSegment),
+		forkCount:    obs.forkCount,
+	}, nil
+}
+
+func (obs *testPartialObserver) Merge(ctx context.Context, partial metainfo.Observer) error {
+	other := partial.(*testPartialObserver)
+	obs.objectCount += other.objectCount
+	obs.remoteSegCount += other.remoteSegCount
+	obs.inlineSegCount += other.inlineSegCount
+	for raw, path := range other.uniquePaths {
+		if _, ok := obs.uniquePaths[raw]; ok {
+			return fmt.Errorf("path %s observed in multiple ranges", raw)
+		}
+		obs.uniquePaths[raw] = path
+	}
+	return nil
+}
+
+// testDistributedObserver is a partial observer which observes only the ranges claimed by the loop.
+type testDistributedObserver struct {
+	*testPartialObserver
+	name string
+}
+
+func newTestDistributedObserver(name string) *testDistributedObserver {
+	return &testDistributedObserver{
+		testPartialObserver: newTestPartialObserver(nil),
+		name:                name,
+	}
+}
+
+func (obs *testDistributedObserver) Fork(ctx context.Context) (metainfo.Observer, error) {
+	partial, err := obs.testPartialObserver.Fork(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &testDistributedObserver{
+		testPartialObserver: partial.(*testPartialObserver),
+		name:                obs.name,
+	}, nil
+}
+
+func (obs *testDistributedObserver) Merge(ctx context.Context, partial metainfo.Observer) error {
+	return obs.testPartialObserver.Merge(ctx, partial.(*testDistributedObserver).testPartialObserver)
+}
+
+func (obs *testDistributedObserver) Distributed() bool { return true }
+
+func (obs *testDistributedObserver) DistributedName() string { return obs.name }
+
+// testLoopRanges is a metainfo.LoopRangesDB for a single pass, where the ranges
+// not claimable are leased by another process.
+type testLoopRanges struct {
+	claimable func(index int) bool
+
+	mu       sync.Mutex
+	owners   map[testLoopRange]uuid.UUID
+	finished map[string][]int
+}
+
+// testLoopRange identifies the range of an observer.
+type testLoopRange struct {
+	observer string
+	index    int
+}
+
+func newTestLoopRanges(claimable func(index int) bool) *testLoopRanges {
+	return &testLoopRanges{
+		claimable: claimable,
+		owners:    map[testLoopRange]uuid.UUID{},
+		finished:  map[string][]int{},
+	}
+}
+
+func (ranges *testLoopRanges) Claim(ctx context.Context, observer string, index int, owner uuid.UUID, passStarted time.Time, lease time.Duration) (bool, error) {
+	if !ranges.claimable(index) {
+		return false, nil
+	}
+
+	ranges.mu.Lock()
+	defer ranges.mu.Unlock()
+
+	// a range is claimed only once during the pass.
+	key := testLoopRange{observer: observer, index: index}
+	if _, ok := ranges.owners[key]; ok {
+		return false, nil
+	}
+	ranges.owners[key] = owner
+	return true, nil
+}
+
+func (ranges *testLoopRanges) Finish(ctx context.Context, observer string, index int, owner uuid.UUID, finishedAt time.Time) error {
+	ranges.mu.Lock()
+	defer ranges.mu.Unlock()
+
+	if ranges.owners[testLoopRange{observer: observer, index: index}] != owner {
+		return fmt.Errorf("range %d of %s finished by another owner", index, observer)
+	}
+
+	// keep the finished ranges sorted for comparing.
+	finished := ranges.finished[observer]
+	i := 0
+	for i < len(finished) && finished[i] < index {
+		i++
+	}
+	ranges.finished[observer] = append(finished[:i], append([]int{index}, finished[i:]...)...)
+	return nil
+}
+
+func (ranges *testLoopRanges) Release(ctx context.Context, observer string, index int, owner uuid.UUID) error {
+	return errors.New("unexpected release")
+}
+
+func (ranges *testLoopRanges) finishedRanges(observer string) []int {
+	ranges.mu.Lock()
+	defer ranges.mu.Unlock()
+	return append([]int(nil), ranges.finished[observer]...)
+}
+
+// slowPointerDB is a pointer database which waits for latency before
+// returning each batch of listed items.
+type slowPointerDB struct {
+	metainfo.PointerDB
+	latency time.Duration
+}
+
+func (db *slowPointerDB) IterateWithoutLookupLimit(ctx context.Context, opts storage.IterateOptions, fn func(context.Context, storage.Iterator) error) error {
+	return db.PointerDB.IterateWithoutLookupLimit(ctx, opts, func(ctx context.Context, it storage.Iterator) error {
+		listed := 0
+		return fn(ctx, storage.IteratorFunc(func(ctx context.Context, item *storage.ListItem) bool {
+			if listed%opts.Limit == 0 {
+				time.Sleep(db.latency)
+			}
+			listed++
+			return it.Next(ctx, item)
+		}))
+	})
+}
@@ -31,9 +31,11 @@ import (
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/metainfo/piecedeletion"
 	"storj.io/storj/satellite/metainfo/pointerverification"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/satellite/rewards"
+	"storj.io/storj/storage"
 	"storj.io/uplink/private/eestream"
 	"storj.io/uplink/private/storage/meta"
 )
@@ -83,9 +85,12 @@ type Endpoint struct {
 	projectUsage         *accounting.Service
 	projects             console.Projects
 	apiKeys              APIKeys
+	requestCounter       *RequestCounter
 	createRequests       *createRequests
 	satellite            signing.Signer
 	limiterCache         *lrucache.ExpiringLRU
+	keyLimiterCache      *lrucache.ExpiringLRU
+	bucketCache          *lrucache.ExpiringLRU
 	encInlineSegmentSize int64 // max inline segment size + encryption overhead
 	config               Config
 }
@@ -94,8 +99,8 @@ type Endpoint struct {
 func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion.Service,
 	orders *orders.Service, cache *overlay.Service, attributions attribution.DB,
 	partners *rewards.PartnersService, peerIdentities overlay.PeerIdentities,
-	apiKeys APIKeys, projectUsage *accounting.Service, projects console.Projects,
-	satellite signing.Signer, config Config) (*Endpoint, error) {
+	apiKeys APIKeys, requestCounter *RequestCounter, projectUsage *accounting.Service,
+	projects console.Projects, satellite signing.Signer, config Config) (*Endpoint, error) {
 	// TODO do something with too many params
 
 	encInlineSegmentSize, err := encryption.CalcEncryptedSize(config.MaxInlineSegmentSize.Int64(), storj.EncryptionParameters{
@@ -115,6 +120,7 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion
 		partners:            partners,
 		pointerVerification: pointerverification.NewService(peerIdentities),
 		apiKeys:             apiKeys,
+		requestCounter:      requestCounter,
 		projectUsage:        projectUsage,
 		projects:            projects,
 		createRequests:      newCreateRequests(),
@@ -123,6 +129,14 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion
 			Capacity:   config.RateLimiter.CacheCapacity,
 			Expiration: config.RateLimiter.CacheExpiration,
 		}),
+		keyLimiterCache: lrucache.New(lrucache.Options{
+			Capacity:   config.RateLimiter.KeyCacheCapacity,
+			Expiration: config.RateLimiter.KeyCacheExpiration,
+		}),
+		bucketCache: lrucache.New(lrucache.Options{
+			Capacity:   config.BucketCache.Capacity,
+			Expiration: config.BucketCache.Expiration,
+		}),
 		encInlineSegmentSize: encInlineSegmentSize,
 		config:               config,
 	}, nil
@@ -181,7 +195,12 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid expiration time")
 	}
 
-	err = endpoint.validateRedundancy(ctx, req.Redundancy)
+	profile, err := endpoint.bucketRSProfile(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	err = endpoint.validateRedundancy(ctx, req.Redundancy, profile)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
@@ -205,8 +224,14 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 
 	maxPieceSize := eestream.CalcPieceSize(req.GetMaxEncryptedSegmentSize(), redundancy)
 
+	placement, err := endpoint.bucketPlacement(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: int(req.Redundancy.Total),
+		Placement:      placement,
 	}
 	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
@@ -229,6 +254,126 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 	return &pb.SegmentWriteResponseOld{AddressedLimits: addressedLimits, RootPieceId: rootPieceID, PrivateKey: piecePrivateKey}, nil
 }
 
+// bucketPlacement returns the placement of the bucket, which new segments must honor.
+func (endpoint *Endpoint) bucketPlacement(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ nodeselection.Placement, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucketName)
+	if storj.ErrBucketNotFound.Has(err) {
+		// the old api allows uploading before the bucket is created.
+		return nil, nil
+	}
+	return settings.Placement, err
+}
+
+// getBucketSettings returns the settings of the bucket. The settings are cached,
+// so the segments of an upload or download don't cost a bucket query each.
+func (endpoint *Endpoint) getBucketSettings(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ BucketSettings, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.bucketCache.Get(bucketCacheKey(projectID, bucketName), func() (interface{}, error) {
+		return endpoint.metainfo.GetBucketSettings(ctx, bucketName, projectID)
+	})
+	if err != nil {
+		return BucketSettings{}, err
+	}
+	return settings.(BucketSettings), nil
+}
+
+// bucketCacheKey returns the key of the bucket in the bucket cache.
+func bucketCacheKey(projectID uuid.UUID, bucketName []byte) string {
+	return projectID.String() + "/" + string(bucketName)
+}
+
+// checkBucketStorageLimits returns an error when the bucket is over its storage limit or,
+// for new objects, at its object limit.
+func (endpoint *Endpoint) checkBucketStorageLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte, newObject bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucketName)
+	if err != nil {
+		if !storj.ErrBucketNotFound.Has(err) {
+			endpoint.log.Error("Retrieving bucket limits failed.", zap.Error(err))
+		}
+		return nil
+	}
+	limits := settings.Limits
+
+	exceeded, err := endpoint.projectUsage.ExceedsBucketStorageUsage(ctx, projectID, bucketName, limits, newObject)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket storage totals failed.", zap.Error(err))
+	}
+	if exceeded {
+		endpoint.log.Error("Bucket storage limit exceeded.",
+			zap.Stringer("Limit", limits.Storage),
+			zap.Int64("Object Limit", limits.Objects),
+			zap.Stringer("Project ID", projectID),
+			zap.ByteString("Bucket", bucketName),
+		)
+		return rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Bucket Usage Limit")
+	}
+	return nil
+}
+
+// checkBucketBandwidthLimit returns an error when the bucket is over its monthly bandwidth limit.
+func (endpoint *Endpoint) checkBucketBandwidthLimit(ctx context.Context, projectID uuid.UUID, bucketName []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucketName)
+	if err != nil {
+		if !storj.ErrBucketNotFound.Has(err) {
+			endpoint.log.Error("Retrieving bucket limits failed.", zap.Error(err))
+		}
+		return nil
+	}
+	limits := settings.Limits
+
+	exceeded, err := endpoint.projectUsage.ExceedsBucketBandwidthUsage(ctx, projectID, bucketName, limits)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket bandwidth total failed.", zap.Error(err))
+	}
+	if exceeded {
+		endpoint.log.Error("Monthly bucket bandwidth limit exceeded.",
+			zap.Stringer("Limit", limits.Bandwidth),
+			zap.Stringer("Project ID", projectID),
+			zap.ByteString("Bucket", bucketName),
+		)
+		return rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Bucket Usage Limit")
+	}
+	return nil
+}
+
+// replacesObject returns true when committing an object at the path replaces the current
+// object instead of adding one, because the bucket doesn't keep noncurrent versions.
+func (endpoint *Endpoint) replacesObject(ctx context.Context, projectID uuid.UUID, bucketName, encryptedPath []byte) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucketName)
+	if err != nil || settings.Versioning {
+		return false, err
+	}
+
+	_, _, err = endpoint.getPointer(ctx, projectID, lastSegment, bucketName, encryptedPath)
+	if err != nil {
+		if errs2.IsRPC(err, rpcstatus.NotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// addBucketUsage lets the live accounting know about new storage or objects in the bucket.
+func (endpoint *Endpoint) addBucketUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, usage accounting.BucketLiveUsage) {
+	if err := endpoint.projectUsage.AddBucketUsage(ctx, projectID, bucketName, usage); err != nil {
+		endpoint.log.Error("Could not track new usage by bucket",
+			zap.Stringer("Project ID", projectID),
+			zap.Error(err),
+		)
+		// but continue. the only thing that will be affected is the bucket limits.
+	}
+}
+
 func calculateSpaceUsed(ptr *pb.Pointer) (segmentSize, totalStored int64) {
 	inline := ptr.GetInlineSegment()
 	if inline != nil {
@@ -297,6 +442,18 @@ func (endpoint *Endpoint) CommitSegmentOld(ctx context.Context, req *pb.SegmentC
 		piece.Hash = nil
 	}
 	req.Pointer.PieceHashesVerified = true
+	// the lock is only set by the satellite
+	req.Pointer.XXX_unrecognized = nil
+
+	existing, err := endpoint.metainfo.Get(ctx, path)
+	if err != nil && !storj.ErrObjectNotFound.Has(err) {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if err == nil {
+		if err := checkNotLocked(existing, path); err != nil {
+			return nil, err
+		}
+	}
 
 	segmentSize, totalStored := calculateSpaceUsed(req.Pointer)
 
@@ -321,6 +478,12 @@ func (endpoint *Endpoint) CommitSegmentOld(ctx context.Context, req *pb.SegmentC
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
 
+	err = endpoint.lockNewSegment(ctx, keyInfo.ProjectID, req.Bucket, req.Pointer, time.Now())
+	if err != nil {
+		endpoint.log.Error("unable to lock segment", zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	err = endpoint.metainfo.UnsynchronizedPut(ctx, path, req.Pointer)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
@@ -442,13 +605,20 @@ func (endpoint *Endpoint) DeleteSegmentOld(ctx context.Context, req *pb.SegmentD
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
+	if err := checkNotLocked(pointer, path); err != nil {
+		return nil, err
+	}
+
 	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
 
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+	// pieces shared with copies of the segment must not be deleted.
+	piecesShared := endpoint.metainfo.ReleasePieces(ctx, path, pointer)
+
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !piecesShared {
 		bucketID := createBucketID(keyInfo.ProjectID, req.Bucket)
 		limits, privateKey, err := endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
 		if err != nil {
@@ -480,7 +650,7 @@ func (endpoint *Endpoint) ListSegmentsOld(ctx context.Context, req *pb.ListSegme
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
-	items, more, err := endpoint.metainfo.List(ctx, prefix, string(req.StartAfter), req.Recursive, req.Limit, req.MetaFlags)
+	items, more, err := endpoint.listCurrentObjects(ctx, prefix, string(req.StartAfter), req.Recursive, req.Limit, req.MetaFlags)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
@@ -559,6 +729,8 @@ func (endpoint *Endpoint) filterValidPieces(ctx context.Context, pointer *pb.Poi
 		return rpcstatus.Error(rpcstatus.InvalidArgument, errMsg)
 	}
 
+	endpoint.overlay.RecordUploads(ctx, originalLimits, validPieces)
+
 	remote.RemotePieces = validPieces
 
 	return nil
@@ -627,8 +799,13 @@ func (endpoint *Endpoint) GetBucket(ctx context.Context, req *pb.BucketGetReques
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
+	rs, err := endpoint.bucketRedundancyScheme(ctx, keyInfo.ProjectID, req.GetName())
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	// override RS to fit satellite settings
-	convBucket, err := convertBucketToProto(ctx, bucket, endpoint.redundancyScheme())
+	convBucket, err := convertBucketToProto(ctx, bucket, rs)
 	if err != nil {
 		return resp, err
 	}
@@ -919,8 +1096,10 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, storj.ErrNoBucket.New("").Error())
 	}
 
-	// TODO this needs to be optimized to avoid DB call on each request
-	_, err = endpoint.metainfo.GetBucket(ctx, req.Bucket, keyInfo.ProjectID)
+	// the settings are read once for every new object, so the cache doesn't hide a deleted
+	// bucket, and the segments of the object use the cached settings.
+	endpoint.bucketCache.Delete(bucketCacheKey(keyInfo.ProjectID, req.Bucket))
+	settings, err := endpoint.getBucketSettings(ctx, keyInfo.ProjectID, req.Bucket)
 	if err != nil {
 		if storj.ErrBucketNotFound.Has(err) {
 			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
@@ -930,12 +1109,29 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
+	// an object which replaces the current one doesn't count against the object limit,
+	// so the current object is only looked up for buckets with an object limit.
+	replacesObject := false
+	if settings.Limits.Objects > 0 {
+		replacesObject, err = endpoint.replacesObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+		if err != nil {
+			endpoint.log.Error("Retrieving current object failed.", zap.Error(err))
+		}
+	}
+
+	if err := endpoint.checkBucketStorageLimits(ctx, keyInfo.ProjectID, req.Bucket, !replacesObject); err != nil {
+		return nil, err
+	}
+
 	if err := endpoint.ensureAttribution(ctx, req.Header, req.Bucket); err != nil {
 		return nil, err
 	}
 
 	// use only satellite values for Redundancy Scheme
-	pbRS := endpoint.redundancyScheme()
+	pbRS, err := endpoint.bucketRedundancyScheme(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
 
 	streamID, err := endpoint.packStreamID(ctx, &pb.SatStreamID{
 		Bucket:         req.Bucket,
@@ -949,10 +1145,14 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	err = endpoint.deleteOrPreserveObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
 	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
 		return nil, err
 	}
+	if err == nil && replacesObject {
+		// committing the new object counts it again.
+		endpoint.addBucketUsage(ctx, keyInfo.ProjectID, req.Bucket, accounting.BucketLiveUsage{Objects: -1})
+	}
 
 	endpoint.log.Info("Object Upload", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "put"), zap.String("type", "object"))
 	mon.Meter("req_put_object").Mark(1)
@@ -1050,11 +1250,18 @@ func (endpoint *Endpoint) commitObject(ctx context.Context, req *pb.ObjectCommit
 		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
 	}
 
+	err = endpoint.lockNewSegment(ctx, keyInfo.ProjectID, streamID.Bucket, lastSegmentPointer, streamID.CreationDate)
+	if err != nil {
+		endpoint.log.Error("unable to lock segment", zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
+	}
+
 	err = endpoint.metainfo.UnsynchronizedPut(ctx, lastSegmentPath, lastSegmentPointer)
 	if err != nil {
 		endpoint.log.Error("unable to put pointer", zap.Error(err))
 		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
 	}
+	endpoint.addBucketUsage(ctx, keyInfo.ProjectID, streamID.Bucket, accounting.BucketLiveUsage{Objects: 1})
 
 	return &pb.ObjectCommitResponse{}, nil
 }
@@ -1092,7 +1299,9 @@ func (endpoint *Endpoint) GetObject(ctx context.Context, req *pb.ObjectGetReques
 }
 
 func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (*pb.Object, error) {
-	pointer, _, err := endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	objectPath := objectVersionPath(encryptedPath, version)
+
+	pointer, _, err := endpoint.getPointer(ctx, projectID, lastSegment, bucket, objectPath)
 	if err != nil {
 		return nil, err
 	}
@@ -1126,6 +1335,9 @@ func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bu
 			BlockSize:   int64(streamMeta.EncryptionBlockSize),
 		},
 	}
+	if version > 0 {
+		object.Version = version
+	}
 
 	if pointer.Remote != nil {
 		object.RedundancyScheme = pointer.Remote.Redundancy
@@ -1142,7 +1354,7 @@ func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bu
 
 		index := int64(0)
 		for {
-			path, err := CreatePath(ctx, projectID, index, bucket, encryptedPath)
+			path, err := CreatePath(ctx, projectID, index, bucket, objectPath)
 			if err != nil {
 				endpoint.log.Error("unable to get pointer path", zap.Error(err))
 				return nil, rpcstatus.Error(rpcstatus.Internal, "unable to get object")
@@ -1205,7 +1417,7 @@ func (endpoint *Endpoint) ListObjects(ctx context.Context, req *pb.ObjectListReq
 
 	metaflags := meta.All
 	// TODO use flags
-	segments, more, err := endpoint.metainfo.List(ctx, prefix, string(req.EncryptedCursor), req.Recursive, req.Limit, metaflags)
+	segments, more, err := endpoint.listCurrentObjects(ctx, prefix, string(req.EncryptedCursor), req.Recursive, req.Limit, metaflags)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
@@ -1298,7 +1510,11 @@ func (endpoint *Endpoint) BeginDeleteObject(ctx context.Context, req *pb.ObjectB
 		}
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+	if satStreamID.Version > 0 {
+		err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, satStreamID.Bucket, streamObjectPath(satStreamID))
+	} else {
+		err = endpoint.deleteOrPreserveObject(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+	}
 	if err != nil {
 		if !canRead && !canList {
 			// No error info is returned if neither Read, nor List permission is granted
@@ -1387,6 +1603,10 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
+	if err := endpoint.checkBucketStorageLimits(ctx, keyInfo.ProjectID, streamID.Bucket, false); err != nil {
+		return nil, err
+	}
+
 	redundancy, err := eestream.NewRedundancyStrategyFromProto(streamID.Redundancy)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
@@ -1394,8 +1614,14 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 
 	maxPieceSize := eestream.CalcPieceSize(req.MaxOrderLimit, redundancy)
 
+	placement, err := endpoint.bucketPlacement(ctx, keyInfo.ProjectID, streamID.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: redundancy.TotalCount(),
+		Placement:      placement,
 	}
 	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
@@ -1558,6 +1784,7 @@ func (endpoint *Endpoint) commitSegment(ctx context.Context, req *pb.SegmentComm
 		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
+	endpoint.addBucketUsage(ctx, keyInfo.ProjectID, streamID.Bucket, accounting.BucketLiveUsage{Storage: segmentSize})
 
 	if savePointer {
 		path, err := CreatePath(ctx, keyInfo.ProjectID, int64(segmentID.Index), streamID.Bucket, streamID.EncryptedPath)
@@ -1565,6 +1792,11 @@ func (endpoint *Endpoint) commitSegment(ctx context.Context, req *pb.SegmentComm
 			return nil, nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 		}
 
+		err = endpoint.lockNewSegment(ctx, keyInfo.ProjectID, streamID.Bucket, pointer, streamID.CreationDate)
+		if err != nil {
+			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
 		err = endpoint.metainfo.UnsynchronizedPut(ctx, path, pointer)
 		if err != nil {
 			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
@@ -1629,6 +1861,7 @@ func (endpoint *Endpoint) makeInlineSegment(ctx context.Context, req *pb.Segment
 		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
+	endpoint.addBucketUsage(ctx, keyInfo.ProjectID, streamID.Bucket, accounting.BucketLiveUsage{Storage: inlineUsed})
 
 	metadata, err := pb.Marshal(&pb.SegmentMeta{
 		EncryptedKey: req.EncryptedKey,
@@ -1650,6 +1883,11 @@ func (endpoint *Endpoint) makeInlineSegment(ctx context.Context, req *pb.Segment
 			return nil, nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 		}
 
+		err = endpoint.lockNewSegment(ctx, keyInfo.ProjectID, streamID.Bucket, pointer, streamID.CreationDate)
+		if err != nil {
+			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
 		err = endpoint.metainfo.UnsynchronizedPut(ctx, path, pointer)
 		if err != nil {
 			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
@@ -1691,14 +1929,8 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		return nil, err
 	}
 
-	var limits []*pb.AddressedOrderLimit
-	var privateKey storj.PiecePrivateKey
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
-		bucketID := createBucketID(keyInfo.ProjectID, streamID.Bucket)
-		limits, privateKey, err = endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
-		if err != nil {
-			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
-		}
+	if err := checkNotLocked(pointer, path); err != nil {
+		return nil, err
 	}
 
 	// moved from FinishDeleteSegment to avoid inconsistency if someone will not
@@ -1708,6 +1940,19 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
+	// pieces shared with copies of the segment must not be deleted.
+	piecesShared := endpoint.metainfo.ReleasePieces(ctx, path, pointer)
+
+	var limits []*pb.AddressedOrderLimit
+	var privateKey storj.PiecePrivateKey
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !piecesShared {
+		bucketID := createBucketID(keyInfo.ProjectID, streamID.Bucket)
+		limits, privateKey, err = endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
 	segmentID, err := endpoint.packSegmentID(ctx, &pb.SatSegmentID{
 		StreamId:            streamID,
 		OriginalOrderLimits: limits,
@@ -1775,7 +2020,7 @@ func (endpoint *Endpoint) ListSegments(ctx context.Context, req *pb.SegmentListR
 		limit = listLimit
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamID.EncryptedPath)
+	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamObjectPath(streamID))
 	if err != nil {
 		if rpcstatus.Code(err) == rpcstatus.NotFound {
 			return &pb.SegmentListResponse{}, nil
@@ -1880,7 +2125,7 @@ func (endpoint *Endpoint) listSegmentsManually(ctx context.Context, projectID uu
 	more := false
 
 	for {
-		_, _, err := endpoint.getPointer(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath)
+		_, _, err := endpoint.getPointer(ctx, projectID, index, streamID.Bucket, streamObjectPath(streamID))
 		if err != nil {
 			if rpcstatus.Code(err) != rpcstatus.NotFound {
 				return nil, err
@@ -1951,7 +2196,11 @@ func (endpoint *Endpoint) DownloadSegment(ctx context.Context, req *pb.SegmentDo
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamID.EncryptedPath)
+	if err := endpoint.checkBucketBandwidthLimit(ctx, keyInfo.ProjectID, streamID.Bucket); err != nil {
+		return nil, err
+	}
+
+	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamObjectPath(streamID))
 	if err != nil {
 		return nil, err
 	}
@@ -2259,8 +2508,12 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 
 	if !lastSegmentNotFound {
 		// first delete the last segment
-		pointer, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+		pointer, piecesShared, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath)
 		if err != nil {
+			if ErrObjectLocked.Has(err) {
+				// all segments of an object share the lock, so nothing was deleted yet.
+				return rpcstatus.Error(rpcstatus.PermissionDenied, err.Error())
+			}
 			if storj.ErrObjectNotFound.Has(err) {
 				endpoint.log.Warn(
 					"unexpected not found error while deleting a pointer, it may have been deleted concurrently",
@@ -2280,7 +2533,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			}
 		}
 
-		if err == nil && pointer.Type == pb.Pointer_REMOTE {
+		if err == nil && pointer.Type == pb.Pointer_REMOTE && !piecesShared {
 			rootPieceID := pointer.GetRemote().RootPieceId
 			for _, piece := range pointer.GetRemote().GetRemotePieces() {
 				pieceID := rootPieceID.Derive(piece.NodeId, piece.PieceNum)
@@ -2297,7 +2550,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	}
 
 	for segmentIdx := prevLastSegmentIndex; segmentIdx >= 0; segmentIdx-- {
-		pointer, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath)
+		pointer, piecesShared, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath)
 		if err != nil {
 			segment := "s" + strconv.FormatInt(segmentIdx, 10)
 			if storj.ErrObjectNotFound.Has(err) {
@@ -2324,7 +2577,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			continue
 		}
 
-		if pointer.Type != pb.Pointer_REMOTE {
+		if pointer.Type != pb.Pointer_REMOTE || piecesShared {
 			continue
 		}
 
@@ -2372,29 +2625,40 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	return endpoint.deletePieces.Delete(ctx, requests, deleteObjectPiecesSuccessThreshold)
 }
 
-// deletePointer deletes a pointer returning the deleted pointer.
+// deletePointer deletes a pointer returning the deleted pointer and whether
+// its pieces are still used by copies of the segment.
 //
 // If the pointer isn't found when getting or deleting it, it returns
-// storj.ErrObjectNotFound error.
+// storj.ErrObjectNotFound error. Locked pointers aren't deleted and
+// ErrObjectLocked is returned, the pointer is only deleted when it didn't
+// change since its lock was checked.
 func (endpoint *Endpoint) deletePointer(
 	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte,
-) (_ *pb.Pointer, err error) {
+) (_ *pb.Pointer, piecesShared bool, err error) {
 	defer mon.Task()(&ctx, projectID, segmentIndex, bucket, encryptedPath)(&err)
 
-	pointer, path, err := endpoint.getPointer(ctx, projectID, segmentIndex, bucket, encryptedPath)
+	path, err := CreatePath(ctx, projectID, segmentIndex, bucket, encryptedPath)
 	if err != nil {
-		if errs2.IsRPC(err, rpcstatus.NotFound) {
-			return nil, storj.ErrObjectNotFound.New("%s", err.Error())
-		}
-		return nil, err
+		return nil, false, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
-	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
-	if err != nil {
-		return nil, err
-	}
+	for {
+		pointerBytes, pointer, err := endpoint.metainfo.GetWithBytes(ctx, path)
+		if err != nil {
+			return nil, false, err
+		}
 
-	return pointer, nil
+		// DeleteSegment checks the lock of the pointer it compares with.
+		piecesShared, err = endpoint.metainfo.DeleteSegment(ctx, path, pointerBytes)
+		if storage.ErrValueChanged.Has(err) {
+			// the pointer was updated concurrently, e.g. by repair or a lock change.
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return pointer, piecesShared, nil
+	}
 }
 
 // findIndexPreviousLastSegmentWhenNotKnowingNumSegments returns the index of
@@ -2424,6 +2688,49 @@ func (endpoint *Endpoint) findIndexPreviousLastSegmentWhenNotKnowingNumSegments(
 	return lastIdxFound, nil
 }
 
+// bucketRedundancyScheme returns the redundancy scheme of the bucket, which new segments must use.
+func (endpoint *Endpoint) bucketRedundancyScheme(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ *pb.RedundancyScheme, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	profile, err := endpoint.bucketRSProfile(ctx, projectID, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return endpoint.redundancyScheme(), nil
+	}
+	return profile.RedundancyScheme(endpoint.config.RS.ErasureShareSize.Int32()), nil
+}
+
+// bucketRSProfile returns the redundancy scheme profile of the bucket,
+// nil means the bucket uses the default redundancy scheme.
+func (endpoint *Endpoint) bucketRSProfile(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ *RSProfile, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucketName)
+	if storj.ErrBucketNotFound.Has(err) {
+		// the old api allows uploading before the bucket is created.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	name := settings.RSProfile
+	if name == "" {
+		return nil, nil
+	}
+
+	profile, ok := endpoint.config.RS.Profiles.Lookup(name)
+	if !ok {
+		endpoint.log.Warn("bucket uses an unknown redundancy scheme profile, using the default",
+			zap.Stringer("Project ID", projectID),
+			zap.String("Profile", name),
+		)
+		return nil, nil
+	}
+	return &profile, nil
+}
+
 func (endpoint *Endpoint) redundancyScheme() *pb.RedundancyScheme {
 	return &pb.RedundancyScheme{
 		Type:             pb.RedundancyScheme_RS,
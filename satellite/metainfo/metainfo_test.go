@@ -28,6 +28,7 @@ import (
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/console"
 	satMetainfo "storj.io/storj/satellite/metainfo"
 	"storj.io/uplink"
 	"storj.io/uplink/private/metainfo"
@@ -1248,6 +1249,67 @@ func TestRateLimit_Disabled(t *testing.T) {
 	})
 }
 
+func TestRateLimit_APIKey(t *testing.T) {
+	keyRateLimit := 2
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Metainfo.RateLimiter.Rate = 100
+				config.Metainfo.RateLimiter.KeyRate = float64(keyRateLimit)
+				config.Metainfo.RateLimiter.CacheExpiration = 500 * time.Millisecond
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		ul := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		// TODO find a way to reset limiter before test is executed, currently
+		// testplanet is doing one additional request to get access
+		time.Sleep(1 * time.Second)
+
+		var group errs2.Group
+		for i := 0; i <= keyRateLimit; i++ {
+			group.Go(func() error {
+				return ul.CreateBucket(ctx, satellite, testrand.BucketName())
+			})
+		}
+		groupErrs := group.Wait()
+		require.Len(t, groupErrs, 1)
+	})
+}
+
+func TestAPIKeyRequestCounts(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		ul := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+		projectID := ul.Projects[0].ID
+
+		satellite.API.Metainfo.RequestCounter.Loop.Pause()
+		satellite.API.Metainfo.RequestCounter.Flush(ctx)
+
+		getCount := func() int64 {
+			page, err := satellite.DB.Console().APIKeys().GetPagedByProjectID(ctx, projectID, console.APIKeyCursor{
+				Page:  1,
+				Limit: 10,
+			})
+			require.NoError(t, err)
+			require.Len(t, page.APIKeys, 1)
+			return page.APIKeys[0].RequestCount
+		}
+		before := getCount()
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, ul.CreateBucket(ctx, satellite, testrand.BucketName()))
+		}
+		satellite.API.Metainfo.RequestCounter.Flush(ctx)
+
+		require.GreaterOrEqual(t, getCount(), before+3)
+	})
+}
+
 func TestRateLimit_ProjectRateLimitOverride(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
@@ -1516,6 +1578,48 @@ func TestInlineSegmentThreshold(t *testing.T) {
 	})
 }
 
+func TestBucketRSProfile(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				require.NoError(t, config.Metainfo.RS.Profiles.Set("small:1/2/3/4"))
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		projectID := planet.Uplinks[0].Projects[0].ID
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, satellite, "small-bucket"))
+		require.NoError(t, satellite.Metainfo.Service.UpdateBucketRSProfile(ctx, []byte("small-bucket"), projectID, "small"))
+
+		for _, bucket := range []string{"small-bucket", "default-bucket"} {
+			err := planet.Uplinks[0].Upload(ctx, satellite, bucket, "object", testrand.Bytes(8*memory.KiB))
+			require.NoError(t, err)
+		}
+
+		expected := map[string]int32{
+			"small-bucket":   4,
+			"default-bucket": int32(satellite.Config.Metainfo.RS.TotalThreshold),
+		}
+		for bucket, total := range expected {
+			// we don't know encrypted path
+			prefix, err := satMetainfo.CreatePath(ctx, projectID, -1, []byte(bucket), []byte{})
+			require.NoError(t, err)
+
+			items, _, err := satellite.Metainfo.Service.List(ctx, prefix, "", false, 0, meta.All)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(items))
+
+			pointer, err := satellite.Metainfo.Service.Get(ctx, prefix+"/"+items[0].Path)
+			require.NoError(t, err)
+			require.Equal(t, pb.Pointer_REMOTE, pointer.Type)
+			require.Equal(t, total, pointer.Remote.Redundancy.Total, bucket)
+			require.True(t, len(pointer.Remote.RemotePieces) <= int(total), bucket)
+		}
+	})
+}
+
 // TestCommitObjectMetadataSize ensures that CommitObject returns an error when the metadata provided by the user is too large.
 func TestCommitObjectMetadataSize(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
@@ -0,0 +1,417 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/storage"
+)
+
+var (
+	// ErrObjectLocked is returned when a locked segment would be deleted or overwritten.
+	ErrObjectLocked = errs.Class("object locked")
+	// ErrInvalidObjectLock is returned when a lock can't be applied to an object.
+	ErrInvalidObjectLock = errs.Class("invalid object lock")
+)
+
+// objectLockField is the protobuf field number under which the lock is kept
+// in the unrecognized fields of a pointer, since the pointer message is
+// defined outside of this repository. The number is far above the fields of
+// the pointer, so it doesn't collide with future fields.
+const objectLockField = 1000
+
+// fields of the encoded object lock.
+const (
+	objectLockRetainUntilField = 1
+	objectLockLegalHoldField   = 2
+)
+
+// protobuf wire types used for skipping unrelated unrecognized fields.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ObjectLock prevents the segments of an object from being deleted or
+// overwritten until the retention period ended and the legal hold was
+// removed.
+//
+// The lock is stored in every segment pointer of the object, so each delete
+// path can check it without looking up the other segments. It's kept when
+// the object is moved, copied or preserved as a noncurrent version.
+type ObjectLock struct {
+	// RetainUntil is stored with second precision.
+	RetainUntil time.Time
+	LegalHold   bool
+}
+
+// IsZero returns whether the lock doesn't restrict anything.
+func (lock ObjectLock) IsZero() bool {
+	return lock.RetainUntil.IsZero() && !lock.LegalHold
+}
+
+// Locked returns whether the lock prevents deleting the object at now.
+func (lock ObjectLock) Locked(now time.Time) bool {
+	return lock.LegalHold || now.Before(lock.RetainUntil)
+}
+
+// GetObjectLock returns the lock stored in the pointer.
+func GetObjectLock(pointer *pb.Pointer) (lock ObjectLock, err error) {
+	err = walkFields(pointer.XXX_unrecognized, func(field, wireType uint64, value uint64, data []byte) error {
+		if field != objectLockField {
+			return nil
+		}
+		if wireType != wireBytes {
+			return Error.New("invalid lock wire type %d", wireType)
+		}
+
+		lock = ObjectLock{}
+		return walkFields(data, func(field, wireType uint64, value uint64, _ []byte) error {
+			if wireType != wireVarint {
+				return nil
+			}
+			switch field {
+			case objectLockRetainUntilField:
+				lock.RetainUntil = time.Unix(int64(value), 0).UTC()
+			case objectLockLegalHoldField:
+				lock.LegalHold = value != 0
+			}
+			return nil
+		})
+	})
+	return lock, err
+}
+
+// SetObjectLock stores the lock in the pointer, a zero lock removes it.
+func SetObjectLock(pointer *pb.Pointer, lock ObjectLock) error {
+	var unrecognized []byte
+	err := walkFields(pointer.XXX_unrecognized, func(field, wireType uint64, value uint64, data []byte) error {
+		if field == objectLockField {
+			return nil
+		}
+		unrecognized = appendField(unrecognized, field, wireType, value, data)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !lock.IsZero() {
+		var encoded []byte
+		if !lock.RetainUntil.IsZero() {
+			encoded = appendField(encoded, objectLockRetainUntilField, wireVarint, uint64(lock.RetainUntil.Unix()), nil)
+		}
+		if lock.LegalHold {
+			encoded = appendField(encoded, objectLockLegalHoldField, wireVarint, 1, nil)
+		}
+		unrecognized = appendField(unrecognized, objectLockField, wireBytes, 0, encoded)
+	}
+
+	pointer.XXX_unrecognized = unrecognized
+	return nil
+}
+
+// IsLocked returns whether the pointer can't be deleted at now. Pointers with
+// an invalid lock are considered locked, since it's not known when the lock
+// ends.
+func IsLocked(pointer *pb.Pointer, now time.Time) bool {
+	lock, err := GetObjectLock(pointer)
+	return err != nil || lock.Locked(now)
+}
+
+// IsExpired returns whether the pointer expired at now.
+func IsExpired(pointer *pb.Pointer, now time.Time) bool {
+	return !pointer.ExpirationDate.IsZero() && pointer.ExpirationDate.Before(now)
+}
+
+// walkFields calls fn for every field of the protobuf encoded data. The value
+// is set for varint and fixed fields, the data for length delimited fields.
+func walkFields(encoded []byte, fn func(field, wireType uint64, value uint64, data []byte) error) error {
+	for len(encoded) > 0 {
+		key, n := binary.Uvarint(encoded)
+		if n <= 0 {
+			return Error.New("invalid field key")
+		}
+		encoded = encoded[n:]
+
+		field, wireType := key>>3, key&7
+		var value uint64
+		var data []byte
+
+		switch wireType {
+		case wireVarint:
+			value, n = binary.Uvarint(encoded)
+			if n <= 0 {
+				return Error.New("invalid varint field %d", field)
+			}
+			encoded = encoded[n:]
+		case wireFixed64:
+			if len(encoded) < 8 {
+				return Error.New("invalid fixed64 field %d", field)
+			}
+			value, encoded = binary.LittleEndian.Uint64(encoded), encoded[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(encoded)
+			if n <= 0 || uint64(len(encoded)-n) < length {
+				return Error.New("invalid length delimited field %d", field)
+			}
+			data, encoded = encoded[n:n+int(length)], encoded[n+int(length):]
+		case wireFixed32:
+			if len(encoded) < 4 {
+				return Error.New("invalid fixed32 field %d", field)
+			}
+			value, encoded = uint64(binary.LittleEndian.Uint32(encoded)), encoded[4:]
+		default:
+			return Error.New("unsupported wire type %d of field %d", wireType, field)
+		}
+
+		if err := fn(field, wireType, value, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendField appends a protobuf encoded field to buf.
+func appendField(buf []byte, field, wireType uint64, value uint64, data []byte) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	buf = append(buf, scratch[:binary.PutUvarint(scratch[:], field<<3|wireType)]...)
+
+	switch wireType {
+	case wireVarint:
+		buf = append(buf, scratch[:binary.PutUvarint(scratch[:], value)]...)
+	case wireFixed64:
+		binary.LittleEndian.PutUint64(scratch[:8], value)
+		buf = append(buf, scratch[:8]...)
+	case wireBytes:
+		buf = append(buf, scratch[:binary.PutUvarint(scratch[:], uint64(len(data)))]...)
+		buf = append(buf, data...)
+	case wireFixed32:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(value))
+		buf = append(buf, scratch[:4]...)
+	}
+	return buf
+}
+
+// GetObjectLock returns the lock of an object, which is read from its last segment.
+func (s *Service) GetObjectLock(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (_ ObjectLock, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := CreatePath(ctx, projectID, lastSegment, bucket, encryptedPath)
+	if err != nil {
+		return ObjectLock{}, Error.Wrap(err)
+	}
+	pointer, err := s.Get(ctx, path)
+	if err != nil {
+		return ObjectLock{}, err
+	}
+
+	lock, err := GetObjectLock(pointer)
+	return lock, Error.Wrap(err)
+}
+
+// SetObjectLock replaces the lock of all segments of an object. The retention
+// period of a locked object can only be extended, while the legal hold can be
+// set and removed at any time. The retention period can't end after the
+// object expires, since the storage nodes delete its pieces then.
+func (s *Service) SetObjectLock(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, lock ObjectLock) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := s.objectSegments(ctx, projectID, bucket, encryptedPath, bucket, encryptedPath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, segment := range segments {
+		current, err := GetObjectLock(segment.pointer)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if current.RetainUntil.After(now) && lock.RetainUntil.Before(current.RetainUntil) {
+			return ErrObjectLocked.New("retention of %s can't be shortened", segment.path)
+		}
+		if !segment.pointer.ExpirationDate.IsZero() && lock.RetainUntil.After(segment.pointer.ExpirationDate) {
+			return ErrInvalidObjectLock.New("retention of %s can't end after it expires", segment.path)
+		}
+
+		if err := SetObjectLock(segment.pointer, lock); err != nil {
+			return Error.Wrap(err)
+		}
+		segments[i].newPointerBytes, err = pb.Marshal(segment.pointer)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	// the last segment is updated last, so the object is never reported with
+	// a lock which doesn't apply to all of its segments yet.
+	for _, segment := range segments {
+		err := s.db.CompareAndSwap(ctx, storage.Key(segment.path), segment.pointerBytes, segment.newPointerBytes)
+		if err != nil {
+			if storage.ErrValueChanged.Has(err) || storage.ErrKeyNotFound.Has(err) {
+				return ErrObjectChanged.New("%s", encryptedPath)
+			}
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// GetObjectLockRequest is the request for getting the lock of an object.
+type GetObjectLockRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3"`
+
+	Bucket        []byte `protobuf:"bytes,1,opt,name=bucket,proto3"`
+	EncryptedPath []byte `protobuf:"bytes,2,opt,name=encrypted_path,proto3"`
+	// Version selects a noncurrent version of the object, when it's set.
+	Version int32 `protobuf:"varint,3,opt,name=version,proto3"`
+}
+
+// GetObjectLockResponse is the response for getting the lock of an object.
+type GetObjectLockResponse struct {
+	RetainUntil time.Time `protobuf:"bytes,1,opt,name=retain_until,proto3,stdtime"`
+	LegalHold   bool      `protobuf:"varint,2,opt,name=legal_hold,proto3"`
+}
+
+// SetObjectLockRequest is the request for setting the lock of an object.
+type SetObjectLockRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3"`
+
+	Bucket        []byte `protobuf:"bytes,1,opt,name=bucket,proto3"`
+	EncryptedPath []byte `protobuf:"bytes,2,opt,name=encrypted_path,proto3"`
+	// Version selects a noncurrent version of the object, when it's set.
+	Version int32 `protobuf:"varint,3,opt,name=version,proto3"`
+
+	RetainUntil time.Time `protobuf:"bytes,4,opt,name=retain_until,proto3,stdtime"`
+	LegalHold   bool      `protobuf:"varint,5,opt,name=legal_hold,proto3"`
+}
+
+// SetObjectLockResponse is the response for setting the lock of an object.
+type SetObjectLockResponse struct{}
+
+// GetObjectLock returns the retention period and the legal hold of an object.
+func (endpoint *Endpoint) GetObjectLock(ctx context.Context, req *GetObjectLockRequest) (resp *GetObjectLockResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	lock, err := endpoint.metainfo.GetObjectLock(ctx, keyInfo.ProjectID, req.Bucket, objectVersionPath(req.EncryptedPath, req.Version))
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	return &GetObjectLockResponse{
+		RetainUntil: lock.RetainUntil,
+		LegalHold:   lock.LegalHold,
+	}, nil
+}
+
+// SetObjectLock sets the retention period and the legal hold of an object.
+func (endpoint *Endpoint) SetObjectLock(ctx context.Context, req *SetObjectLockRequest) (resp *SetObjectLockResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionWrite,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	err = endpoint.metainfo.SetObjectLock(ctx, keyInfo.ProjectID, req.Bucket, objectVersionPath(req.EncryptedPath, req.Version), ObjectLock{
+		RetainUntil: req.RetainUntil,
+		LegalHold:   req.LegalHold,
+	})
+	if err != nil {
+		switch {
+		case storj.ErrObjectNotFound.Has(err):
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		case ErrObjectLocked.Has(err):
+			return nil, rpcstatus.Error(rpcstatus.PermissionDenied, err.Error())
+		case ErrInvalidObjectLock.Has(err):
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+		case ErrObjectChanged.Has(err):
+			return nil, rpcstatus.Error(rpcstatus.Aborted, err.Error())
+		default:
+			endpoint.log.Error("unable to set object lock", zap.Error(err))
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
+	endpoint.log.Info("Object Lock", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "lock"), zap.String("type", "object"))
+	mon.Meter("req_set_object_lock").Mark(1)
+
+	return &SetObjectLockResponse{}, nil
+}
+
+// lockNewSegment applies the default retention of the bucket to the pointer of
+// a new segment, so the segment is locked as soon as it's stored. The retention
+// ends when the segment expires at the latest.
+func (endpoint *Endpoint) lockNewSegment(ctx context.Context, projectID uuid.UUID, bucket []byte, pointer *pb.Pointer, created time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucket)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			// the old api allows uploading before the bucket is created.
+			return nil
+		}
+		return err
+	}
+	days := settings.DefaultRetentionDays
+	if days <= 0 {
+		return nil
+	}
+
+	retainUntil := created.AddDate(0, 0, days)
+	if !pointer.ExpirationDate.IsZero() && retainUntil.After(pointer.ExpirationDate) {
+		retainUntil = pointer.ExpirationDate
+	}
+	return SetObjectLock(pointer, ObjectLock{RetainUntil: retainUntil})
+}
+
+// checkNotLocked returns a permission denied error when the pointer is locked.
+func checkNotLocked(pointer *pb.Pointer, path storj.Path) error {
+	if IsLocked(pointer, time.Now()) {
+		return rpcstatus.Error(rpcstatus.PermissionDenied, ErrObjectLocked.New("%s", path).Error())
+	}
+	return nil
+}
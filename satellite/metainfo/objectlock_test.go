@@ -0,0 +1,296 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/uplink/private/testuplink"
+)
+
+func TestObjectLockEncoding(t *testing.T) {
+	now := time.Now()
+	retainUntil := now.Add(time.Hour).Truncate(time.Second).UTC()
+
+	// varint field 999, which isn't known to the pointer message
+	unrelated := []byte{0xb8, 0x3e, 0x01}
+
+	pointer := &pb.Pointer{
+		Type:          pb.Pointer_INLINE,
+		InlineSegment: []byte("data"),
+		SegmentSize:   4,
+	}
+	pointer.XXX_unrecognized = append([]byte{}, unrelated...)
+	require.False(t, metainfo.IsLocked(pointer, now))
+
+	require.NoError(t, metainfo.SetObjectLock(pointer, metainfo.ObjectLock{
+		RetainUntil: retainUntil,
+		LegalHold:   true,
+	}))
+
+	// the lock survives storing the pointer
+	pointerBytes, err := pb.Marshal(pointer)
+	require.NoError(t, err)
+	decoded := &pb.Pointer{}
+	require.NoError(t, pb.Unmarshal(pointerBytes, decoded))
+	require.Equal(t, pointer.InlineSegment, decoded.InlineSegment)
+
+	lock, err := metainfo.GetObjectLock(decoded)
+	require.NoError(t, err)
+	require.Equal(t, metainfo.ObjectLock{RetainUntil: retainUntil, LegalHold: true}, lock)
+
+	// the legal hold applies after the retention period
+	require.True(t, metainfo.IsLocked(decoded, retainUntil.Add(time.Hour)))
+
+	require.NoError(t, metainfo.SetObjectLock(decoded, metainfo.ObjectLock{RetainUntil: retainUntil}))
+	require.True(t, metainfo.IsLocked(decoded, now))
+	require.False(t, metainfo.IsLocked(decoded, retainUntil.Add(time.Second)))
+
+	require.NoError(t, metainfo.SetObjectLock(decoded, metainfo.ObjectLock{}))
+	require.Equal(t, unrelated, decoded.XXX_unrecognized)
+
+	t.Run("expiration", func(t *testing.T) {
+		pointer := &pb.Pointer{ExpirationDate: now.Add(-time.Minute)}
+		require.True(t, metainfo.IsExpired(pointer, now))
+
+		// the lock doesn't keep the pieces, which the storage nodes delete on expiration
+		require.NoError(t, metainfo.SetObjectLock(pointer, metainfo.ObjectLock{RetainUntil: retainUntil}))
+		require.True(t, metainfo.IsExpired(pointer, now))
+		require.True(t, metainfo.IsLocked(pointer, now))
+	})
+
+	t.Run("invalid lock", func(t *testing.T) {
+		// length delimited field 1000 with a missing byte
+		pointer := &pb.Pointer{}
+		pointer.XXX_unrecognized = []byte{0xc2, 0x3e, 0x02, 0x10}
+
+		_, err := metainfo.GetObjectLock(pointer)
+		require.Error(t, err)
+		require.True(t, metainfo.IsLocked(pointer, now))
+	})
+}
+
+func TestObjectLock(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		endpoint := satellite.Metainfo.Endpoint2
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+		bucket := []byte("locked")
+
+		expiredChore := satellite.Core.ExpiredDeletion.Chore
+		lifecycleChore := satellite.Core.BucketLifecycle.Chore
+		lifecycleChore.Loop.Pause()
+
+		require.NoError(t, uplnk.CreateBucket(ctx, satellite, "locked"))
+		require.NoError(t, satellite.Metainfo.Service.UpdateBucketDefaultRetention(ctx, bucket, uplnk.Projects[0].ID, 1))
+
+		started := time.Now()
+		data := testrand.Bytes(30 * memory.KiB)
+		uploadCtx := testuplink.WithMaxSegmentSize(ctx, 13*memory.KiB)
+		require.NoError(t, uplnk.Upload(uploadCtx, satellite, "locked", "object", data))
+
+		projectID, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+		pointers := objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath)
+		require.Len(t, pointers, 3)
+		for _, pointer := range pointers {
+			lock, err := metainfo.GetObjectLock(pointer)
+			require.NoError(t, err)
+			require.False(t, lock.LegalHold)
+			require.WithinDuration(t, started.Add(24*time.Hour), lock.RetainUntil, time.Minute)
+		}
+		usedSpace := storageNodesUsedSpace(ctx, t, planet)
+
+		t.Run("delete", func(t *testing.T) {
+			err := uplnk.DeleteObject(ctx, satellite, "locked", "object")
+			require.Error(t, err)
+
+			object, err := endpoint.GetObject(ctx, &pb.ObjectGetRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+			})
+			require.NoError(t, err)
+
+			_, err = endpoint.BeginDeleteSegment(ctx, &pb.SegmentBeginDeleteRequest{
+				Header:   header,
+				StreamId: object.Object.StreamId,
+				Position: &pb.SegmentPosition{Index: 0},
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+			require.Equal(t, pointers, objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath))
+		})
+
+		t.Run("overwrite", func(t *testing.T) {
+			require.Error(t, uplnk.Upload(ctx, satellite, "locked", "object", testrand.Bytes(10*memory.KiB)))
+			require.Equal(t, pointers, objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath))
+		})
+
+		t.Run("move", func(t *testing.T) {
+			_, err := endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+				Header:           header,
+				Bucket:           bucket,
+				EncryptedPath:    encryptedPath,
+				NewBucket:        bucket,
+				NewEncryptedPath: []byte("moved"),
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+			require.Equal(t, pointers, objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath))
+			require.Empty(t, objectPointers(ctx, t, satellite, projectID, "locked", []byte("moved")))
+		})
+
+		t.Run("chores", func(t *testing.T) {
+			// the segments are expired, but the lock keeps them
+			for path, pointer := range pointers {
+				pointer.ExpirationDate = time.Now().Add(-time.Hour)
+				require.NoError(t, satellite.Metainfo.Service.UnsynchronizedPut(ctx, path, pointer))
+			}
+			expiredChore.Loop.TriggerWait()
+
+			require.NoError(t, satellite.DB.Buckets().UpdateBucketLifecycle(ctx, bucket, projectID, metainfo.LifecycleRules{
+				{ExpirationDays: 1},
+			}))
+			lifecycleChore.SetNow(func() time.Time {
+				return time.Now().Add(48 * time.Hour)
+			})
+			require.NoError(t, lifecycleChore.RunOnce(ctx))
+
+			// the zombie cleanup and audits delete segments through the service
+			for path := range pointers {
+				pointerBytes, _, err := satellite.Metainfo.Service.GetWithBytes(ctx, path)
+				require.NoError(t, err)
+				err = satellite.Metainfo.Service.Delete(ctx, path, pointerBytes)
+				require.True(t, metainfo.ErrObjectLocked.Has(err), err)
+			}
+
+			require.Len(t, objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath), 3)
+
+			for path, pointer := range pointers {
+				pointer.ExpirationDate = time.Time{}
+				require.NoError(t, satellite.Metainfo.Service.UnsynchronizedPut(ctx, path, pointer))
+			}
+		})
+
+		// the piece deletion service never ran for the locked segments
+		planet.WaitForStorageNodeDeleters(ctx)
+		require.Equal(t, usedSpace, storageNodesUsedSpace(ctx, t, planet))
+
+		downloaded, err := uplnk.Download(ctx, satellite, "locked", "object")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+
+		t.Run("retention", func(t *testing.T) {
+			_, err := endpoint.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+				RetainUntil:   time.Now(),
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+			retainUntil := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+			_, err = endpoint.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+				RetainUntil:   retainUntil,
+			})
+			require.NoError(t, err)
+
+			lock, err := endpoint.GetObjectLock(ctx, &metainfo.GetObjectLockRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+			})
+			require.NoError(t, err)
+			require.Equal(t, retainUntil, lock.RetainUntil)
+			for _, pointer := range objectPointers(ctx, t, satellite, projectID, "locked", encryptedPath) {
+				require.True(t, metainfo.IsLocked(pointer, time.Now().Add(47*time.Hour)))
+			}
+		})
+
+		t.Run("expiration", func(t *testing.T) {
+			expiration := time.Now().Add(12 * time.Hour)
+			require.NoError(t, uplnk.UploadWithExpiration(ctx, satellite, "locked", "expiring", testrand.Bytes(10*memory.KiB), expiration))
+
+			var expiringPath []byte
+			for path, pointer := range objectPointers(ctx, t, satellite, projectID, "locked", nil) {
+				if pointer.ExpirationDate.IsZero() {
+					continue
+				}
+				expiringPath = []byte(storj.SplitPath(path)[3])
+
+				// the default retention ends when the object expires
+				lock, err := metainfo.GetObjectLock(pointer)
+				require.NoError(t, err)
+				require.Equal(t, pointer.ExpirationDate.Unix(), lock.RetainUntil.Unix())
+			}
+			require.NotNil(t, expiringPath)
+
+			_, err := endpoint.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: expiringPath,
+				RetainUntil:   expiration.Add(time.Hour),
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.InvalidArgument), err)
+		})
+
+		t.Run("legal hold", func(t *testing.T) {
+			require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "held", testrand.Bytes(10*memory.KiB)))
+			heldPointers := objectPointers(ctx, t, satellite, projectID, "testbucket", nil)
+			require.Len(t, heldPointers, 1)
+
+			var heldPath []byte
+			for path, pointer := range heldPointers {
+				heldPath = []byte(storj.SplitPath(path)[3])
+				require.False(t, metainfo.IsLocked(pointer, time.Now()))
+			}
+			heldUsedSpace := storageNodesUsedSpace(ctx, t, planet)
+
+			_, err := endpoint.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+				Header:        header,
+				Bucket:        []byte("testbucket"),
+				EncryptedPath: heldPath,
+				LegalHold:     true,
+			})
+			require.NoError(t, err)
+
+			require.Error(t, uplnk.DeleteObject(ctx, satellite, "testbucket", "held"))
+			planet.WaitForStorageNodeDeleters(ctx)
+			require.Equal(t, heldUsedSpace, storageNodesUsedSpace(ctx, t, planet))
+
+			// removing the legal hold allows deleting the object
+			_, err = endpoint.SetObjectLock(ctx, &metainfo.SetObjectLockRequest{
+				Header:        header,
+				Bucket:        []byte("testbucket"),
+				EncryptedPath: heldPath,
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, uplnk.DeleteObject(ctx, satellite, "testbucket", "held"))
+			planet.WaitForStorageNodeDeleters(ctx)
+			require.Less(t, storageNodesUsedSpace(ctx, t, planet), heldUsedSpace)
+		})
+	})
+}
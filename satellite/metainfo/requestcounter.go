@@ -0,0 +1,100 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+	"storj.io/common/uuid"
+)
+
+// APIKeyRequestCounts stores the number of requests made with each api key.
+//
+// architecture: Database
+type APIKeyRequestCounts interface {
+	// IncrementRequestCounts adds the given amounts to the request counts of api keys.
+	IncrementRequestCounts(ctx context.Context, counts map[uuid.UUID]int64) error
+}
+
+// RequestCounterConfig is a configuration struct for the api key request counter.
+type RequestCounterConfig struct {
+	FlushInterval time.Duration `help:"how often to flush api key request counts to the database" devDefault:"30s" releaseDefault:"1m"`
+}
+
+// RequestCounter accumulates the number of requests made with each api key
+// and periodically flushes them to the database.
+//
+// architecture: Chore
+type RequestCounter struct {
+	log  *zap.Logger
+	db   APIKeyRequestCounts
+	Loop *sync2.Cycle
+
+	mu     sync.Mutex
+	counts map[uuid.UUID]int64
+}
+
+// NewRequestCounter creates a new api key request counter.
+func NewRequestCounter(log *zap.Logger, db APIKeyRequestCounts, config RequestCounterConfig) *RequestCounter {
+	return &RequestCounter{
+		log:    log,
+		db:     db,
+		Loop:   sync2.NewCycle(config.FlushInterval),
+		counts: make(map[uuid.UUID]int64),
+	}
+}
+
+// Increment counts a single request made with the api key.
+func (counter *RequestCounter) Increment(keyID uuid.UUID) {
+	counter.mu.Lock()
+	counter.counts[keyID]++
+	counter.mu.Unlock()
+}
+
+// Flush writes the accumulated request counts to the database.
+func (counter *RequestCounter) Flush(ctx context.Context) {
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	counter.mu.Lock()
+	counts := counter.counts
+	counter.counts = make(map[uuid.UUID]int64)
+	counter.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	err = counter.db.IncrementRequestCounts(ctx, counts)
+	if err != nil {
+		counter.log.Error("failed to flush api key request counts", zap.Error(err))
+
+		// keep the counts around so that they are written with the next flush.
+		counter.mu.Lock()
+		for keyID, count := range counts {
+			counter.counts[keyID] += count
+		}
+		counter.mu.Unlock()
+	}
+}
+
+// Run starts the request counter flush loop.
+func (counter *RequestCounter) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return counter.Loop.Run(ctx, func(ctx context.Context) error {
+		counter.Flush(ctx)
+		return nil
+	})
+}
+
+// Close stops the request counter flush loop.
+func (counter *RequestCounter) Close() error {
+	counter.Loop.Close()
+	return nil
+}
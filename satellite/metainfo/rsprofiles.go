@@ -0,0 +1,153 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+)
+
+// ErrRSProfile is the error class for invalid redundancy scheme profiles.
+var ErrRSProfile = errs.Class("rs profile")
+
+// RSProfile is a named redundancy scheme, which buckets can use instead of the
+// default redundancy scheme of the satellite.
+type RSProfile struct {
+	Name             string
+	MinThreshold     int
+	RepairThreshold  int
+	SuccessThreshold int
+	TotalThreshold   int
+}
+
+// String formats the profile as name:k/m/o/n.
+func (profile RSProfile) String() string {
+	return fmt.Sprintf("%s:%d/%d/%d/%d", profile.Name,
+		profile.MinThreshold, profile.RepairThreshold, profile.SuccessThreshold, profile.TotalThreshold)
+}
+
+// Validate checks that the thresholds of the profile are consistent.
+func (profile RSProfile) Validate() error {
+	if profile.Name == "" {
+		return ErrRSProfile.New("missing name")
+	}
+	if profile.MinThreshold <= 0 ||
+		profile.RepairThreshold < profile.MinThreshold ||
+		profile.SuccessThreshold < profile.RepairThreshold ||
+		profile.TotalThreshold < profile.SuccessThreshold {
+		return ErrRSProfile.New("%q: thresholds must satisfy 0 < k <= m <= o <= n", profile.Name)
+	}
+	return nil
+}
+
+// RedundancyScheme returns the redundancy scheme of the profile.
+func (profile RSProfile) RedundancyScheme(erasureShareSize int32) *pb.RedundancyScheme {
+	return &pb.RedundancyScheme{
+		Type:             pb.RedundancyScheme_RS,
+		MinReq:           int32(profile.MinThreshold),
+		RepairThreshold:  int32(profile.RepairThreshold),
+		SuccessThreshold: int32(profile.SuccessThreshold),
+		Total:            int32(profile.TotalThreshold),
+		ErasureShareSize: erasureShareSize,
+	}
+}
+
+// RSProfiles is the list of redundancy scheme profiles configured on the satellite.
+type RSProfiles []RSProfile
+
+// Lookup returns the profile with the given name.
+func (profiles RSProfiles) Lookup(name string) (RSProfile, bool) {
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return RSProfile{}, false
+}
+
+// Match returns the profile the redundancy scheme was created from.
+func (profiles RSProfiles) Match(redundancy *pb.RedundancyScheme) (RSProfile, bool) {
+	for _, profile := range profiles {
+		if int32(profile.MinThreshold) == redundancy.GetMinReq() &&
+			int32(profile.RepairThreshold) == redundancy.GetRepairThreshold() &&
+			int32(profile.SuccessThreshold) == redundancy.GetSuccessThreshold() &&
+			int32(profile.TotalThreshold) == redundancy.GetTotal() {
+			return profile, true
+		}
+	}
+	return RSProfile{}, false
+}
+
+// String formats the profiles as a comma separated list.
+func (profiles RSProfiles) String() string {
+	s := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		s = append(s, profile.String())
+	}
+	return strings.Join(s, ",")
+}
+
+// Set implements pflag.Value by parsing a comma separated list of profiles
+// in the form name:k/m/o/n.
+func (profiles *RSProfiles) Set(value string) error {
+	var entries []string
+	if value != "" {
+		entries = strings.Split(value, ",")
+	}
+
+	var toSet RSProfiles
+	for _, entry := range entries {
+		profile, err := parseRSProfile(strings.TrimSpace(entry))
+		if err != nil {
+			return err
+		}
+		if _, exists := toSet.Lookup(profile.Name); exists {
+			return ErrRSProfile.New("duplicate profile %q", profile.Name)
+		}
+		toSet = append(toSet, profile)
+	}
+
+	*profiles = toSet
+	return nil
+}
+
+// Type returns the type of the pflag.Value.
+func (profiles RSProfiles) Type() string {
+	return "rs-profiles"
+}
+
+// parseRSProfile parses a single profile in the form name:k/m/o/n.
+func parseRSProfile(entry string) (profile RSProfile, err error) {
+	name, thresholds := entry, ""
+	if i := strings.IndexByte(entry, ':'); i >= 0 {
+		name, thresholds = entry[:i], entry[i+1:]
+	}
+
+	values := strings.Split(thresholds, "/")
+	if len(values) != 4 {
+		return RSProfile{}, ErrRSProfile.New("invalid profile %q, expected name:k/m/o/n", entry)
+	}
+
+	parsed := make([]int, len(values))
+	for i, value := range values {
+		parsed[i], err = strconv.Atoi(value)
+		if err != nil {
+			return RSProfile{}, ErrRSProfile.New("invalid profile %q: %v", entry, err)
+		}
+	}
+
+	profile = RSProfile{
+		Name:             name,
+		MinThreshold:     parsed[0],
+		RepairThreshold:  parsed[1],
+		SuccessThreshold: parsed[2],
+		TotalThreshold:   parsed[3],
+	}
+	return profile, profile.Validate()
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestRSProfiles(t *testing.T) {
+	var profiles metainfo.RSProfiles
+	require.NoError(t, profiles.Set("archive:16/20/30/40, critical:29/50/80/130"))
+	require.Equal(t, "archive:16/20/30/40,critical:29/50/80/130", profiles.String())
+
+	profile, ok := profiles.Lookup("critical")
+	require.True(t, ok)
+	require.Equal(t, metainfo.RSProfile{
+		Name:             "critical",
+		MinThreshold:     29,
+		RepairThreshold:  50,
+		SuccessThreshold: 80,
+		TotalThreshold:   130,
+	}, profile)
+
+	scheme := profile.RedundancyScheme(256)
+	require.EqualValues(t, 29, scheme.MinReq)
+	require.EqualValues(t, 130, scheme.Total)
+	require.EqualValues(t, 256, scheme.ErasureShareSize)
+
+	matched, ok := profiles.Match(scheme)
+	require.True(t, ok)
+	require.Equal(t, profile, matched)
+
+	scheme.RepairThreshold = 35
+	_, ok = profiles.Match(scheme)
+	require.False(t, ok)
+
+	_, ok = profiles.Lookup("unknown")
+	require.False(t, ok)
+
+	require.NoError(t, profiles.Set(""))
+	require.Empty(t, profiles)
+
+	for _, invalid := range []string{
+		"archive",
+		"archive:16/20/30",
+		":16/20/30/40",
+		"archive:16/20/x/40",
+		"archive:20/16/30/40",
+		"archive:0/20/30/40",
+		"archive:16/20/30/40,archive:16/20/30/50",
+	} {
+		require.Error(t, profiles.Set(invalid), invalid)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/storage"
 	"storj.io/uplink/private/storage/meta"
 )
@@ -28,14 +30,15 @@ var (
 //
 // architecture: Service
 type Service struct {
-	logger    *zap.Logger
-	db        PointerDB
-	bucketsDB BucketsDB
+	logger     *zap.Logger
+	db         PointerDB
+	bucketsDB  BucketsDB
+	references SegmentReferencesDB
 }
 
 // NewService creates new metainfo service.
-func NewService(logger *zap.Logger, db PointerDB, bucketsDB BucketsDB) *Service {
-	return &Service{logger: logger, db: db, bucketsDB: bucketsDB}
+func NewService(logger *zap.Logger, db PointerDB, bucketsDB BucketsDB, references SegmentReferencesDB) *Service {
+	return &Service{logger: logger, db: db, bucketsDB: bucketsDB, references: references}
 }
 
 // Put puts pointer to db under specific path.
@@ -86,9 +89,128 @@ func (s *Service) UpdatePieces(ctx context.Context, path string, ref *pb.Pointer
 // Then it will remove the toRemove pieces and then it will add the toAdd pieces.
 // Replacing the node ID and the hash of a piece can be done by adding the
 // piece to both toAdd and toRemove.
+//
+// The same changes are applied to the copies of the segment which share its
+// pieces, so they don't keep pointing to pieces which were removed.
 func (s *Service) UpdatePiecesCheckDuplicates(ctx context.Context, path string, ref *pb.Pointer, toAdd, toRemove []*pb.RemotePiece, checkDuplicates bool) (pointer *pb.Pointer, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	pointer, err = s.updatePieces(ctx, path, ref, toAdd, toRemove, checkDuplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	if pointer.Type == pb.Pointer_REMOTE {
+		s.updateSharedPieces(ctx, path, pointer.GetRemote().RootPieceId, toAdd, toRemove)
+	}
+	return pointer, nil
+}
+
+// updateSharedPieces applies the piece changes of the segment at path to the
+// other segments sharing its pieces. Failures are only logged, the pieces of
+// a copy which wasn't updated are repaired as usual.
+func (s *Service) updateSharedPieces(ctx context.Context, path string, rootPieceID storj.PieceID, toAdd, toRemove []*pb.RemotePiece) {
+	defer mon.Task()(&ctx)(nil)
+
+	paths, err := s.references.Get(ctx, rootPieceID)
+	if err != nil {
+		s.logger.Warn("unable to get segments sharing pieces", zap.String("path", path), zap.Error(err))
+		return
+	}
+
+	for _, sharedPath := range paths {
+		if sharedPath == path {
+			continue
+		}
+
+		shared, err := s.Get(ctx, sharedPath)
+		if err != nil {
+			if !storj.ErrObjectNotFound.Has(err) {
+				s.logger.Warn("unable to get segment sharing pieces", zap.String("path", sharedPath), zap.Error(err))
+			}
+			continue
+		}
+		if shared.Remote == nil || shared.Remote.RootPieceId != rootPieceID {
+			continue
+		}
+
+		_, err = s.updatePieces(ctx, sharedPath, shared, clonePieces(toAdd), toRemove, false)
+		if err != nil {
+			s.logger.Warn("unable to update pieces of segment sharing pieces", zap.String("path", sharedPath), zap.Error(err))
+		}
+	}
+}
+
+// SharedRepairPath returns the path of the segment which is repaired in place of the
+// segment at path. The piece IDs of segments sharing their pieces are derived from the
+// same root piece ID, so repairing them independently could upload the same piece to
+// the same new node twice. Only the first of them is repaired, and the changes are
+// applied to the others. It returns path when the pieces aren't shared, or when the
+// pieces of the first segment differ, because updating the copy failed.
+func (s *Service) SharedRepairPath(ctx context.Context, path string, pointer *pb.Pointer) (_ string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if pointer.GetRemote() == nil {
+		return path, nil
+	}
+	rootPieceID := pointer.GetRemote().RootPieceId
+
+	paths, err := s.references.Get(ctx, rootPieceID)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if len(paths) == 0 || paths[0] == path {
+		return path, nil
+	}
+
+	first, err := s.Get(ctx, paths[0])
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return path, nil
+		}
+		return "", err
+	}
+	if first.GetRemote() == nil || first.GetRemote().RootPieceId != rootPieceID ||
+		!samePieces(first.GetRemote().GetRemotePieces(), pointer.GetRemote().GetRemotePieces()) {
+		return path, nil
+	}
+	return paths[0], nil
+}
+
+// samePieces returns whether both lists contain the same pieces on the same nodes.
+func samePieces(a, b []*pb.RemotePiece) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	nodes := make(map[int32]storj.NodeID, len(a))
+	for _, piece := range a {
+		nodes[piece.PieceNum] = piece.NodeId
+	}
+	for _, piece := range b {
+		nodeID, ok := nodes[piece.PieceNum]
+		if !ok || nodeID != piece.NodeId {
+			return false
+		}
+	}
+	return true
+}
+
+// clonePieces returns a copy of the pieces which can be stored in another pointer.
+func clonePieces(pieces []*pb.RemotePiece) []*pb.RemotePiece {
+	cloned := make([]*pb.RemotePiece, 0, len(pieces))
+	for _, piece := range pieces {
+		if piece == nil {
+			continue
+		}
+		cloned = append(cloned, &pb.RemotePiece{PieceNum: piece.PieceNum, NodeId: piece.NodeId})
+	}
+	return cloned
+}
+
+// updatePieces implements UpdatePiecesCheckDuplicates for a single pointer.
+func (s *Service) updatePieces(ctx context.Context, path string, ref *pb.Pointer, toAdd, toRemove []*pb.RemotePiece, checkDuplicates bool) (pointer *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
 	for {
 		// read the pointer
 		oldPointerBytes, err := s.db.Get(ctx, []byte(path))
@@ -312,11 +434,55 @@ func (s *Service) setMetadata(item *pb.ListResponse_Item, data []byte, metaFlags
 func (s *Service) Delete(ctx context.Context, path string, oldPointerBytes []byte) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	_, err = s.DeleteSegment(ctx, path, oldPointerBytes)
+	return err
+}
+
+// DeleteSegment deletes a pointer bytes when it matches oldPointerBytes, like
+// Delete, and returns whether its remote pieces are still used by copies of
+// the segment, in which case they must not be deleted from the storage nodes.
+// Locked segments aren't deleted and ErrObjectLocked is returned.
+func (s *Service) DeleteSegment(ctx context.Context, path string, oldPointerBytes []byte) (piecesShared bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointer := &pb.Pointer{}
+	if err := pb.Unmarshal(oldPointerBytes, pointer); err != nil {
+		return false, Error.Wrap(err)
+	}
+	if IsLocked(pointer, time.Now()) {
+		return false, ErrObjectLocked.New("%s", path)
+	}
+
 	err = s.db.CompareAndSwap(ctx, []byte(path), oldPointerBytes, nil)
-	if storage.ErrKeyNotFound.Has(err) {
-		err = storj.ErrObjectNotFound.Wrap(err)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			err = storj.ErrObjectNotFound.Wrap(err)
+		}
+		return false, Error.Wrap(err)
 	}
-	return Error.Wrap(err)
+
+	return s.ReleasePieces(ctx, path, pointer), nil
+}
+
+// ReleasePieces must be called once after the pointer at path was deleted
+// without DeleteSegment. It returns whether the remote pieces of the pointer
+// are still used by copies of the segment, in which case they must not be
+// deleted from the storage nodes.
+func (s *Service) ReleasePieces(ctx context.Context, path string, pointer *pb.Pointer) (piecesShared bool) {
+	defer mon.Task()(&ctx)(nil)
+
+	if pointer.Type != pb.Pointer_REMOTE || pointer.Remote == nil {
+		return false
+	}
+
+	piecesShared, err := s.references.Remove(ctx, pointer.Remote.RootPieceId, path)
+	if err != nil {
+		// keeping the pieces is safe, garbage collection deletes them when
+		// they aren't used anymore.
+		s.logger.Warn("unable to release segment pieces, keeping them", zap.String("path", path), zap.Error(err))
+		return true
+	}
+	return piecesShared
 }
 
 // UnsynchronizedDelete deletes from item from db without verifying whether the pointer has changed in the database.
@@ -348,6 +514,93 @@ func (s *Service) UpdateBucket(ctx context.Context, bucket storj.Bucket) (_ stor
 	return s.bucketsDB.UpdateBucket(ctx, bucket)
 }
 
+// GetBucketSettings returns the settings of a bucket in the buckets db
+func (s *Service) GetBucketSettings(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ BucketSettings, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketSettings(ctx, bucketName, projectID)
+}
+
+// GetBucketPlacement returns the placement of a bucket in the buckets db
+func (s *Service) GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ nodeselection.Placement, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketPlacement(ctx, bucketName, projectID)
+}
+
+// UpdateBucketPlacement sets the placement of a bucket in the buckets db
+func (s *Service) UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement nodeselection.Placement) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketPlacement(ctx, bucketName, projectID, placement)
+}
+
+// GetBucketLimits returns the usage limits of a bucket in the buckets db
+func (s *Service) GetBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ accounting.BucketLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketLimits(ctx, bucketName, projectID)
+}
+
+// UpdateBucketLimits sets the usage limits of a bucket in the buckets db
+func (s *Service) UpdateBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID, limits accounting.BucketLimits) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketLimits(ctx, bucketName, projectID, limits)
+}
+
+// GetBucketRSProfile returns the redundancy scheme profile name of a bucket in the buckets db
+func (s *Service) GetBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketRSProfile(ctx, bucketName, projectID)
+}
+
+// UpdateBucketRSProfile sets the redundancy scheme profile name of a bucket in the buckets db
+func (s *Service) UpdateBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID, profile string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketRSProfile(ctx, bucketName, projectID, profile)
+}
+
+// GetBucketVersioning returns whether versioning is enabled for a bucket in the buckets db
+func (s *Service) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketVersioning(ctx, bucketName, projectID)
+}
+
+// UpdateBucketVersioning enables or disables versioning for a bucket in the buckets db
+func (s *Service) UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, enabled bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketVersioning(ctx, bucketName, projectID, enabled)
+}
+
+// GetBucketDefaultRetention returns the number of days new objects of a bucket are locked for in the buckets db
+func (s *Service) GetBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ int, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketDefaultRetention(ctx, bucketName, projectID)
+}
+
+// UpdateBucketDefaultRetention sets the number of days new objects of a bucket are locked for in the buckets db
+func (s *Service) UpdateBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID, days int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketDefaultRetention(ctx, bucketName, projectID, days)
+}
+
+// SegmentPlacement returns the placement of the bucket the segment at path belongs to.
+// Segments of buckets which have since been deleted may be placed anywhere.
+func (s *Service) SegmentPlacement(ctx context.Context, path storj.Path) (_ nodeselection.Placement, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	comps := storj.SplitPath(path)
+	if len(comps) < 3 {
+		return nil, Error.New("no bucket component in path: %s", path)
+	}
+	projectID, err := uuid.FromString(comps[0])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	placement, err := s.bucketsDB.GetBucketPlacement(ctx, []byte(comps[2]), projectID)
+	if storj.ErrBucketNotFound.Has(err) {
+		return nil, nil
+	}
+	return placement, err
+}
+
 // DeleteBucket deletes a bucket from the bucekts db
 func (s *Service) DeleteBucket(ctx context.Context, bucketName []byte, projectID uuid.UUID) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -6,6 +6,7 @@ package metainfo
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"regexp"
 	"sync"
 	"time"
@@ -19,7 +20,6 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/storj"
-	"storj.io/common/uuid"
 	"storj.io/storj/pkg/auth"
 	"storj.io/storj/satellite/console"
 )
@@ -150,7 +150,7 @@ func (endpoint *Endpoint) validateAuth(ctx context.Context, header *pb.RequestHe
 		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "Unauthorized API credentials")
 	}
 
-	if err = endpoint.checkRate(ctx, keyInfo.ProjectID); err != nil {
+	if err = endpoint.checkRate(ctx, keyInfo, key.Head()); err != nil {
 		endpoint.log.Debug("rate check failed", zap.Error(err))
 		return nil, err
 	}
@@ -162,6 +162,10 @@ func (endpoint *Endpoint) validateAuth(ctx context.Context, header *pb.RequestHe
 		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "Unauthorized API credentials")
 	}
 
+	if endpoint.requestCounter != nil {
+		endpoint.requestCounter.Increment(keyInfo.ID)
+	}
+
 	return keyInfo, nil
 }
 
@@ -182,11 +186,42 @@ func (endpoint *Endpoint) getKeyInfo(ctx context.Context, header *pb.RequestHead
 	return keyInfo, nil
 }
 
-func (endpoint *Endpoint) checkRate(ctx context.Context, projectID uuid.UUID) (err error) {
+// checkRate checks the request rate of the api key, identified by its macaroon head, and of its project.
+func (endpoint *Endpoint) checkRate(ctx context.Context, keyInfo *console.APIKeyInfo, head []byte) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	if !endpoint.config.RateLimiter.Enabled {
 		return nil
 	}
+
+	// the key limiter is checked first, so that requests rejected for a single key
+	// don't use up the rate of the other keys in the project.
+	keyLimiter, err := endpoint.keyLimiterCache.Get(hex.EncodeToString(head), func() (interface{}, error) {
+		limit := rate.Limit(endpoint.config.RateLimiter.KeyRate)
+		if keyInfo.RateLimit != nil && *keyInfo.RateLimit > 0 {
+			limit = rate.Limit(*keyInfo.RateLimit)
+		}
+		if limit <= 0 {
+			return rate.NewLimiter(rate.Inf, 0), nil
+		}
+
+		return rate.NewLimiter(limit, int(limit)), nil
+	})
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Unavailable, err.Error())
+	}
+
+	if !keyLimiter.(*rate.Limiter).Allow() {
+		endpoint.log.Warn("too many requests for api key",
+			zap.Stringer("projectID", keyInfo.ProjectID),
+			zap.Stringer("apiKeyID", keyInfo.ID),
+			zap.Float64("limit", float64(keyLimiter.(*rate.Limiter).Limit())))
+
+		mon.Event("metainfo_key_rate_limit_exceeded")
+
+		return rpcstatus.Error(rpcstatus.ResourceExhausted, "Too Many Requests")
+	}
+
+	projectID := keyInfo.ProjectID
 	limiter, err := endpoint.limiterCache.Get(projectID.String(), func() (interface{}, error) {
 		limit := rate.Limit(endpoint.config.RateLimiter.Rate)
 
@@ -395,9 +430,34 @@ func (endpoint *Endpoint) validatePointer(ctx context.Context, pointer *pb.Point
 	return nil
 }
 
-func (endpoint *Endpoint) validateRedundancy(ctx context.Context, redundancy *pb.RedundancyScheme) (err error) {
+func (endpoint *Endpoint) validateRedundancy(ctx context.Context, redundancy *pb.RedundancyScheme, profile *RSProfile) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if endpoint.config.RS.Validate && profile != nil {
+		want := profile.RedundancyScheme(endpoint.config.RS.ErasureShareSize.Int32())
+		if want.ErasureShareSize != redundancy.ErasureShareSize ||
+			want.MinReq != redundancy.MinReq ||
+			want.RepairThreshold != redundancy.RepairThreshold ||
+			want.SuccessThreshold != redundancy.SuccessThreshold ||
+			want.Total != redundancy.Total {
+			return Error.New("provided redundancy scheme parameters not allowed for profile %q: want [%d, %d, %d, %d, %d] got [%d, %d, %d, %d, %d]",
+				profile.Name,
+				want.MinReq,
+				want.RepairThreshold,
+				want.SuccessThreshold,
+				want.Total,
+				want.ErasureShareSize,
+
+				redundancy.MinReq,
+				redundancy.RepairThreshold,
+				redundancy.SuccessThreshold,
+				redundancy.Total,
+				redundancy.ErasureShareSize,
+			)
+		}
+		return nil
+	}
+
 	if endpoint.config.RS.Validate {
 		if endpoint.config.RS.ErasureShareSize.Int32() != redundancy.ErasureShareSize ||
 			endpoint.config.RS.MinTotalThreshold > int(redundancy.Total) ||
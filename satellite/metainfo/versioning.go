@@ -0,0 +1,245 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/storage"
+)
+
+// versionMarker separates the encrypted path of an object from the number of
+// its noncurrent version. Encrypted path components never contain a zero
+// byte, so the keys of versions don't collide with the keys of other objects.
+const versionMarker = "\x00v"
+
+// preserveVersionAttempts is how many times preserving a version is retried
+// when a concurrent overwrite of the same object took the version number.
+const preserveVersionAttempts = 3
+
+// ObjectVersion is a noncurrent version of an object.
+type ObjectVersion struct {
+	Version int32
+	// Pointer is the last segment of the version.
+	Pointer *pb.Pointer
+}
+
+// VersionedPath returns the encrypted path under which the segments of a
+// noncurrent version of an object are kept.
+func VersionedPath(encryptedPath []byte, version int32) []byte {
+	path := make([]byte, 0, len(encryptedPath)+len(versionMarker)+10)
+	path = append(path, encryptedPath...)
+	return append(path, fmt.Sprintf("%s%010d", versionMarker, version)...)
+}
+
+// IsVersionedPath returns whether the path belongs to a noncurrent version of an object.
+func IsVersionedPath(path string) bool {
+	return strings.Contains(path, versionMarker)
+}
+
+// ListObjectVersions returns the noncurrent versions of an object ordered
+// from the oldest to the newest.
+func (s *Service) ListObjectVersions(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (versions []ObjectVersion, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prefix, err := CreatePath(ctx, projectID, lastSegment, bucket, append(append([]byte{}, encryptedPath...), versionMarker...))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = s.db.Iterate(ctx, storage.IterateOptions{
+		Prefix:  storage.Key(prefix),
+		Recurse: true,
+	}, func(ctx context.Context, it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(ctx, &item) {
+			version, err := strconv.ParseInt(string(item.Key[len(prefix):]), 10, 32)
+			if err != nil {
+				s.logger.Warn("invalid object version", zap.Binary("path", item.Key), zap.Error(err))
+				continue
+			}
+
+			pointer := &pb.Pointer{}
+			if err := pb.Unmarshal(item.Value, pointer); err != nil {
+				return err
+			}
+			versions = append(versions, ObjectVersion{
+				Version: int32(version),
+				Pointer: pointer,
+			})
+		}
+		return nil
+	})
+	return versions, Error.Wrap(err)
+}
+
+// PreserveObjectVersion keeps the current object as its newest noncurrent
+// version instead of deleting it. The segments keep their pieces, they are
+// only moved to the versioned path.
+func (s *Service) PreserveObjectVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for attempt := 1; ; attempt++ {
+		versions, err := s.ListObjectVersions(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return 0, err
+		}
+
+		version = 1
+		if len(versions) > 0 {
+			version = versions[len(versions)-1].Version + 1
+		}
+
+		_, err = s.MoveObject(ctx, projectID, bucket, encryptedPath, bucket, VersionedPath(encryptedPath, version), nil)
+		if (ErrObjectExists.Has(err) || ErrObjectChanged.Has(err)) && attempt < preserveVersionAttempts {
+			continue
+		}
+		return version, err
+	}
+}
+
+// ListObjectVersionsRequest is the request for listing the noncurrent versions of an object.
+type ListObjectVersionsRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3"`
+
+	Bucket        []byte `protobuf:"bytes,1,opt,name=bucket,proto3"`
+	EncryptedPath []byte `protobuf:"bytes,2,opt,name=encrypted_path,proto3"`
+}
+
+// ListObjectVersionsResponse is the response for listing the noncurrent versions of an object.
+type ListObjectVersionsResponse struct {
+	Items []*pb.ObjectListItem `protobuf:"bytes,1,rep,name=items,proto3"`
+}
+
+// ListObjectVersions lists the noncurrent versions of an object. The versions
+// are downloaded and deleted by passing their number to GetObject and
+// BeginDeleteObject.
+func (endpoint *Endpoint) ListObjectVersions(ctx context.Context, req *ListObjectVersionsRequest) (resp *ListObjectVersionsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionList,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	versions, err := endpoint.metainfo.ListObjectVersions(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	items := make([]*pb.ObjectListItem, len(versions))
+	for i, version := range versions {
+		items[i] = &pb.ObjectListItem{
+			EncryptedPath:     req.EncryptedPath,
+			Version:           version.Version,
+			EncryptedMetadata: version.Pointer.Metadata,
+			CreatedAt:         version.Pointer.CreationDate,
+			ExpiresAt:         version.Pointer.ExpirationDate,
+		}
+	}
+	endpoint.log.Info("Object Versions List", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "list"), zap.String("type", "object"))
+	mon.Meter("req_list_object_versions").Mark(1)
+
+	return &ListObjectVersionsResponse{Items: items}, nil
+}
+
+// deleteOrPreserveObject deletes the current object at the path, or keeps it
+// as a noncurrent version when versioning is enabled for the bucket.
+func (endpoint *Endpoint) deleteOrPreserveObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	settings, err := endpoint.getBucketSettings(ctx, projectID, bucket)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if !settings.Versioning {
+		return endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+	}
+
+	version, err := endpoint.metainfo.PreserveObjectVersion(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		endpoint.log.Error("unable to preserve object version",
+			zap.Stringer("project_id", projectID),
+			zap.ByteString("bucket_name", bucket),
+			zap.Binary("encrypted_path", encryptedPath),
+			zap.Error(err),
+		)
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	endpoint.log.Debug("object version preserved", zap.Stringer("Project ID", projectID), zap.Int32("version", version))
+	mon.Meter("object_version_preserved").Mark(1)
+	return nil
+}
+
+// listCurrentObjects lists the items like metainfo.List, but skips the
+// noncurrent versions of objects.
+func (endpoint *Endpoint) listCurrentObjects(ctx context.Context, prefix, startAfter string, recursive bool, limit int32, metaFlags uint32) (items []*pb.ListResponse_Item, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		pageLimit := limit
+		if limit > 0 {
+			pageLimit = limit - int32(len(items))
+		}
+
+		listed, listedMore, err := endpoint.metainfo.List(ctx, prefix, startAfter, recursive, pageLimit, metaFlags)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, item := range listed {
+			if !IsVersionedPath(item.Path) {
+				items = append(items, item)
+			}
+		}
+
+		// keep listing while versions left the page empty or short, so clients
+		// always get an item to continue from.
+		if !listedMore || len(listed) == 0 || len(items) > 0 && (limit <= 0 || int32(len(items)) >= limit) {
+			return items, listedMore, nil
+		}
+		startAfter = listed[len(listed)-1].Path
+	}
+}
+
+// streamObjectPath returns the encrypted path of the object version a stream refers to.
+func streamObjectPath(streamID *pb.SatStreamID) []byte {
+	return objectVersionPath(streamID.EncryptedPath, streamID.Version)
+}
+
+// objectVersionPath returns the encrypted path of an object version, the
+// current object is selected when the version isn't set.
+func objectVersionPath(encryptedPath []byte, version int32) []byte {
+	if version > 0 {
+		return VersionedPath(encryptedPath, version)
+	}
+	return encryptedPath
+}
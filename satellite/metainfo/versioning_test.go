@@ -0,0 +1,154 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/accounting/tally"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestVersionedPath(t *testing.T) {
+	path := metainfo.VersionedPath([]byte("encrypted/path"), 12)
+	require.Equal(t, "encrypted/path\x00v0000000012", string(path))
+	require.True(t, metainfo.IsVersionedPath(string(path)))
+	require.False(t, metainfo.IsVersionedPath("encrypted/path"))
+
+	// versions sort by number after the current object
+	require.Less(t, "encrypted/path", string(path))
+	require.Less(t, string(metainfo.VersionedPath([]byte("encrypted/path"), 2)), string(path))
+}
+
+func TestObjectVersioning(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplnk := planet.Uplinks[0]
+		endpoint := satellite.Metainfo.Endpoint2
+		header := &pb.RequestHeader{ApiKey: uplnk.APIKey[satellite.ID()].SerializeRaw()}
+		bucket := []byte("testbucket")
+
+		require.NoError(t, uplnk.CreateBucket(ctx, satellite, "testbucket"))
+		require.NoError(t, satellite.Metainfo.Service.UpdateBucketVersioning(ctx, bucket, uplnk.Projects[0].ID, true))
+
+		first := testrand.Bytes(10 * memory.KiB)
+		require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "object", first))
+		projectID, encryptedPath := getProjectIDAndEncPathFirstObject(ctx, t, satellite)
+		firstUsedSpace := storageNodesUsedSpace(ctx, t, planet)
+
+		// overwriting keeps the first upload as version 1
+		second := testrand.Bytes(12 * memory.KiB)
+		require.NoError(t, uplnk.Upload(ctx, satellite, "testbucket", "object", second))
+		planet.WaitForStorageNodeDeleters(ctx)
+		usedSpace := storageNodesUsedSpace(ctx, t, planet)
+		require.Greater(t, usedSpace, firstUsedSpace)
+
+		downloaded, err := uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, downloaded)
+
+		versions, err := endpoint.ListObjectVersions(ctx, &metainfo.ListObjectVersionsRequest{
+			Header:        header,
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+		require.Len(t, versions.Items, 1)
+		require.EqualValues(t, 1, versions.Items[0].Version)
+
+		object, err := endpoint.GetObject(ctx, &pb.ObjectGetRequest{
+			Header:        header,
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+			Version:       1,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, object.Object.Version)
+		require.Equal(t, versions.Items[0].EncryptedMetadata, object.Object.EncryptedMetadata)
+
+		// deleting the current object keeps it as version 2
+		require.NoError(t, uplnk.DeleteObject(ctx, satellite, "testbucket", "object"))
+		planet.WaitForStorageNodeDeleters(ctx)
+		require.Equal(t, usedSpace, storageNodesUsedSpace(ctx, t, planet))
+
+		_, err = uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.Error(t, err)
+
+		listed, err := endpoint.ListObjects(ctx, &pb.ObjectListRequest{
+			Header:    header,
+			Bucket:    bucket,
+			Recursive: true,
+		})
+		require.NoError(t, err)
+		require.Empty(t, listed.Items)
+
+		versions, err = endpoint.ListObjectVersions(ctx, &metainfo.ListObjectVersionsRequest{
+			Header:        header,
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+		require.Len(t, versions.Items, 2)
+		require.EqualValues(t, 2, versions.Items[1].Version)
+
+		// noncurrent versions are accounted as stored data
+		obs := tally.NewObserver(satellite.Log.Named("observer"), time.Now())
+		require.NoError(t, satellite.Metainfo.Loop.Join(ctx, obs))
+		require.Len(t, obs.Bucket, 1)
+		for _, tally := range obs.Bucket {
+			require.EqualValues(t, 2, tally.ObjectCount)
+			require.EqualValues(t, 2, tally.RemoteSegments)
+		}
+
+		t.Run("delete version", func(t *testing.T) {
+			_, err := endpoint.BeginDeleteObject(ctx, &pb.ObjectBeginDeleteRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+				Version:       1,
+			})
+			require.NoError(t, err)
+			planet.WaitForStorageNodeDeleters(ctx)
+			require.Less(t, storageNodesUsedSpace(ctx, t, planet), usedSpace)
+
+			_, err = endpoint.GetObject(ctx, &pb.ObjectGetRequest{
+				Header:        header,
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+				Version:       1,
+			})
+			require.True(t, errs2.IsRPC(err, rpcstatus.NotFound), err)
+		})
+
+		// a version is restored by moving it back to the object path
+		_, err = endpoint.MoveObject(ctx, &metainfo.MoveObjectRequest{
+			Header:           header,
+			Bucket:           bucket,
+			EncryptedPath:    metainfo.VersionedPath(encryptedPath, 2),
+			NewBucket:        bucket,
+			NewEncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+		require.Len(t, objectPointers(ctx, t, satellite, projectID, "testbucket", encryptedPath), 1)
+
+		downloaded, err = uplnk.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, downloaded)
+	})
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedeletion
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+)
+
+var (
+	// Error defines the zombiedeletion chore errors class
+	Error = errs.Class("zombiedeletion chore error")
+	mon   = monkit.Package()
+)
+
+// Config contains configurable values for zombie segment cleanup
+type Config struct {
+	Interval    time.Duration `help:"the time between each attempt to go through the db and clean up zombie segments" releaseDefault:"24h" devDefault:"10m"`
+	Enabled     bool          `help:"set if zombie segment cleanup is enabled or not" releaseDefault:"true" devDefault:"true"`
+	DryRun      bool          `help:"with this option zombie segments are only detected and reported, not deleted" default:"false"`
+	MaxSegments int           `help:"maximum number of zombie segments deleted in a single run" default:"100000"`
+	DeleteRate  float64       `help:"maximum number of zombie segments deleted per second" default:"100"`
+}
+
+// Stats contains the results of a zombie segment cleanup run.
+type Stats struct {
+	Detected int
+	Deleted  int
+	Skipped  int
+	Errored  int
+	// DeletedBytes is the sum of the sizes of the deleted segments.
+	DeletedBytes int64
+}
+
+// Chore implements the zombie segment cleanup chore
+//
+// architecture: Chore
+type Chore struct {
+	log    *zap.Logger
+	config Config
+	Loop   *sync2.Cycle
+
+	maxCommitInterval time.Duration
+	metainfo          *metainfo.Service
+	metainfoLoop      *metainfo.Loop
+
+	nowFn func() time.Time
+}
+
+// NewChore creates a new instance of the zombiedeletion chore, segments are
+// considered zombies when their upload wasn't committed within maxCommitInterval.
+func NewChore(log *zap.Logger, config Config, maxCommitInterval time.Duration, meta *metainfo.Service, loop *metainfo.Loop) *Chore {
+	return &Chore{
+		log:               log,
+		config:            config,
+		Loop:              sync2.NewCycle(config.Interval),
+		maxCommitInterval: maxCommitInterval,
+		metainfo:          meta,
+		metainfoLoop:      loop,
+		nowFn:             time.Now,
+	}
+}
+
+// Run starts the zombiedeletion loop service
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !chore.config.Enabled {
+		return nil
+	}
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		_, err := chore.RunOnce(ctx)
+		if err != nil {
+			chore.log.Error("error cleaning up zombie segments", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the zombiedeletion loop service
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
+
+// SetNow allows tests to have the Chore act as if the current time is whatever
+// they want. This avoids races and sleeping, making tests more reliable and efficient.
+func (chore *Chore) SetNow(now func() time.Time) {
+	chore.nowFn = now
+}
+
+// RunOnce detects the zombie segments and deletes them.
+func (chore *Chore) RunOnce(ctx context.Context) (stats Stats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cutoff := chore.nowFn().Add(-chore.maxCommitInterval)
+
+	obs := newObserver(cutoff, chore.config.MaxSegments)
+	err = chore.metainfoLoop.Join(ctx, obs)
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+	// the segments of the last project are analyzed when the loop is finished
+	err = obs.analyzeProject(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Detected = len(obs.zombies)
+	if obs.limited {
+		chore.log.Info("zombie segments limit reached, remaining segments are deleted in the next run",
+			zap.Int("limit", chore.config.MaxSegments))
+	}
+
+	err = chore.deleteSegments(ctx, obs.zombies, cutoff, &stats)
+
+	mon.IntVal("zombie_segments_detected").Observe(int64(stats.Detected))
+	mon.IntVal("zombie_segments_deleted").Observe(int64(stats.Deleted))
+	mon.IntVal("zombie_segments_deleted_bytes").Observe(stats.DeletedBytes)
+
+	chore.log.Info("zombie segments cleanup finished",
+		zap.Bool("dry run", chore.config.DryRun),
+		zap.Int("detected", stats.Detected),
+		zap.Int("deleted", stats.Deleted),
+		zap.Int("skipped", stats.Skipped),
+		zap.Int("errored", stats.Errored),
+		zap.Int64("deleted bytes", stats.DeletedBytes),
+	)
+
+	return stats, err
+}
+
+// deleteSegments deletes the zombie segments which weren't changed since cutoff.
+func (chore *Chore) deleteSegments(ctx context.Context, zombies []zombieSegment, cutoff time.Time, stats *Stats) (err error) {
+	defer mon.Task()(&ctx, len(zombies))(&err)
+
+	limiter := rate.NewLimiter(rate.Limit(chore.config.DeleteRate), 1)
+
+	for _, zombie := range zombies {
+		if err := limiter.Wait(ctx); err != nil {
+			return Error.Wrap(err)
+		}
+
+		size, deleted, err := chore.deleteSegment(ctx, zombie.Path(), cutoff)
+		if err != nil {
+			chore.log.Warn("unable to delete zombie segment",
+				zap.String("Project ID", zombie.ProjectID),
+				zap.String("Segment", zombie.Segment),
+				zap.String("Bucket", zombie.BucketName),
+				zap.Error(err),
+			)
+			stats.Errored++
+			continue
+		}
+		if !deleted {
+			stats.Skipped++
+			continue
+		}
+
+		stats.Deleted++
+		stats.DeletedBytes += size
+		mon.Meter("zombie_segments_deleted").Mark(1)
+		mon.Meter("zombie_segments_deleted_bytes").Mark64(size)
+	}
+
+	return nil
+}
+
+// deleteSegment deletes the segment using compare-and-swap, unless it was
+// deleted or replaced since the cutoff.
+func (chore *Chore) deleteSegment(ctx context.Context, path storj.Path, cutoff time.Time) (size int64, deleted bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointerBytes, pointer, err := chore.metainfo.GetWithBytes(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			// segment already deleted by user
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	if pointer.CreationDate.After(cutoff) {
+		// pointer has been replaced since detection, do not delete it.
+		return 0, false, nil
+	}
+
+	if metainfo.IsLocked(pointer, time.Now()) {
+		// locked segments are kept until the lock ends.
+		return 0, false, nil
+	}
+
+	if chore.config.DryRun {
+		return pointer.SegmentSize, true, nil
+	}
+
+	err = chore.metainfo.Delete(ctx, path, pointerBytes)
+	if storj.ErrObjectNotFound.Has(err) || storage.ErrValueChanged.Has(err) {
+		// race detected while deleting the pointer, do not try deleting it again.
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return pointer.SegmentSize, true, nil
+}
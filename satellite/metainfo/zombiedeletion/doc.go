@@ -0,0 +1,21 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package zombiedeletion contains the functions needed to run zombie segment deletion
+
+Zombie segments are segments which are left in metainfo by uploads which were
+never committed, e.g. segments of objects without a last segment or segments
+which don't belong to the sequence of segments of a committed object.
+
+The zombiedeletion.observer implements the metainfo loop Observer interface
+and detects zombie segments with the rules of the zombiedetection package,
+which the segment-reaper detect command uses as well. Only objects where all
+segments are older than the metainfo MaxCommitInterval are considered,
+because uploads can't be committed after it.
+
+The zombiedeletion chore will subscribe the observer to the metainfo loop
+and delete the detected segments from metainfo. The pieces of the deleted
+segments are removed from the storage nodes by garbage collection.
+*/
+package zombiedeletion
@@ -0,0 +1,200 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedeletion
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/zombiedetection"
+)
+
+var _ metainfo.PartialObserver = (*observer)(nil)
+var _ metainfo.DistributedObserver = (*observer)(nil)
+
+// zombieSegment identifies a segment which was detected as zombie.
+type zombieSegment struct {
+	ProjectID     string
+	Segment       string
+	BucketName    string
+	EncryptedPath string
+}
+
+// Path returns the path of the segment in metainfo.
+func (segment zombieSegment) Path() storj.Path {
+	return storj.JoinPaths(segment.ProjectID, segment.Segment, segment.BucketName, segment.EncryptedPath)
+}
+
+// observer implements the metainfo loop observer interface for detecting zombie segments
+//
+// architecture: Observer
+type observer struct {
+	cutoff      time.Time
+	maxSegments int
+
+	lastProjectID string
+	zombieBuffer  []int
+	objects       zombiedetection.BucketsObjects
+
+	// zombies are the segments queued for deletion.
+	zombies []zombieSegment
+	// limited is set when more zombie segments were detected than could be queued.
+	limited bool
+}
+
+// newObserver creates an observer which detects zombie segments with all
+// segments of their object created before cutoff.
+func newObserver(cutoff time.Time, maxSegments int) *observer {
+	return &observer{
+		cutoff:       cutoff,
+		maxSegments:  maxSegments,
+		zombieBuffer: make([]int, 0),
+		objects:      make(zombiedetection.BucketsObjects),
+	}
+}
+
+// RemoteSegment processes a segment to collect data needed to detect zombie segment.
+func (obsvr *observer) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return obsvr.processSegment(ctx, path, pointer)
+}
+
+// InlineSegment processes a segment to collect data needed to detect zombie segment.
+func (obsvr *observer) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return obsvr.processSegment(ctx, path, pointer)
+}
+
+// Object returns nil because the observer only cares about segments.
+func (obsvr *observer) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+// Fork returns an observer for a range of the metainfo loop.
+func (obsvr *observer) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return newObserver(obsvr.cutoff, obsvr.maxSegments), nil
+}
+
+// Merge analyzes the last project of a forked observer and queues its zombie
+// segments within the limit.
+func (obsvr *observer) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*observer)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+
+	err = fork.analyzeProject(ctx)
+	if err != nil {
+		return err
+	}
+
+	if available := obsvr.maxSegments - len(obsvr.zombies); len(fork.zombies) > available {
+		fork.zombies = fork.zombies[:available]
+		obsvr.limited = true
+	}
+	obsvr.zombies = append(obsvr.zombies, fork.zombies...)
+	obsvr.limited = obsvr.limited || fork.limited
+	return nil
+}
+
+// Distributed returns true, because the zombie segments are deleted by the
+// process which detected them.
+func (obsvr *observer) Distributed() bool {
+	return true
+}
+
+// DistributedName returns the name of the observer for claiming the ranges.
+func (obsvr *observer) DistributedName() string {
+	return "zombie-deletion"
+}
+
+// processSegment aggregates the segments of the objects of a project, the
+// project is analyzed when the segments of the next project start.
+//
+// NOTE it's expected that this method is called continually for the objects
+// which belong to a same project before calling it with objects of another
+// project, which is the order of the metainfo loop.
+func (obsvr *observer) processSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	if obsvr.lastProjectID != "" && obsvr.lastProjectID != path.ProjectIDString {
+		err := obsvr.analyzeProject(ctx)
+		if err != nil {
+			return err
+		}
+
+		// cleanup map to free memory
+		obsvr.clearBucketsObjects()
+	}
+
+	obsvr.lastProjectID = path.ProjectIDString
+
+	object := zombiedetection.FindOrCreate(path.BucketName, path.EncryptedObjectPath, obsvr.objects)
+	if object.Skip {
+		return nil
+	}
+	if pointer.CreationDate.After(obsvr.cutoff) {
+		object.Skip = true
+		// release the memory consumed by the segments because it won't be used
+		// for skip objects
+		object.Segments = nil
+		return nil
+	}
+
+	err := object.AddSegment(path.Segment, pointer)
+	if err != nil {
+		return Error.New("%s: %v", path.Raw, err)
+	}
+	return nil
+}
+
+// analyzeProject analyzes the objects of the last project and queues the
+// zombie segments for deletion.
+func (obsvr *observer) analyzeProject(ctx context.Context) error {
+	for bucket, objects := range obsvr.objects {
+		for path, object := range objects {
+			if object.Skip {
+				continue
+			}
+
+			var err error
+			obsvr.zombieBuffer, err = object.ZombieSegments(obsvr.zombieBuffer[:0])
+			if err != nil {
+				return Error.Wrap(err)
+			}
+
+			for _, segmentIndex := range obsvr.zombieBuffer {
+				obsvr.queueSegment(segmentIndex, bucket, path)
+			}
+		}
+	}
+	return nil
+}
+
+// queueSegment adds the segment to the list of segments to delete.
+func (obsvr *observer) queueSegment(segmentIndex int, bucket, path string) {
+	if len(obsvr.zombies) >= obsvr.maxSegments {
+		obsvr.limited = true
+		return
+	}
+
+	segment := "l"
+	if segmentIndex != zombiedetection.LastSegment {
+		segment = "s" + strconv.Itoa(segmentIndex)
+	}
+
+	obsvr.zombies = append(obsvr.zombies, zombieSegment{
+		ProjectID:     obsvr.lastProjectID,
+		Segment:       segment,
+		BucketName:    bucket,
+		EncryptedPath: path,
+	})
+}
+
+// clearBucketsObjects clears up the buckets objects map for reusing it.
+func (obsvr *observer) clearBucketsObjects() {
+	for b := range obsvr.objects {
+		delete(obsvr.objects, b)
+	}
+}
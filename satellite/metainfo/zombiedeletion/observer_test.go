@@ -0,0 +1,104 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedeletion
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestObserver(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	now := time.Now()
+	old := now.Add(-72 * time.Hour)
+	cutoff := now.Add(-48 * time.Hour)
+
+	lastSegmentMeta := func(numberOfSegments int64) []byte {
+		data, err := pb.Marshal(&pb.StreamMeta{NumberOfSegments: numberOfSegments})
+		require.NoError(t, err)
+		return data
+	}
+
+	type segment struct {
+		project, index, path string
+		created              time.Time
+		numberOfSegments     int64
+	}
+
+	// segments are in the order of the metainfo loop
+	segments := []segment{
+		{project: "p1", index: "l", path: "complete", created: old, numberOfSegments: 3},
+		{project: "p1", index: "l", path: "extra", created: old, numberOfSegments: 2},
+		{project: "p1", index: "l", path: "missing", created: old, numberOfSegments: 3},
+		{project: "p1", index: "s0", path: "complete", created: old},
+		{project: "p1", index: "s0", path: "extra", created: old},
+		{project: "p1", index: "s0", path: "missing", created: old},
+		{project: "p1", index: "s0", path: "pending", created: old},
+		{project: "p1", index: "s0", path: "recent", created: old},
+		{project: "p1", index: "s1", path: "complete", created: old},
+		{project: "p1", index: "s1", path: "pending", created: old},
+		{project: "p1", index: "s1", path: "recent", created: now},
+		{project: "p1", index: "s3", path: "extra", created: old},
+		{project: "p2", index: "s0", path: "pending", created: old},
+	}
+
+	obs := newObserver(cutoff, 100)
+	for _, segment := range segments {
+		pointer := &pb.Pointer{CreationDate: segment.created}
+		if segment.index == "l" {
+			pointer.Metadata = lastSegmentMeta(segment.numberOfSegments)
+		}
+		path := metainfo.ScopedPath{
+			ProjectIDString:     segment.project,
+			Segment:             segment.index,
+			BucketName:          "bucket",
+			EncryptedObjectPath: segment.path,
+			Raw:                 storj.JoinPaths(segment.project, segment.index, "bucket", segment.path),
+		}
+		require.NoError(t, obs.InlineSegment(ctx, path, pointer))
+	}
+	require.NoError(t, obs.analyzeProject(ctx))
+
+	var detected []string
+	for _, zombie := range obs.zombies {
+		detected = append(detected, zombie.Path())
+	}
+	sort.Strings(detected)
+
+	require.Equal(t, []string{
+		"p1/l/bucket/missing",
+		"p1/s0/bucket/missing",
+		"p1/s0/bucket/pending",
+		"p1/s1/bucket/pending",
+		"p1/s3/bucket/extra",
+		"p2/s0/bucket/pending",
+	}, detected)
+	require.False(t, obs.limited)
+
+	t.Run("limit", func(t *testing.T) {
+		obs := newObserver(cutoff, 1)
+		for _, index := range []string{"s0", "s1"} {
+			path := metainfo.ScopedPath{
+				ProjectIDString:     "p1",
+				Segment:             index,
+				BucketName:          "bucket",
+				EncryptedObjectPath: "pending",
+			}
+			require.NoError(t, obs.RemoteSegment(ctx, path, &pb.Pointer{CreationDate: old}))
+		}
+		require.NoError(t, obs.analyzeProject(ctx))
+		require.Len(t, obs.zombies, 1)
+		require.True(t, obs.limited)
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedeletion_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/uplink/private/testuplink"
+)
+
+// TestZombieDeletion does the following:
+// * Upload two objects with two segments each
+// * Remove the last segment of one of them to leave an uncommitted upload
+// * Run the chore and verify that nothing is deleted before the max commit interval
+// * Run the chore as if two days have passed
+// * Verify that only the segment of the uncommitted upload was deleted
+func TestZombieDeletion(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+
+		chore := satellite.Core.ZombieDeletion.Chore
+		chore.Loop.Pause()
+
+		uploadCtx := testuplink.WithMaxSegmentSize(ctx, 5*memory.KiB)
+
+		require.NoError(t, upl.Upload(uploadCtx, satellite, "testbucket", "zombie", testrand.Bytes(8*memory.KiB)))
+		for _, path := range listSegments(ctx, t, satellite.Metainfo.Database) {
+			if storj.SplitPath(path)[1] == "l" {
+				require.NoError(t, satellite.Metainfo.Service.UnsynchronizedDelete(ctx, path))
+			}
+		}
+		require.NoError(t, upl.Upload(uploadCtx, satellite, "testbucket", "complete", testrand.Bytes(8*memory.KiB)))
+
+		stats, err := chore.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Zero(t, stats.Detected)
+		require.Len(t, listSegments(ctx, t, satellite.Metainfo.Database), 3)
+
+		chore.SetNow(func() time.Time {
+			return time.Now().Add(48 * time.Hour)
+		})
+		stats, err = chore.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.Detected)
+		require.Equal(t, 1, stats.Deleted)
+		require.NotZero(t, stats.DeletedBytes)
+
+		segments := listSegments(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, segments, 2)
+		for _, path := range segments {
+			require.Equal(t, "complete", storj.SplitPath(path)[3])
+		}
+	})
+}
+
+// listSegments returns the paths of all segments in metainfo.
+func listSegments(ctx context.Context, t *testing.T, db metainfo.PointerDB) []string {
+	var segments []string
+	err := db.Iterate(ctx, storage.IterateOptions{Recurse: true},
+		func(ctx context.Context, it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(ctx, &item) {
+				segments = append(segments, item.Key.String())
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	return segments
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package zombiedetection contains the rules for detecting zombie segments
+
+Zombie segments are segments which are left in metainfo by uploads which were
+never committed, e.g. segments of objects without a last segment or segments
+which don't belong to the sequence of segments of a committed object.
+
+The segments of the objects of a project are tracked with Object, which
+returns the zombie segments once all segments of the project were seen.
+The rules are shared by the segment-reaper detect command and the
+zombiedeletion chore.
+*/
+package zombiedetection
@@ -0,0 +1,160 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedetection
+
+import (
+	"strconv"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+)
+
+// Error is the default error class for zombie segment detection.
+var Error = errs.Class("zombie detection error")
+
+// LastSegment is the index of the last segment of an object.
+const LastSegment = int(-1)
+
+// Object represents object with segments.
+type Object struct {
+	Segments                 BitArray
+	ExpectedNumberOfSegments int
+	HasLastSegment           bool
+	// if Skip is true then segments from this object shouldn't be treated as zombie segments,
+	// e.g. when one of segments is out of the analyzed date range
+	Skip bool
+}
+
+// BucketsObjects keeps a list of objects associated with their path per bucket
+// name.
+type BucketsObjects map[string]map[storj.Path]*Object
+
+// FindOrCreate returns the object with the path in the bucket, the object is
+// added when it doesn't exist yet.
+func FindOrCreate(bucketName string, path string, buckets BucketsObjects) *Object {
+	objects, ok := buckets[bucketName]
+	if !ok {
+		objects = make(map[storj.Path]*Object)
+		buckets[bucketName] = objects
+	}
+
+	obj, ok := objects[path]
+	if !ok {
+		obj = &Object{Segments: BitArray{}}
+		objects[path] = obj
+	}
+
+	return obj
+}
+
+// AddSegment tracks a segment of the object, segment is "l" for the last segment
+// or "s" followed by the segment index.
+func (object *Object) AddSegment(segment string, pointer *pb.Pointer) error {
+	if segment == "l" {
+		object.HasLastSegment = true
+
+		streamMeta := pb.StreamMeta{}
+		err := pb.Unmarshal(pointer.Metadata, &streamMeta)
+		if err != nil {
+			return Error.New("unexpected error unmarshalling pointer metadata %s", err)
+		}
+
+		if streamMeta.NumberOfSegments > 0 {
+			object.ExpectedNumberOfSegments = int(streamMeta.NumberOfSegments)
+		}
+		return nil
+	}
+
+	if len(segment) < 2 || segment[0] != 's' {
+		return Error.New("invalid segment index: %q", segment)
+	}
+	segmentIndex, err := strconv.Atoi(segment[1:])
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	ok, err := object.Segments.Has(segmentIndex)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if ok {
+		return Error.New("segment is duplicated: %s", segment)
+	}
+
+	return Error.Wrap(object.Segments.Set(segmentIndex))
+}
+
+// ZombieSegments appends the indexes of the zombie segments of the object to
+// buffer, the last segment is appended as LastSegment.
+func (object *Object) ZombieSegments(buffer []int) (_ []int, err error) {
+	if !object.HasLastSegment {
+		return object.appendSegmentsAfter(buffer, 0)
+	}
+
+	segmentsCount := object.Segments.Count()
+
+	switch {
+	// this case is only for old style pointers with encrypted number of segments
+	// value 0 means that we don't know how much segments object should have
+	case object.ExpectedNumberOfSegments == 0:
+		sequenceLength, err := FirstSequenceLength(object.Segments)
+		if err != nil {
+			return buffer, err
+		}
+		return object.appendSegmentsAfter(buffer, sequenceLength)
+	// using 'ExpectedNumberOfSegments-1' because 'Segments' doesn't contain last segment
+	case segmentsCount > object.ExpectedNumberOfSegments-1:
+		sequenceLength, err := FirstSequenceLength(object.Segments)
+		if err != nil {
+			return buffer, err
+		}
+		if sequenceLength == object.ExpectedNumberOfSegments-1 {
+			return object.appendSegmentsAfter(buffer, sequenceLength)
+		}
+		return object.appendAllSegments(buffer)
+	case segmentsCount < object.ExpectedNumberOfSegments-1,
+		segmentsCount == object.ExpectedNumberOfSegments-1 && !object.Segments.IsSequence():
+		return object.appendAllSegments(buffer)
+	}
+
+	return buffer, nil
+}
+
+// appendAllSegments appends the indexes of all object segments, including the last segment.
+func (object *Object) appendAllSegments(buffer []int) ([]int, error) {
+	buffer, err := object.appendSegmentsAfter(buffer, 0)
+	if err != nil {
+		return buffer, err
+	}
+	return append(buffer, LastSegment), nil
+}
+
+// appendSegmentsAfter appends the indexes of the object segments starting from index.
+func (object *Object) appendSegmentsAfter(buffer []int, index int) ([]int, error) {
+	for ; index < object.Segments.Length(); index++ {
+		has, err := object.Segments.Has(index)
+		if err != nil {
+			return buffer, Error.Wrap(err)
+		}
+		if has {
+			buffer = append(buffer, index)
+		}
+	}
+	return buffer, nil
+}
+
+// FirstSequenceLength returns the number of segments in the sequence starting from index 0.
+func FirstSequenceLength(segments BitArray) (int, error) {
+	for index := 0; index < segments.Length(); index++ {
+		has, err := segments.Has(index)
+		if err != nil {
+			return 0, Error.Wrap(err)
+		}
+		if !has {
+			return index, nil
+		}
+	}
+	return segments.Length(), nil
+}
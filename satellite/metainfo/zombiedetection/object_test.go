@@ -0,0 +1,34 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombiedetection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/storj/satellite/metainfo/zombiedetection"
+)
+
+func TestObject(t *testing.T) {
+	lastSegment, err := pb.Marshal(&pb.StreamMeta{NumberOfSegments: 4})
+	require.NoError(t, err)
+
+	object := &zombiedetection.Object{}
+	require.NoError(t, object.AddSegment("s0", &pb.Pointer{}))
+	require.NoError(t, object.AddSegment("s2", &pb.Pointer{}))
+	require.NoError(t, object.AddSegment("s5", &pb.Pointer{}))
+	require.NoError(t, object.AddSegment("l", &pb.Pointer{Metadata: lastSegment}))
+
+	// invalid segments are returned as errors instead of panicking.
+	require.Error(t, object.AddSegment("s-1", &pb.Pointer{}))
+	require.Error(t, object.AddSegment("x1", &pb.Pointer{}))
+	require.Error(t, object.AddSegment("s2", &pb.Pointer{}))
+
+	// the segments aren't a sequence, so all of them are zombies.
+	zombies, err := object.ZombieSegments(nil)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 2, 5, zombiedetection.LastSegment}, zombies)
+}
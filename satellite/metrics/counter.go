@@ -6,6 +6,8 @@ package metrics
 import (
 	"context"
 
+	"github.com/zeebo/errs"
+
 	"storj.io/common/pb"
 	"storj.io/common/rpc/rpcstatus"
 	"storj.io/storj/satellite/metainfo"
@@ -52,3 +54,21 @@ func (counter *Counter) RemoteSegment(ctx context.Context, path metainfo.ScopedP
 func (counter *Counter) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	return nil
 }
+
+// Fork returns a counter for a range of the metainfo loop.
+func (counter *Counter) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return NewCounter(), nil
+}
+
+// Merge adds the counts of a forked counter.
+func (counter *Counter) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	fork, ok := partial.(*Counter)
+	if !ok {
+		return errs.New("unexpected partial observer %T", partial)
+	}
+
+	counter.RemoteDependent += fork.RemoteDependent
+	counter.Inline += fork.Inline
+	counter.Total += fork.Total
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection
+
+// Diversity limits how many pieces of a segment may be stored on nodes of the same
+// operator or in the same autonomous system. Zero values mean no limit.
+type Diversity struct {
+	MaxPerOperator int
+	MaxPerASN      int
+}
+
+// IsZero returns whether the diversity doesn't limit the selection.
+func (diversity Diversity) IsZero() bool {
+	return diversity.MaxPerOperator <= 0 && diversity.MaxPerASN <= 0
+}
+
+// NewCounter returns a counter for the pieces of a single segment.
+func (diversity Diversity) NewCounter() *DiversityCounter {
+	return &DiversityCounter{
+		diversity: diversity,
+		operators: map[string]int{},
+		asns:      map[string]int{},
+	}
+}
+
+// DiversityCounter counts the pieces of a segment per operator and autonomous system.
+// Nodes with an unknown operator or ASN are not limited.
+type DiversityCounter struct {
+	diversity Diversity
+	operators map[string]int
+	asns      map[string]int
+}
+
+// Allows returns whether adding a piece on node stays within the limits.
+func (counter *DiversityCounter) Allows(node *Node) bool {
+	if counter.diversity.MaxPerOperator > 0 && node.Operator != "" &&
+		counter.operators[node.Operator] >= counter.diversity.MaxPerOperator {
+		return false
+	}
+	if counter.diversity.MaxPerASN > 0 && node.ASN != "" &&
+		counter.asns[node.ASN] >= counter.diversity.MaxPerASN {
+		return false
+	}
+	return true
+}
+
+// Add counts a piece on node.
+func (counter *DiversityCounter) Add(node *Node) {
+	if node.Operator != "" {
+		counter.operators[node.Operator]++
+	}
+	if node.ASN != "" {
+		counter.asns[node.ASN]++
+	}
+}
+
+// Exceeded returns whether an operator or an autonomous system holds more pieces than allowed.
+func (counter *DiversityCounter) Exceeded() bool {
+	if counter.diversity.MaxPerOperator > 0 {
+		for _, count := range counter.operators {
+			if count > counter.diversity.MaxPerOperator {
+				return true
+			}
+		}
+	}
+	if counter.diversity.MaxPerASN > 0 {
+		for _, count := range counter.asns {
+			if count > counter.diversity.MaxPerASN {
+				return true
+			}
+		}
+	}
+	return false
+}
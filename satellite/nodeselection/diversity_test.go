@@ -0,0 +1,139 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite/nodeselection"
+)
+
+func TestDiversityCounter(t *testing.T) {
+	require.True(t, nodeselection.Diversity{}.IsZero())
+
+	diversity := nodeselection.Diversity{MaxPerOperator: 2, MaxPerASN: 1}
+	require.False(t, diversity.IsZero())
+
+	a1 := &nodeselection.Node{Operator: "a", ASN: "AS1"}
+	a2 := &nodeselection.Node{Operator: "a", ASN: "AS2"}
+	a3 := &nodeselection.Node{Operator: "a", ASN: "AS3"}
+	b1 := &nodeselection.Node{Operator: "b", ASN: "AS1"}
+	unknown := &nodeselection.Node{}
+
+	counter := diversity.NewCounter()
+	require.True(t, counter.Allows(a1))
+	counter.Add(a1)
+	require.True(t, counter.Allows(a2))
+	counter.Add(a2)
+
+	require.False(t, counter.Allows(a3), "operator limit")
+	require.False(t, counter.Allows(b1), "asn limit")
+	require.True(t, counter.Allows(unknown))
+	require.False(t, counter.Exceeded())
+
+	counter.Add(b1)
+	require.True(t, counter.Exceeded())
+}
+
+func TestState_Select_Diversity(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// 10 nodes of a single operator and 10 nodes of different operators.
+	reputableNodes := createRandomNodes(10, "1.0.1")
+	for _, node := range reputableNodes {
+		node.Operator = "big"
+	}
+	others := createRandomNodes(10, "1.0.2")
+	for i, node := range others {
+		node.Operator = string(rune('a' + i))
+	}
+	reputableNodes = append(reputableNodes, others...)
+
+	state := nodeselection.NewState(reputableNodes, nil)
+
+	for i := 0; i < 20; i++ {
+		selected, err := state.Select(ctx, nodeselection.Request{
+			Count:     6,
+			Diversity: nodeselection.Diversity{MaxPerOperator: 2},
+		})
+		require.NoError(t, err)
+		require.Len(t, selected, 6)
+
+		counts := map[string]int{}
+		for _, node := range selected {
+			counts[node.Operator]++
+		}
+		require.LessOrEqual(t, counts["big"], 2)
+	}
+
+	// the retained nodes hold pieces of the segment already.
+	excludedIDs := []storj.NodeID{reputableNodes[0].ID, reputableNodes[1].ID}
+	selected, err := state.Select(ctx, nodeselection.Request{
+		Count:       6,
+		ExcludedIDs: excludedIDs,
+		Retained:    excludedIDs,
+		Diversity:   nodeselection.Diversity{MaxPerOperator: 2},
+	})
+	require.NoError(t, err)
+	for _, node := range selected {
+		require.NotEqual(t, "big", node.Operator)
+	}
+	require.Len(t, excludedIDs, 2)
+
+	// the excluded nodes which don't keep their pieces aren't counted.
+	bigSelected := false
+	for i := 0; i < 20 && !bigSelected; i++ {
+		selected, err = state.Select(ctx, nodeselection.Request{
+			Count:       10,
+			ExcludedIDs: excludedIDs,
+			Diversity:   nodeselection.Diversity{MaxPerOperator: 2},
+		})
+		require.NoError(t, err)
+		for _, node := range selected {
+			bigSelected = bigSelected || node.Operator == "big"
+		}
+	}
+	require.True(t, bigSelected)
+
+	// there aren't enough operators to satisfy the limit.
+	_, err = state.Select(ctx, nodeselection.Request{
+		Count:     15,
+		Diversity: nodeselection.Diversity{MaxPerOperator: 2},
+	})
+	require.True(t, nodeselection.ErrNotEnoughNodes.Has(err))
+}
+
+func TestState_Select_DiversityDistinct(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// a retained node of the operator and a subnet shared by another node of
+	// the operator and a node of a different operator.
+	retained := createRandomNodes(1, "1.0.1")
+	retained[0].Operator = "big"
+	shared := createRandomNodes(2, "1.0.2")
+	shared[0].Operator = "big"
+	shared[1].Operator = "small"
+
+	state := nodeselection.NewState(append(retained, shared...), nil)
+
+	// rejecting the node of the operator doesn't exclude the subnet.
+	for i := 0; i < 20; i++ {
+		selected, err := state.Select(ctx, nodeselection.Request{
+			Count:       1,
+			Distinct:    true,
+			ExcludedIDs: []storj.NodeID{retained[0].ID},
+			Retained:    []storj.NodeID{retained[0].ID},
+			Diversity:   nodeselection.Diversity{MaxPerOperator: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, selected, 1)
+		require.Equal(t, shared[1].ID, selected[0].ID)
+	}
+}
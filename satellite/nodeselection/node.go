@@ -12,6 +12,16 @@ type Node struct {
 	storj.NodeURL
 	LastNet    string
 	LastIPPort string
+	// Tags are the region tags of the node, see Placement.
+	Tags []string
+	// Operator and ASN are used for diversity limits, empty when unknown.
+	Operator string
+	ASN      string
+
+	// FreeDisk, Latency90 and UploadFailureRate are used by Weighting.
+	FreeDisk          int64
+	Latency90         int64
+	UploadFailureRate float64
 }
 
 // Clone returns a deep clone of the selected node.
@@ -20,5 +30,12 @@ func (node *Node) Clone() *Node {
 		NodeURL:    node.NodeURL,
 		LastNet:    node.LastNet,
 		LastIPPort: node.LastIPPort,
+		Tags:       append([]string(nil), node.Tags...),
+		Operator:   node.Operator,
+		ASN:        node.ASN,
+
+		FreeDisk:          node.FreeDisk,
+		Latency90:         node.Latency90,
+		UploadFailureRate: node.UploadFailureRate,
 	}
 }
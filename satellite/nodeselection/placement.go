@@ -0,0 +1,62 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection
+
+import (
+	"sort"
+	"strings"
+)
+
+// Placement is a set of region tags, such as countries or larger regions, which
+// restricts the nodes that may store the pieces of a segment. A node satisfies the
+// placement when it has at least one of the tags. The zero value allows all nodes.
+type Placement []string
+
+// ParsePlacement parses a comma-separated list of region tags, such as "DE,FR".
+func ParsePlacement(s string) (Placement, error) {
+	var placement Placement
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if strings.ContainsAny(tag, " \t") {
+			return nil, Error.New("invalid region tag %q", tag)
+		}
+		if !placement.contains(tag) {
+			placement = append(placement, tag)
+		}
+	}
+	sort.Strings(placement)
+	return placement, nil
+}
+
+// IsZero returns whether the placement allows all nodes.
+func (placement Placement) IsZero() bool { return len(placement) == 0 }
+
+// String returns the placement in the format accepted by ParsePlacement.
+func (placement Placement) String() string { return strings.Join(placement, ",") }
+
+// Allows returns whether a node with the given region tags satisfies the placement.
+func (placement Placement) Allows(tags []string) bool {
+	if placement.IsZero() {
+		return true
+	}
+	for _, tag := range tags {
+		if placement.contains(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns whether the placement includes tag.
+func (placement Placement) contains(tag string) bool {
+	for _, k := range placement {
+		if k == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/nodeselection"
+)
+
+func TestPlacement(t *testing.T) {
+	placement, err := nodeselection.ParsePlacement(" fr, DE ,,de")
+	require.NoError(t, err)
+	require.Equal(t, nodeselection.Placement{"DE", "FR"}, placement)
+	require.Equal(t, "DE,FR", placement.String())
+
+	require.True(t, placement.Allows([]string{"US", "FR"}))
+	require.False(t, placement.Allows([]string{"US"}))
+	require.False(t, placement.Allows(nil))
+
+	empty, err := nodeselection.ParsePlacement("")
+	require.NoError(t, err)
+	require.True(t, empty.IsZero())
+	require.True(t, empty.Allows(nil))
+
+	_, err = nodeselection.ParsePlacement("DE,United States")
+	require.Error(t, err)
+}
@@ -87,9 +87,9 @@ func (subnets SelectBySubnet) Select(n int, excludedIDs []storj.NodeID, excluded
 	selected := []*Node{}
 	for _, idx := range mathrand.Perm(len(subnets)) {
 		subnet := subnets[idx]
-		node := subnet.Nodes[mathrand.Intn(len(subnet.Nodes))]
+		node := subnetNode(subnet.Nodes, mathrand.Intn(len(subnet.Nodes)), excludedIDs)
 
-		if ContainsID(excludedIDs, node.ID) {
+		if node == nil {
 			continue
 		}
 		if excludedNets != nil {
@@ -108,6 +108,18 @@ func (subnets SelectBySubnet) Select(n int, excludedIDs []storj.NodeID, excluded
 	return selected
 }
 
+// subnetNode returns the node of a subnet at index, or the next one when it's excluded.
+// It returns nil when all the nodes of the subnet are excluded.
+func subnetNode(nodes []*Node, index int, excludedIDs []storj.NodeID) *Node {
+	for i := range nodes {
+		node := nodes[(index+i)%len(nodes)]
+		if !ContainsID(excludedIDs, node.ID) {
+			return node
+		}
+	}
+	return nil
+}
+
 // ContainsID returns whether ids contains id.
 func ContainsID(ids []storj.NodeID, id storj.NodeID) bool {
 	for _, k := range ids {
@@ -22,15 +22,44 @@ type State struct {
 	stats Stats
 	// netByID returns subnet based on storj.NodeID
 	netByID map[storj.NodeID]string
+	// nodeByID returns the node based on storj.NodeID, used for diversity limits.
+	nodeByID map[storj.NodeID]*Node
 	// nonDistinct contains selectors for non-distinct selection.
 	nonDistinct struct {
-		Reputable SelectByID
-		New       SelectByID
+		Reputable Selector
+		New       Selector
 	}
 	// distinct contains selectors for distinct slection.
 	distinct struct {
-		Reputable SelectBySubnet
-		New       SelectBySubnet
+		Reputable Selector
+		New       Selector
+	}
+
+	// reputable and new contain all the nodes for building placement selectors.
+	reputable []*Node
+	new       []*Node
+
+	// weighting and weights are used for the selectors when weighting is enabled.
+	weighting Weighting
+	weights   map[storj.NodeID]float64
+
+	// selections counts how many times each node has been selected.
+	selections *SelectionCounts
+
+	// byPlacement contains lazily created selectors for placements.
+	placementMu sync.Mutex
+	byPlacement map[string]*placementSelectors
+}
+
+// placementSelectors contains the selectors for nodes that satisfy a placement.
+type placementSelectors struct {
+	nonDistinct struct {
+		Reputable Selector
+		New       Selector
+	}
+	distinct struct {
+		Reputable Selector
+		New       Selector
 	}
 }
 
@@ -52,23 +81,84 @@ type Selector interface {
 	Select(n int, excludedIDs []storj.NodeID, excludeNets map[string]struct{}) []*Node
 }
 
+// NodeSelectionStats contains the selection information of a node.
+type NodeSelectionStats struct {
+	ID       storj.NodeID
+	Weight   float64
+	Selected int64
+}
+
+// SelectionCounts counts how many times each node has been selected. The states
+// created by a cache share the counts, so that they survive refreshes.
+type SelectionCounts struct {
+	mu     sync.Mutex
+	counts map[storj.NodeID]int64
+}
+
+// NewSelectionCounts returns empty selection counts.
+func NewSelectionCounts() *SelectionCounts {
+	return &SelectionCounts{counts: map[storj.NodeID]int64{}}
+}
+
+// add counts a selection of every node.
+func (selections *SelectionCounts) add(nodes []*Node) {
+	selections.mu.Lock()
+	defer selections.mu.Unlock()
+
+	for _, node := range nodes {
+		selections.counts[node.ID]++
+	}
+}
+
+// retain forgets the counts of the nodes which aren't in nodes.
+func (selections *SelectionCounts) retain(nodes map[storj.NodeID]string) {
+	selections.mu.Lock()
+	defer selections.mu.Unlock()
+
+	for id := range selections.counts {
+		if _, ok := nodes[id]; !ok {
+			delete(selections.counts, id)
+		}
+	}
+}
+
 // NewState returns a state based on the input.
 func NewState(reputableNodes, newNodes []*Node) *State {
+	return NewWeightedState(reputableNodes, newNodes, Weighting{})
+}
+
+// NewWeightedState returns a state based on the input, which selects nodes based on the weighting.
+func NewWeightedState(reputableNodes, newNodes []*Node, weighting Weighting) *State {
+	return NewWeightedStateWithCounts(reputableNodes, newNodes, weighting, NewSelectionCounts())
+}
+
+// NewWeightedStateWithCounts returns a weighted state, which adds the selections to the
+// counts. The counts of the nodes which aren't in the state are removed.
+func NewWeightedStateWithCounts(reputableNodes, newNodes []*Node, weighting Weighting, selections *SelectionCounts) *State {
 	state := &State{}
+	state.weighting = weighting
+	state.weights = weighting.Weights(append(append([]*Node{}, reputableNodes...), newNodes...))
+	state.selections = selections
 
 	state.netByID = map[storj.NodeID]string{}
+	state.nodeByID = map[storj.NodeID]*Node{}
 	for _, node := range reputableNodes {
 		state.netByID[node.ID] = node.LastNet
+		state.nodeByID[node.ID] = node
 	}
 	for _, node := range newNodes {
 		state.netByID[node.ID] = node.LastNet
+		state.nodeByID[node.ID] = node
 	}
 
-	state.nonDistinct.Reputable = SelectByID(reputableNodes)
-	state.nonDistinct.New = SelectByID(newNodes)
+	state.selections.retain(state.netByID)
 
-	state.distinct.Reputable = SelectBySubnetFromNodes(reputableNodes)
-	state.distinct.New = SelectBySubnetFromNodes(newNodes)
+	state.nonDistinct.Reputable, state.distinct.Reputable = state.selectors(reputableNodes)
+	state.nonDistinct.New, state.distinct.New = state.selectors(newNodes)
+
+	state.reputable = reputableNodes
+	state.new = newNodes
+	state.byPlacement = map[string]*placementSelectors{}
 
 	state.stats = Stats{
 		New:       state.nonDistinct.New.Count(),
@@ -87,6 +177,14 @@ type Request struct {
 	NewFraction float64
 	Distinct    bool
 	ExcludedIDs []storj.NodeID
+	// Placement restricts the selection to nodes with matching region tags.
+	Placement Placement
+	// Diversity limits the pieces per operator and ASN, counting the retained
+	// nodes as already holding pieces of the segment.
+	Diversity Diversity
+	// Retained are the nodes which keep their pieces of the segment. Only the
+	// retained nodes in the state, which are online and healthy, are counted.
+	Retained []storj.NodeID
 }
 
 // Select selects requestedCount nodes where there will be newFraction nodes.
@@ -119,15 +217,38 @@ func (state *State) Select(ctx context.Context, request Request) (_ []*Node, err
 		newNodes = state.nonDistinct.New
 	}
 
+	if !request.Placement.IsZero() {
+		selectors := state.placementSelectors(request.Placement)
+		if request.Distinct {
+			reputableNodes = selectors.distinct.Reputable
+			newNodes = selectors.distinct.New
+		} else {
+			reputableNodes = selectors.nonDistinct.Reputable
+			newNodes = selectors.nonDistinct.New
+		}
+	}
+
+	var counter *DiversityCounter
+	if !request.Diversity.IsZero() {
+		counter = request.Diversity.NewCounter()
+		for _, id := range request.Retained {
+			if node, ok := state.nodeByID[id]; ok {
+				counter.Add(node)
+			}
+		}
+	}
+
 	// Get a random selection of new nodes out of the cache first so that if there aren't
 	// enough new nodes on the network, we can fall back to using reputable nodes instead.
 	selected = append(selected,
-		newNodes.Select(newCount, request.ExcludedIDs, excludedNets)...)
+		selectDiverse(newNodes, newCount, request.ExcludedIDs, excludedNets, counter)...)
 
 	// Get all the remaining reputable nodes.
 	reputableCount := totalCount - len(selected)
 	selected = append(selected,
-		reputableNodes.Select(reputableCount, request.ExcludedIDs, excludedNets)...)
+		selectDiverse(reputableNodes, reputableCount, request.ExcludedIDs, excludedNets, counter)...)
+
+	state.recordSelections(selected)
 
 	if len(selected) < totalCount {
 		return selected, ErrNotEnoughNodes.New("requested from cache %d, found %d", totalCount, len(selected))
@@ -142,3 +263,113 @@ func (state *State) Stats() Stats {
 
 	return state.stats
 }
+
+// SelectionStats returns the weight and selection count of every node.
+func (state *State) SelectionStats() []NodeSelectionStats {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	state.selections.mu.Lock()
+	defer state.selections.mu.Unlock()
+
+	stats := make([]NodeSelectionStats, 0, len(state.netByID))
+	for id := range state.netByID {
+		stats = append(stats, NodeSelectionStats{
+			ID:       id,
+			Weight:   state.weights[id],
+			Selected: state.selections.counts[id],
+		})
+	}
+	return stats
+}
+
+// recordSelections updates the selection counts and metrics for the selected nodes.
+func (state *State) recordSelections(selected []*Node) {
+	state.selections.add(selected)
+	for _, node := range selected {
+		mon.FloatVal("selected_node_weight").Observe(state.weights[node.ID])
+	}
+}
+
+// selectors returns the non-distinct and distinct selectors for nodes.
+func (state *State) selectors(nodes []*Node) (nonDistinct, distinct Selector) {
+	if state.weighting.Enabled() {
+		return SelectByWeightFromNodes(nodes, state.weights), SelectBySubnetWeightFromNodes(nodes, state.weights)
+	}
+	return SelectByID(nodes), SelectBySubnetFromNodes(nodes)
+}
+
+// placementSelectors returns the selectors for nodes that satisfy the placement,
+// creating them on first use.
+func (state *State) placementSelectors(placement Placement) *placementSelectors {
+	state.placementMu.Lock()
+	defer state.placementMu.Unlock()
+
+	key := placement.String()
+	if selectors, ok := state.byPlacement[key]; ok {
+		return selectors
+	}
+
+	reputableNodes := filterByPlacement(state.reputable, placement)
+	newNodes := filterByPlacement(state.new, placement)
+
+	selectors := &placementSelectors{}
+	selectors.nonDistinct.Reputable, selectors.distinct.Reputable = state.selectors(reputableNodes)
+	selectors.nonDistinct.New, selectors.distinct.New = state.selectors(newNodes)
+
+	state.byPlacement[key] = selectors
+	return selectors
+}
+
+// selectDiverse selects upto n nodes from selector, skipping the nodes which would
+// exceed the diversity limits of counter. Without a counter it's the same as selector.Select.
+//
+// The selector excludes the networks of all the candidates it returns, hence it gets
+// a copy of excludedNets and only the networks of the accepted nodes are excluded.
+func selectDiverse(selector Selector, n int, excludedIDs []storj.NodeID, excludedNets map[string]struct{}, counter *DiversityCounter) []*Node {
+	if counter == nil {
+		return selector.Select(n, excludedIDs, excludedNets)
+	}
+
+	// the candidates of the previous rounds, whether accepted or rejected.
+	candidateIDs := append([]storj.NodeID{}, excludedIDs...)
+
+	var selected []*Node
+	for len(selected) < n {
+		var candidateNets map[string]struct{}
+		if excludedNets != nil {
+			candidateNets = make(map[string]struct{}, len(excludedNets))
+			for net := range excludedNets {
+				candidateNets[net] = struct{}{}
+			}
+		}
+
+		candidates := selector.Select(n-len(selected), candidateIDs, candidateNets)
+		if len(candidates) == 0 {
+			break
+		}
+		for _, node := range candidates {
+			candidateIDs = append(candidateIDs, node.ID)
+			if !counter.Allows(node) {
+				continue
+			}
+			counter.Add(node)
+			selected = append(selected, node)
+			if excludedNets != nil {
+				excludedNets[node.LastNet] = struct{}{}
+			}
+		}
+	}
+	return selected
+}
+
+// filterByPlacement returns the nodes which satisfy the placement.
+func filterByPlacement(nodes []*Node, placement Placement) []*Node {
+	var filtered []*Node
+	for _, node := range nodes {
+		if placement.Allows(node.Tags) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
@@ -161,6 +161,48 @@ func TestState_Select_Concurrent(t *testing.T) {
 	require.NoError(t, group.Wait())
 }
 
+func TestState_Select_Placement(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	german := tagNodes(createRandomNodes(3, "1.0.1"), "DE", "EU")
+	french := tagNodes(createRandomNodes(3, "1.0.2"), "FR", "EU")
+	american := tagNodes(createRandomNodes(3, "1.0.3"), "US")
+	untagged := createRandomNodes(3, "1.0.4")
+
+	state := nodeselection.NewState(joinNodes(german, french, american, untagged), nil)
+
+	placement, err := nodeselection.ParsePlacement("de")
+	require.NoError(t, err)
+
+	selected, err := state.Select(ctx, nodeselection.Request{
+		Count:     3,
+		Placement: placement,
+	})
+	require.NoError(t, err)
+	require.Len(t, intersectLists(selected, german), 3)
+
+	// only a single node per subnet is selected when distinct.
+	placement, err = nodeselection.ParsePlacement("EU")
+	require.NoError(t, err)
+
+	selected, err = state.Select(ctx, nodeselection.Request{
+		Count:     2,
+		Distinct:  true,
+		Placement: placement,
+	})
+	require.NoError(t, err)
+	require.Len(t, intersectLists(selected, german), 1)
+	require.Len(t, intersectLists(selected, french), 1)
+
+	_, err = state.Select(ctx, nodeselection.Request{
+		Count:     3,
+		Distinct:  true,
+		Placement: placement,
+	})
+	require.True(t, nodeselection.ErrNotEnoughNodes.Has(err))
+}
+
 // createRandomNodes creates n random nodes all in the subnet.
 func createRandomNodes(n int, subnet string) []*nodeselection.Node {
 	xs := make([]*nodeselection.Node, n)
@@ -178,6 +220,14 @@ func createRandomNodes(n int, subnet string) []*nodeselection.Node {
 	return xs
 }
 
+// tagNodes sets the region tags of the nodes.
+func tagNodes(nodes []*nodeselection.Node, tags ...string) []*nodeselection.Node {
+	for _, node := range nodes {
+		node.Tags = tags
+	}
+	return nodes
+}
+
 // joinNodes appends all slices into a single slice.
 func joinNodes(lists ...[]*nodeselection.Node) []*nodeselection.Node {
 	xs := []*nodeselection.Node{}
@@ -0,0 +1,229 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection
+
+import (
+	"math"
+	mathrand "math/rand" // Using mathrand here because crypto-graphic randomness is not required and simplifies code.
+	"sort"
+
+	"storj.io/common/storj"
+)
+
+// Weighting configures how node selection favours nodes by their capacity and performance.
+type Weighting struct {
+	// Performance blends between fair selection (0), where every node is equally likely
+	// to be selected, and selection proportional to the score of the node (1).
+	Performance float64
+}
+
+// Enabled returns whether the weighting changes the selection probabilities.
+func (weighting Weighting) Enabled() bool { return weighting.Performance > 0 }
+
+// Weights returns the selection weight of every node.
+//
+// The score of a node is the product of its free disk relative to the node with
+// the most free disk, its upload success rate and a latency factor, which is 1/2
+// for a node with the median latency. The weight blends the score with 1 based on
+// the Performance setting.
+func (weighting Weighting) Weights(nodes []*Node) map[storj.NodeID]float64 {
+	blend := math.Min(math.Max(weighting.Performance, 0), 1)
+
+	var maxFreeDisk int64
+	var latencies []int64
+	for _, node := range nodes {
+		if node.FreeDisk > maxFreeDisk {
+			maxFreeDisk = node.FreeDisk
+		}
+		if node.Latency90 > 0 {
+			latencies = append(latencies, node.Latency90)
+		}
+	}
+
+	var medianLatency int64
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, k int) bool { return latencies[i] < latencies[k] })
+		medianLatency = latencies[len(latencies)/2]
+	}
+
+	weights := make(map[storj.NodeID]float64, len(nodes))
+	for _, node := range nodes {
+		score := 1.0
+		if maxFreeDisk > 0 {
+			score *= math.Max(float64(node.FreeDisk), 0) / float64(maxFreeDisk)
+		}
+		if medianLatency > 0 && node.Latency90 > 0 {
+			score *= float64(medianLatency) / float64(medianLatency+node.Latency90)
+		}
+		score *= 1 - math.Min(math.Max(node.UploadFailureRate, 0), 1)
+
+		weights[node.ID] = (1 - blend) + blend*score
+	}
+	return weights
+}
+
+// SelectByWeight implements selection from nodes with the probability of every node
+// proportional to its weight.
+type SelectByWeight struct {
+	nodes   []*Node
+	weights []float64
+}
+
+var _ Selector = (*SelectByWeight)(nil)
+
+// SelectByWeightFromNodes creates SelectByWeight selector from nodes.
+func SelectByWeightFromNodes(nodes []*Node, weights map[storj.NodeID]float64) *SelectByWeight {
+	selector := &SelectByWeight{
+		nodes:   nodes,
+		weights: make([]float64, len(nodes)),
+	}
+	for i, node := range nodes {
+		selector.weights[i] = weights[node.ID]
+	}
+	return selector
+}
+
+// Count returns the number of maximum number of nodes that it can return.
+func (selector *SelectByWeight) Count() int { return len(selector.nodes) }
+
+// Select selects upto n nodes.
+func (selector *SelectByWeight) Select(n int, excludedIDs []storj.NodeID, excludedNets map[string]struct{}) []*Node {
+	if n <= 0 {
+		return nil
+	}
+
+	selected := []*Node{}
+	for _, idx := range weightedPerm(selector.weights) {
+		node := selector.nodes[idx]
+
+		if ContainsID(excludedIDs, node.ID) {
+			continue
+		}
+		if excludedNets != nil {
+			if _, excluded := excludedNets[node.LastNet]; excluded {
+				continue
+			}
+			excludedNets[node.LastNet] = struct{}{}
+		}
+
+		selected = append(selected, node.Clone())
+		if len(selected) >= n {
+			break
+		}
+	}
+
+	return selected
+}
+
+// SelectBySubnetWeight implements selection from nodes with every subnet having a
+// probability proportional to the average weight of its nodes.
+type SelectBySubnetWeight struct {
+	subnets []weightedSubnet
+	weights []float64
+}
+
+var _ Selector = (*SelectBySubnetWeight)(nil)
+
+// weightedSubnet groups together nodes with the same subnet, along with their weights.
+type weightedSubnet struct {
+	Net     string
+	Nodes   []*Node
+	Weights []float64
+}
+
+// SelectBySubnetWeightFromNodes creates SelectBySubnetWeight selector from nodes.
+func SelectBySubnetWeightFromNodes(nodes []*Node, weights map[storj.NodeID]float64) *SelectBySubnetWeight {
+	selector := &SelectBySubnetWeight{}
+	for _, subnet := range SelectBySubnetFromNodes(nodes) {
+		weighted := weightedSubnet{
+			Net:     subnet.Net,
+			Nodes:   subnet.Nodes,
+			Weights: make([]float64, len(subnet.Nodes)),
+		}
+
+		var total float64
+		for i, node := range subnet.Nodes {
+			weighted.Weights[i] = weights[node.ID]
+			total += weighted.Weights[i]
+		}
+
+		selector.subnets = append(selector.subnets, weighted)
+		selector.weights = append(selector.weights, total/float64(len(subnet.Nodes)))
+	}
+	return selector
+}
+
+// Count returns the number of maximum number of nodes that it can return.
+func (selector *SelectBySubnetWeight) Count() int { return len(selector.subnets) }
+
+// Select selects upto n nodes.
+func (selector *SelectBySubnetWeight) Select(n int, excludedIDs []storj.NodeID, excludedNets map[string]struct{}) []*Node {
+	if n <= 0 {
+		return nil
+	}
+
+	selected := []*Node{}
+	for _, idx := range weightedPerm(selector.weights) {
+		subnet := selector.subnets[idx]
+		node := subnetNode(subnet.Nodes, weightedIndex(subnet.Weights), excludedIDs)
+
+		if node == nil {
+			continue
+		}
+		if excludedNets != nil {
+			if _, excluded := excludedNets[node.LastNet]; excluded {
+				continue
+			}
+			excludedNets[node.LastNet] = struct{}{}
+		}
+
+		selected = append(selected, node.Clone())
+		if len(selected) >= n {
+			break
+		}
+	}
+
+	return selected
+}
+
+// weightedPerm returns a random permutation of the indices of weights, where the
+// probability of an index coming before the others is proportional to its weight.
+//
+// It uses the method of Efraimidis and Spirakis: every index gets the key
+// u^(1/weight) for uniformly random u, and indices are ordered by decreasing key.
+// Indices with zero weight come last.
+func weightedPerm(weights []float64) []int {
+	keys := make([]float64, len(weights))
+	perm := make([]int, len(weights))
+	for i, weight := range weights {
+		perm[i] = i
+		if weight > 0 {
+			keys[i] = math.Pow(mathrand.Float64(), 1/weight)
+		} else {
+			keys[i] = -mathrand.Float64()
+		}
+	}
+	sort.Slice(perm, func(i, k int) bool { return keys[perm[i]] > keys[perm[k]] })
+	return perm
+}
+
+// weightedIndex returns a random index of weights, with the probability proportional to its weight.
+func weightedIndex(weights []float64) int {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total <= 0 {
+		return mathrand.Intn(len(weights))
+	}
+
+	target := mathrand.Float64() * total
+	for i, weight := range weights {
+		target -= weight
+		if target < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeselection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/nodeselection"
+)
+
+func TestWeighting_Weights(t *testing.T) {
+	empty := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, FreeDisk: 0}
+	half := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, FreeDisk: memory.TB.Int64()}
+	full := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, FreeDisk: 2 * memory.TB.Int64()}
+	failing := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, FreeDisk: 2 * memory.TB.Int64(), UploadFailureRate: 0.75}
+	nodes := []*nodeselection.Node{empty, half, full, failing}
+
+	fair := nodeselection.Weighting{}.Weights(nodes)
+	for _, node := range nodes {
+		assert.Equal(t, 1.0, fair[node.ID])
+	}
+
+	weights := nodeselection.Weighting{Performance: 1}.Weights(nodes)
+	assert.InDelta(t, 0.0, weights[empty.ID], 1e-9)
+	assert.InDelta(t, 0.5, weights[half.ID], 1e-9)
+	assert.InDelta(t, 1.0, weights[full.ID], 1e-9)
+	assert.InDelta(t, 0.25, weights[failing.ID], 1e-9)
+
+	blended := nodeselection.Weighting{Performance: 0.5}.Weights(nodes)
+	assert.InDelta(t, 0.5, blended[empty.ID], 1e-9)
+	assert.InDelta(t, 0.75, blended[half.ID], 1e-9)
+
+	slow := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, Latency90: 300}
+	median := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, Latency90: 100}
+	fast := &nodeselection.Node{NodeURL: storj.NodeURL{ID: testrand.NodeID()}, Latency90: 50}
+	latency := nodeselection.Weighting{Performance: 1}.Weights([]*nodeselection.Node{slow, median, fast})
+	assert.InDelta(t, 0.5, latency[median.ID], 1e-9)
+	assert.Less(t, latency[slow.ID], latency[median.ID])
+	assert.Greater(t, latency[fast.ID], latency[median.ID])
+}
+
+func TestSelectByWeight(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// create 3 nodes on distinct subnets, one having three times the free disk of the others.
+	small1 := createRandomNodes(1, "1.0.1")[0]
+	small2 := createRandomNodes(1, "1.0.2")[0]
+	large := createRandomNodes(1, "1.0.3")[0]
+	small1.FreeDisk, small2.FreeDisk, large.FreeDisk = 1, 1, 3
+
+	nodes := []*nodeselection.Node{small1, small2, large}
+	weights := nodeselection.Weighting{Performance: 1}.Weights(nodes)
+
+	const executionCount = 10000
+
+	for _, selector := range []nodeselection.Selector{
+		nodeselection.SelectByWeightFromNodes(nodes, weights),
+		nodeselection.SelectBySubnetWeightFromNodes(nodes, weights),
+	} {
+		require.Equal(t, 3, selector.Count())
+
+		selectedNodeCount := map[storj.NodeID]int{}
+		for i := 0; i < executionCount; i++ {
+			selected := selector.Select(1, nil, nil)
+			require.Len(t, selected, 1)
+			selectedNodeCount[selected[0].ID]++
+		}
+
+		// the large node should be selected 3/5 of the time and the small ones 1/5.
+		assert.InDelta(t, 0.6, float64(selectedNodeCount[large.ID])/executionCount, 0.05)
+		assert.InDelta(t, 0.2, float64(selectedNodeCount[small1.ID])/executionCount, 0.05)
+		assert.InDelta(t, 0.2, float64(selectedNodeCount[small2.ID])/executionCount, 0.05)
+
+		// excluded nodes are never selected, even if they have the highest weight.
+		selected := selector.Select(3, []storj.NodeID{large.ID}, map[string]struct{}{})
+		require.Len(t, selected, 2)
+		for _, node := range selected {
+			require.NotEqual(t, large.ID, node.ID)
+		}
+	}
+}
+
+func TestState_SelectionStats(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	reputableNodes := createRandomNodes(5, "1.0.1")
+	for i, node := range reputableNodes {
+		node.FreeDisk = int64(i + 1)
+	}
+
+	state := nodeselection.NewWeightedState(reputableNodes, nil, nodeselection.Weighting{Performance: 1})
+
+	const executionCount = 100
+	for i := 0; i < executionCount; i++ {
+		selected, err := state.Select(ctx, nodeselection.Request{Count: 2})
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+	}
+
+	stats := state.SelectionStats()
+	require.Len(t, stats, len(reputableNodes))
+
+	var total int64
+	for _, stat := range stats {
+		assert.Greater(t, stat.Weight, 0.0)
+		total += stat.Selected
+	}
+	assert.Equal(t, int64(2*executionCount), total)
+}
+
+func TestState_SelectionCountsSurviveRefresh(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	reputableNodes := createRandomNodes(5, "1.0.1")
+	counts := nodeselection.NewSelectionCounts()
+
+	state := nodeselection.NewWeightedStateWithCounts(reputableNodes, nil, nodeselection.Weighting{}, counts)
+	for i := 0; i < 10; i++ {
+		_, err := state.Select(ctx, nodeselection.Request{Count: 5})
+		require.NoError(t, err)
+	}
+
+	// a refreshed state without the first node keeps the counts of the others.
+	state = nodeselection.NewWeightedStateWithCounts(reputableNodes[1:], nil, nodeselection.Weighting{}, counts)
+	_, err := state.Select(ctx, nodeselection.Request{Count: 4})
+	require.NoError(t, err)
+
+	stats := state.SelectionStats()
+	require.Len(t, stats, 4)
+	for _, stat := range stats {
+		assert.NotEqual(t, reputableNodes[0].ID, stat.ID)
+		assert.EqualValues(t, 11, stat.Selected)
+	}
+}
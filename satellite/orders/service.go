@@ -499,10 +499,24 @@ func (service *Service) CreateDeleteOrderLimits(ctx context.Context, bucketID []
 
 // CreateAuditOrderLimits creates the order limits for auditing the pieces of pointer.
 func (service *Service) CreateAuditOrderLimits(ctx context.Context, bucketID []byte, pointer *pb.Pointer, skip map[storj.NodeID]bool) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+	shareSize := pointer.GetRemote().GetRedundancy().GetErasureShareSize()
+	return service.createAuditOrderLimits(ctx, bucketID, pointer, skip, int64(shareSize))
+}
+
+// CreateAuditPieceOrderLimits creates the order limits for auditing the pieces of pointer
+// by downloading them in full.
+func (service *Service) CreateAuditPieceOrderLimits(ctx context.Context, bucketID []byte, pointer *pb.Pointer, skip map[storj.NodeID]bool, pieceSize int64) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.createAuditOrderLimits(ctx, bucketID, pointer, skip, pieceSize)
+}
+
+// createAuditOrderLimits creates the order limits for auditing the pieces of pointer,
+// which allow downloading limit bytes of every piece.
+func (service *Service) createAuditOrderLimits(ctx context.Context, bucketID []byte, pointer *pb.Pointer, skip map[storj.NodeID]bool, limit int64) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 	rootPieceID := pointer.GetRemote().RootPieceId
 	redundancy := pointer.GetRemote().GetRedundancy()
-	shareSize := redundancy.GetErasureShareSize()
 	totalPieces := redundancy.GetTotal()
 
 	pieceExpiration := pointer.ExpirationDate
@@ -551,7 +565,7 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, bucketID []b
 			StorageNodeId:    piece.NodeId,
 			PieceId:          rootPieceID.Derive(piece.NodeId, piece.PieceNum),
 			Action:           pb.PieceAction_GET_AUDIT,
-			Limit:            int64(shareSize),
+			Limit:            limit,
 			PieceExpiration:  pieceExpiration,
 			OrderCreation:    time.Now(),
 			OrderExpiration:  orderExpiration,
@@ -10,6 +10,7 @@ import (
 	"github.com/zeebo/errs"
 
 	"storj.io/common/memory"
+	"storj.io/storj/satellite/nodeselection"
 )
 
 var (
@@ -22,7 +23,9 @@ var (
 type Config struct {
 	Node                 NodeSelectionConfig
 	NodeSelectionCache   CacheConfig
-	UpdateStatsBatchSize int `help:"number of update requests to process per transaction" default:"100"`
+	UpdateStatsBatchSize int    `help:"number of update requests to process per transaction" default:"100"`
+	RegionsFile          string `help:"path to a file mapping ip ranges to region tags, which are used for bucket placement" default:""`
+	ASNFile              string `help:"path to a file mapping ip ranges to autonomous system numbers, which are used for piece diversity" default:""`
 }
 
 // NodeSelectionConfig is a configuration struct to determine the minimum
@@ -43,4 +46,15 @@ type NodeSelectionConfig struct {
 	AuditReputationDQ           float64       `help:"the reputation cut-off for disqualifying SNs based on audit history" default:"0.6"`
 	SuspensionGracePeriod       time.Duration `help:"the time period that must pass before suspended nodes will be disqualified" releaseDefault:"168h" devDefault:"1h"`
 	SuspensionDQEnabled         bool          `help:"whether nodes will be disqualified if they have been suspended for longer than the suspended grace period" releaseDefault:"false" devDefault:"true"`
+	PerformanceWeight           float64       `help:"blend between fair (0) and free disk, upload success and latency weighted (1) node selection" default:"0"`
+	MaxPiecesPerOperator        int           `help:"maximum number of pieces of a segment stored by nodes with the same operator wallet or email, 0 disables the limit" default:"0"`
+	MaxPiecesPerASN             int           `help:"maximum number of pieces of a segment stored by nodes in the same autonomous system, 0 disables the limit" default:"0"`
+}
+
+// Diversity returns the diversity limits for node selection.
+func (config *NodeSelectionConfig) Diversity() nodeselection.Diversity {
+	return nodeselection.Diversity{
+		MaxPerOperator: config.MaxPiecesPerOperator,
+		MaxPerASN:      config.MaxPiecesPerASN,
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/spacemonkeygo/monkit/v3"
 	"go.uber.org/zap"
 
 	"storj.io/common/pb"
@@ -37,20 +38,29 @@ type NodeSelectionCache struct {
 	db              CacheDB
 	selectionConfig NodeSelectionConfig
 	staleness       time.Duration
+	ipdbs           *IPDatabases
+	uploads         *UploadStats
+	selections      *nodeselection.SelectionCounts
 
 	mu          sync.RWMutex
 	lastRefresh time.Time
 	state       *nodeselection.State
 }
 
-// NewNodeSelectionCache creates a new cache that keeps a list of all the storage nodes that are qualified to store data
-func NewNodeSelectionCache(log *zap.Logger, db CacheDB, staleness time.Duration, config NodeSelectionConfig) *NodeSelectionCache {
-	return &NodeSelectionCache{
+// NewNodeSelectionCache creates a new cache that keeps a list of all the storage nodes that are qualified to store data.
+// The ip databases are used to tag the nodes for placement and diversity, they may be nil.
+func NewNodeSelectionCache(log *zap.Logger, db CacheDB, staleness time.Duration, config NodeSelectionConfig, ipdbs *IPDatabases) *NodeSelectionCache {
+	cache := &NodeSelectionCache{
 		log:             log,
 		db:              db,
 		staleness:       staleness,
 		selectionConfig: config,
+		ipdbs:           ipdbs,
+		uploads:         NewUploadStats(),
+		selections:      nodeselection.NewSelectionCounts(),
 	}
+	mon.Chain(cache)
+	return cache
 }
 
 // Refresh populates the cache with all of the reputableNodes and newNode nodes
@@ -79,7 +89,12 @@ func (cache *NodeSelectionCache) refresh(ctx context.Context) (state *nodeselect
 	}
 
 	cache.lastRefresh = time.Now().UTC()
-	cache.state = nodeselection.NewState(convSelectedNodesToNodes(reputableNodes), convSelectedNodesToNodes(newNodes))
+	cache.state = nodeselection.NewWeightedStateWithCounts(
+		cache.convSelectedNodesToNodes(reputableNodes),
+		cache.convSelectedNodesToNodes(newNodes),
+		nodeselection.Weighting{Performance: cache.selectionConfig.PerformanceWeight},
+		cache.selections,
+	)
 
 	mon.IntVal("refresh_cache_size_reputable").Observe(int64(len(reputableNodes)))
 	mon.IntVal("refresh_cache_size_new").Observe(int64(len(newNodes)))
@@ -105,11 +120,43 @@ func (cache *NodeSelectionCache) GetNodes(ctx context.Context, req FindStorageNo
 		}
 	}
 
+	return cache.selectFrom(ctx, state, req, &cache.selectionConfig)
+}
+
+// GetNodesWithPreferences selects nodes like GetNodes, but it honors the preferences
+// when they differ from the configuration of the cache. In that case the nodes
+// qualifying for the preferences are queried from the database, without caching them.
+func (cache *NodeSelectionCache) GetNodesWithPreferences(ctx context.Context, req FindStorageNodesRequest, preferences *NodeSelectionConfig) (_ []*SelectedNode, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if *preferences == cache.selectionConfig {
+		return cache.GetNodes(ctx, req)
+	}
+
+	reputableNodes, newNodes, err := cache.db.SelectAllStorageNodesUpload(ctx, *preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	state := nodeselection.NewWeightedStateWithCounts(
+		cache.convSelectedNodesToNodes(reputableNodes),
+		cache.convSelectedNodesToNodes(newNodes),
+		nodeselection.Weighting{Performance: preferences.PerformanceWeight},
+		cache.selections,
+	)
+	return cache.selectFrom(ctx, state, req, preferences)
+}
+
+// selectFrom selects the nodes for req from state using the selection config.
+func (cache *NodeSelectionCache) selectFrom(ctx context.Context, state *nodeselection.State, req FindStorageNodesRequest, config *NodeSelectionConfig) (_ []*SelectedNode, err error) {
 	selected, err := state.Select(ctx, nodeselection.Request{
 		Count:       req.RequestedCount,
-		NewFraction: cache.selectionConfig.NewNodeFraction,
-		Distinct:    cache.selectionConfig.DistinctIP,
+		NewFraction: config.NewNodeFraction,
+		Distinct:    config.DistinctIP,
 		ExcludedIDs: req.ExcludedIDs,
+		Placement:   req.Placement,
+		Diversity:   config.Diversity(),
+		Retained:    req.RetainedIDs,
 	})
 	if nodeselection.ErrNotEnoughNodes.Has(err) {
 		err = ErrNotEnoughNodes.Wrap(err)
@@ -144,7 +191,57 @@ func convNodesToSelectedNodes(nodes []*nodeselection.Node) (xs []*SelectedNode)
 	return xs
 }
 
-func convSelectedNodesToNodes(nodes []*SelectedNode) (xs []*nodeselection.Node) {
+// SelectionStats returns the weight and selection count of every node in the cache.
+func (cache *NodeSelectionCache) SelectionStats() []nodeselection.NodeSelectionStats {
+	cache.mu.RLock()
+	state := cache.state
+	cache.mu.RUnlock()
+
+	if state == nil {
+		return nil
+	}
+	return state.SelectionStats()
+}
+
+// Stats implements monkit.StatSource, it reports the spread of the weights and
+// selection counts of the nodes in the cache.
+func (cache *NodeSelectionCache) Stats(cb func(key monkit.SeriesKey, field string, val float64)) {
+	stats := cache.SelectionStats()
+	if len(stats) == 0 {
+		return
+	}
+
+	minWeight, maxWeight, totalWeight := stats[0].Weight, stats[0].Weight, 0.0
+	minSelected, maxSelected, totalSelected := stats[0].Selected, stats[0].Selected, int64(0)
+	for _, stat := range stats {
+		if stat.Weight < minWeight {
+			minWeight = stat.Weight
+		}
+		if stat.Weight > maxWeight {
+			maxWeight = stat.Weight
+		}
+		totalWeight += stat.Weight
+
+		if stat.Selected < minSelected {
+			minSelected = stat.Selected
+		}
+		if stat.Selected > maxSelected {
+			maxSelected = stat.Selected
+		}
+		totalSelected += stat.Selected
+	}
+
+	key := monkit.NewSeriesKey("node_selection_cache")
+	cb(key, "nodes", float64(len(stats)))
+	cb(key, "weight_min", minWeight)
+	cb(key, "weight_max", maxWeight)
+	cb(key, "weight_avg", totalWeight/float64(len(stats)))
+	cb(key, "selected_min", float64(minSelected))
+	cb(key, "selected_max", float64(maxSelected))
+	cb(key, "selected_total", float64(totalSelected))
+}
+
+func (cache *NodeSelectionCache) convSelectedNodesToNodes(nodes []*SelectedNode) (xs []*nodeselection.Node) {
 	for _, n := range nodes {
 		xs = append(xs, &nodeselection.Node{
 			NodeURL: storj.NodeURL{
@@ -153,6 +250,13 @@ func convSelectedNodesToNodes(nodes []*SelectedNode) (xs []*nodeselection.Node)
 			},
 			LastNet:    n.LastNet,
 			LastIPPort: n.LastIPPort,
+			Tags:       cache.ipdbs.NodeTags(n),
+			Operator:   n.Operator,
+			ASN:        cache.ipdbs.NodeASN(n.LastIPPort, n.LastNet),
+
+			FreeDisk:          n.FreeDisk,
+			Latency90:         n.Latency90,
+			UploadFailureRate: cache.uploads.FailureRate(n.ID),
 		})
 	}
 	return xs
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -21,6 +22,7 @@ import (
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
 )
@@ -53,6 +55,7 @@ func TestRefresh(t *testing.T) {
 			db.OverlayCache(),
 			lowStaleness,
 			nodeSelectionConfig,
+			nil,
 		)
 		// the cache should have no nodes to start
 		err := cache.Refresh(ctx)
@@ -118,10 +121,11 @@ func addNodesToNodesTable(ctx context.Context, t *testing.T, db overlay.DB, coun
 }
 
 type mockdb struct {
-	mu        sync.Mutex
-	callCount int
-	reputable []*overlay.SelectedNode
-	new       []*overlay.SelectedNode
+	mu         sync.Mutex
+	callCount  int
+	lastConfig overlay.NodeSelectionConfig
+	reputable  []*overlay.SelectedNode
+	new        []*overlay.SelectedNode
 }
 
 func (m *mockdb) SelectAllStorageNodesUpload(ctx context.Context, selectionCfg overlay.NodeSelectionConfig) (reputable, new []*overlay.SelectedNode, err error) {
@@ -129,6 +133,7 @@ func (m *mockdb) SelectAllStorageNodesUpload(ctx context.Context, selectionCfg o
 	defer m.mu.Unlock()
 	sync2.Sleep(ctx, 500*time.Millisecond)
 	m.callCount++
+	m.lastConfig = selectionCfg
 
 	reputable = make([]*overlay.SelectedNode, len(m.reputable))
 	for i, n := range m.reputable {
@@ -153,6 +158,7 @@ func TestRefreshConcurrent(t *testing.T) {
 		&mockDB,
 		highStaleness,
 		nodeSelectionConfig,
+		nil,
 	)
 
 	var group errgroup.Group
@@ -174,6 +180,7 @@ func TestRefreshConcurrent(t *testing.T) {
 		&mockDB,
 		lowStaleness,
 		nodeSelectionConfig,
+		nil,
 	)
 	group.Go(func() error {
 		return cache.Refresh(ctx)
@@ -202,6 +209,7 @@ func TestGetNodes(t *testing.T) {
 			db.OverlayCache(),
 			lowStaleness,
 			nodeSelectionConfig,
+			nil,
 		)
 		// the cache should have no nodes to start
 		reputable, new := cache.Size()
@@ -256,6 +264,7 @@ func TestGetNodesConcurrent(t *testing.T) {
 		&mockDB,
 		highStaleness,
 		nodeSelectionConfig,
+		nil,
 	)
 
 	var group errgroup.Group
@@ -296,6 +305,7 @@ func TestGetNodesConcurrent(t *testing.T) {
 		&mockDB,
 		lowStaleness,
 		nodeSelectionConfig,
+		nil,
 	)
 
 	group.Go(func() error {
@@ -383,6 +393,7 @@ func TestGetNodesDistinct(t *testing.T) {
 			&mockDB,
 			highStaleness,
 			config,
+			nil,
 		)
 
 		// selecting 3 should be possible
@@ -411,6 +422,7 @@ func TestGetNodesDistinct(t *testing.T) {
 			&mockDB,
 			highStaleness,
 			config,
+			nil,
 		)
 
 		_, err := cache.GetNodes(ctx, overlay.FindStorageNodesRequest{
@@ -420,6 +432,108 @@ func TestGetNodesDistinct(t *testing.T) {
 	}
 }
 
+func TestGetNodesWithPreferences(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	var reputableNodes []*overlay.SelectedNode
+	for i := 0; i < 3; i++ {
+		address := "127.0.0." + strconv.Itoa(i+1)
+		reputableNodes = append(reputableNodes, &overlay.SelectedNode{
+			ID:         testrand.NodeID(),
+			Address:    &pb.NodeAddress{Address: address},
+			LastNet:    "127.0.0",
+			LastIPPort: address + ":8000",
+			Tags:       []string{"DE", "EU"},
+		})
+	}
+
+	mockDB := mockdb{reputable: reputableNodes}
+	config := nodeSelectionConfig
+	config.DistinctIP = true
+	cache := overlay.NewNodeSelectionCache(zap.NewNop(),
+		&mockDB,
+		highStaleness,
+		config,
+		nil,
+	)
+
+	placement, err := nodeselection.ParsePlacement("DE")
+	require.NoError(t, err)
+	req := overlay.FindStorageNodesRequest{
+		RequestedCount: 3,
+		Placement:      placement,
+	}
+
+	// the nodes share a network, so the configuration of the cache doesn't allow selecting all of them.
+	_, err = cache.GetNodesWithPreferences(ctx, req, &config)
+	require.Error(t, err)
+	require.Equal(t, 1, mockDB.callCount)
+
+	// the preferences are honored by querying the nodes with them.
+	preferences := config
+	preferences.DistinctIP = false
+	preferences.NewNodeFraction = 0
+	nodes, err := cache.GetNodesWithPreferences(ctx, req, &preferences)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	require.Equal(t, 2, mockDB.callCount)
+	require.Equal(t, preferences, mockDB.lastConfig)
+
+	// the reported tags of the nodes don't satisfy other placements.
+	req.Placement, err = nodeselection.ParsePlacement("US")
+	require.NoError(t, err)
+	_, err = cache.GetNodesWithPreferences(ctx, req, &preferences)
+	require.Error(t, err)
+}
+
+func TestSelectionStats(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	var reputableNodes []*overlay.SelectedNode
+	for i := 0; i < 3; i++ {
+		address := "127.0." + strconv.Itoa(i) + ".1"
+		reputableNodes = append(reputableNodes, &overlay.SelectedNode{
+			ID:         testrand.NodeID(),
+			Address:    &pb.NodeAddress{Address: address},
+			LastNet:    "127.0." + strconv.Itoa(i),
+			LastIPPort: address + ":8000",
+		})
+	}
+
+	mockDB := mockdb{reputable: reputableNodes}
+	cache := overlay.NewNodeSelectionCache(zap.NewNop(),
+		&mockDB,
+		lowStaleness,
+		nodeSelectionConfig,
+		nil,
+	)
+
+	// every GetNodes call refreshes the cache, the counts survive the refreshes.
+	for i := 0; i < 2; i++ {
+		nodes, err := cache.GetNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 3})
+		require.NoError(t, err)
+		require.Len(t, nodes, 3)
+	}
+	require.Equal(t, 2, mockDB.callCount)
+
+	stats := cache.SelectionStats()
+	require.Len(t, stats, 3)
+	for _, stat := range stats {
+		require.EqualValues(t, 2, stat.Selected)
+	}
+
+	reported := map[string]float64{}
+	cache.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		reported[field] = val
+	})
+	require.EqualValues(t, 3, reported["nodes"])
+	require.EqualValues(t, 6, reported["selected_total"])
+	require.EqualValues(t, 2, reported["selected_min"])
+	require.EqualValues(t, 2, reported["selected_max"])
+}
+
 func TestGetNodesError(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
@@ -429,6 +543,7 @@ func TestGetNodesError(t *testing.T) {
 		&mockDB,
 		highStaleness,
 		nodeSelectionConfig,
+		nil,
 	)
 
 	// there should be 0 nodes in the cache
@@ -458,6 +573,7 @@ func TestNewNodeFraction(t *testing.T) {
 			db.OverlayCache(),
 			lowStaleness,
 			nodeSelectionConfig,
+			nil,
 		)
 		// the cache should have no nodes to start
 		err := cache.Refresh(ctx)
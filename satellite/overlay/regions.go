@@ -0,0 +1,167 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// Regions maps ip ranges to region tags, such as countries or larger regions.
+//
+// The operator maintains the ranges in a file, where every non-empty line not
+// starting with # contains an ip range in CIDR notation followed by one or more
+// tags, e.g. "85.10.0.0/16 DE EU". When ranges overlap, the most specific one wins.
+type Regions struct {
+	ranges []ipRange
+}
+
+// ipRange is a single entry of Regions.
+type ipRange struct {
+	network *net.IPNet
+	ones    int
+	tags    []string
+}
+
+// LoadRegions loads the regions from the file at path.
+func LoadRegions(path string) (_ *Regions, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	return ParseRegions(file)
+}
+
+// ParseRegions parses the regions from r.
+func ParseRegions(r io.Reader) (*Regions, error) {
+	regions := &Regions{}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, Error.New("regions line %d: expected <cidr> <tag>...", lineNumber)
+		}
+
+		_, network, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return nil, Error.New("regions line %d: %v", lineNumber, err)
+		}
+		ones, _ := network.Mask.Size()
+
+		tags := make([]string, 0, len(fields)-1)
+		for _, tag := range fields[1:] {
+			tags = append(tags, strings.ToUpper(tag))
+		}
+
+		regions.ranges = append(regions.ranges, ipRange{
+			network: network,
+			ones:    ones,
+			tags:    tags,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// check the most specific ranges first.
+	sort.SliceStable(regions.ranges, func(i, k int) bool {
+		return regions.ranges[i].ones > regions.ranges[k].ones
+	})
+
+	return regions, nil
+}
+
+// Lookup returns the tags of the most specific range containing ip.
+func (regions *Regions) Lookup(ip net.IP) []string {
+	if regions == nil || ip == nil {
+		return nil
+	}
+	for _, r := range regions.ranges {
+		if r.network.Contains(ip) {
+			return r.tags
+		}
+	}
+	return nil
+}
+
+// NodeTags returns the tags of a node, based on the address it last checked in from.
+func (regions *Regions) NodeTags(lastIPPort, lastNet string) []string {
+	if regions == nil {
+		return nil
+	}
+	host := lastIPPort
+	if h, _, err := net.SplitHostPort(lastIPPort); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return regions.Lookup(ip)
+	}
+	return regions.Lookup(net.ParseIP(lastNet))
+}
+
+// IPDatabases contains the operator maintained databases about ip ranges.
+type IPDatabases struct {
+	// Regions maps ip ranges to region tags, which are used for placement.
+	Regions *Regions
+	// ASNs maps ip ranges to autonomous system numbers, which are used for diversity.
+	// It uses the same format as Regions with the ASN as the tag, e.g. "85.10.0.0/16 AS24940".
+	ASNs *Regions
+}
+
+// LoadIPDatabases loads the ip range databases configured in config.
+func LoadIPDatabases(config Config) (_ *IPDatabases, err error) {
+	dbs := &IPDatabases{}
+	if config.RegionsFile != "" {
+		dbs.Regions, err = LoadRegions(config.RegionsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.ASNFile != "" {
+		dbs.ASNs, err = LoadRegions(config.ASNFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dbs, nil
+}
+
+// NodeTags returns the region tags of a node. The ranges maintained by the
+// operator take precedence, because they don't depend on what the node claims.
+// The tags reported by the node at check-in are used when no range contains
+// the address it last checked in from.
+func (dbs *IPDatabases) NodeTags(node *SelectedNode) []string {
+	if dbs != nil {
+		if tags := dbs.Regions.NodeTags(node.LastIPPort, node.LastNet); len(tags) > 0 {
+			return tags
+		}
+	}
+	return node.Tags
+}
+
+// NodeASN returns the autonomous system of a node, empty when unknown.
+func (dbs *IPDatabases) NodeASN(lastIPPort, lastNet string) string {
+	if dbs == nil {
+		return ""
+	}
+	tags := dbs.ASNs.NodeTags(lastIPPort, lastNet)
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
+}
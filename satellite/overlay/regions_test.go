@@ -0,0 +1,81 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestRegions(t *testing.T) {
+	regions, err := overlay.ParseRegions(strings.NewReader(`
+# operator maintained ranges
+85.0.0.0/8 eu
+85.10.0.0/16 DE EU
+2001:db8::/32 FR EU
+`))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"DE", "EU"}, regions.Lookup(net.ParseIP("85.10.1.2")))
+	require.Equal(t, []string{"EU"}, regions.Lookup(net.ParseIP("85.11.1.2")))
+	require.Nil(t, regions.Lookup(net.ParseIP("10.0.0.1")))
+
+	require.Equal(t, []string{"DE", "EU"}, regions.NodeTags("85.10.1.2:28967", ""))
+	require.Equal(t, []string{"FR", "EU"}, regions.NodeTags("[2001:db8::1]:28967", ""))
+	require.Equal(t, []string{"DE", "EU"}, regions.NodeTags("", "85.10.1.0"))
+
+	var none *overlay.Regions
+	require.Nil(t, none.NodeTags("85.10.1.2:28967", ""))
+
+	_, err = overlay.ParseRegions(strings.NewReader("85.10.0.0/16"))
+	require.Error(t, err)
+	_, err = overlay.ParseRegions(strings.NewReader("85.10.0.0 DE"))
+	require.Error(t, err)
+}
+
+func TestIPDatabases(t *testing.T) {
+	asns, err := overlay.ParseRegions(strings.NewReader(`
+85.10.0.0/16 AS24940
+`))
+	require.NoError(t, err)
+
+	dbs := &overlay.IPDatabases{ASNs: asns}
+	require.Equal(t, "AS24940", dbs.NodeASN("85.10.1.2:28967", ""))
+	require.Equal(t, "", dbs.NodeASN("10.0.0.1:28967", ""))
+	require.Nil(t, dbs.NodeTags(&overlay.SelectedNode{LastIPPort: "85.10.1.2:28967"}))
+
+	var none *overlay.IPDatabases
+	require.Equal(t, "", none.NodeASN("85.10.1.2:28967", ""))
+	require.Nil(t, none.NodeTags(&overlay.SelectedNode{LastIPPort: "85.10.1.2:28967"}))
+}
+
+func TestIPDatabasesReportedTags(t *testing.T) {
+	regions, err := overlay.ParseRegions(strings.NewReader(`
+85.10.0.0/16 DE EU
+`))
+	require.NoError(t, err)
+	dbs := &overlay.IPDatabases{Regions: regions}
+
+	// the ranges of the operator take precedence over the reported tags.
+	require.Equal(t, []string{"DE", "EU"}, dbs.NodeTags(&overlay.SelectedNode{
+		LastIPPort: "85.10.1.2:28967",
+		Tags:       []string{"US"},
+	}))
+	// the reported tags are used for addresses outside of the ranges.
+	require.Equal(t, []string{"FR", "EU"}, dbs.NodeTags(&overlay.SelectedNode{
+		LastIPPort: "10.0.0.1:28967",
+		Tags:       []string{"FR", "EU"},
+	}))
+
+	var none *overlay.IPDatabases
+	require.Equal(t, []string{"US"}, none.NodeTags(&overlay.SelectedNode{
+		LastIPPort: "85.10.1.2:28967",
+		Tags:       []string{"US"},
+	}))
+}
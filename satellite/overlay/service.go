@@ -14,6 +14,7 @@ import (
 
 	"storj.io/common/pb"
 	"storj.io/common/storj"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/storage"
 )
 
@@ -53,6 +54,12 @@ type DB interface {
 	KnownReliable(ctx context.Context, onlineWindow time.Duration, nodeIDs storj.NodeIDList) ([]*pb.Node, error)
 	// Reliable returns all nodes that are reliable
 	Reliable(context.Context, *NodeCriteria) (storj.NodeIDList, error)
+	// ReliableNodes returns the network and operator information of all reliable nodes.
+	ReliableNodes(context.Context, *NodeCriteria) ([]*SelectedNode, error)
+	// ReliableAtRisk returns the reliable nodes which are exiting or not yet vetted.
+	ReliableAtRisk(context.Context, *NodeCriteria) (exiting, unvetted storj.NodeIDList, err error)
+	// AuditStates returns the nodes which are prioritized by audits and the time to vetting of the nodes vetted after vettedSince.
+	AuditStates(ctx context.Context, vettedSince time.Time) (*AuditStates, error)
 	// BatchUpdateStats updates multiple storagenode's stats in one transaction
 	BatchUpdateStats(ctx context.Context, updateRequests []*UpdateRequest, batchSize int) (failed storj.NodeIDList, err error)
 	// UpdateStats all parts of single storagenode's stats.
@@ -63,6 +70,8 @@ type DB interface {
 	UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool) (stats *NodeStats, err error)
 	// UpdateCheckIn updates a single storagenode's check-in stats.
 	UpdateCheckIn(ctx context.Context, node NodeCheckInInfo, timestamp time.Time, config NodeSelectionConfig) (err error)
+	// UpdateNodeTags replaces the region tags reported by a storagenode.
+	UpdateNodeTags(ctx context.Context, nodeID storj.NodeID, tags []string) (err error)
 
 	// AllPieceCounts returns a map of node IDs to piece counts from the db.
 	AllPieceCounts(ctx context.Context) (pieceCounts map[storj.NodeID]int, err error)
@@ -113,7 +122,9 @@ type NodeCheckInInfo struct {
 type FindStorageNodesRequest struct {
 	RequestedCount int
 	ExcludedIDs    []storj.NodeID
-	MinimumVersion string // semver or empty
+	MinimumVersion string                  // semver or empty
+	Placement      nodeselection.Placement // empty allows all nodes
+	RetainedIDs    []storj.NodeID          // nodes keeping pieces of the segment, counted for diversity
 }
 
 // NodeCriteria are the requirements for selecting nodes
@@ -193,6 +204,15 @@ type NodeDossier struct {
 	LastIPPort   string
 }
 
+// AuditStates contains the nodes in the states, which are prioritized by audits.
+type AuditStates struct {
+	Unvetted  storj.NodeIDList
+	Suspended storj.NodeIDList
+	Contained storj.NodeIDList
+	// TimeToVetting contains how long it took for recently vetted nodes to become vetted.
+	TimeToVetting []time.Duration
+}
+
 // NodeStats contains statistics about a node.
 type NodeStats struct {
 	Latency90                   int64
@@ -225,6 +245,13 @@ type SelectedNode struct {
 	Address    *pb.NodeAddress
 	LastNet    string
 	LastIPPort string
+	// Operator is the wallet or email of the node operator, used for diversity.
+	Operator string
+	// Tags are the region tags reported by the node, used for placement.
+	Tags []string
+	// FreeDisk and Latency90 are used for weighted node selection.
+	FreeDisk  int64
+	Latency90 int64
 }
 
 // Clone returns a deep clone of the selected node.
@@ -237,6 +264,10 @@ func (node *SelectedNode) Clone() *SelectedNode {
 		},
 		LastNet:    node.LastNet,
 		LastIPPort: node.LastIPPort,
+		Operator:   node.Operator,
+		Tags:       append([]string(nil), node.Tags...),
+		FreeDisk:   node.FreeDisk,
+		Latency90:  node.Latency90,
 	}
 }
 
@@ -247,24 +278,38 @@ type Service struct {
 	log            *zap.Logger
 	db             DB
 	config         Config
+	ipdbs          *IPDatabases
 	SelectionCache *NodeSelectionCache
 }
 
 // NewService returns a new Service
-func NewService(log *zap.Logger, db DB, config Config) *Service {
+func NewService(log *zap.Logger, db DB, config Config) (*Service, error) {
+	ipdbs, err := LoadIPDatabases(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
 		log:    log,
 		db:     db,
 		config: config,
+		ipdbs:  ipdbs,
 		SelectionCache: NewNodeSelectionCache(log, db,
-			config.NodeSelectionCache.Staleness, config.Node,
+			config.NodeSelectionCache.Staleness, config.Node, ipdbs,
 		),
-	}
+	}, nil
 }
 
 // Close closes resources
 func (service *Service) Close() error { return nil }
 
+// RecordUploads records which of the nodes of the order limits stored a piece of
+// the committed segment, which is used for weighted node selection.
+func (service *Service) RecordUploads(ctx context.Context, limits []*pb.OrderLimit, pieces []*pb.RemotePiece) {
+	defer mon.Task()(&ctx)(nil)
+	service.SelectionCache.uploads.Record(limits, pieces)
+}
+
 // Inspect lists limited number of items in the cache
 func (service *Service) Inspect(ctx context.Context) (_ storage.Keys, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -337,6 +382,12 @@ func (service *Service) FindStorageNodesForUpload(ctx context.Context, req FindS
 func (service *Service) FindStorageNodesWithPreferences(ctx context.Context, req FindStorageNodesRequest, preferences *NodeSelectionConfig) (nodes []*SelectedNode, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	// the database doesn't know the regions and autonomous systems of the nodes,
+	// hence only the selection cache is able to honor the placement and diversity.
+	if !req.Placement.IsZero() || !preferences.Diversity().IsZero() {
+		return service.SelectionCache.GetNodesWithPreferences(ctx, req, preferences)
+	}
+
 	// TODO: add sanity limits to requested node count
 	// TODO: add sanity limits to excluded nodes
 	totalNeededNodes := req.RequestedCount
@@ -412,6 +463,54 @@ func (service *Service) Reliable(ctx context.Context) (nodes storj.NodeIDList, e
 	return service.db.Reliable(ctx, criteria)
 }
 
+// ReliableAtRisk returns the reliable nodes which are likely to lose their pieces,
+// because they are exiting or not yet vetted.
+func (service *Service) ReliableAtRisk(ctx context.Context) (exiting, unvetted storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+	criteria := &NodeCriteria{
+		AuditCount:   service.config.Node.AuditCount,
+		UptimeCount:  service.config.Node.UptimeCount,
+		OnlineWindow: service.config.Node.OnlineWindow,
+	}
+	return service.db.ReliableAtRisk(ctx, criteria)
+}
+
+// AuditStates returns the nodes in the states, which are prioritized by audits,
+// and how long it took for the nodes vetted after vettedSince to become vetted.
+func (service *Service) AuditStates(ctx context.Context, vettedSince time.Time) (_ *AuditStates, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.db.AuditStates(ctx, vettedSince)
+}
+
+// Diversity returns the configured diversity limits for the pieces of a segment.
+func (service *Service) Diversity() nodeselection.Diversity {
+	return service.config.Node.Diversity()
+}
+
+// ReliableDiversityNodes returns the operator and autonomous system of all reliable nodes.
+func (service *Service) ReliableDiversityNodes(ctx context.Context) (_ map[storj.NodeID]*nodeselection.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+	criteria := &NodeCriteria{
+		OnlineWindow: service.config.Node.OnlineWindow,
+	}
+	selected, err := service.db.ReliableNodes(ctx, criteria)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	nodes := make(map[storj.NodeID]*nodeselection.Node, len(selected))
+	for _, node := range selected {
+		nodes[node.ID] = &nodeselection.Node{
+			NodeURL:    storj.NodeURL{ID: node.ID, Address: node.Address.Address},
+			LastNet:    node.LastNet,
+			LastIPPort: node.LastIPPort,
+			Operator:   node.Operator,
+			ASN:        service.ipdbs.NodeASN(node.LastIPPort, node.LastNet),
+		}
+	}
+	return nodes, nil
+}
+
 // BatchUpdateStats updates multiple storagenode's stats in one transaction
 func (service *Service) BatchUpdateStats(ctx context.Context, requests []*UpdateRequest) (failed storj.NodeIDList, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -461,6 +560,12 @@ func (service *Service) UpdateCheckIn(ctx context.Context, node NodeCheckInInfo,
 	return service.db.UpdateCheckIn(ctx, node, timestamp, service.config.Node)
 }
 
+// UpdateNodeTags replaces the region tags reported by a storagenode.
+func (service *Service) UpdateNodeTags(ctx context.Context, nodeID storj.NodeID, tags []string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.db.UpdateNodeTags(ctx, nodeID, tags)
+}
+
 // GetSuccesfulNodesNotCheckedInSince returns all nodes that last check-in was successful, but haven't checked-in within a given duration.
 func (service *Service) GetSuccesfulNodesNotCheckedInSince(ctx context.Context, duration time.Duration) (nodeLastContacts []NodeLastContact, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -490,6 +595,36 @@ func (service *Service) GetMissingPieces(ctx context.Context, pieces []*pb.Remot
 	return missingPieces, nil
 }
 
+// MisplacedPieces returns the piece numbers of pieces which are stored on online nodes
+// outside of the placement.
+func (service *Service) MisplacedPieces(ctx context.Context, placement nodeselection.Placement, pieces []*pb.RemotePiece) (misplacedPieces []int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if placement.IsZero() {
+		return nil, nil
+	}
+
+	var nodeIDs storj.NodeIDList
+	for _, p := range pieces {
+		nodeIDs = append(nodeIDs, p.NodeId)
+	}
+	nodes, err := service.db.GetOnlineNodesForGetDelete(ctx, nodeIDs, service.config.Node.OnlineWindow)
+	if err != nil {
+		return nil, Error.New("error getting nodes %s", err)
+	}
+
+	for _, p := range pieces {
+		node, ok := nodes[p.NodeId]
+		if !ok {
+			// offline nodes are handled as missing pieces.
+			continue
+		}
+		if !placement.Allows(service.ipdbs.NodeTags(node)) {
+			misplacedPieces = append(misplacedPieces, p.GetPieceNum())
+		}
+	}
+	return misplacedPieces, nil
+}
+
 // DisqualifyNode disqualifies a storage node.
 func (service *Service) DisqualifyNode(ctx context.Context, nodeID storj.NodeID) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -0,0 +1,62 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"sync"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+)
+
+// uploadStatsDecay is how much a single upload changes the failure rate of a node.
+const uploadStatsDecay = 0.05
+
+// UploadStats tracks the recent upload failure rate of nodes.
+//
+// An upload counts as failed when the node received an order limit, but its piece
+// wasn't part of the committed segment. This includes nodes which were too slow
+// and got cut off by the uplink's long tail cancellation.
+type UploadStats struct {
+	mu          sync.Mutex
+	failureRate map[storj.NodeID]float64
+}
+
+// NewUploadStats returns a new upload failure rate tracker.
+func NewUploadStats() *UploadStats {
+	return &UploadStats{
+		failureRate: map[storj.NodeID]float64{},
+	}
+}
+
+// Record records the outcome of uploads to the nodes of the order limits.
+func (stats *UploadStats) Record(limits []*pb.OrderLimit, pieces []*pb.RemotePiece) {
+	uploaded := make(map[storj.NodeID]struct{}, len(pieces))
+	for _, piece := range pieces {
+		uploaded[piece.NodeId] = struct{}{}
+	}
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	for _, limit := range limits {
+		if limit == nil {
+			continue
+		}
+		failed := 1.0
+		if _, ok := uploaded[limit.StorageNodeId]; ok {
+			failed = 0
+		}
+		rate := stats.failureRate[limit.StorageNodeId]
+		stats.failureRate[limit.StorageNodeId] = rate*(1-uploadStatsDecay) + failed*uploadStatsDecay
+	}
+}
+
+// FailureRate returns the recent upload failure rate of the node, 0 when unknown.
+func (stats *UploadStats) FailureRate(nodeID storj.NodeID) float64 {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	return stats.failureRate[nodeID]
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/common/pb"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestUploadStats(t *testing.T) {
+	stats := overlay.NewUploadStats()
+
+	fast, slow := testrand.NodeID(), testrand.NodeID()
+	limits := []*pb.OrderLimit{
+		{StorageNodeId: fast},
+		nil,
+		{StorageNodeId: slow},
+	}
+	pieces := []*pb.RemotePiece{
+		{PieceNum: 0, NodeId: fast},
+	}
+
+	assert.Zero(t, stats.FailureRate(fast))
+	assert.Zero(t, stats.FailureRate(slow))
+
+	for i := 0; i < 100; i++ {
+		stats.Record(limits, pieces)
+	}
+
+	assert.Zero(t, stats.FailureRate(fast))
+	assert.InDelta(t, 1, stats.FailureRate(slow), 0.01)
+	assert.Zero(t, stats.FailureRate(testrand.NodeID()))
+
+	// a successful upload lowers the failure rate again.
+	rate := stats.FailureRate(slow)
+	stats.Record(limits[2:], []*pb.RemotePiece{{NodeId: slow}})
+	assert.Less(t, stats.FailureRate(slow), rate)
+}
@@ -32,7 +32,9 @@ import (
 	"storj.io/storj/satellite/mailservice"
 	"storj.io/storj/satellite/marketingweb"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/metainfo/bucketlifecycle"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
+	"storj.io/storj/satellite/metainfo/zombiedeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -86,6 +88,10 @@ type DB interface {
 	Containment() audit.Containment
 	// Buckets returns the database to interact with buckets
 	Buckets() metainfo.BucketsDB
+	// SegmentReferences returns the database tracking segments which share pieces
+	SegmentReferences() metainfo.SegmentReferencesDB
+	// MetainfoLoopRanges returns the database coordinating the metainfo loop ranges between processes
+	MetainfoLoopRanges() metainfo.LoopRangesDB
 	// GracefulExit returns database for graceful exit
 	GracefulExit() gracefulexit.DB
 	// StripeCoinPayments returns stripecoinpayments database.
@@ -120,6 +126,10 @@ type Config struct {
 
 	ExpiredDeletion expireddeletion.Config
 
+	BucketLifecycle bucketlifecycle.Config
+
+	ZombieDeletion zombiedeletion.Config
+
 	DBCleanup dbcleanup.Config
 
 	Tally          tally.Config
@@ -16,8 +16,11 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/satellite/downtime"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair"
 	"storj.io/storj/satellite/repair/irreparable"
 	"storj.io/storj/satellite/repair/queue"
 )
@@ -35,6 +38,8 @@ type Config struct {
 
 	ReliabilityCacheStaleness time.Duration `help:"how stale reliable node cache can be" releaseDefault:"5m" devDefault:"5m"`
 	RepairOverride            int           `help:"override value for repair threshold" default:"0"`
+	ChurnWindow               time.Duration `help:"time window of tracked node downtime used for estimating the node churn for segment health" default:"720h"`
+	DurabilityReportPath      string        `help:"path of a JSON file to write the durability report of every checker run to, disabled when empty" default:""`
 }
 
 // durabilityStats remote segment information
@@ -45,11 +50,33 @@ type durabilityStats struct {
 	newRemoteSegmentsNeedingRepair int64
 	remoteSegmentsLost             int64
 	remoteSegmentsFailedToCheck    int64
+	remoteSegmentsMisplaced        int64
+	remoteSegmentsOverDiversityCap int64
 	remoteSegmentInfo              []string
 	// remoteSegmentsOverThreshold[0]=# of healthy=rt+1, remoteSegmentsOverThreshold[1]=# of healthy=rt+2, etc...
 	remoteSegmentsOverThreshold [5]int64
 }
 
+// merge adds the stats collected by another observer.
+func (stats *durabilityStats) merge(other *durabilityStats) {
+	stats.objectsChecked += other.objectsChecked
+	stats.remoteSegmentsChecked += other.remoteSegmentsChecked
+	stats.remoteSegmentsNeedingRepair += other.remoteSegmentsNeedingRepair
+	stats.newRemoteSegmentsNeedingRepair += other.newRemoteSegmentsNeedingRepair
+	stats.remoteSegmentsLost += other.remoteSegmentsLost
+	stats.remoteSegmentsFailedToCheck += other.remoteSegmentsFailedToCheck
+	stats.remoteSegmentsMisplaced += other.remoteSegmentsMisplaced
+	stats.remoteSegmentsOverDiversityCap += other.remoteSegmentsOverDiversityCap
+	for _, info := range other.remoteSegmentInfo {
+		if !contains(stats.remoteSegmentInfo, info) {
+			stats.remoteSegmentInfo = append(stats.remoteSegmentInfo, info)
+		}
+	}
+	for i := range stats.remoteSegmentsOverThreshold {
+		stats.remoteSegmentsOverThreshold[i] += other.remoteSegmentsOverThreshold[i]
+	}
+}
+
 // Checker contains the information needed to do checks for missing pieces
 //
 // architecture: Chore
@@ -59,14 +86,19 @@ type Checker struct {
 	irrdb           irreparable.DB
 	metainfo        *metainfo.Service
 	metaLoop        *metainfo.Loop
+	overlay         *overlay.Service
+	downtime        downtime.DB
 	nodestate       *ReliabilityCache
 	repairOverride  int32
+	rsProfiles      metainfo.RSProfiles
+	churnWindow     time.Duration
+	reportPath      string
 	Loop            *sync2.Cycle
 	IrreparableLoop *sync2.Cycle
 }
 
 // NewChecker creates a new instance of checker
-func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irreparable.DB, metainfo *metainfo.Service, metaLoop *metainfo.Loop, overlay *overlay.Service, config Config) *Checker {
+func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irreparable.DB, metainfo *metainfo.Service, metaLoop *metainfo.Loop, overlay *overlay.Service, downtime downtime.DB, rsProfiles metainfo.RSProfiles, config Config) *Checker {
 	return &Checker{
 		logger: logger,
 
@@ -74,8 +106,13 @@ func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irrepar
 		irrdb:          irrdb,
 		metainfo:       metainfo,
 		metaLoop:       metaLoop,
+		overlay:        overlay,
+		downtime:       downtime,
 		nodestate:      NewReliabilityCache(overlay, config.ReliabilityCacheStaleness),
 		repairOverride: int32(config.RepairOverride),
+		rsProfiles:     rsProfiles,
+		churnWindow:    config.ChurnWindow,
+		reportPath:     config.DurabilityReportPath,
 
 		Loop:            sync2.NewCycle(config.Interval),
 		IrreparableLoop: sync2.NewCycle(config.IrreparableInterval),
@@ -114,14 +151,39 @@ func (checker *Checker) Close() error {
 func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	var diversity nodeselection.Diversity
+	var diversityNodes map[storj.NodeID]*nodeselection.Node
+	if checker.overlay != nil && !checker.overlay.Diversity().IsZero() {
+		diversity = checker.overlay.Diversity()
+		diversityNodes, err = checker.overlay.ReliableDiversityNodes(ctx)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	churn, err := checker.estimateChurn(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
 	observer := &checkerObserver{
 		repairQueue:    checker.repairQueue,
 		irrdb:          checker.irrdb,
+		metainfo:       checker.metainfo,
+		overlay:        checker.overlay,
 		nodestate:      checker.nodestate,
+		placements:     map[string]nodeselection.Placement{},
+		diversity:      diversity,
+		diversityNodes: diversityNodes,
 		monStats:       durabilityStats{},
 		overrideRepair: checker.repairOverride,
+		rsProfiles:     checker.rsProfiles,
+		churn:          churn,
 		log:            checker.logger,
 	}
+	if checker.reportPath != "" {
+		observer.report = newDurabilityReporter()
+	}
 	err = checker.metaLoop.Join(ctx, observer)
 	if err != nil {
 		if !errs2.IsCanceled(err) {
@@ -142,6 +204,22 @@ func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error)
 	mon.IntVal("remote_segments_over_threshold_3").Observe(observer.monStats.remoteSegmentsOverThreshold[2])   //locked
 	mon.IntVal("remote_segments_over_threshold_4").Observe(observer.monStats.remoteSegmentsOverThreshold[3])   //locked
 	mon.IntVal("remote_segments_over_threshold_5").Observe(observer.monStats.remoteSegmentsOverThreshold[4])   //locked
+	mon.IntVal("remote_segments_misplaced").Observe(observer.monStats.remoteSegmentsMisplaced)
+	mon.IntVal("remote_segments_over_diversity_cap").Observe(observer.monStats.remoteSegmentsOverDiversityCap)
+
+	if observer.monStats.remoteSegmentsOverDiversityCap > 0 {
+		checker.logger.Info("segments exceeding the operator or ASN diversity cap",
+			zap.Int64("count", observer.monStats.remoteSegmentsOverDiversityCap),
+			zap.Int("max per operator", diversity.MaxPerOperator),
+			zap.Int("max per ASN", diversity.MaxPerASN))
+	}
+
+	if observer.report != nil {
+		// failing to write the report shouldn't stop the checker.
+		if err := WriteDurabilityReport(checker.reportPath, observer.report.report()); err != nil {
+			checker.logger.Error("failed to write durability report", zap.String("path", checker.reportPath), zap.Error(err))
+		}
+	}
 
 	allUnhealthy := observer.monStats.remoteSegmentsNeedingRepair + observer.monStats.remoteSegmentsFailedToCheck
 	allChecked := observer.monStats.remoteSegmentsChecked
@@ -151,6 +229,41 @@ func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error)
 	return nil
 }
 
+// estimateChurn estimates the probability of a node being offline from the tracked
+// downtime of all nodes within the churn window.
+func (checker *Checker) estimateChurn(ctx context.Context) (_ float64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if checker.downtime == nil || checker.churnWindow <= 0 {
+		return 0, nil
+	}
+
+	err = checker.nodestate.Refresh(ctx)
+	if err != nil {
+		return 0, err
+	}
+	numNodes := checker.nodestate.NumReliable()
+	if numNodes == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	offline, err := checker.downtime.GetTotalOfflineTime(ctx, now.Add(-checker.churnWindow), now)
+	if err != nil {
+		return 0, err
+	}
+
+	churn := offline.Seconds() / (float64(numNodes) * checker.churnWindow.Seconds())
+	mon.FloatVal("node_churn_estimate").Observe(churn)
+	return churn, nil
+}
+
+// segmentHealth returns the health of a segment, see SegmentHealth.
+func (checker *Checker) segmentHealth(pieces []*pb.RemotePiece, numHealthy, minReq int32, churn float64) float64 {
+	exiting, unvetted := checker.nodestate.PiecesAtRisk(pieces)
+	return SegmentHealth(int(numHealthy), exiting, unvetted, int(minReq), churn)
+}
+
 // checks for a string in slice
 func contains(a []string, x string) bool {
 	for _, n := range a {
@@ -161,7 +274,7 @@ func contains(a []string, x string) bool {
 	return false
 }
 
-func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, pointer *pb.Pointer, path string) (err error) {
+func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, pointer *pb.Pointer, path string, churn float64) (err error) {
 	// TODO figure out how to reduce duplicate code between here and checkerObs.RemoteSegment
 	defer mon.Task()(&ctx)(&err)
 	remote := pointer.GetRemote()
@@ -183,10 +296,7 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 	numHealthy := int32(len(pieces) - len(missingPieces))
 	redundancy := pointer.Remote.Redundancy
 
-	repairThreshold := redundancy.RepairThreshold
-	if checker.repairOverride != 0 {
-		repairThreshold = checker.repairOverride
-	}
+	repairThreshold := repair.RepairThreshold(redundancy, checker.repairOverride, checker.rsProfiles)
 
 	// we repair when the number of healthy pieces is less than or equal to the repair threshold and is greater or equal to
 	// minimum required pieces in redundancy
@@ -196,7 +306,7 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 			Path:         []byte(path),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		}, int(numHealthy))
+		}, checker.segmentHealth(pieces, numHealthy, redundancy.MinReq, churn))
 		if err != nil {
 			return errs.Combine(Error.New("error adding injured segment to queue"), err)
 		}
@@ -226,7 +336,8 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 	return nil
 }
 
-var _ metainfo.Observer = (*checkerObserver)(nil)
+var _ metainfo.PartialObserver = (*checkerObserver)(nil)
+var _ metainfo.DistributedObserver = (*checkerObserver)(nil)
 
 // checkerObserver implements the metainfo loop Observer interface
 //
@@ -234,17 +345,32 @@ var _ metainfo.Observer = (*checkerObserver)(nil)
 type checkerObserver struct {
 	repairQueue    queue.RepairQueue
 	irrdb          irreparable.DB
+	metainfo       *metainfo.Service
+	overlay        *overlay.Service
 	nodestate      *ReliabilityCache
 	monStats       durabilityStats
 	overrideRepair int32
+	rsProfiles     metainfo.RSProfiles
+	churn          float64
 	log            *zap.Logger
+
+	// placements caches the placement of the buckets seen during the loop.
+	placements map[string]nodeselection.Placement
+
+	// diversity and diversityNodes are used to report segments exceeding the
+	// diversity cap, diversityNodes is nil when the cap is disabled.
+	diversity      nodeselection.Diversity
+	diversityNodes map[storj.NodeID]*nodeselection.Node
+
+	// report collects the durability report, it's nil when the report is disabled.
+	report *durabilityReporter
 }
 
 func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// ignore pointer if expired
-	if !pointer.ExpirationDate.IsZero() && pointer.ExpirationDate.Before(time.Now().UTC()) {
+	if metainfo.IsExpired(pointer, time.Now().UTC()) {
 		return nil
 	}
 
@@ -272,21 +398,32 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 
 	redundancy := pointer.Remote.Redundancy
 
-	repairThreshold := redundancy.RepairThreshold
-	if obs.overrideRepair != 0 {
-		repairThreshold = obs.overrideRepair
+	repairThreshold := repair.RepairThreshold(redundancy, obs.overrideRepair, obs.rsProfiles)
+
+	if obs.exceedsDiversity(pieces, missingPieces) {
+		obs.monStats.remoteSegmentsOverDiversityCap++
+		obs.log.Debug("segment exceeds the diversity cap", zap.String("path", path.Raw))
+	}
+
+	misplacedPieces, err := obs.misplacedPieces(ctx, path, pieces)
+	if err != nil {
+		obs.monStats.remoteSegmentsFailedToCheck++
+		return errs.Combine(Error.New("error getting misplaced pieces"), err)
 	}
 
+	obs.report.segment(redundancy, numHealthy)
+
 	// we repair when the number of healthy pieces is less than or equal to the repair threshold and is greater or equal to
 	// minimum required pieces in redundancy
 	// except for the case when the repair and success thresholds are the same (a case usually seen during testing)
 	if numHealthy >= redundancy.MinReq && numHealthy <= repairThreshold && numHealthy < redundancy.SuccessThreshold {
 		obs.monStats.remoteSegmentsNeedingRepair++
+		obs.report.atRisk(path)
 		alreadyInserted, err := obs.repairQueue.Insert(ctx, &pb.InjuredSegment{
 			Path:         []byte(path.Raw),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		}, int(numHealthy))
+		}, obs.segmentHealth(pieces, numHealthy, redundancy.MinReq))
 		if err != nil {
 			obs.log.Error("error adding injured segment to queue", zap.Error(err))
 			return nil
@@ -328,6 +465,7 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 		mon.IntVal("checker_segment_time_until_irreparable").Observe(int64(segmentAge.Seconds())) //locked
 
 		obs.monStats.remoteSegmentsLost++
+		obs.report.lostSegment(path)
 		// make an entry into the irreparable table
 		segmentInfo := &pb.IrreparableSegment{
 			Path:               []byte(path.Raw),
@@ -343,6 +481,20 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 			obs.log.Error("error handling irreparable segment to queue", zap.Error(err))
 			return nil
 		}
+	} else if len(misplacedPieces) > 0 && numHealthy >= redundancy.MinReq {
+		// the segment is healthy, but some of its pieces are outside of the placement of the bucket.
+		// the repairer keeps the segments it can't move yet in the queue, inserting them again
+		// only updates their health, so they are retried after the retry interval of the queue.
+		obs.monStats.remoteSegmentsMisplaced++
+		_, err := obs.repairQueue.Insert(ctx, &pb.InjuredSegment{
+			Path:         []byte(path.Raw),
+			LostPieces:   append(missingPieces, misplacedPieces...),
+			InsertedTime: time.Now().UTC(),
+		}, obs.segmentHealth(pieces, numHealthy, redundancy.MinReq))
+		if err != nil {
+			obs.log.Error("error adding misplaced segment to queue", zap.Error(err))
+			return nil
+		}
 	} else if numHealthy > repairThreshold && numHealthy <= (repairThreshold+int32(len(obs.monStats.remoteSegmentsOverThreshold))) {
 		// record metrics for segments right above repair threshold
 		// numHealthy=repairThreshold+1 through numHealthy=repairThreshold+5
@@ -357,6 +509,61 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 	return nil
 }
 
+// exceedsDiversity returns whether the healthy pieces of a segment exceed the operator or ASN cap.
+func (obs *checkerObserver) exceedsDiversity(pieces []*pb.RemotePiece, missingPieces []int32) bool {
+	if obs.diversityNodes == nil {
+		return false
+	}
+
+	missing := make(map[int32]struct{}, len(missingPieces))
+	for _, num := range missingPieces {
+		missing[num] = struct{}{}
+	}
+
+	counter := obs.diversity.NewCounter()
+	for _, piece := range pieces {
+		if _, ok := missing[piece.PieceNum]; ok {
+			continue
+		}
+		if node, ok := obs.diversityNodes[piece.NodeId]; ok {
+			counter.Add(node)
+		}
+	}
+	return counter.Exceeded()
+}
+
+// misplacedPieces returns the pieces which are stored outside of the placement of the bucket.
+func (obs *checkerObserver) misplacedPieces(ctx context.Context, path metainfo.ScopedPath, pieces []*pb.RemotePiece) (_ []int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if obs.metainfo == nil || obs.overlay == nil {
+		return nil, nil
+	}
+
+	key := path.ProjectIDString + "/" + path.BucketName
+	placement, ok := obs.placements[key]
+	if !ok {
+		placement, err = obs.metainfo.GetBucketPlacement(ctx, []byte(path.BucketName), path.ProjectID)
+		if err != nil && !storj.ErrBucketNotFound.Has(err) {
+			return nil, err
+		}
+		obs.placements[key] = placement
+	}
+	if placement.IsZero() {
+		return nil, nil
+	}
+
+	return obs.overlay.MisplacedPieces(ctx, placement, pieces)
+}
+
+// segmentHealth returns the health of a segment, see SegmentHealth.
+func (obs *checkerObserver) segmentHealth(pieces []*pb.RemotePiece, numHealthy, minReq int32) float64 {
+	exiting, unvetted := obs.nodestate.PiecesAtRisk(pieces)
+	health := SegmentHealth(int(numHealthy), exiting, unvetted, int(minReq), obs.churn)
+	mon.FloatVal("checker_segment_health").Observe(health)
+	return health
+}
+
 func (obs *checkerObserver) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -370,6 +577,43 @@ func (obs *checkerObserver) InlineSegment(ctx context.Context, path metainfo.Sco
 	return nil
 }
 
+// Fork returns an observer for a range of the metainfo loop.
+func (obs *checkerObserver) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	fork := *obs
+	fork.monStats = durabilityStats{}
+	fork.placements = map[string]nodeselection.Placement{}
+	if obs.report != nil {
+		fork.report = newDurabilityReporter()
+	}
+	return &fork, nil
+}
+
+// Merge adds the stats and the durability report of a forked observer.
+func (obs *checkerObserver) Merge(ctx context.Context, partial metainfo.Observer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	fork, ok := partial.(*checkerObserver)
+	if !ok {
+		return Error.New("unexpected partial observer %T", partial)
+	}
+	obs.monStats.merge(&fork.monStats)
+	obs.report.merge(fork.report)
+	return nil
+}
+
+// Distributed returns whether the checker can skip the segments checked by
+// other satellite processes, which isn't possible when writing the durability report.
+func (obs *checkerObserver) Distributed() bool {
+	return obs.report == nil
+}
+
+// DistributedName returns the name of the observer for claiming the ranges.
+func (obs *checkerObserver) DistributedName() string {
+	return "checker"
+}
+
 // IrreparableProcess iterates over all items in the irreparabledb. If an item can
 // now be repaired then it is added to a worker queue.
 func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
@@ -377,6 +621,11 @@ func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
 	const limit = 1000
 	lastSeenSegmentPath := []byte{}
 
+	churn, err := checker.estimateChurn(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
 	for {
 		segments, err := checker.irrdb.GetLimited(ctx, limit, lastSeenSegmentPath)
 		if err != nil {
@@ -391,7 +640,7 @@ func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
 		lastSeenSegmentPath = segments[len(segments)-1].Path
 
 		for _, segment := range segments {
-			err = checker.updateIrreparableSegmentStatus(ctx, segment.GetSegmentDetail(), string(segment.GetPath()))
+			err = checker.updateIrreparableSegmentStatus(ctx, segment.GetSegmentDetail(), string(segment.GetPath()), churn)
 			if err != nil {
 				checker.logger.Error("irrepair segment checker failed: ", zap.Error(err))
 			}
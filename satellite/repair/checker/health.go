@@ -0,0 +1,32 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import "math"
+
+const (
+	// unvettedPieceRisk is the fraction of a piece on an unvetted node, which is
+	// considered lost, since new nodes leave the network more often.
+	unvettedPieceRisk = 0.5
+	// minimumChurn avoids dividing by zero when no downtime has been tracked.
+	minimumChurn = 1e-5
+)
+
+// SegmentHealth returns an estimate of how many churn periods a segment survives
+// before it drops below the minimum number of pieces. Lower values mean the segment
+// is more at risk, a negative value means it can't be reconstructed anymore.
+//
+// Pieces on exiting nodes count as lost, pieces on unvetted nodes count partially,
+// see unvettedPieceRisk. Pieces on suspended nodes are already missing. The churn is
+// the probability of a node being offline during a period.
+func SegmentHealth(numHealthy, numExiting, numUnvetted, minPieces int, churn float64) float64 {
+	effective := float64(numHealthy-numExiting) - unvettedPieceRisk*float64(numUnvetted)
+	margin := effective - float64(minPieces) + 1
+	if margin <= 0 || effective <= 0 {
+		return margin
+	}
+
+	churn = math.Min(math.Max(churn, minimumChurn), 1)
+	return margin / (effective * churn)
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/satellite/repair/checker"
+)
+
+func TestSegmentHealth(t *testing.T) {
+	const churn = 0.01
+
+	// more healthy pieces means a healthier segment.
+	assert.Less(t,
+		checker.SegmentHealth(30, 0, 0, 29, churn),
+		checker.SegmentHealth(35, 0, 0, 29, churn))
+
+	// pieces on exiting and unvetted nodes put the segment at risk.
+	stable := checker.SegmentHealth(35, 0, 0, 29, churn)
+	assert.Less(t, checker.SegmentHealth(35, 5, 0, 29, churn), stable)
+	assert.Less(t, checker.SegmentHealth(35, 0, 5, 29, churn), stable)
+	assert.Less(t, checker.SegmentHealth(35, 5, 0, 29, churn), checker.SegmentHealth(35, 0, 5, 29, churn))
+	assert.Equal(t, checker.SegmentHealth(30, 0, 0, 29, churn), checker.SegmentHealth(35, 5, 0, 29, churn))
+
+	// higher churn means less health.
+	assert.Less(t, checker.SegmentHealth(35, 0, 0, 29, 0.1), stable)
+
+	// segments which can't be reconstructed anymore are the least healthy.
+	assert.LessOrEqual(t, checker.SegmentHealth(28, 0, 0, 29, churn), 0.0)
+	assert.Less(t, checker.SegmentHealth(28, 0, 0, 29, churn), checker.SegmentHealth(29, 0, 0, 29, churn))
+
+	// unknown churn doesn't break the ordering.
+	assert.Less(t, checker.SegmentHealth(30, 0, 0, 29, 0), checker.SegmentHealth(31, 0, 0, 29, 0))
+}
@@ -28,6 +28,8 @@ type ReliabilityCache struct {
 // reliabilityState
 type reliabilityState struct {
 	reliable map[storj.NodeID]struct{}
+	exiting  map[storj.NodeID]struct{}
+	unvetted map[storj.NodeID]struct{}
 	created  time.Time
 }
 
@@ -81,6 +83,32 @@ func (cache *ReliabilityCache) MissingPieces(ctx context.Context, created time.T
 	return unreliable, nil
 }
 
+// PiecesAtRisk returns how many of the pieces are on reliable nodes, which are exiting
+// or not yet vetted, based on the last refresh of the cache.
+func (cache *ReliabilityCache) PiecesAtRisk(pieces []*pb.RemotePiece) (exiting, unvetted int) {
+	state, ok := cache.state.Load().(*reliabilityState)
+	if !ok {
+		return 0, 0
+	}
+
+	for _, piece := range pieces {
+		if _, ok := state.exiting[piece.NodeId]; ok {
+			exiting++
+		} else if _, ok := state.unvetted[piece.NodeId]; ok {
+			unvetted++
+		}
+	}
+	return exiting, unvetted
+}
+
+// NumReliable returns the number of reliable nodes, based on the last refresh of the cache.
+func (cache *ReliabilityCache) NumReliable() int {
+	if state, ok := cache.state.Load().(*reliabilityState); ok {
+		return len(state.reliable)
+	}
+	return 0
+}
+
 // Refresh refreshes the cache.
 func (cache *ReliabilityCache) Refresh(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -101,13 +129,26 @@ func (cache *ReliabilityCache) refreshLocked(ctx context.Context) (_ *reliabilit
 		return nil, Error.Wrap(err)
 	}
 
+	exiting, unvetted, err := cache.overlay.ReliableAtRisk(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	state := &reliabilityState{
 		created:  time.Now(),
 		reliable: make(map[storj.NodeID]struct{}, len(nodes)),
+		exiting:  make(map[storj.NodeID]struct{}, len(exiting)),
+		unvetted: make(map[storj.NodeID]struct{}, len(unvetted)),
 	}
 	for _, id := range nodes {
 		state.reliable[id] = struct{}{}
 	}
+	for _, id := range exiting {
+		state.exiting[id] = struct{}{}
+	}
+	for _, id := range unvetted {
+		state.unvetted[id] = struct{}{}
+	}
 
 	cache.state.Store(state)
 	return state, nil
@@ -0,0 +1,244 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/storj/satellite/metainfo"
+)
+
+// DurabilityReport contains the health of all remote segments seen during a single checker run.
+type DurabilityReport struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+
+	// Schemes contains the histogram of healthy pieces per redundancy scheme.
+	Schemes []SchemeDurability `json:"schemes"`
+	// Projects and Buckets contain the number of segments needing repair and lost segments.
+	Projects []AtRiskCount `json:"projects"`
+	Buckets  []AtRiskCount `json:"buckets"`
+	// Lost contains the paths of the segments which can't be reconstructed anymore.
+	Lost []string `json:"lost"`
+}
+
+// SchemeDurability contains the histogram of healthy pieces of segments with the same redundancy scheme.
+type SchemeDurability struct {
+	// Scheme is formatted as min/repair/success/total.
+	Scheme   string `json:"scheme"`
+	Segments int64  `json:"segments"`
+	// HealthyPieces maps the number of healthy pieces to the number of segments.
+	HealthyPieces map[int32]int64 `json:"healthyPieces"`
+}
+
+// AtRiskCount contains the number of segments at risk in a project or a bucket.
+type AtRiskCount struct {
+	ProjectID string `json:"projectID"`
+	Bucket    string `json:"bucket,omitempty"`
+	AtRisk    int64  `json:"atRisk"`
+	Lost      int64  `json:"lost"`
+}
+
+// ReadDurabilityReport reads the durability report written by the checker.
+func ReadDurabilityReport(path string) (_ *DurabilityReport, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var report DurabilityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, Error.New("invalid durability report %q: %v", path, err)
+	}
+	return &report, nil
+}
+
+// WriteDurabilityReport atomically replaces the durability report at path.
+func WriteDurabilityReport(path string, report *DurabilityReport) (err error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, os.Remove(file.Name()))
+		}
+	}()
+
+	_, err = file.Write(data)
+	err = errs.Combine(err, file.Close())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(file.Name(), path))
+}
+
+// durabilityReporter collects the durability report during a checker run.
+// All methods are no-ops on a nil reporter.
+type durabilityReporter struct {
+	started  time.Time
+	schemes  map[string]*SchemeDurability
+	projects map[string]*AtRiskCount
+	buckets  map[string]*AtRiskCount
+	lost     []string
+}
+
+func newDurabilityReporter() *durabilityReporter {
+	return &durabilityReporter{
+		started:  time.Now().UTC(),
+		schemes:  map[string]*SchemeDurability{},
+		projects: map[string]*AtRiskCount{},
+		buckets:  map[string]*AtRiskCount{},
+	}
+}
+
+// segment records the number of healthy pieces of a segment.
+func (reporter *durabilityReporter) segment(redundancy *pb.RedundancyScheme, numHealthy int32) {
+	if reporter == nil {
+		return
+	}
+
+	scheme := fmt.Sprintf("%d/%d/%d/%d", redundancy.MinReq, redundancy.RepairThreshold, redundancy.SuccessThreshold, redundancy.Total)
+	stats, ok := reporter.schemes[scheme]
+	if !ok {
+		stats = &SchemeDurability{Scheme: scheme, HealthyPieces: map[int32]int64{}}
+		reporter.schemes[scheme] = stats
+	}
+	stats.Segments++
+	stats.HealthyPieces[numHealthy]++
+}
+
+// atRisk records a segment needing repair.
+func (reporter *durabilityReporter) atRisk(path metainfo.ScopedPath) {
+	if reporter == nil {
+		return
+	}
+	reporter.project(path).AtRisk++
+	reporter.bucket(path).AtRisk++
+}
+
+// lostSegment records a segment which can't be reconstructed anymore.
+func (reporter *durabilityReporter) lostSegment(path metainfo.ScopedPath) {
+	if reporter == nil {
+		return
+	}
+	reporter.project(path).Lost++
+	reporter.bucket(path).Lost++
+	reporter.lost = append(reporter.lost, path.Raw)
+}
+
+// merge adds the report collected by another reporter.
+func (reporter *durabilityReporter) merge(other *durabilityReporter) {
+	if reporter == nil || other == nil {
+		return
+	}
+
+	for scheme, stats := range other.schemes {
+		merged, ok := reporter.schemes[scheme]
+		if !ok {
+			merged = &SchemeDurability{Scheme: scheme, HealthyPieces: map[int32]int64{}}
+			reporter.schemes[scheme] = merged
+		}
+		merged.Segments += stats.Segments
+		for numHealthy, count := range stats.HealthyPieces {
+			merged.HealthyPieces[numHealthy] += count
+		}
+	}
+	mergeCounts(reporter.projects, other.projects)
+	mergeCounts(reporter.buckets, other.buckets)
+	reporter.lost = append(reporter.lost, other.lost...)
+}
+
+// mergeCounts adds the counts of other to counts.
+func mergeCounts(counts, other map[string]*AtRiskCount) {
+	for key, count := range other {
+		merged, ok := counts[key]
+		if !ok {
+			merged = &AtRiskCount{ProjectID: count.ProjectID, Bucket: count.Bucket}
+			counts[key] = merged
+		}
+		merged.AtRisk += count.AtRisk
+		merged.Lost += count.Lost
+	}
+}
+
+func (reporter *durabilityReporter) project(path metainfo.ScopedPath) *AtRiskCount {
+	count, ok := reporter.projects[path.ProjectIDString]
+	if !ok {
+		count = &AtRiskCount{ProjectID: path.ProjectIDString}
+		reporter.projects[path.ProjectIDString] = count
+	}
+	return count
+}
+
+func (reporter *durabilityReporter) bucket(path metainfo.ScopedPath) *AtRiskCount {
+	key := path.ProjectIDString + "/" + path.BucketName
+	count, ok := reporter.buckets[key]
+	if !ok {
+		count = &AtRiskCount{ProjectID: path.ProjectIDString, Bucket: path.BucketName}
+		reporter.buckets[key] = count
+	}
+	return count
+}
+
+// report returns the collected report with deterministically ordered entries.
+func (reporter *durabilityReporter) report() *DurabilityReport {
+	report := &DurabilityReport{
+		Started:  reporter.started,
+		Finished: time.Now().UTC(),
+		Schemes:  []SchemeDurability{},
+		Projects: []AtRiskCount{},
+		Buckets:  []AtRiskCount{},
+		Lost:     append([]string{}, reporter.lost...),
+	}
+
+	for _, scheme := range reporter.schemes {
+		report.Schemes = append(report.Schemes, *scheme)
+	}
+	sort.Slice(report.Schemes, func(i, k int) bool {
+		return report.Schemes[i].Scheme < report.Schemes[k].Scheme
+	})
+
+	report.Projects = sortedCounts(reporter.projects)
+	report.Buckets = sortedCounts(reporter.buckets)
+	sort.Strings(report.Lost)
+
+	return report
+}
+
+// sortedCounts returns the counts ordered by the number of lost and at risk segments.
+func sortedCounts(counts map[string]*AtRiskCount) []AtRiskCount {
+	sorted := make([]AtRiskCount, 0, len(counts))
+	for _, count := range counts {
+		sorted = append(sorted, *count)
+	}
+	sort.Slice(sorted, func(i, k int) bool {
+		a, b := sorted[i], sorted[k]
+		if a.Lost != b.Lost {
+			return a.Lost > b.Lost
+		}
+		if a.AtRisk != b.AtRisk {
+			return a.AtRisk > b.AtRisk
+		}
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID < b.ProjectID
+		}
+		return a.Bucket < b.Bucket
+	})
+	return sorted
+}
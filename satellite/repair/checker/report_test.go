@@ -0,0 +1,45 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite/repair/checker"
+)
+
+func TestDurabilityReport_WriteRead(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	report := &checker.DurabilityReport{
+		Started:  now.Add(-time.Minute),
+		Finished: now,
+		Schemes: []checker.SchemeDurability{
+			{Scheme: "29/35/80/110", Segments: 3, HealthyPieces: map[int32]int64{28: 1, 35: 2}},
+		},
+		Projects: []checker.AtRiskCount{{ProjectID: "project", AtRisk: 2, Lost: 1}},
+		Buckets:  []checker.AtRiskCount{{ProjectID: "project", Bucket: "bucket", AtRisk: 2, Lost: 1}},
+		Lost:     []string{"project/s0/bucket/object"},
+	}
+
+	path := filepath.Join(ctx.Dir(), "durability.json")
+	require.NoError(t, checker.WriteDurabilityReport(path, report))
+
+	// writing again replaces the previous report.
+	require.NoError(t, checker.WriteDurabilityReport(path, report))
+
+	read, err := checker.ReadDurabilityReport(path)
+	require.NoError(t, err)
+	require.Equal(t, report, read)
+
+	_, err = checker.ReadDurabilityReport(filepath.Join(ctx.Dir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -14,10 +14,14 @@ import (
 //
 // architecture: Database
 type RepairQueue interface {
-	// Insert adds an injured segment.
-	Insert(ctx context.Context, s *pb.InjuredSegment, numHealthy int) (alreadyInserted bool, err error)
-	// Select gets an injured segment.
+	// Insert adds an injured segment, or updates the health of an already queued segment.
+	Insert(ctx context.Context, s *pb.InjuredSegment, segmentHealth float64) (alreadyInserted bool, err error)
+	// Select gets the injured segment with the lowest health.
 	Select(ctx context.Context) (*pb.InjuredSegment, error)
+	// SaveProgress stores the progress of repairing a queued segment, it does nothing when the segment isn't queued.
+	SaveProgress(ctx context.Context, path []byte, progress []byte) error
+	// Progress returns the stored progress of repairing a queued segment, nil when there is none.
+	Progress(ctx context.Context, path []byte) ([]byte, error)
 	// Delete removes an injured segment.
 	Delete(ctx context.Context, s *pb.InjuredSegment) error
 	// SelectN lists limit amount of injured segments.
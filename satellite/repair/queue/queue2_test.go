@@ -137,7 +137,7 @@ func TestOrderHealthyPieces(t *testing.T) {
 		// insert the 8 segments according to the plan above
 		injuredSegList := []struct {
 			path      []byte
-			health    int
+			health    float64
 			attempted time.Time
 		}{
 			{[]byte("path/a"), 6, time.Now().Add(-8 * time.Hour)},
@@ -213,7 +213,7 @@ func TestOrderOverwrite(t *testing.T) {
 		// insert the 8 segments according to the plan above
 		injuredSegList := []struct {
 			path   []byte
-			health int
+			health float64
 		}{
 			{[]byte("path/a"), 10},
 			{[]byte("path/b"), 9},
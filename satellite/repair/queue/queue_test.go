@@ -54,6 +54,40 @@ func TestInsertDuplicate(t *testing.T) {
 	})
 }
 
+func TestProgress(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		q := db.RepairQueue()
+
+		seg := &pb.InjuredSegment{Path: []byte("abc")}
+
+		// saving the progress of a segment which isn't queued does nothing.
+		require.NoError(t, q.SaveProgress(ctx, seg.Path, []byte("progress")))
+		progress, err := q.Progress(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+
+		_, err = q.Insert(ctx, seg, 10)
+		require.NoError(t, err)
+		progress, err = q.Progress(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+
+		require.NoError(t, q.SaveProgress(ctx, seg.Path, []byte("progress")))
+
+		// the checker updating the segment keeps the progress.
+		_, err = q.Insert(ctx, seg, 5)
+		require.NoError(t, err)
+		progress, err = q.Progress(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Equal(t, []byte("progress"), progress)
+
+		require.NoError(t, q.Delete(ctx, seg))
+		progress, err = q.Progress(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+	})
+}
+
 func TestDequeueEmptyQueue(t *testing.T) {
 	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
 		q := db.RepairQueue()
@@ -3,4 +3,22 @@
 
 package repair
 
-// Empty file to workaround coverpkg issue https://golang.org/issue/27333
+import (
+	"storj.io/common/pb"
+	"storj.io/storj/satellite/metainfo"
+)
+
+// RepairThreshold returns the repair threshold of a segment with the redundancy scheme.
+//
+// The override replaces the repair threshold of the segments with the default
+// redundancy scheme. Segments of buckets with a redundancy scheme profile keep
+// the repair threshold of their profile.
+func RepairThreshold(redundancy *pb.RedundancyScheme, override int32, profiles metainfo.RSProfiles) int32 {
+	if override == 0 {
+		return redundancy.GetRepairThreshold()
+	}
+	if _, ok := profiles.Match(redundancy); ok {
+		return redundancy.GetRepairThreshold()
+	}
+	return override
+}
@@ -20,7 +20,9 @@ import (
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair"
 	"storj.io/storj/storage"
 )
 
@@ -1422,3 +1424,20 @@ func corruptPieceData(ctx context.Context, t *testing.T, planet *testplanet.Plan
 	err = writer.Commit(ctx)
 	require.NoError(t, err)
 }
+
+func TestRepairThreshold(t *testing.T) {
+	redundancy := &pb.RedundancyScheme{MinReq: 3, RepairThreshold: 5, SuccessThreshold: 8, Total: 10}
+
+	profiles := metainfo.RSProfiles{{Name: "archive", MinThreshold: 2, RepairThreshold: 4, SuccessThreshold: 6, TotalThreshold: 8}}
+
+	require.EqualValues(t, 5, repair.RepairThreshold(redundancy, 0, profiles))
+	require.EqualValues(t, 3, repair.RepairThreshold(redundancy, 3, profiles))
+	require.EqualValues(t, 7, repair.RepairThreshold(redundancy, 7, profiles))
+	require.EqualValues(t, 2, repair.RepairThreshold(redundancy, 2, nil))
+	require.EqualValues(t, 8, repair.RepairThreshold(redundancy, 8, nil))
+
+	// segments of buckets with a profile keep the repair threshold of the profile
+	archive := profiles[0].RedundancyScheme(256)
+	require.EqualValues(t, 4, repair.RepairThreshold(archive, 0, profiles))
+	require.EqualValues(t, 4, repair.RepairThreshold(archive, 7, profiles))
+}
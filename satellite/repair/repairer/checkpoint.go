@@ -0,0 +1,185 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/repair/queue"
+)
+
+// repairProgress is the progress of repairing a segment, which is stored in the repair queue
+// so that a later attempt can continue where an interrupted repair stopped.
+type repairProgress struct {
+	// CreationDate identifies the segment, the progress of a replaced segment is discarded.
+	CreationDate time.Time `json:"creationDate"`
+	// Uploaded contains the pieces uploaded to new nodes, which may not be committed to the pointer yet.
+	Uploaded []uploadedPiece `json:"uploaded,omitempty"`
+}
+
+// uploadedPiece is a repaired piece stored on a new node.
+type uploadedPiece struct {
+	PieceNum int32        `json:"pieceNum"`
+	NodeID   storj.NodeID `json:"nodeID"`
+	// Hash is the marshaled piece hash signed by the node.
+	Hash []byte `json:"hash"`
+}
+
+// checkpoints persists the progress of repairs in the repair queue and keeps the downloaded
+// and verified pieces of a segment on disk until the segment is repaired.
+//
+// The uploaded pieces are resumed by any repairer, because the progress is in the queue,
+// but the downloaded pieces are resumed only by a repairer on the same host.
+type checkpoints struct {
+	log   *zap.Logger
+	queue queue.RepairQueue
+	// dir is where the pieces are kept, it's empty when pieces are downloaded in memory.
+	dir string
+}
+
+// load returns the stored progress and the piece checkpoint of the segment.
+// The checkpoint is nil when pieces aren't kept on disk.
+func (c *checkpoints) load(ctx context.Context, path storj.Path, pointer *pb.Pointer) (_ *repairProgress, _ *segmentCheckpoint, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	progress := &repairProgress{CreationDate: pointer.CreationDate}
+
+	var checkpoint *segmentCheckpoint
+	if c.dir != "" {
+		checkpoint = &segmentCheckpoint{dir: filepath.Join(c.dir, checkpointName(path, pointer.CreationDate))}
+	}
+
+	if c.queue == nil {
+		return progress, checkpoint, nil
+	}
+
+	data, err := c.queue.Progress(ctx, []byte(path))
+	if err != nil || len(data) == 0 {
+		return progress, checkpoint, err
+	}
+
+	var stored repairProgress
+	if err := json.Unmarshal(data, &stored); err != nil {
+		c.log.Warn("discarding invalid repair progress", zap.String("path", path), zap.Error(err))
+		return progress, checkpoint, nil
+	}
+	if stored.CreationDate.Equal(pointer.CreationDate) {
+		progress = &stored
+	}
+
+	return progress, checkpoint, nil
+}
+
+// save stores the progress of repairing the segment.
+func (c *checkpoints) save(ctx context.Context, path storj.Path, progress *repairProgress) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.queue == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return c.queue.SaveProgress(ctx, []byte(path), data)
+}
+
+// cleanup removes the pieces of segments which haven't been touched for longer than expiration.
+func (c *checkpoints) cleanup(ctx context.Context, expiration time.Duration) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.dir == "" || expiration <= 0 {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return Error.Wrap(err)
+	}
+
+	var group errs.Group
+	for _, entry := range entries {
+		if !entry.IsDir() || time.Since(entry.ModTime()) < expiration {
+			continue
+		}
+		group.Add(os.RemoveAll(filepath.Join(c.dir, entry.Name())))
+	}
+	return Error.Wrap(group.Err())
+}
+
+// checkpointName returns the directory name for the pieces of the segment.
+func checkpointName(path storj.Path, creationDate time.Time) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:]) + "-" + strconv.FormatInt(creationDate.UnixNano(), 10)
+}
+
+// segmentCheckpoint keeps the downloaded and verified pieces of a segment on disk.
+// Pieces are written to a partial file and renamed after verification, so only verified
+// pieces are ever found under their final name.
+type segmentCheckpoint struct {
+	dir string
+}
+
+func (checkpoint *segmentCheckpoint) piecePath(pieceNum int) string {
+	return filepath.Join(checkpoint.dir, fmt.Sprintf("piece-%d", pieceNum))
+}
+
+// open returns the verified piece when it has been downloaded by a previous attempt.
+func (checkpoint *segmentCheckpoint) open(pieceNum int, pieceSize int64) (io.ReadCloser, bool) {
+	if checkpoint == nil {
+		return nil, false
+	}
+
+	file, err := os.Open(checkpoint.piecePath(pieceNum))
+	if err != nil {
+		return nil, false
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.Size() != pieceSize {
+		_ = file.Close()
+		return nil, false
+	}
+	return file, true
+}
+
+// create returns a new partial file for downloading a piece.
+func (checkpoint *segmentCheckpoint) create() (*os.File, error) {
+	if err := os.MkdirAll(checkpoint.dir, 0700); err != nil {
+		return nil, err
+	}
+	return ioutil.TempFile(checkpoint.dir, "partial-*")
+}
+
+// commit marks the partial file as the verified piece.
+func (checkpoint *segmentCheckpoint) commit(file *os.File, pieceNum int) error {
+	return os.Rename(file.Name(), checkpoint.piecePath(pieceNum))
+}
+
+// remove removes all pieces of the segment.
+func (checkpoint *segmentCheckpoint) remove() error {
+	if checkpoint == nil {
+		return nil
+	}
+	return os.RemoveAll(checkpoint.dir)
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/repair/queue"
+)
+
+func TestSegmentCheckpoint(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	checkpoint := &segmentCheckpoint{dir: filepath.Join(ctx.Dir(), "segment")}
+
+	_, ok := checkpoint.open(1, 3)
+	require.False(t, ok)
+
+	file, err := checkpoint.create()
+	require.NoError(t, err)
+	_, err = file.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	// partial pieces aren't used.
+	_, ok = checkpoint.open(1, 3)
+	require.False(t, ok)
+
+	require.NoError(t, checkpoint.commit(file, 1))
+	require.NoError(t, file.Close())
+
+	_, ok = checkpoint.open(1, 4)
+	require.False(t, ok, "piece with a different size")
+
+	reader, ok := checkpoint.open(1, 3)
+	require.True(t, ok)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, []byte("abc"), data)
+	require.NoError(t, reader.Close())
+
+	require.NoError(t, checkpoint.remove())
+	_, ok = checkpoint.open(1, 3)
+	require.False(t, ok)
+
+	var disabled *segmentCheckpoint
+	_, ok = disabled.open(1, 3)
+	require.False(t, ok)
+	require.NoError(t, disabled.remove())
+}
+
+func TestCheckpoints_LoadSave(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	repairQueue := &progressQueue{progress: map[string][]byte{}}
+	checkpoints := &checkpoints{log: zaptest.NewLogger(t), queue: repairQueue, dir: ctx.Dir()}
+
+	path := "project/s0/bucket/object"
+	pointer := &pb.Pointer{CreationDate: time.Now().UTC().Truncate(time.Second)}
+
+	progress, checkpoint, err := checkpoints.load(ctx, path, pointer)
+	require.NoError(t, err)
+	require.Empty(t, progress.Uploaded)
+	require.NotNil(t, checkpoint)
+
+	progress.Uploaded = []uploadedPiece{{PieceNum: 3, NodeID: testrand.NodeID(), Hash: []byte{1}}}
+	require.NoError(t, checkpoints.save(ctx, path, progress))
+
+	loaded, loadedCheckpoint, err := checkpoints.load(ctx, path, pointer)
+	require.NoError(t, err)
+	require.Equal(t, progress.Uploaded, loaded.Uploaded)
+	require.Equal(t, checkpoint, loadedCheckpoint)
+
+	// the progress of a replaced segment is discarded.
+	replaced := &pb.Pointer{CreationDate: pointer.CreationDate.Add(time.Second)}
+	loaded, loadedCheckpoint, err = checkpoints.load(ctx, path, replaced)
+	require.NoError(t, err)
+	require.Empty(t, loaded.Uploaded)
+	require.NotEqual(t, checkpoint, loadedCheckpoint)
+}
+
+func TestCheckpoints_Cleanup(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	checkpoints := &checkpoints{log: zaptest.NewLogger(t), dir: ctx.Dir()}
+
+	expired := filepath.Join(ctx.Dir(), "expired")
+	recent := filepath.Join(ctx.Dir(), "recent")
+	require.NoError(t, os.MkdirAll(expired, 0700))
+	require.NoError(t, os.MkdirAll(recent, 0700))
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(expired, old, old))
+
+	require.NoError(t, checkpoints.cleanup(ctx, time.Hour))
+
+	_, err := os.Stat(expired)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(recent)
+	require.NoError(t, err)
+}
+
+// progressQueue is a repair queue which only stores the repair progress.
+type progressQueue struct {
+	queue.RepairQueue
+	progress map[string][]byte
+}
+
+func (q *progressQueue) SaveProgress(ctx context.Context, path []byte, progress []byte) error {
+	q.progress[string(path)] = progress
+	return nil
+}
+
+func (q *progressQueue) Progress(ctx context.Context, path []byte) ([]byte, error) {
+	return q.progress[string(path)], nil
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"io"
 	"io/ioutil"
+	"os"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -62,30 +63,51 @@ func (ec *ECRepairer) dialPiecestore(ctx context.Context, n storj.NodeURL) (*pie
 // After downloading a piece, the ECRepairer will verify the hash and original order limit for that piece.
 // If verification fails, another piece will be downloaded until we reach the minimum required or run out of order limits.
 // If piece hash verification fails, it will return all failed node IDs.
-func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, dataSize int64, path storj.Path) (_ io.ReadCloser, failedPieces []*pb.RemotePiece, err error) {
+// Pieces found in the checkpoint are used without downloading them and downloaded pieces are
+// added to the checkpoint, checkpoint may be nil.
+func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, dataSize int64, path storj.Path, checkpoint *segmentCheckpoint) (_ io.ReadCloser, failedPieces []*pb.RemotePiece, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if len(limits) != es.TotalCount() {
 		return nil, nil, Error.New("number of limits slice (%d) does not match total count (%d) of erasure scheme", len(limits), es.TotalCount())
 	}
 
-	nonNilLimits := nonNilCount(limits)
+	pieceSize := eestream.CalcPieceSize(dataSize, es)
+
+	var successfulPieces, inProgress int
+	pieceReaders := make(map[int]io.ReadCloser)
 
-	if nonNilLimits < es.RequiredCount() {
-		return nil, nil, Error.New("number of non-nil limits (%d) is less than required count (%d) of erasure scheme", nonNilCount(limits), es.RequiredCount())
+	// use the pieces downloaded and verified by a previous attempt
+	for pieceNum := range limits {
+		if successfulPieces >= es.RequiredCount() {
+			break
+		}
+		if reader, ok := checkpoint.open(pieceNum, pieceSize); ok {
+			pieceReaders[pieceNum] = reader
+			successfulPieces++
+		}
 	}
+	mon.IntVal("repair_checkpointed_pieces").Observe(int64(successfulPieces))
 
-	pieceSize := eestream.CalcPieceSize(dataSize, es)
+	var nonNilLimits int
+	for pieceNum, limit := range limits {
+		if _, ok := pieceReaders[pieceNum]; !ok && limit != nil {
+			nonNilLimits++
+		}
+	}
+
+	if successfulPieces+nonNilLimits < es.RequiredCount() {
+		closeReaders(pieceReaders)
+		return nil, nil, Error.New("number of non-nil limits (%d) is less than required count (%d) of erasure scheme", successfulPieces+nonNilLimits, es.RequiredCount())
+	}
 
-	var successfulPieces, inProgress int
 	unusedLimits := nonNilLimits
-	pieceReaders := make(map[int]io.ReadCloser)
 
 	limiter := sync2.NewLimiter(es.RequiredCount())
 	cond := sync.NewCond(&sync.Mutex{})
 
 	for currentLimitIndex, limit := range limits {
-		if limit == nil {
+		if _, ok := pieceReaders[currentLimitIndex]; ok || limit == nil {
 			continue
 		}
 
@@ -116,7 +138,7 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 				inProgress++
 				cond.L.Unlock()
 
-				pieceReadCloser, err := ec.downloadAndVerifyPiece(ctx, limit, privateKey, pieceSize)
+				pieceReadCloser, err := ec.downloadAndVerifyPiece(ctx, limit, privateKey, pieceSize, checkpoint, currentLimitIndex)
 				cond.L.Lock()
 				inProgress--
 				if err != nil {
@@ -144,6 +166,7 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 	limiter.Wait()
 
 	if successfulPieces < es.RequiredCount() {
+		closeReaders(pieceReaders)
 		mon.Meter("download_failed_not_enough_pieces_repair").Mark(1) //locked
 		return nil, failedPieces, &irreparableError{
 			path:            path,
@@ -169,7 +192,8 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 // downloadAndVerifyPiece downloads a piece from a storagenode,
 // expects the original order limit to have the correct piece public key,
 // and expects the hash of the data to match the signed hash provided by the storagenode.
-func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, pieceSize int64) (pieceReadCloser io.ReadCloser, err error) {
+// When downloading to disk and checkpoint is not nil, the verified piece is kept in the checkpoint.
+func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, pieceSize int64, checkpoint *segmentCheckpoint, pieceNum int) (pieceReadCloser io.ReadCloser, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// contact node
@@ -203,7 +227,12 @@ func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.Addr
 		downloadedPieceSize = int64(len(pieceBytes))
 		pieceReadCloser = ioutil.NopCloser(bytes.NewReader(pieceBytes))
 	} else {
-		tempfile, err := tmpfile.New("", "satellite-repair-*")
+		var tempfile *os.File
+		if checkpoint != nil {
+			tempfile, err = checkpoint.create()
+		} else {
+			tempfile, err = tmpfile.New("", "satellite-repair-*")
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -211,6 +240,9 @@ func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.Addr
 			// close and remove file if there is some error
 			if err != nil {
 				err = errs.Combine(err, tempfile.Close())
+				if checkpoint != nil {
+					err = errs.Combine(err, os.Remove(tempfile.Name()))
+				}
 			}
 		}()
 
@@ -251,9 +283,23 @@ func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.Addr
 		return nil, ErrPieceHashVerifyFailed.Wrap(err)
 	}
 
+	if checkpoint != nil && !ec.inmemory {
+		// the open file can still be read after renaming it.
+		if err := checkpoint.commit(pieceReadCloser.(*os.File), pieceNum); err != nil {
+			ec.log.Warn("failed to checkpoint downloaded piece", zap.Error(err))
+		}
+	}
+
 	return pieceReadCloser, nil
 }
 
+// closeReaders closes the piece readers which won't be decoded.
+func closeReaders(readers map[int]io.ReadCloser) {
+	for _, reader := range readers {
+		_ = reader.Close()
+	}
+}
+
 func verifyPieceHash(ctx context.Context, limit *pb.OrderLimit, hash *pb.PieceHash, expectedHash []byte) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -36,6 +36,8 @@ type Config struct {
 	MaxBufferMem                  memory.Size   `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"4M"`
 	MaxExcessRateOptimalThreshold float64       `help:"ratio applied to the optimal threshold to calculate the excess of the maximum number of repaired pieces to upload" default:"0.05"`
 	InMemoryRepair                bool          `help:"whether to download pieces for repair in memory (true) or download to disk (false)" default:"false"`
+	CheckpointDir                 string        `help:"persistent directory to keep downloaded pieces of unfinished repairs in, required when downloading to disk; interrupted repairs are resumed from it only on the same host" default:""`
+	CheckpointExpiration          time.Duration `help:"how long to keep downloaded pieces of unfinished repairs" default:"24h"`
 }
 
 // Service contains the information needed to run the repair service
@@ -94,6 +96,10 @@ func (service *Service) Run(ctx context.Context) (err error) {
 // processWhileQueueHasItems keeps calling process() until the queue is empty or something
 // else goes wrong in fetching from the queue.
 func (service *Service) processWhileQueueHasItems(ctx context.Context) error {
+	if err := service.repairer.checkpoints.cleanup(ctx, service.config.CheckpointExpiration); err != nil {
+		service.log.Warn("failed to remove expired repair checkpoints", zap.Error(err))
+	}
+
 	for {
 		err := service.process(ctx)
 		if err != nil {
@@ -19,6 +19,8 @@ import (
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair"
+	"storj.io/storj/satellite/repair/queue"
 	"storj.io/uplink/private/eestream"
 )
 
@@ -55,6 +57,9 @@ type SegmentRepairer struct {
 	ec       *ECRepairer
 	timeout  time.Duration
 
+	// checkpoints keeps the progress of interrupted repairs.
+	checkpoints *checkpoints
+
 	// multiplierOptimalThreshold is the value that multiplied by the optimal
 	// threshold results in the maximum limit of number of nodes to upload
 	// repaired pieces
@@ -62,6 +67,9 @@ type SegmentRepairer struct {
 
 	//repairOverride is the value handed over from the checker to override the Repair Threshold
 	repairOverride int
+
+	// rsProfiles are the redundancy scheme profiles, whose segments keep their own Repair Threshold
+	rsProfiles metainfo.RSProfiles
 }
 
 // NewSegmentRepairer creates a new instance of SegmentRepairer.
@@ -69,18 +77,29 @@ type SegmentRepairer struct {
 // excessPercentageOptimalThreshold is the percentage to apply over the optimal
 // threshould to determine the maximum limit of nodes to upload repaired pieces,
 // when negative, 0 is applied.
+//
+// When downloading to disk, the verified pieces of segments are kept in checkpointDir
+// until the segment is repaired, the directory must be persistent and is required.
+// The progress of repairs is kept in repairQueue, which is shared by all repairers,
+// but the pieces are only on the host of the interrupted repairer, so repairers on
+// other hosts download the pieces again.
 func NewSegmentRepairer(
 	log *zap.Logger, metainfo *metainfo.Service, orders *orders.Service,
 	overlay *overlay.Service, dialer rpc.Dialer, timeout time.Duration,
-	excessOptimalThreshold float64, repairOverride int,
+	excessOptimalThreshold float64, repairOverride int, rsProfiles metainfo.RSProfiles,
 	downloadTimeout time.Duration, inMemoryRepair bool,
 	satelliteSignee signing.Signee,
+	repairQueue queue.RepairQueue, checkpointDir string,
 ) *SegmentRepairer {
 
 	if excessOptimalThreshold < 0 {
 		excessOptimalThreshold = 0
 	}
 
+	if inMemoryRepair {
+		checkpointDir = ""
+	}
+
 	return &SegmentRepairer{
 		log:                        log,
 		metainfo:                   metainfo,
@@ -90,6 +109,12 @@ func NewSegmentRepairer(
 		timeout:                    timeout,
 		multiplierOptimalThreshold: 1 + excessOptimalThreshold,
 		repairOverride:             repairOverride,
+		rsProfiles:                 rsProfiles,
+		checkpoints: &checkpoints{
+			log:   log.Named("checkpoints"),
+			queue: repairQueue,
+			dir:   checkpointDir,
+		},
 	}
 }
 
@@ -115,11 +140,36 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 		return true, invalidRepairError.New("cannot repair inline segment")
 	}
 
-	if !pointer.ExpirationDate.IsZero() && pointer.ExpirationDate.Before(time.Now().UTC()) {
+	if metainfo.IsExpired(pointer, time.Now().UTC()) {
 		mon.Meter("repair_expired").Mark(1) //locked
 		return true, nil
 	}
 
+	// copies sharing the pieces of the segment are repaired together with it.
+	repairedPath, err := repairer.metainfo.SharedRepairPath(ctx, path, pointer)
+	if err != nil {
+		return false, metainfoGetError.Wrap(err)
+	}
+	if repairedPath != path {
+		mon.Meter("repair_shared_pieces_skipped").Mark(1)
+		repairer.log.Debug("segment shares its pieces, they are repaired with another segment",
+			zap.String("path", path), zap.String("repaired path", repairedPath))
+		return true, nil
+	}
+
+	progress, checkpoint, err := repairer.checkpoints.load(ctx, path, pointer)
+	if err != nil {
+		// the repair doesn't depend on the progress of previous attempts.
+		repairer.log.Warn("failed to load repair progress", zap.String("path", path), zap.Error(err))
+	}
+	if len(progress.Uploaded) > 0 {
+		pointer, err = repairer.commitUploads(ctx, path, pointer, progress.Uploaded)
+		if err != nil {
+			return false, metainfoPutError.Wrap(err)
+		}
+		progress.Uploaded = nil
+	}
+
 	mon.Meter("repair_attempts").Mark(1)                                //locked
 	mon.IntVal("repair_segment_size").Observe(pointer.GetSegmentSize()) //locked
 
@@ -149,13 +199,43 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 		}
 	}
 
-	repairThreshold := pointer.Remote.Redundancy.RepairThreshold
-	if repairer.repairOverride != 0 {
-		repairThreshold = int32(repairer.repairOverride)
+	placement, err := repairer.metainfo.SegmentPlacement(ctx, path)
+	if err != nil {
+		return false, metainfoGetError.Wrap(err)
+	}
+
+	misplacedPieces, err := repairer.overlay.MisplacedPieces(ctx, placement, pieces)
+	if err != nil {
+		return false, overlayQueryError.New("error identifying misplaced pieces: %w", err)
 	}
 
+	// pieces outside of the placement of the bucket are replaced like missing pieces,
+	// as long as the segment can be reconstructed without them.
+	unmovable := false
+	if len(misplacedPieces) > 0 {
+		if int32(numHealthy-len(misplacedPieces)) >= pointer.Remote.Redundancy.MinReq {
+			mon.Meter("repair_misplaced").Mark(1)
+			missingPieces = append(missingPieces, misplacedPieces...)
+			numHealthy -= len(misplacedPieces)
+		} else {
+			repairer.log.Warn("too few pieces within the placement to move misplaced pieces",
+				zap.String("path", path), zap.Int("misplaced", len(misplacedPieces)))
+			misplacedPieces = nil
+			unmovable = true
+		}
+	}
+
+	repairThreshold := repair.RepairThreshold(pointer.Remote.Redundancy, int32(repairer.repairOverride), repairer.rsProfiles)
+
 	// repair not needed
-	if int32(numHealthy) > repairThreshold {
+	if int32(numHealthy) > repairThreshold && len(misplacedPieces) == 0 {
+		if unmovable {
+			// the segment stays in the queue, so it's attempted again only after the retry
+			// interval of the queue, instead of after every loop of the checker, which
+			// only updates the health of queued segments.
+			mon.Meter("repair_misplaced_unmovable").Mark(1)
+			return false, nil
+		}
 		mon.Meter("repair_unnecessary").Mark(1) //locked
 		repairer.log.Debug("segment above repair threshold", zap.Int("numHealthy", numHealthy), zap.Int32("repairThreshold", repairThreshold))
 		return true, nil
@@ -170,11 +250,13 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 	lostPiecesSet := sliceToSet(missingPieces)
 
 	// Populate healthyPieces with all pieces from the pointer except those correlating to indices in lostPieces
+	var retainedNodeIDs storj.NodeIDList
 	for _, piece := range pieces {
 		excludeNodeIDs = append(excludeNodeIDs, piece.NodeId)
 		if !lostPiecesSet[piece.GetPieceNum()] {
 			healthyPieces = append(healthyPieces, piece)
 			healthyMap[piece.GetPieceNum()] = true
+			retainedNodeIDs = append(retainedNodeIDs, piece.NodeId)
 		} else {
 			unhealthyPieces = append(unhealthyPieces, piece)
 		}
@@ -201,10 +283,21 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 		minSuccessfulNeeded = redundancy.OptimalThreshold() - len(healthyPieces)
 	}
 
+	if requestCount <= 0 {
+		// there are enough healthy pieces within the placement, the misplaced ones only need to be dropped.
+		_, err = repairer.metainfo.UpdatePieces(ctx, path, pointer, nil, unhealthyPieces)
+		if err != nil {
+			return false, metainfoPutError.Wrap(err)
+		}
+		return true, nil
+	}
+
 	// Request Overlay for n-h new storage nodes
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: requestCount,
 		ExcludedIDs:    excludeNodeIDs,
+		Placement:      placement,
+		RetainedIDs:    retainedNodeIDs,
 	}
 	newNodes, err := repairer.overlay.FindStorageNodesForRepair(ctx, request)
 	if err != nil {
@@ -218,7 +311,7 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 	}
 
 	// Download the segment using just the healthy pieces
-	segmentReader, failedPieces, err := repairer.ec.Get(ctx, getOrderLimits, getPrivateKey, redundancy, pointer.GetSegmentSize(), path)
+	segmentReader, failedPieces, err := repairer.ec.Get(ctx, getOrderLimits, getPrivateKey, redundancy, pointer.GetSegmentSize(), path, checkpoint)
 
 	// Populate node IDs that failed piece hashes verification
 	var failedNodeIDs storj.NodeIDList
@@ -268,6 +361,11 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 		repairedMap[int32(i)] = true
 	}
 
+	// keep the uploaded pieces, so that a later attempt can commit them when updating the pointer fails.
+	if err := repairer.saveUploads(ctx, path, progress, repairedPieces); err != nil {
+		repairer.log.Warn("failed to save repair progress", zap.String("path", path), zap.Error(err))
+	}
+
 	healthyAfterRepair := int32(len(healthyPieces) + len(repairedPieces))
 	switch {
 	case healthyAfterRepair <= pointer.Remote.Redundancy.RepairThreshold:
@@ -326,9 +424,94 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 	mon.IntVal("segment_time_until_repair").Observe(int64(segmentAge.Seconds())) //locked
 	mon.IntVal("segment_repair_count").Observe(int64(pointer.RepairCount))       //locked
 
+	if err := checkpoint.remove(); err != nil {
+		repairer.log.Warn("failed to remove repair checkpoint", zap.String("path", path), zap.Error(err))
+	}
+
 	return true, nil
 }
 
+// commitUploads adds the pieces uploaded by a previous attempt to the pointer, which weren't
+// committed because the attempt was interrupted. Pieces on nodes, which aren't healthy anymore,
+// are dropped and the pieces they replace are removed from the pointer.
+func (repairer *SegmentRepairer) commitUploads(ctx context.Context, path storj.Path, pointer *pb.Pointer, uploaded []uploadedPiece) (_ *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var uploadedPieces []*pb.RemotePiece
+	for _, piece := range uploaded {
+		hash := &pb.PieceHash{}
+		if err := pb.Unmarshal(piece.Hash, hash); err != nil {
+			repairer.log.Warn("discarding uploaded piece with invalid hash", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		uploadedPieces = append(uploadedPieces, &pb.RemotePiece{
+			PieceNum: piece.PieceNum,
+			NodeId:   piece.NodeID,
+			Hash:     hash,
+		})
+	}
+
+	missingUploads, err := repairer.overlay.GetMissingPieces(ctx, uploadedPieces)
+	if err != nil {
+		return nil, overlayQueryError.New("error identifying missing pieces: %w", err)
+	}
+	pieces := pointer.GetRemote().GetRemotePieces()
+	missingPieces, err := repairer.overlay.GetMissingPieces(ctx, pieces)
+	if err != nil {
+		return nil, overlayQueryError.New("error identifying missing pieces: %w", err)
+	}
+	missingUploadsSet, missingPiecesSet := sliceToSet(missingUploads), sliceToSet(missingPieces)
+
+	current := make(map[int32]*pb.RemotePiece, len(pieces))
+	for _, piece := range pieces {
+		current[piece.PieceNum] = piece
+	}
+
+	var toAdd, toRemove []*pb.RemotePiece
+	for _, piece := range uploadedPieces {
+		if missingUploadsSet[piece.PieceNum] {
+			continue
+		}
+		if existing, ok := current[piece.PieceNum]; ok {
+			if existing.NodeId == piece.NodeId || !missingPiecesSet[piece.PieceNum] {
+				continue
+			}
+			toRemove = append(toRemove, existing)
+		}
+		toAdd = append(toAdd, piece)
+	}
+
+	if len(toAdd) == 0 {
+		return pointer, nil
+	}
+
+	pointer, err = repairer.metainfo.UpdatePieces(ctx, path, pointer, toAdd, toRemove)
+	if err != nil {
+		return nil, err
+	}
+	mon.Meter("repair_resumed_uploads").Mark(len(toAdd))
+	return pointer, nil
+}
+
+// saveUploads stores the uploaded pieces in the repair progress.
+func (repairer *SegmentRepairer) saveUploads(ctx context.Context, path storj.Path, progress *repairProgress, pieces []*pb.RemotePiece) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	progress.Uploaded = progress.Uploaded[:0]
+	for _, piece := range pieces {
+		hash, err := pb.Marshal(piece.Hash)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		progress.Uploaded = append(progress.Uploaded, uploadedPiece{
+			PieceNum: piece.PieceNum,
+			NodeID:   piece.NodeId,
+			Hash:     hash,
+		})
+	}
+	return repairer.checkpoints.save(ctx, path, progress)
+}
+
 func (repairer *SegmentRepairer) updateAuditFailStatus(ctx context.Context, failedAuditNodeIDs storj.NodeIDList) (failedNum int, err error) {
 	updateRequests := make([]*overlay.UpdateRequest, len(failedAuditNodeIDs))
 	for i, nodeID := range failedAuditNodeIDs {
@@ -69,7 +69,7 @@ type Repairer struct {
 func NewRepairer(log *zap.Logger, full *identity.FullIdentity,
 	pointerDB metainfo.PointerDB,
 	revocationDB extensions.RevocationDB, repairQueue queue.RepairQueue,
-	bucketsDB metainfo.BucketsDB, overlayCache overlay.DB,
+	bucketsDB metainfo.BucketsDB, segmentReferences metainfo.SegmentReferencesDB, overlayCache overlay.DB,
 	rollupsWriteCache *orders.RollupsWriteCache, irrDB irreparable.DB,
 	versionInfo version.Info, config *Config, atomicLogLevel *zap.AtomicLevel) (*Repairer, error) {
 	peer := &Repairer{
@@ -128,11 +128,15 @@ func NewRepairer(log *zap.Logger, full *identity.FullIdentity,
 	}
 
 	{ // setup metainfo
-		peer.Metainfo = metainfo.NewService(log.Named("metainfo"), pointerDB, bucketsDB)
+		peer.Metainfo = metainfo.NewService(log.Named("metainfo"), pointerDB, bucketsDB, segmentReferences)
 	}
 
 	{ // setup overlay
-		peer.Overlay = overlay.NewService(log.Named("overlay"), overlayCache, config.Overlay)
+		var err error
+		peer.Overlay, err = overlay.NewService(log.Named("overlay"), overlayCache, config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "overlay",
 			Close: peer.Overlay.Close,
@@ -166,6 +170,10 @@ func NewRepairer(log *zap.Logger, full *identity.FullIdentity,
 	}
 
 	{ // setup repairer
+		if !config.Repairer.InMemoryRepair && config.Repairer.CheckpointDir == "" {
+			return nil, errs.Combine(errs.New("repairer.checkpoint-dir is required when repairer.in-memory-repair is false"), peer.Close())
+		}
+
 		peer.SegmentRepairer = repairer.NewSegmentRepairer(
 			log.Named("segment-repair"),
 			peer.Metainfo,
@@ -175,9 +183,12 @@ func NewRepairer(log *zap.Logger, full *identity.FullIdentity,
 			config.Repairer.Timeout,
 			config.Repairer.MaxExcessRateOptimalThreshold,
 			config.Checker.RepairOverride,
+			config.Metainfo.RS.Profiles,
 			config.Repairer.DownloadTimeout,
 			config.Repairer.InMemoryRepair,
 			signing.SigneeFromPeerIdentity(peer.Identity.PeerIdentity()),
+			repairQueue,
+			config.Repairer.CheckpointDir,
 		)
 		peer.Repairer = repairer.NewService(log.Named("repairer"), repairQueue, &config.Repairer, peer.SegmentRepairer, irrDB)
 
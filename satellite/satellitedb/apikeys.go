@@ -7,6 +7,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/lib/pq"
 	"github.com/zeebo/errs"
 
 	"storj.io/common/uuid"
@@ -70,8 +71,9 @@ func (keys *apikeys) GetPagedByProjectID(ctx context.Context, projectID uuid.UUI
 	}
 
 	repoundQuery := keys.db.Rebind(`
-		SELECT ak.id, ak.project_id, ak.name, ak.partner_id, ak.created_at
+		SELECT ak.id, ak.project_id, ak.name, ak.partner_id, ak.created_at, ak.rate_limit, COALESCE(rc.count, 0)
 		FROM api_keys ak
+		LEFT JOIN api_key_request_counts rc ON rc.api_key_id = ak.id
 		WHERE ak.project_id = ?
 		AND lower(ak.name) LIKE ?
 		ORDER BY ` + sanitizedAPIKeyOrderColumnName(cursor.Order) + `
@@ -95,7 +97,7 @@ func (keys *apikeys) GetPagedByProjectID(ctx context.Context, projectID uuid.UUI
 		ak := console.APIKeyInfo{}
 		var partnerID uuid.NullUUID
 
-		err = rows.Scan(&ak.ID, &ak.ProjectID, &ak.Name, &partnerID, &ak.CreatedAt)
+		err = rows.Scan(&ak.ID, &ak.ProjectID, &ak.Name, &partnerID, &ak.CreatedAt, &ak.RateLimit, &ak.RequestCount)
 		if err != nil {
 			return nil, err
 		}
@@ -212,6 +214,51 @@ func (keys *apikeys) Delete(ctx context.Context, id uuid.UUID) (err error) {
 	return err
 }
 
+// UpdateRateLimit implements satellite.APIKeys
+func (keys *apikeys) UpdateRateLimit(ctx context.Context, id uuid.UUID, newLimit int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rateLimit := &newLimit
+	if newLimit == 0 {
+		rateLimit = nil
+	}
+
+	return keys.methods.UpdateNoReturn_ApiKey_By_Id(
+		ctx,
+		dbx.ApiKey_Id(id[:]),
+		dbx.ApiKey_Update_Fields{
+			RateLimit: dbx.ApiKey_RateLimit_Raw(rateLimit),
+		},
+	)
+}
+
+// IncrementRequestCounts implements satellite.APIKeys
+func (keys *apikeys) IncrementRequestCounts(ctx context.Context, counts map[uuid.UUID]int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if len(counts) == 0 {
+		return nil
+	}
+
+	keyIDs := make([][]byte, 0, len(counts))
+	amounts := make([]int64, 0, len(counts))
+	for id, count := range counts {
+		id := id
+		keyIDs = append(keyIDs, id[:])
+		amounts = append(amounts, count)
+	}
+
+	// keys deleted since the requests were counted are skipped by the join.
+	_, err = keys.db.ExecContext(ctx, `
+		INSERT INTO api_key_request_counts (api_key_id, count)
+		SELECT api_keys.id, counts.count
+		FROM unnest($1::bytea[], $2::int8[]) AS counts(api_key_id, count)
+		JOIN api_keys ON api_keys.id = counts.api_key_id
+		ON CONFLICT(api_key_id)
+		DO UPDATE SET count = api_key_request_counts.count + EXCLUDED.count
+	`, pq.ByteaArray(keyIDs), pq.Array(amounts))
+	return Error.Wrap(err)
+}
+
 // fromDBXAPIKey converts dbx.ApiKey to satellite.APIKeyInfo
 func fromDBXAPIKey(ctx context.Context, key *dbx.ApiKey) (_ *console.APIKeyInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -231,6 +278,7 @@ func fromDBXAPIKey(ctx context.Context, key *dbx.ApiKey) (_ *console.APIKeyInfo,
 		Name:      key.Name,
 		CreatedAt: key.CreatedAt,
 		Secret:    key.Secret,
+		RateLimit: key.RateLimit,
 	}
 
 	if key.PartnerId != nil {
@@ -8,10 +8,15 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/zeebo/errs"
+
 	"storj.io/common/macaroon"
+	"storj.io/common/memory"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/nodeselection"
 	"storj.io/storj/satellite/satellitedb/dbx"
 )
 
@@ -96,6 +101,287 @@ func (db *bucketsDB) UpdateBucket(ctx context.Context, bucket storj.Bucket) (_ s
 	return convertDBXtoBucket(dbxBucket)
 }
 
+// GetBucketSettings returns the settings of a bucket
+func (db *bucketsDB) GetBucketSettings(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ metainfo.BucketSettings, err error) {
+	defer mon.Task()(&ctx)(&err)
+	dbxBucket, err := db.db.Get_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return metainfo.BucketSettings{}, storj.ErrBucketNotFound.New("%s", bucketName)
+		}
+		return metainfo.BucketSettings{}, storj.ErrBucket.Wrap(err)
+	}
+
+	var settings metainfo.BucketSettings
+	if dbxBucket.Placement != nil {
+		settings.Placement, err = nodeselection.ParsePlacement(*dbxBucket.Placement)
+		if err != nil {
+			return metainfo.BucketSettings{}, storj.ErrBucket.Wrap(err)
+		}
+	}
+	if dbxBucket.StorageLimit != nil {
+		settings.Limits.Storage = memory.Size(*dbxBucket.StorageLimit)
+	}
+	if dbxBucket.BandwidthLimit != nil {
+		settings.Limits.Bandwidth = memory.Size(*dbxBucket.BandwidthLimit)
+	}
+	if dbxBucket.ObjectLimit != nil {
+		settings.Limits.Objects = *dbxBucket.ObjectLimit
+	}
+	if dbxBucket.RsProfile != nil {
+		settings.RSProfile = *dbxBucket.RsProfile
+	}
+	settings.Versioning = dbxBucket.Versioning != nil && *dbxBucket.Versioning
+	if dbxBucket.DefaultRetentionDays != nil {
+		settings.DefaultRetentionDays = *dbxBucket.DefaultRetentionDays
+	}
+	return settings, nil
+}
+
+// GetBucketPlacement returns the placement of a bucket
+func (db *bucketsDB) GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ nodeselection.Placement, err error) {
+	defer mon.Task()(&ctx)(&err)
+	settings, err := db.GetBucketSettings(ctx, bucketName, projectID)
+	return settings.Placement, err
+}
+
+// UpdateBucketPlacement sets the placement of a bucket
+func (db *bucketsDB) UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement nodeselection.Placement) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var updateFields dbx.BucketMetainfo_Update_Fields
+	if placement.IsZero() {
+		updateFields.Placement = dbx.BucketMetainfo_Placement_Null()
+	} else {
+		updateFields.Placement = dbx.BucketMetainfo_Placement(placement.String())
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		updateFields,
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketLimits returns the usage limits of a bucket
+func (db *bucketsDB) GetBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ accounting.BucketLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+	settings, err := db.GetBucketSettings(ctx, bucketName, projectID)
+	return settings.Limits, err
+}
+
+// UpdateBucketLimits sets the usage limits of a bucket
+func (db *bucketsDB) UpdateBucketLimits(ctx context.Context, bucketName []byte, projectID uuid.UUID, limits accounting.BucketLimits) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var updateFields dbx.BucketMetainfo_Update_Fields
+	updateFields.StorageLimit = dbx.BucketMetainfo_StorageLimit_Null()
+	if limits.Storage > 0 {
+		updateFields.StorageLimit = dbx.BucketMetainfo_StorageLimit(limits.Storage.Int64())
+	}
+	updateFields.BandwidthLimit = dbx.BucketMetainfo_BandwidthLimit_Null()
+	if limits.Bandwidth > 0 {
+		updateFields.BandwidthLimit = dbx.BucketMetainfo_BandwidthLimit(limits.Bandwidth.Int64())
+	}
+	updateFields.ObjectLimit = dbx.BucketMetainfo_ObjectLimit_Null()
+	if limits.Objects > 0 {
+		updateFields.ObjectLimit = dbx.BucketMetainfo_ObjectLimit(limits.Objects)
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		updateFields,
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketLifecycle returns the lifecycle rules of a bucket
+func (db *bucketsDB) GetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ metainfo.LifecycleRules, err error) {
+	defer mon.Task()(&ctx)(&err)
+	dbxBucket, err := db.db.Get_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storj.ErrBucketNotFound.New("%s", bucketName)
+		}
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+
+	rules, err := metainfo.UnmarshalLifecycleRules(dbxBucket.LifecycleRules)
+	if err != nil {
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+	return rules, nil
+}
+
+// UpdateBucketLifecycle sets the lifecycle rules of a bucket
+func (db *bucketsDB) UpdateBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID, rules metainfo.LifecycleRules) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	data, err := metainfo.MarshalLifecycleRules(rules)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		dbx.BucketMetainfo_Update_Fields{
+			LifecycleRules: dbx.BucketMetainfo_LifecycleRules_Raw(data),
+		},
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// ListBucketLifecycles returns the lifecycle rules of all buckets which have any
+func (db *bucketsDB) ListBucketLifecycles(ctx context.Context) (lifecycles []metainfo.BucketLifecycle, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT project_id, name, lifecycle_rules
+		FROM bucket_metainfos
+		WHERE lifecycle_rules IS NOT NULL
+	`)
+	if err != nil {
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var lifecycle metainfo.BucketLifecycle
+		var data []byte
+		err = rows.Scan(&lifecycle.ProjectID, &lifecycle.BucketName, &data)
+		if err != nil {
+			return nil, storj.ErrBucket.Wrap(err)
+		}
+
+		lifecycle.Rules, err = metainfo.UnmarshalLifecycleRules(data)
+		if err != nil {
+			return nil, storj.ErrBucket.Wrap(err)
+		}
+		if len(lifecycle.Rules) > 0 {
+			lifecycles = append(lifecycles, lifecycle)
+		}
+	}
+	return lifecycles, storj.ErrBucket.Wrap(rows.Err())
+}
+
+// GetBucketRSProfile returns the name of the redundancy scheme profile of a bucket
+func (db *bucketsDB) GetBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	settings, err := db.GetBucketSettings(ctx, bucketName, projectID)
+	return settings.RSProfile, err
+}
+
+// UpdateBucketRSProfile sets the redundancy scheme profile of a bucket
+func (db *bucketsDB) UpdateBucketRSProfile(ctx context.Context, bucketName []byte, projectID uuid.UUID, profile string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var updateFields dbx.BucketMetainfo_Update_Fields
+	if profile == "" {
+		updateFields.RsProfile = dbx.BucketMetainfo_RsProfile_Null()
+	} else {
+		updateFields.RsProfile = dbx.BucketMetainfo_RsProfile(profile)
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		updateFields,
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketVersioning returns whether versioning is enabled for a bucket
+func (db *bucketsDB) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+	settings, err := db.GetBucketSettings(ctx, bucketName, projectID)
+	return settings.Versioning, err
+}
+
+// UpdateBucketVersioning enables or disables versioning for a bucket
+func (db *bucketsDB) UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, enabled bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		dbx.BucketMetainfo_Update_Fields{
+			Versioning: dbx.BucketMetainfo_Versioning(enabled),
+		},
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketDefaultRetention returns the number of days new objects of a bucket are locked for
+func (db *bucketsDB) GetBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ int, err error) {
+	defer mon.Task()(&ctx)(&err)
+	settings, err := db.GetBucketSettings(ctx, bucketName, projectID)
+	return settings.DefaultRetentionDays, err
+}
+
+// UpdateBucketDefaultRetention sets the number of days new objects of a bucket are locked for
+func (db *bucketsDB) UpdateBucketDefaultRetention(ctx context.Context, bucketName []byte, projectID uuid.UUID, days int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	retention := dbx.BucketMetainfo_DefaultRetentionDays_Null()
+	if days > 0 {
+		retention = dbx.BucketMetainfo_DefaultRetentionDays(days)
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		dbx.BucketMetainfo_Update_Fields{
+			DefaultRetentionDays: retention,
+		},
+	)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
 // DeleteBucket deletes a bucket
 func (db *bucketsDB) DeleteBucket(ctx context.Context, bucketName []byte, projectID uuid.UUID) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -122,6 +122,8 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	segment_health double precision NOT NULL DEFAULT 1000000,
+	repair_progress bytea,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -132,6 +134,14 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE metainfo_loop_ranges (
+	observer text NOT NULL,
+	range_index integer NOT NULL,
+	owner bytea,
+	lease_expires_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( observer, range_index )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -174,6 +184,11 @@ CREATE TABLE nodes (
 	exit_success boolean NOT NULL DEFAULT false,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE node_tags (
+	node_id bytea NOT NULL,
+	tags text NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes_offline_times (
 	node_id bytea NOT NULL,
 	tracked_at timestamp with time zone NOT NULL,
@@ -239,6 +254,11 @@ CREATE TABLE project_bandwidth_rollups (
 	egress_allocated bigint NOT NULL,
 	PRIMARY KEY ( project_id, interval_month )
 );
+CREATE TABLE project_live_totals (
+	project_id bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id )
+);
 CREATE TABLE registration_tokens (
 	secret bytea NOT NULL,
 	owner_id bytea,
@@ -264,6 +284,16 @@ CREATE TABLE reset_password_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE segment_references (
+	root_piece_id bytea NOT NULL,
+	path bytea NOT NULL,
+	PRIMARY KEY ( root_piece_id, path )
+);
+CREATE TABLE segment_relocations (
+	path bytea NOT NULL,
+	relocated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -373,6 +403,7 @@ CREATE TABLE api_keys (
 	secret bytea NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	rate_limit integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
@@ -393,6 +424,14 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement text,
+	storage_limit bigint,
+	bandwidth_limit bigint,
+	object_limit bigint,
+	lifecycle_rules bytea,
+	rs_profile text,
+	versioning boolean,
+	default_retention_days integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -435,12 +474,18 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE api_key_request_counts (
+	api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+	count bigint NOT NULL,
+	PRIMARY KEY ( api_key_id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
 CREATE INDEX injuredsegments_attempted_index ON injuredsegments ( attempted );
 CREATE INDEX injuredsegments_num_healthy_pieces_index ON injuredsegments ( num_healthy_pieces );
+CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );
 CREATE INDEX node_last_ip ON nodes ( last_net );
 CREATE INDEX nodes_offline_times_node_id_index ON nodes_offline_times ( node_id );
 CREATE UNIQUE INDEX serial_number_index ON serial_numbers ( serial_number );
@@ -394,6 +394,8 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	segment_health double precision NOT NULL DEFAULT 1000000,
+	repair_progress bytea,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -404,6 +406,14 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE metainfo_loop_ranges (
+	observer text NOT NULL,
+	range_index integer NOT NULL,
+	owner bytea,
+	lease_expires_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( observer, range_index )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -446,6 +456,11 @@ CREATE TABLE nodes (
 	exit_success boolean NOT NULL DEFAULT false,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE node_tags (
+	node_id bytea NOT NULL,
+	tags text NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes_offline_times (
 	node_id bytea NOT NULL,
 	tracked_at timestamp with time zone NOT NULL,
@@ -511,6 +526,11 @@ CREATE TABLE project_bandwidth_rollups (
 	egress_allocated bigint NOT NULL,
 	PRIMARY KEY ( project_id, interval_month )
 );
+CREATE TABLE project_live_totals (
+	project_id bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id )
+);
 CREATE TABLE registration_tokens (
 	secret bytea NOT NULL,
 	owner_id bytea,
@@ -536,6 +556,16 @@ CREATE TABLE reset_password_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE segment_references (
+	root_piece_id bytea NOT NULL,
+	path bytea NOT NULL,
+	PRIMARY KEY ( root_piece_id, path )
+);
+CREATE TABLE segment_relocations (
+	path bytea NOT NULL,
+	relocated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -645,6 +675,7 @@ CREATE TABLE api_keys (
 	secret bytea NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	rate_limit integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
@@ -665,6 +696,14 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement text,
+	storage_limit bigint,
+	bandwidth_limit bigint,
+	object_limit bigint,
+	lifecycle_rules bytea,
+	rs_profile text,
+	versioning boolean,
+	default_retention_days integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -707,12 +746,18 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE api_key_request_counts (
+	api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+	count bigint NOT NULL,
+	PRIMARY KEY ( api_key_id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
 CREATE INDEX injuredsegments_attempted_index ON injuredsegments ( attempted );
 CREATE INDEX injuredsegments_num_healthy_pieces_index ON injuredsegments ( num_healthy_pieces );
+CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );
 CREATE INDEX node_last_ip ON nodes ( last_net );
 CREATE INDEX nodes_offline_times_node_id_index ON nodes_offline_times ( node_id );
 CREATE UNIQUE INDEX serial_number_index ON serial_numbers ( serial_number );
@@ -906,6 +951,8 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	segment_health double precision NOT NULL DEFAULT 1000000,
+	repair_progress bytea,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -916,6 +963,14 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE metainfo_loop_ranges (
+	observer text NOT NULL,
+	range_index integer NOT NULL,
+	owner bytea,
+	lease_expires_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( observer, range_index )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -958,6 +1013,11 @@ CREATE TABLE nodes (
 	exit_success boolean NOT NULL DEFAULT false,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE node_tags (
+	node_id bytea NOT NULL,
+	tags text NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes_offline_times (
 	node_id bytea NOT NULL,
 	tracked_at timestamp with time zone NOT NULL,
@@ -1023,6 +1083,11 @@ CREATE TABLE project_bandwidth_rollups (
 	egress_allocated bigint NOT NULL,
 	PRIMARY KEY ( project_id, interval_month )
 );
+CREATE TABLE project_live_totals (
+	project_id bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id )
+);
 CREATE TABLE registration_tokens (
 	secret bytea NOT NULL,
 	owner_id bytea,
@@ -1048,6 +1113,16 @@ CREATE TABLE reset_password_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE segment_references (
+	root_piece_id bytea NOT NULL,
+	path bytea NOT NULL,
+	PRIMARY KEY ( root_piece_id, path )
+);
+CREATE TABLE segment_relocations (
+	path bytea NOT NULL,
+	relocated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -1157,6 +1232,7 @@ CREATE TABLE api_keys (
 	secret bytea NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	rate_limit integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
@@ -1177,6 +1253,14 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement text,
+	storage_limit bigint,
+	bandwidth_limit bigint,
+	object_limit bigint,
+	lifecycle_rules bytea,
+	rs_profile text,
+	versioning boolean,
+	default_retention_days integer,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -1219,12 +1303,18 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE api_key_request_counts (
+	api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+	count bigint NOT NULL,
+	PRIMARY KEY ( api_key_id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
 CREATE INDEX injuredsegments_attempted_index ON injuredsegments ( attempted );
 CREATE INDEX injuredsegments_num_healthy_pieces_index ON injuredsegments ( num_healthy_pieces );
+CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );
 CREATE INDEX node_last_ip ON nodes ( last_net );
 CREATE INDEX nodes_offline_times_node_id_index ON nodes_offline_times ( node_id );
 CREATE UNIQUE INDEX serial_number_index ON serial_numbers ( serial_number );
@@ -3137,6 +3227,8 @@ type Injuredsegment struct {
 	Data             []byte
 	Attempted        *time.Time
 	NumHealthyPieces int
+	SegmentHealth    float64
+	RepairProgress   []byte
 }
 
 func (Injuredsegment) _Table() string { return "injuredsegments" }
@@ -3144,10 +3236,13 @@ func (Injuredsegment) _Table() string { return "injuredsegments" }
 type Injuredsegment_Create_Fields struct {
 	Attempted        Injuredsegment_Attempted_Field
 	NumHealthyPieces Injuredsegment_NumHealthyPieces_Field
+	SegmentHealth    Injuredsegment_SegmentHealth_Field
+	RepairProgress   Injuredsegment_RepairProgress_Field
 }
 
 type Injuredsegment_Update_Fields struct {
-	Attempted Injuredsegment_Attempted_Field
+	Attempted      Injuredsegment_Attempted_Field
+	RepairProgress Injuredsegment_RepairProgress_Field
 }
 
 type Injuredsegment_Path_Field struct {
@@ -3239,6 +3334,59 @@ func (f Injuredsegment_NumHealthyPieces_Field) value() interface{} {
 
 func (Injuredsegment_NumHealthyPieces_Field) _Column() string { return "num_healthy_pieces" }
 
+type Injuredsegment_SegmentHealth_Field struct {
+	_set   bool
+	_null  bool
+	_value float64
+}
+
+func Injuredsegment_SegmentHealth(v float64) Injuredsegment_SegmentHealth_Field {
+	return Injuredsegment_SegmentHealth_Field{_set: true, _value: v}
+}
+
+func (f Injuredsegment_SegmentHealth_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_SegmentHealth_Field) _Column() string { return "segment_health" }
+
+type Injuredsegment_RepairProgress_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func Injuredsegment_RepairProgress(v []byte) Injuredsegment_RepairProgress_Field {
+	return Injuredsegment_RepairProgress_Field{_set: true, _value: v}
+}
+
+func Injuredsegment_RepairProgress_Raw(v []byte) Injuredsegment_RepairProgress_Field {
+	if v == nil {
+		return Injuredsegment_RepairProgress_Null()
+	}
+	return Injuredsegment_RepairProgress(v)
+}
+
+func Injuredsegment_RepairProgress_Null() Injuredsegment_RepairProgress_Field {
+	return Injuredsegment_RepairProgress_Field{_set: true, _null: true}
+}
+
+func (f Injuredsegment_RepairProgress_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f Injuredsegment_RepairProgress_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_RepairProgress_Field) _Column() string { return "repair_progress" }
+
 type Irreparabledb struct {
 	Segmentpath        []byte
 	Segmentdetail      []byte
@@ -3351,6 +3499,166 @@ func (f Irreparabledb_RepairAttemptCount_Field) value() interface{} {
 
 func (Irreparabledb_RepairAttemptCount_Field) _Column() string { return "repair_attempt_count" }
 
+type MetainfoLoopRange struct {
+	Observer       string
+	RangeIndex     int
+	Owner          []byte
+	LeaseExpiresAt *time.Time
+	FinishedAt     *time.Time
+}
+
+func (MetainfoLoopRange) _Table() string { return "metainfo_loop_ranges" }
+
+type MetainfoLoopRange_Create_Fields struct {
+	Owner          MetainfoLoopRange_Owner_Field
+	LeaseExpiresAt MetainfoLoopRange_LeaseExpiresAt_Field
+	FinishedAt     MetainfoLoopRange_FinishedAt_Field
+}
+
+type MetainfoLoopRange_Update_Fields struct {
+	Owner          MetainfoLoopRange_Owner_Field
+	LeaseExpiresAt MetainfoLoopRange_LeaseExpiresAt_Field
+	FinishedAt     MetainfoLoopRange_FinishedAt_Field
+}
+
+type MetainfoLoopRange_Observer_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func MetainfoLoopRange_Observer(v string) MetainfoLoopRange_Observer_Field {
+	return MetainfoLoopRange_Observer_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_Observer_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_Observer_Field) _Column() string { return "observer" }
+
+type MetainfoLoopRange_RangeIndex_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func MetainfoLoopRange_RangeIndex(v int) MetainfoLoopRange_RangeIndex_Field {
+	return MetainfoLoopRange_RangeIndex_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_RangeIndex_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_RangeIndex_Field) _Column() string { return "range_index" }
+
+type MetainfoLoopRange_Owner_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func MetainfoLoopRange_Owner(v []byte) MetainfoLoopRange_Owner_Field {
+	return MetainfoLoopRange_Owner_Field{_set: true, _value: v}
+}
+
+func MetainfoLoopRange_Owner_Raw(v []byte) MetainfoLoopRange_Owner_Field {
+	if v == nil {
+		return MetainfoLoopRange_Owner_Null()
+	}
+	return MetainfoLoopRange_Owner(v)
+}
+
+func MetainfoLoopRange_Owner_Null() MetainfoLoopRange_Owner_Field {
+	return MetainfoLoopRange_Owner_Field{_set: true, _null: true}
+}
+
+func (f MetainfoLoopRange_Owner_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f MetainfoLoopRange_Owner_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_Owner_Field) _Column() string { return "owner" }
+
+type MetainfoLoopRange_LeaseExpiresAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func MetainfoLoopRange_LeaseExpiresAt(v time.Time) MetainfoLoopRange_LeaseExpiresAt_Field {
+	return MetainfoLoopRange_LeaseExpiresAt_Field{_set: true, _value: &v}
+}
+
+func MetainfoLoopRange_LeaseExpiresAt_Raw(v *time.Time) MetainfoLoopRange_LeaseExpiresAt_Field {
+	if v == nil {
+		return MetainfoLoopRange_LeaseExpiresAt_Null()
+	}
+	return MetainfoLoopRange_LeaseExpiresAt(*v)
+}
+
+func MetainfoLoopRange_LeaseExpiresAt_Null() MetainfoLoopRange_LeaseExpiresAt_Field {
+	return MetainfoLoopRange_LeaseExpiresAt_Field{_set: true, _null: true}
+}
+
+func (f MetainfoLoopRange_LeaseExpiresAt_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f MetainfoLoopRange_LeaseExpiresAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_LeaseExpiresAt_Field) _Column() string { return "lease_expires_at" }
+
+type MetainfoLoopRange_FinishedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func MetainfoLoopRange_FinishedAt(v time.Time) MetainfoLoopRange_FinishedAt_Field {
+	return MetainfoLoopRange_FinishedAt_Field{_set: true, _value: &v}
+}
+
+func MetainfoLoopRange_FinishedAt_Raw(v *time.Time) MetainfoLoopRange_FinishedAt_Field {
+	if v == nil {
+		return MetainfoLoopRange_FinishedAt_Null()
+	}
+	return MetainfoLoopRange_FinishedAt(*v)
+}
+
+func MetainfoLoopRange_FinishedAt_Null() MetainfoLoopRange_FinishedAt_Field {
+	return MetainfoLoopRange_FinishedAt_Field{_set: true, _null: true}
+}
+
+func (f MetainfoLoopRange_FinishedAt_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f MetainfoLoopRange_FinishedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_FinishedAt_Field) _Column() string { return "finished_at" }
+
 type Node struct {
 	Id                          []byte
 	Address                     string
@@ -4301,6 +4609,55 @@ func (f Node_ExitSuccess_Field) value() interface{} {
 
 func (Node_ExitSuccess_Field) _Column() string { return "exit_success" }
 
+type NodeTag struct {
+	NodeId []byte
+	Tags   string
+}
+
+func (NodeTag) _Table() string { return "node_tags" }
+
+type NodeTag_Update_Fields struct {
+	Tags NodeTag_Tags_Field
+}
+
+type NodeTag_NodeId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func NodeTag_NodeId(v []byte) NodeTag_NodeId_Field {
+	return NodeTag_NodeId_Field{_set: true, _value: v}
+}
+
+func (f NodeTag_NodeId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeTag_NodeId_Field) _Column() string { return "node_id" }
+
+type NodeTag_Tags_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func NodeTag_Tags(v string) NodeTag_Tags_Field {
+	return NodeTag_Tags_Field{_set: true, _value: v}
+}
+
+func (f NodeTag_Tags_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeTag_Tags_Field) _Column() string { return "tags" }
+
 type NodesOfflineTime struct {
 	NodeId    []byte
 	TrackedAt time.Time
@@ -5340,6 +5697,55 @@ func (f ProjectBandwidthRollup_EgressAllocated_Field) value() interface{} {
 
 func (ProjectBandwidthRollup_EgressAllocated_Field) _Column() string { return "egress_allocated" }
 
+type ProjectLiveTotal struct {
+	ProjectId []byte
+	Total     int64
+}
+
+func (ProjectLiveTotal) _Table() string { return "project_live_totals" }
+
+type ProjectLiveTotal_Update_Fields struct {
+	Total ProjectLiveTotal_Total_Field
+}
+
+type ProjectLiveTotal_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ProjectLiveTotal_ProjectId(v []byte) ProjectLiveTotal_ProjectId_Field {
+	return ProjectLiveTotal_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f ProjectLiveTotal_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ProjectLiveTotal_ProjectId_Field) _Column() string { return "project_id" }
+
+type ProjectLiveTotal_Total_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func ProjectLiveTotal_Total(v int64) ProjectLiveTotal_Total_Field {
+	return ProjectLiveTotal_Total_Field{_set: true, _value: v}
+}
+
+func (f ProjectLiveTotal_Total_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ProjectLiveTotal_Total_Field) _Column() string { return "total" }
+
 type RegistrationToken struct {
 	Secret       []byte
 	OwnerId      []byte
@@ -5663,59 +6069,155 @@ func (f ResetPasswordToken_CreatedAt_Field) value() interface{} {
 
 func (ResetPasswordToken_CreatedAt_Field) _Column() string { return "created_at" }
 
-type SerialNumber struct {
-	Id           int
-	SerialNumber []byte
-	BucketId     []byte
-	ExpiresAt    time.Time
+type SegmentReference struct {
+	RootPieceId []byte
+	Path        []byte
 }
 
-func (SerialNumber) _Table() string { return "serial_numbers" }
+func (SegmentReference) _Table() string { return "segment_references" }
 
-type SerialNumber_Update_Fields struct {
+type SegmentReference_Update_Fields struct {
 }
 
-type SerialNumber_Id_Field struct {
+type SegmentReference_RootPieceId_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value []byte
 }
 
-func SerialNumber_Id(v int) SerialNumber_Id_Field {
-	return SerialNumber_Id_Field{_set: true, _value: v}
+func SegmentReference_RootPieceId(v []byte) SegmentReference_RootPieceId_Field {
+	return SegmentReference_RootPieceId_Field{_set: true, _value: v}
 }
 
-func (f SerialNumber_Id_Field) value() interface{} {
+func (f SegmentReference_RootPieceId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (SerialNumber_Id_Field) _Column() string { return "id" }
+func (SegmentReference_RootPieceId_Field) _Column() string { return "root_piece_id" }
 
-type SerialNumber_SerialNumber_Field struct {
+type SegmentReference_Path_Field struct {
 	_set   bool
 	_null  bool
 	_value []byte
 }
 
-func SerialNumber_SerialNumber(v []byte) SerialNumber_SerialNumber_Field {
-	return SerialNumber_SerialNumber_Field{_set: true, _value: v}
+func SegmentReference_Path(v []byte) SegmentReference_Path_Field {
+	return SegmentReference_Path_Field{_set: true, _value: v}
 }
 
-func (f SerialNumber_SerialNumber_Field) value() interface{} {
+func (f SegmentReference_Path_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (SerialNumber_SerialNumber_Field) _Column() string { return "serial_number" }
+func (SegmentReference_Path_Field) _Column() string { return "path" }
 
-type SerialNumber_BucketId_Field struct {
-	_set   bool
-	_null  bool
+type SegmentRelocation struct {
+	Path        []byte
+	RelocatedAt time.Time
+}
+
+func (SegmentRelocation) _Table() string { return "segment_relocations" }
+
+type SegmentRelocation_Update_Fields struct {
+}
+
+type SegmentRelocation_Path_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func SegmentRelocation_Path(v []byte) SegmentRelocation_Path_Field {
+	return SegmentRelocation_Path_Field{_set: true, _value: v}
+}
+
+func (f SegmentRelocation_Path_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SegmentRelocation_Path_Field) _Column() string { return "path" }
+
+type SegmentRelocation_RelocatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func SegmentRelocation_RelocatedAt(v time.Time) SegmentRelocation_RelocatedAt_Field {
+	return SegmentRelocation_RelocatedAt_Field{_set: true, _value: v}
+}
+
+func (f SegmentRelocation_RelocatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SegmentRelocation_RelocatedAt_Field) _Column() string { return "relocated_at" }
+
+type SerialNumber struct {
+	Id           int
+	SerialNumber []byte
+	BucketId     []byte
+	ExpiresAt    time.Time
+}
+
+func (SerialNumber) _Table() string { return "serial_numbers" }
+
+type SerialNumber_Update_Fields struct {
+}
+
+type SerialNumber_Id_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func SerialNumber_Id(v int) SerialNumber_Id_Field {
+	return SerialNumber_Id_Field{_set: true, _value: v}
+}
+
+func (f SerialNumber_Id_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SerialNumber_Id_Field) _Column() string { return "id" }
+
+type SerialNumber_SerialNumber_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func SerialNumber_SerialNumber(v []byte) SerialNumber_SerialNumber_Field {
+	return SerialNumber_SerialNumber_Field{_set: true, _value: v}
+}
+
+func (f SerialNumber_SerialNumber_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SerialNumber_SerialNumber_Field) _Column() string { return "serial_number" }
+
+type SerialNumber_BucketId_Field struct {
+	_set   bool
+	_null  bool
 	_value []byte
 }
 
@@ -7227,16 +7729,19 @@ type ApiKey struct {
 	Secret    []byte
 	PartnerId []byte
 	CreatedAt time.Time
+	RateLimit *int
 }
 
 func (ApiKey) _Table() string { return "api_keys" }
 
 type ApiKey_Create_Fields struct {
 	PartnerId ApiKey_PartnerId_Field
+	RateLimit ApiKey_RateLimit_Field
 }
 
 type ApiKey_Update_Fields struct {
-	Name ApiKey_Name_Field
+	Name      ApiKey_Name_Field
+	RateLimit ApiKey_RateLimit_Field
 }
 
 type ApiKey_Id_Field struct {
@@ -7385,6 +7890,38 @@ func (f ApiKey_CreatedAt_Field) value() interface{} {
 
 func (ApiKey_CreatedAt_Field) _Column() string { return "created_at" }
 
+type ApiKey_RateLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int
+}
+
+func ApiKey_RateLimit(v int) ApiKey_RateLimit_Field {
+	return ApiKey_RateLimit_Field{_set: true, _value: &v}
+}
+
+func ApiKey_RateLimit_Raw(v *int) ApiKey_RateLimit_Field {
+	if v == nil {
+		return ApiKey_RateLimit_Null()
+	}
+	return ApiKey_RateLimit(*v)
+}
+
+func ApiKey_RateLimit_Null() ApiKey_RateLimit_Field {
+	return ApiKey_RateLimit_Field{_set: true, _null: true}
+}
+
+func (f ApiKey_RateLimit_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f ApiKey_RateLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_RateLimit_Field) _Column() string { return "rate_limit" }
+
 type BucketMetainfo struct {
 	Id                              []byte
 	ProjectId                       []byte
@@ -7401,12 +7938,28 @@ type BucketMetainfo struct {
 	DefaultRedundancyRepairShares   int
 	DefaultRedundancyOptimalShares  int
 	DefaultRedundancyTotalShares    int
+	Placement                       *string
+	StorageLimit                    *int64
+	BandwidthLimit                  *int64
+	ObjectLimit                     *int64
+	LifecycleRules                  []byte
+	RsProfile                       *string
+	Versioning                      *bool
+	DefaultRetentionDays            *int
 }
 
 func (BucketMetainfo) _Table() string { return "bucket_metainfos" }
 
 type BucketMetainfo_Create_Fields struct {
-	PartnerId BucketMetainfo_PartnerId_Field
+	PartnerId            BucketMetainfo_PartnerId_Field
+	Placement            BucketMetainfo_Placement_Field
+	StorageLimit         BucketMetainfo_StorageLimit_Field
+	BandwidthLimit       BucketMetainfo_BandwidthLimit_Field
+	ObjectLimit          BucketMetainfo_ObjectLimit_Field
+	LifecycleRules       BucketMetainfo_LifecycleRules_Field
+	RsProfile            BucketMetainfo_RsProfile_Field
+	Versioning           BucketMetainfo_Versioning_Field
+	DefaultRetentionDays BucketMetainfo_DefaultRetentionDays_Field
 }
 
 type BucketMetainfo_Update_Fields struct {
@@ -7420,6 +7973,14 @@ type BucketMetainfo_Update_Fields struct {
 	DefaultRedundancyRepairShares   BucketMetainfo_DefaultRedundancyRepairShares_Field
 	DefaultRedundancyOptimalShares  BucketMetainfo_DefaultRedundancyOptimalShares_Field
 	DefaultRedundancyTotalShares    BucketMetainfo_DefaultRedundancyTotalShares_Field
+	Placement                       BucketMetainfo_Placement_Field
+	StorageLimit                    BucketMetainfo_StorageLimit_Field
+	BandwidthLimit                  BucketMetainfo_BandwidthLimit_Field
+	ObjectLimit                     BucketMetainfo_ObjectLimit_Field
+	LifecycleRules                  BucketMetainfo_LifecycleRules_Field
+	RsProfile                       BucketMetainfo_RsProfile_Field
+	Versioning                      BucketMetainfo_Versioning_Field
+	DefaultRetentionDays            BucketMetainfo_DefaultRetentionDays_Field
 }
 
 type BucketMetainfo_Id_Field struct {
@@ -7637,104 +8198,368 @@ type BucketMetainfo_DefaultRedundancyShareSize_Field struct {
 	_value int
 }
 
-func BucketMetainfo_DefaultRedundancyShareSize(v int) BucketMetainfo_DefaultRedundancyShareSize_Field {
-	return BucketMetainfo_DefaultRedundancyShareSize_Field{_set: true, _value: v}
+func BucketMetainfo_DefaultRedundancyShareSize(v int) BucketMetainfo_DefaultRedundancyShareSize_Field {
+	return BucketMetainfo_DefaultRedundancyShareSize_Field{_set: true, _value: v}
+}
+
+func (f BucketMetainfo_DefaultRedundancyShareSize_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_DefaultRedundancyShareSize_Field) _Column() string {
+	return "default_redundancy_share_size"
+}
+
+type BucketMetainfo_DefaultRedundancyRequiredShares_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func BucketMetainfo_DefaultRedundancyRequiredShares(v int) BucketMetainfo_DefaultRedundancyRequiredShares_Field {
+	return BucketMetainfo_DefaultRedundancyRequiredShares_Field{_set: true, _value: v}
+}
+
+func (f BucketMetainfo_DefaultRedundancyRequiredShares_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_DefaultRedundancyRequiredShares_Field) _Column() string {
+	return "default_redundancy_required_shares"
+}
+
+type BucketMetainfo_DefaultRedundancyRepairShares_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func BucketMetainfo_DefaultRedundancyRepairShares(v int) BucketMetainfo_DefaultRedundancyRepairShares_Field {
+	return BucketMetainfo_DefaultRedundancyRepairShares_Field{_set: true, _value: v}
+}
+
+func (f BucketMetainfo_DefaultRedundancyRepairShares_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_DefaultRedundancyRepairShares_Field) _Column() string {
+	return "default_redundancy_repair_shares"
+}
+
+type BucketMetainfo_DefaultRedundancyOptimalShares_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func BucketMetainfo_DefaultRedundancyOptimalShares(v int) BucketMetainfo_DefaultRedundancyOptimalShares_Field {
+	return BucketMetainfo_DefaultRedundancyOptimalShares_Field{_set: true, _value: v}
+}
+
+func (f BucketMetainfo_DefaultRedundancyOptimalShares_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_DefaultRedundancyOptimalShares_Field) _Column() string {
+	return "default_redundancy_optimal_shares"
+}
+
+type BucketMetainfo_DefaultRedundancyTotalShares_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func BucketMetainfo_DefaultRedundancyTotalShares(v int) BucketMetainfo_DefaultRedundancyTotalShares_Field {
+	return BucketMetainfo_DefaultRedundancyTotalShares_Field{_set: true, _value: v}
+}
+
+func (f BucketMetainfo_DefaultRedundancyTotalShares_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_DefaultRedundancyTotalShares_Field) _Column() string {
+	return "default_redundancy_total_shares"
+}
+
+type BucketMetainfo_Placement_Field struct {
+	_set   bool
+	_null  bool
+	_value *string
+}
+
+func BucketMetainfo_Placement(v string) BucketMetainfo_Placement_Field {
+	return BucketMetainfo_Placement_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_Placement_Raw(v *string) BucketMetainfo_Placement_Field {
+	if v == nil {
+		return BucketMetainfo_Placement_Null()
+	}
+	return BucketMetainfo_Placement(*v)
+}
+
+func BucketMetainfo_Placement_Null() BucketMetainfo_Placement_Field {
+	return BucketMetainfo_Placement_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_Placement_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_Placement_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_Placement_Field) _Column() string { return "placement" }
+
+type BucketMetainfo_StorageLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int64
+}
+
+func BucketMetainfo_StorageLimit(v int64) BucketMetainfo_StorageLimit_Field {
+	return BucketMetainfo_StorageLimit_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_StorageLimit_Raw(v *int64) BucketMetainfo_StorageLimit_Field {
+	if v == nil {
+		return BucketMetainfo_StorageLimit_Null()
+	}
+	return BucketMetainfo_StorageLimit(*v)
+}
+
+func BucketMetainfo_StorageLimit_Null() BucketMetainfo_StorageLimit_Field {
+	return BucketMetainfo_StorageLimit_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_StorageLimit_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f BucketMetainfo_StorageLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_StorageLimit_Field) _Column() string { return "storage_limit" }
+
+type BucketMetainfo_BandwidthLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int64
+}
+
+func BucketMetainfo_BandwidthLimit(v int64) BucketMetainfo_BandwidthLimit_Field {
+	return BucketMetainfo_BandwidthLimit_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_BandwidthLimit_Raw(v *int64) BucketMetainfo_BandwidthLimit_Field {
+	if v == nil {
+		return BucketMetainfo_BandwidthLimit_Null()
+	}
+	return BucketMetainfo_BandwidthLimit(*v)
+}
+
+func BucketMetainfo_BandwidthLimit_Null() BucketMetainfo_BandwidthLimit_Field {
+	return BucketMetainfo_BandwidthLimit_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_BandwidthLimit_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f BucketMetainfo_BandwidthLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_BandwidthLimit_Field) _Column() string { return "bandwidth_limit" }
+
+type BucketMetainfo_ObjectLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int64
+}
+
+func BucketMetainfo_ObjectLimit(v int64) BucketMetainfo_ObjectLimit_Field {
+	return BucketMetainfo_ObjectLimit_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_ObjectLimit_Raw(v *int64) BucketMetainfo_ObjectLimit_Field {
+	if v == nil {
+		return BucketMetainfo_ObjectLimit_Null()
+	}
+	return BucketMetainfo_ObjectLimit(*v)
+}
+
+func BucketMetainfo_ObjectLimit_Null() BucketMetainfo_ObjectLimit_Field {
+	return BucketMetainfo_ObjectLimit_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_ObjectLimit_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_ObjectLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_ObjectLimit_Field) _Column() string { return "object_limit" }
+
+type BucketMetainfo_LifecycleRules_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketMetainfo_LifecycleRules(v []byte) BucketMetainfo_LifecycleRules_Field {
+	return BucketMetainfo_LifecycleRules_Field{_set: true, _value: v}
+}
+
+func BucketMetainfo_LifecycleRules_Raw(v []byte) BucketMetainfo_LifecycleRules_Field {
+	if v == nil {
+		return BucketMetainfo_LifecycleRules_Null()
+	}
+	return BucketMetainfo_LifecycleRules(v)
+}
+
+func BucketMetainfo_LifecycleRules_Null() BucketMetainfo_LifecycleRules_Field {
+	return BucketMetainfo_LifecycleRules_Field{_set: true, _null: true}
 }
 
-func (f BucketMetainfo_DefaultRedundancyShareSize_Field) value() interface{} {
+func (f BucketMetainfo_LifecycleRules_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f BucketMetainfo_LifecycleRules_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (BucketMetainfo_DefaultRedundancyShareSize_Field) _Column() string {
-	return "default_redundancy_share_size"
-}
+func (BucketMetainfo_LifecycleRules_Field) _Column() string { return "lifecycle_rules" }
 
-type BucketMetainfo_DefaultRedundancyRequiredShares_Field struct {
+type BucketMetainfo_RsProfile_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value *string
 }
 
-func BucketMetainfo_DefaultRedundancyRequiredShares(v int) BucketMetainfo_DefaultRedundancyRequiredShares_Field {
-	return BucketMetainfo_DefaultRedundancyRequiredShares_Field{_set: true, _value: v}
+func BucketMetainfo_RsProfile(v string) BucketMetainfo_RsProfile_Field {
+	return BucketMetainfo_RsProfile_Field{_set: true, _value: &v}
 }
 
-func (f BucketMetainfo_DefaultRedundancyRequiredShares_Field) value() interface{} {
+func BucketMetainfo_RsProfile_Raw(v *string) BucketMetainfo_RsProfile_Field {
+	if v == nil {
+		return BucketMetainfo_RsProfile_Null()
+	}
+	return BucketMetainfo_RsProfile(*v)
+}
+
+func BucketMetainfo_RsProfile_Null() BucketMetainfo_RsProfile_Field {
+	return BucketMetainfo_RsProfile_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_RsProfile_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_RsProfile_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (BucketMetainfo_DefaultRedundancyRequiredShares_Field) _Column() string {
-	return "default_redundancy_required_shares"
-}
+func (BucketMetainfo_RsProfile_Field) _Column() string { return "rs_profile" }
 
-type BucketMetainfo_DefaultRedundancyRepairShares_Field struct {
+type BucketMetainfo_Versioning_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value *bool
 }
 
-func BucketMetainfo_DefaultRedundancyRepairShares(v int) BucketMetainfo_DefaultRedundancyRepairShares_Field {
-	return BucketMetainfo_DefaultRedundancyRepairShares_Field{_set: true, _value: v}
+func BucketMetainfo_Versioning(v bool) BucketMetainfo_Versioning_Field {
+	return BucketMetainfo_Versioning_Field{_set: true, _value: &v}
 }
 
-func (f BucketMetainfo_DefaultRedundancyRepairShares_Field) value() interface{} {
+func BucketMetainfo_Versioning_Raw(v *bool) BucketMetainfo_Versioning_Field {
+	if v == nil {
+		return BucketMetainfo_Versioning_Null()
+	}
+	return BucketMetainfo_Versioning(*v)
+}
+
+func BucketMetainfo_Versioning_Null() BucketMetainfo_Versioning_Field {
+	return BucketMetainfo_Versioning_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_Versioning_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_Versioning_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (BucketMetainfo_DefaultRedundancyRepairShares_Field) _Column() string {
-	return "default_redundancy_repair_shares"
-}
+func (BucketMetainfo_Versioning_Field) _Column() string { return "versioning" }
 
-type BucketMetainfo_DefaultRedundancyOptimalShares_Field struct {
+type BucketMetainfo_DefaultRetentionDays_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value *int
 }
 
-func BucketMetainfo_DefaultRedundancyOptimalShares(v int) BucketMetainfo_DefaultRedundancyOptimalShares_Field {
-	return BucketMetainfo_DefaultRedundancyOptimalShares_Field{_set: true, _value: v}
+func BucketMetainfo_DefaultRetentionDays(v int) BucketMetainfo_DefaultRetentionDays_Field {
+	return BucketMetainfo_DefaultRetentionDays_Field{_set: true, _value: &v}
 }
 
-func (f BucketMetainfo_DefaultRedundancyOptimalShares_Field) value() interface{} {
-	if !f._set || f._null {
-		return nil
+func BucketMetainfo_DefaultRetentionDays_Raw(v *int) BucketMetainfo_DefaultRetentionDays_Field {
+	if v == nil {
+		return BucketMetainfo_DefaultRetentionDays_Null()
 	}
-	return f._value
-}
-
-func (BucketMetainfo_DefaultRedundancyOptimalShares_Field) _Column() string {
-	return "default_redundancy_optimal_shares"
+	return BucketMetainfo_DefaultRetentionDays(*v)
 }
 
-type BucketMetainfo_DefaultRedundancyTotalShares_Field struct {
-	_set   bool
-	_null  bool
-	_value int
+func BucketMetainfo_DefaultRetentionDays_Null() BucketMetainfo_DefaultRetentionDays_Field {
+	return BucketMetainfo_DefaultRetentionDays_Field{_set: true, _null: true}
 }
 
-func BucketMetainfo_DefaultRedundancyTotalShares(v int) BucketMetainfo_DefaultRedundancyTotalShares_Field {
-	return BucketMetainfo_DefaultRedundancyTotalShares_Field{_set: true, _value: v}
+func (f BucketMetainfo_DefaultRetentionDays_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
 }
 
-func (f BucketMetainfo_DefaultRedundancyTotalShares_Field) value() interface{} {
+func (f BucketMetainfo_DefaultRetentionDays_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (BucketMetainfo_DefaultRedundancyTotalShares_Field) _Column() string {
-	return "default_redundancy_total_shares"
-}
+func (BucketMetainfo_DefaultRetentionDays_Field) _Column() string { return "default_retention_days" }
 
 type ProjectInvoiceStamp struct {
 	ProjectId []byte
@@ -8238,6 +9063,55 @@ func (f UserCredit_CreatedAt_Field) value() interface{} {
 
 func (UserCredit_CreatedAt_Field) _Column() string { return "created_at" }
 
+type ApiKeyRequestCount struct {
+	ApiKeyId []byte
+	Count    int64
+}
+
+func (ApiKeyRequestCount) _Table() string { return "api_key_request_counts" }
+
+type ApiKeyRequestCount_Update_Fields struct {
+	Count ApiKeyRequestCount_Count_Field
+}
+
+type ApiKeyRequestCount_ApiKeyId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKeyRequestCount_ApiKeyId(v []byte) ApiKeyRequestCount_ApiKeyId_Field {
+	return ApiKeyRequestCount_ApiKeyId_Field{_set: true, _value: v}
+}
+
+func (f ApiKeyRequestCount_ApiKeyId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKeyRequestCount_ApiKeyId_Field) _Column() string { return "api_key_id" }
+
+type ApiKeyRequestCount_Count_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func ApiKeyRequestCount_Count(v int64) ApiKeyRequestCount_Count_Field {
+	return ApiKeyRequestCount_Count_Field{_set: true, _value: v}
+}
+
+func (f ApiKeyRequestCount_Count_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKeyRequestCount_Count_Field) _Column() string { return "count" }
+
 func toUTC(t time.Time) time.Time {
 	return t.UTC()
 }
@@ -9115,17 +9989,18 @@ func (obj *postgresImpl) Create_ApiKey(ctx context.Context,
 	__secret_val := api_key_secret.value()
 	__partner_id_val := optional.PartnerId.value()
 	__created_at_val := __now
+	__rate_limit_val := optional.RateLimit.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO api_keys ( id, project_id, head, name, secret, partner_id, created_at ) VALUES ( ?, ?, ?, ?, ?, ?, ? ) RETURNING api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO api_keys ( id, project_id, head, name, secret, partner_id, created_at, rate_limit ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __head_val, __name_val, __secret_val, __partner_id_val, __created_at_val)
+	__values = append(__values, __id_val, __project_id_val, __head_val, __name_val, __secret_val, __partner_id_val, __created_at_val, __rate_limit_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -9597,17 +10472,25 @@ func (obj *postgresImpl) Create_BucketMetainfo(ctx context.Context,
 	__default_redundancy_repair_shares_val := bucket_metainfo_default_redundancy_repair_shares.value()
 	__default_redundancy_optimal_shares_val := bucket_metainfo_default_redundancy_optimal_shares.value()
 	__default_redundancy_total_shares_val := bucket_metainfo_default_redundancy_total_shares.value()
+	__placement_val := optional.Placement.value()
+	__storage_limit_val := optional.StorageLimit.value()
+	__bandwidth_limit_val := optional.BandwidthLimit.value()
+	__object_limit_val := optional.ObjectLimit.value()
+	__lifecycle_rules_val := optional.LifecycleRules.value()
+	__rs_profile_val := optional.RsProfile.value()
+	__versioning_val := optional.Versioning.value()
+	__default_retention_days_val := optional.DefaultRetentionDays.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares, placement, storage_limit, bandwidth_limit, object_limit, lifecycle_rules, rs_profile, versioning, default_retention_days ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val)
+	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val, __placement_val, __storage_limit_val, __bandwidth_limit_val, __object_limit_val, __lifecycle_rules_val, __rs_profile_val, __versioning_val, __default_retention_days_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -10866,7 +11749,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Id(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_id.value())
@@ -10875,7 +11758,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -10888,7 +11771,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Head(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.head = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.head = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_head.value())
@@ -10897,7 +11780,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Head(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -10911,7 +11794,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Name_And_ProjectId(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.name = ? AND api_keys.project_id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.name = ? AND api_keys.project_id = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_name.value(), api_key_project_id.value())
@@ -10920,7 +11803,7 @@ func (obj *postgresImpl) Get_ApiKey_By_Name_And_ProjectId(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -10933,7 +11816,7 @@ func (obj *postgresImpl) All_ApiKey_By_ProjectId_OrderBy_Asc_Name(ctx context.Co
 	rows []*ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.project_id = ? ORDER BY api_keys.name")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.project_id = ? ORDER BY api_keys.name")
 
 	var __values []interface{}
 	__values = append(__values, api_key_project_id.value())
@@ -10949,7 +11832,7 @@ func (obj *postgresImpl) All_ApiKey_By_ProjectId_OrderBy_Asc_Name(ctx context.Co
 
 	for __rows.Next() {
 		api_key := &ApiKey{}
-		err = __rows.Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+		err = __rows.Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -11620,7 +12503,7 @@ func (obj *postgresImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.Co
 	bucket_metainfo *BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name.value())
@@ -11629,7 +12512,7 @@ func (obj *postgresImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.Co
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err != nil {
 		return (*BucketMetainfo)(nil), obj.makeErr(err)
 	}
@@ -11644,7 +12527,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOrE
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater_or_equal.value())
@@ -11662,7 +12545,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOrE
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -11682,7 +12565,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_Or
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater.value())
@@ -11700,7 +12583,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_Or
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -13111,6 +13994,11 @@ func (obj *postgresImpl) UpdateNoReturn_ApiKey_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("name = ?"))
 	}
 
+	if update.RateLimit._set {
+		__values = append(__values, update.RateLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rate_limit = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return emptyUpdate()
 	}
@@ -13304,7 +14192,7 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -13360,6 +14248,46 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_redundancy_total_shares = ?"))
 	}
 
+	if update.Placement._set {
+		__values = append(__values, update.Placement.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("placement = ?"))
+	}
+
+	if update.StorageLimit._set {
+		__values = append(__values, update.StorageLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("storage_limit = ?"))
+	}
+
+	if update.BandwidthLimit._set {
+		__values = append(__values, update.BandwidthLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("bandwidth_limit = ?"))
+	}
+
+	if update.ObjectLimit._set {
+		__values = append(__values, update.ObjectLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("object_limit = ?"))
+	}
+
+	if update.LifecycleRules._set {
+		__values = append(__values, update.LifecycleRules.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("lifecycle_rules = ?"))
+	}
+
+	if update.RsProfile._set {
+		__values = append(__values, update.RsProfile.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rs_profile = ?"))
+	}
+
+	if update.Versioning._set {
+		__values = append(__values, update.Versioning.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("versioning = ?"))
+	}
+
+	if update.DefaultRetentionDays._set {
+		__values = append(__values, update.DefaultRetentionDays.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_retention_days = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -13373,7 +14301,7 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -14311,6 +15239,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 	defer mon.Task()(&ctx)(&err)
 	var __res sql.Result
 	var __count int64
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM api_key_request_counts;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
 	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM user_credits;")
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14466,6 +15404,26 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM segment_relocations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM segment_references;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14506,6 +15464,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM project_live_totals;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14576,6 +15544,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM node_tags;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14586,6 +15564,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_ranges;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -15261,17 +16249,18 @@ func (obj *cockroachImpl) Create_ApiKey(ctx context.Context,
 	__secret_val := api_key_secret.value()
 	__partner_id_val := optional.PartnerId.value()
 	__created_at_val := __now
+	__rate_limit_val := optional.RateLimit.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO api_keys ( id, project_id, head, name, secret, partner_id, created_at ) VALUES ( ?, ?, ?, ?, ?, ?, ? ) RETURNING api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO api_keys ( id, project_id, head, name, secret, partner_id, created_at, rate_limit ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __head_val, __name_val, __secret_val, __partner_id_val, __created_at_val)
+	__values = append(__values, __id_val, __project_id_val, __head_val, __name_val, __secret_val, __partner_id_val, __created_at_val, __rate_limit_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -15743,17 +16732,25 @@ func (obj *cockroachImpl) Create_BucketMetainfo(ctx context.Context,
 	__default_redundancy_repair_shares_val := bucket_metainfo_default_redundancy_repair_shares.value()
 	__default_redundancy_optimal_shares_val := bucket_metainfo_default_redundancy_optimal_shares.value()
 	__default_redundancy_total_shares_val := bucket_metainfo_default_redundancy_total_shares.value()
+	__placement_val := optional.Placement.value()
+	__storage_limit_val := optional.StorageLimit.value()
+	__bandwidth_limit_val := optional.BandwidthLimit.value()
+	__object_limit_val := optional.ObjectLimit.value()
+	__lifecycle_rules_val := optional.LifecycleRules.value()
+	__rs_profile_val := optional.RsProfile.value()
+	__versioning_val := optional.Versioning.value()
+	__default_retention_days_val := optional.DefaultRetentionDays.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares, placement, storage_limit, bandwidth_limit, object_limit, lifecycle_rules, rs_profile, versioning, default_retention_days ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val)
+	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val, __placement_val, __storage_limit_val, __bandwidth_limit_val, __object_limit_val, __lifecycle_rules_val, __rs_profile_val, __versioning_val, __default_retention_days_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -17012,7 +18009,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Id(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_id.value())
@@ -17021,7 +18018,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -17034,7 +18031,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Head(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.head = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.head = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_head.value())
@@ -17043,7 +18040,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Head(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -17057,7 +18054,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Name_And_ProjectId(ctx context.Context,
 	api_key *ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.name = ? AND api_keys.project_id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.name = ? AND api_keys.project_id = ?")
 
 	var __values []interface{}
 	__values = append(__values, api_key_name.value(), api_key_project_id.value())
@@ -17066,7 +18063,7 @@ func (obj *cockroachImpl) Get_ApiKey_By_Name_And_ProjectId(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	api_key = &ApiKey{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 	if err != nil {
 		return (*ApiKey)(nil), obj.makeErr(err)
 	}
@@ -17079,7 +18076,7 @@ func (obj *cockroachImpl) All_ApiKey_By_ProjectId_OrderBy_Asc_Name(ctx context.C
 	rows []*ApiKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at FROM api_keys WHERE api_keys.project_id = ? ORDER BY api_keys.name")
+	var __embed_stmt = __sqlbundle_Literal("SELECT api_keys.id, api_keys.project_id, api_keys.head, api_keys.name, api_keys.secret, api_keys.partner_id, api_keys.created_at, api_keys.rate_limit FROM api_keys WHERE api_keys.project_id = ? ORDER BY api_keys.name")
 
 	var __values []interface{}
 	__values = append(__values, api_key_project_id.value())
@@ -17095,7 +18092,7 @@ func (obj *cockroachImpl) All_ApiKey_By_ProjectId_OrderBy_Asc_Name(ctx context.C
 
 	for __rows.Next() {
 		api_key := &ApiKey{}
-		err = __rows.Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt)
+		err = __rows.Scan(&api_key.Id, &api_key.ProjectId, &api_key.Head, &api_key.Name, &api_key.Secret, &api_key.PartnerId, &api_key.CreatedAt, &api_key.RateLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -17766,7 +18763,7 @@ func (obj *cockroachImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.C
 	bucket_metainfo *BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name.value())
@@ -17775,7 +18772,7 @@ func (obj *cockroachImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.C
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err != nil {
 		return (*BucketMetainfo)(nil), obj.makeErr(err)
 	}
@@ -17790,7 +18787,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOr
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater_or_equal.value())
@@ -17808,7 +18805,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOr
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -17828,7 +18825,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_O
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater.value())
@@ -17846,7 +18843,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_O
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -19257,6 +20254,11 @@ func (obj *cockroachImpl) UpdateNoReturn_ApiKey_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("name = ?"))
 	}
 
+	if update.RateLimit._set {
+		__values = append(__values, update.RateLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rate_limit = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return emptyUpdate()
 	}
@@ -19450,7 +20452,7 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit, bucket_metainfos.object_limit, bucket_metainfos.lifecycle_rules, bucket_metainfos.rs_profile, bucket_metainfos.versioning, bucket_metainfos.default_retention_days")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -19506,6 +20508,46 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_redundancy_total_shares = ?"))
 	}
 
+	if update.Placement._set {
+		__values = append(__values, update.Placement.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("placement = ?"))
+	}
+
+	if update.StorageLimit._set {
+		__values = append(__values, update.StorageLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("storage_limit = ?"))
+	}
+
+	if update.BandwidthLimit._set {
+		__values = append(__values, update.BandwidthLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("bandwidth_limit = ?"))
+	}
+
+	if update.ObjectLimit._set {
+		__values = append(__values, update.ObjectLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("object_limit = ?"))
+	}
+
+	if update.LifecycleRules._set {
+		__values = append(__values, update.LifecycleRules.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("lifecycle_rules = ?"))
+	}
+
+	if update.RsProfile._set {
+		__values = append(__values, update.RsProfile.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rs_profile = ?"))
+	}
+
+	if update.Versioning._set {
+		__values = append(__values, update.Versioning.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("versioning = ?"))
+	}
+
+	if update.DefaultRetentionDays._set {
+		__values = append(__values, update.DefaultRetentionDays.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_retention_days = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -19519,7 +20561,7 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit, &bucket_metainfo.ObjectLimit, &bucket_metainfo.LifecycleRules, &bucket_metainfo.RsProfile, &bucket_metainfo.Versioning, &bucket_metainfo.DefaultRetentionDays)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -20457,6 +21499,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 	defer mon.Task()(&ctx)(&err)
 	var __res sql.Result
 	var __count int64
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM api_key_request_counts;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
 	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM user_credits;")
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20612,6 +21664,26 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM segment_relocations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM segment_references;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20652,6 +21724,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM project_live_totals;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20722,6 +21804,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM node_tags;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20732,6 +21824,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_ranges;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
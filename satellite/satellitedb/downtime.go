@@ -49,3 +49,19 @@ func (db *downtimeTrackingDB) GetOfflineTime(ctx context.Context, nodeID storj.N
 	duration := time.Duration(totalSeconds) * time.Second
 	return duration, nil
 }
+
+// GetTotalOfflineTime gets the sum of the offline time of all nodes within a certain timeframe,
+// with the same definition of offline time windows as GetOfflineTime.
+func (db *downtimeTrackingDB) GetTotalOfflineTime(ctx context.Context, begin, end time.Time) (_ time.Duration, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var totalSeconds int64
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT COALESCE(SUM(seconds), 0) FROM nodes_offline_times
+		WHERE tracked_at > ? AND tracked_at <= ?
+	`), begin, end).Scan(&totalSeconds)
+	if err != nil {
+		return time.Duration(0), Error.Wrap(err)
+	}
+	return time.Duration(totalSeconds) * time.Second, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+)
+
+var _ metainfo.LoopRangesDB = (*loopRanges)(nil)
+
+// loopRanges implements metainfo.LoopRangesDB.
+type loopRanges struct {
+	db *satelliteDB
+}
+
+// MetainfoLoopRanges returns the database coordinating the metainfo loop ranges between processes.
+func (db *satelliteDB) MetainfoLoopRanges() metainfo.LoopRangesDB {
+	return &loopRanges{db: db}
+}
+
+// Claim leases the range of the observer to owner, unless another owner holds
+// an unexpired lease or the range was finished after passStarted.
+func (ranges *loopRanges) Claim(ctx context.Context, observer string, index int, owner uuid.UUID, passStarted time.Time, lease time.Duration) (claimed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now().UTC()
+
+	var claimedIndex int
+	err = ranges.db.QueryRowContext(ctx, ranges.db.Rebind(`
+		INSERT INTO metainfo_loop_ranges (observer, range_index, owner, lease_expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (observer, range_index) DO UPDATE
+		SET owner = EXCLUDED.owner, lease_expires_at = EXCLUDED.lease_expires_at
+		WHERE (metainfo_loop_ranges.lease_expires_at IS NULL OR metainfo_loop_ranges.lease_expires_at < ?)
+			AND (metainfo_loop_ranges.finished_at IS NULL OR metainfo_loop_ranges.finished_at < ?)
+		RETURNING range_index
+	`), observer, index, owner[:], now.Add(lease), now, passStarted.UTC()).Scan(&claimedIndex)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return true, nil
+}
+
+// Finish marks the range of the observer as finished by owner and releases the lease.
+func (ranges *loopRanges) Finish(ctx context.Context, observer string, index int, owner uuid.UUID, finishedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = ranges.db.ExecContext(ctx, ranges.db.Rebind(`
+		UPDATE metainfo_loop_ranges SET finished_at = ?, lease_expires_at = NULL
+		WHERE observer = ? AND range_index = ? AND owner = ?
+	`), finishedAt.UTC(), observer, index, owner[:])
+	return Error.Wrap(err)
+}
+
+// Release releases the lease of owner without marking the range of the observer as finished.
+func (ranges *loopRanges) Release(ctx context.Context, observer string, index int, owner uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = ranges.db.ExecContext(ctx, ranges.db.Rebind(`
+		UPDATE metainfo_loop_ranges SET lease_expires_at = NULL
+		WHERE observer = ? AND range_index = ? AND owner = ?
+	`), observer, index, owner[:])
+	return Error.Wrap(err)
+}
@@ -1143,6 +1143,166 @@ func (db *satelliteDB) PostgresMigration() *migrate.Migration {
 						AND period < '2020-03'
 				`},
 			},
+			{
+				DB:          db.DB,
+				Description: "add placement column to bucket_metainfos",
+				Version:     113,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN placement text;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add segment_health to injuredsegments",
+				Version:     114,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ADD COLUMN segment_health double precision NOT NULL DEFAULT 1;`,
+					`CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add repair_progress to injuredsegments",
+				Version:     115,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ADD COLUMN repair_progress bytea;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add project_live_totals table",
+				Version:     116,
+				Action: migrate.SQL{
+					`CREATE TABLE project_live_totals (
+						project_id bytea NOT NULL,
+						total bigint NOT NULL,
+						PRIMARY KEY ( project_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add limits to bucket_metainfos",
+				Version:     117,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN storage_limit bigint;`,
+					`ALTER TABLE bucket_metainfos ADD COLUMN bandwidth_limit bigint;`,
+					`ALTER TABLE bucket_metainfos ADD COLUMN object_limit bigint;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add rate limits and request counts to api keys",
+				Version:     118,
+				Action: migrate.SQL{
+					`ALTER TABLE api_keys ADD COLUMN rate_limit integer;`,
+					`CREATE TABLE api_key_request_counts (
+						api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+						count bigint NOT NULL,
+						PRIMARY KEY ( api_key_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add lifecycle rules to bucket_metainfos",
+				Version:     119,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN lifecycle_rules bytea;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add redundancy scheme profile to bucket_metainfos",
+				Version:     120,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN rs_profile text;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add segment_references and segment_relocations tables",
+				Version:     121,
+				Action: migrate.SQL{
+					`CREATE TABLE segment_references (
+						root_piece_id bytea NOT NULL,
+						path bytea NOT NULL,
+						PRIMARY KEY ( root_piece_id, path )
+					);`,
+					`CREATE TABLE segment_relocations (
+						path bytea NOT NULL,
+						relocated_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( path )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add versioning to bucket_metainfos",
+				Version:     122,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN versioning boolean;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add default_retention_days to bucket_metainfos",
+				Version:     123,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN default_retention_days integer;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add metainfo_loop_ranges table",
+				Version:     124,
+				Action: migrate.SQL{
+					`CREATE TABLE metainfo_loop_ranges (
+						range_index integer NOT NULL,
+						owner bytea,
+						lease_expires_at timestamp with time zone,
+						finished_at timestamp with time zone,
+						PRIMARY KEY ( range_index )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "rank unscored injuredsegments last in the repair queue",
+				Version:     125,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ALTER COLUMN segment_health SET DEFAULT 1000000;`,
+					`UPDATE injuredsegments SET segment_health = 1000000 WHERE segment_health = 1;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "claim the metainfo loop ranges separately for every observer",
+				Version:     126,
+				Action: migrate.SQL{
+					`DROP TABLE metainfo_loop_ranges;`,
+					`CREATE TABLE metainfo_loop_ranges (
+						observer text NOT NULL,
+						range_index integer NOT NULL,
+						owner bytea,
+						lease_expires_at timestamp with time zone,
+						finished_at timestamp with time zone,
+						PRIMARY KEY ( observer, range_index )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "add node_tags table",
+				Version:     127,
+				Action: migrate.SQL{
+					`CREATE TABLE node_tags (
+						node_id bytea NOT NULL,
+						tags text NOT NULL,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
 		},
 	}
 }
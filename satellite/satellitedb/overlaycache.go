@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -38,8 +39,9 @@ func (cache *overlaycache) SelectAllStorageNodesUpload(ctx context.Context, sele
 	defer mon.Task()(&ctx)(&err)
 
 	query := `
-		SELECT id, address, last_net, last_ip_port, (total_audit_count < $1 OR total_uptime_count < $2) as isnew
+		SELECT id, address, last_net, last_ip_port, wallet, email, node_tags.tags, free_disk, latency_90, (total_audit_count < $1 OR total_uptime_count < $2) as isnew
 			FROM nodes
+			LEFT JOIN node_tags ON node_tags.node_id = nodes.id
 			WHERE disqualified IS NULL
 			AND suspended IS NULL
 			AND exit_initiated_at IS NULL
@@ -80,15 +82,18 @@ func (cache *overlaycache) SelectAllStorageNodesUpload(ctx context.Context, sele
 	for rows.Next() {
 		var node overlay.SelectedNode
 		node.Address = &pb.NodeAddress{}
-		var lastIPPort sql.NullString
+		var lastIPPort, tags sql.NullString
+		var wallet, email string
 		var isnew bool
-		err = rows.Scan(&node.ID, &node.Address.Address, &node.LastNet, &lastIPPort, &isnew)
+		err = rows.Scan(&node.ID, &node.Address.Address, &node.LastNet, &lastIPPort, &wallet, &email, &tags, &node.FreeDisk, &node.Latency90, &isnew)
 		if err != nil {
 			return nil, nil, err
 		}
 		if lastIPPort.Valid {
 			node.LastIPPort = lastIPPort.String
 		}
+		node.Operator = nodeOperator(wallet, email)
+		node.Tags = decodeNodeTags(tags)
 
 		if isnew {
 			newNodes = append(newNodes, &node)
@@ -151,8 +156,9 @@ func (cache *overlaycache) GetOnlineNodesForGetDelete(ctx context.Context, nodeI
 
 	var rows *sql.Rows
 	rows, err = cache.db.Query(ctx, cache.db.Rebind(`
-		SELECT last_net, id, address, last_ip_port
+		SELECT last_net, id, address, last_ip_port, node_tags.tags
 		FROM nodes
+		LEFT JOIN node_tags ON node_tags.node_id = nodes.id
 		WHERE id = any($1::bytea[])
 			AND disqualified IS NULL
 			AND last_contact_success > $2
@@ -167,14 +173,15 @@ func (cache *overlaycache) GetOnlineNodesForGetDelete(ctx context.Context, nodeI
 		var node overlay.SelectedNode
 		node.Address = &pb.NodeAddress{Transport: pb.NodeTransport_TCP_TLS_GRPC}
 
-		var lastIPPort sql.NullString
-		err = rows.Scan(&node.LastNet, &node.ID, &node.Address.Address, &lastIPPort)
+		var lastIPPort, tags sql.NullString
+		err = rows.Scan(&node.LastNet, &node.ID, &node.Address.Address, &lastIPPort, &tags)
 		if err != nil {
 			return nil, err
 		}
 		if lastIPPort.Valid {
 			node.LastIPPort = lastIPPort.String
 		}
+		node.Tags = decodeNodeTags(tags)
 
 		nodes[node.ID] = &node
 	}
@@ -322,6 +329,133 @@ func (cache *overlaycache) Reliable(ctx context.Context, criteria *overlay.NodeC
 	return nodes, Error.Wrap(rows.Err())
 }
 
+// ReliableNodes returns the network and operator information of all reliable nodes.
+func (cache *overlaycache) ReliableNodes(ctx context.Context, criteria *overlay.NodeCriteria) (nodes []*overlay.SelectedNode, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT id, address, last_net, last_ip_port, wallet, email FROM nodes
+		WHERE disqualified IS NULL
+		AND suspended IS NULL
+		AND exit_finished_at IS NULL
+		AND last_contact_success > ?
+	`), time.Now().Add(-criteria.OnlineWindow))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var node overlay.SelectedNode
+		node.Address = &pb.NodeAddress{}
+		var lastIPPort sql.NullString
+		var wallet, email string
+		err = rows.Scan(&node.ID, &node.Address.Address, &node.LastNet, &lastIPPort, &wallet, &email)
+		if err != nil {
+			return nil, err
+		}
+		if lastIPPort.Valid {
+			node.LastIPPort = lastIPPort.String
+		}
+		node.Operator = nodeOperator(wallet, email)
+		nodes = append(nodes, &node)
+	}
+	return nodes, Error.Wrap(rows.Err())
+}
+
+// ReliableAtRisk returns the reliable nodes which are exiting or not yet vetted.
+func (cache *overlaycache) ReliableAtRisk(ctx context.Context, criteria *overlay.NodeCriteria) (exiting, unvetted storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT id, exit_initiated_at IS NOT NULL, (total_audit_count < ? OR total_uptime_count < ?)
+		FROM nodes
+		WHERE disqualified IS NULL
+		AND suspended IS NULL
+		AND exit_finished_at IS NULL
+		AND last_contact_success > ?
+		AND (exit_initiated_at IS NOT NULL OR total_audit_count < ? OR total_uptime_count < ?)
+	`), criteria.AuditCount, criteria.UptimeCount, time.Now().Add(-criteria.OnlineWindow), criteria.AuditCount, criteria.UptimeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var id storj.NodeID
+		var isExiting, isUnvetted bool
+		err = rows.Scan(&id, &isExiting, &isUnvetted)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isExiting {
+			exiting = append(exiting, id)
+		} else if isUnvetted {
+			unvetted = append(unvetted, id)
+		}
+	}
+	return exiting, unvetted, Error.Wrap(rows.Err())
+}
+
+// AuditStates returns the nodes which are prioritized by audits and the time to vetting of the nodes vetted after vettedSince.
+func (cache *overlaycache) AuditStates(ctx context.Context, vettedSince time.Time) (_ *overlay.AuditStates, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT id, vetted_at, created_at, suspended IS NOT NULL, contained
+		FROM nodes
+		WHERE disqualified IS NULL
+		AND exit_finished_at IS NULL
+		AND (vetted_at IS NULL OR vetted_at > ? OR suspended IS NOT NULL OR contained)
+	`), vettedSince)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	states := &overlay.AuditStates{}
+	for rows.Next() {
+		var id storj.NodeID
+		var vettedAt *time.Time
+		var createdAt time.Time
+		var suspended, contained bool
+		err = rows.Scan(&id, &vettedAt, &createdAt, &suspended, &contained)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		if vettedAt == nil {
+			states.Unvetted = append(states.Unvetted, id)
+		} else if vettedAt.After(vettedSince) {
+			states.TimeToVetting = append(states.TimeToVetting, vettedAt.Sub(createdAt))
+		}
+		if suspended {
+			states.Suspended = append(states.Suspended, id)
+		}
+		if contained {
+			states.Contained = append(states.Contained, id)
+		}
+	}
+	return states, Error.Wrap(rows.Err())
+}
+
+// nodeOperator returns the key which identifies the operator of a node.
+func nodeOperator(wallet, email string) string {
+	if wallet != "" {
+		return "wallet:" + strings.ToLower(wallet)
+	}
+	if email != "" {
+		return "email:" + strings.ToLower(email)
+	}
+	return ""
+}
+
 // BatchUpdateStats updates multiple storagenode's stats in one transaction
 func (cache *overlaycache) BatchUpdateStats(ctx context.Context, updateRequests []*overlay.UpdateRequest, batchSize int) (failed storj.NodeIDList, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1412,3 +1546,29 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 
 	return nil
 }
+
+// UpdateNodeTags replaces the region tags reported by a storagenode, the tags are removed when empty.
+func (cache *overlaycache) UpdateNodeTags(ctx context.Context, nodeID storj.NodeID, tags []string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(tags) == 0 {
+		_, err = cache.db.ExecContext(ctx, cache.db.Rebind(`
+			DELETE FROM node_tags WHERE node_id = ?
+		`), nodeID.Bytes())
+		return Error.Wrap(err)
+	}
+
+	_, err = cache.db.ExecContext(ctx, cache.db.Rebind(`
+		INSERT INTO node_tags (node_id, tags) VALUES (?, ?)
+		ON CONFLICT (node_id) DO UPDATE SET tags = EXCLUDED.tags
+	`), nodeID.Bytes(), strings.Join(tags, ","))
+	return Error.Wrap(err)
+}
+
+// decodeNodeTags decodes the comma-separated tags of the node_tags table.
+func decodeNodeTags(tags sql.NullString) []string {
+	if !tags.Valid || tags.String == "" {
+		return nil
+	}
+	return strings.Split(tags.String, ",")
+}
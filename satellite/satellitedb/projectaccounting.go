@@ -130,6 +130,19 @@ func (db *ProjectAccounting) GetAllocatedBandwidthTotal(ctx context.Context, pro
 	return *sum, err
 }
 
+// GetBucketAllocatedBandwidthTotal returns the sum of GET bandwidth usage allocated for a bucket in the past time frame
+func (db *ProjectAccounting) GetBucketAllocatedBandwidthTotal(ctx context.Context, projectID uuid.UUID, bucketName []byte, from time.Time) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var sum *int64
+	query := `SELECT SUM(allocated) FROM bucket_bandwidth_rollups WHERE project_id = ? AND bucket_name = ? AND action = ? AND interval_start >= ?;`
+	err = db.db.QueryRow(ctx, db.db.Rebind(query), projectID[:], bucketName, pb.PieceAction_GET, from.UTC()).Scan(&sum)
+	if err == sql.ErrNoRows || sum == nil {
+		return 0, nil
+	}
+
+	return *sum, err
+}
+
 // GetProjectAllocatedBandwidth returns allocated bandwidth for the specified year and month.
 func (db *ProjectAccounting) GetProjectAllocatedBandwidth(ctx context.Context, projectID uuid.UUID, year int, month time.Month) (_ int64, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -648,3 +661,59 @@ func (db *ProjectAccounting) getBuckets(ctx context.Context, projectID uuid.UUID
 func timeTruncateDown(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
 }
+
+// SaveLiveTotals replaces the stored totals of the in-process live accounting cache.
+func (db *ProjectAccounting) SaveLiveTotals(ctx context.Context, totals map[uuid.UUID]int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projectIDs := make([][]byte, 0, len(totals))
+	values := make([]int64, 0, len(totals))
+	for projectID, total := range totals {
+		projectID := projectID
+		projectIDs = append(projectIDs, projectID[:])
+		values = append(values, total)
+	}
+
+	return Error.Wrap(db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		_, err := tx.Tx.ExecContext(ctx, `DELETE FROM project_live_totals`)
+		if err != nil {
+			return err
+		}
+		if len(projectIDs) == 0 {
+			return nil
+		}
+
+		_, err = tx.Tx.ExecContext(ctx, `
+			INSERT INTO project_live_totals (project_id, total)
+			SELECT unnest($1::bytea[]), unnest($2::int8[])`,
+			pq.ByteaArray(projectIDs), pq.Array(values))
+		return err
+	}))
+}
+
+// GetLiveTotals returns the stored totals of the in-process live accounting cache.
+func (db *ProjectAccounting) GetLiveTotals(ctx context.Context) (_ map[uuid.UUID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.DB.QueryContext(ctx, `SELECT project_id, total FROM project_live_totals`)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	totals := make(map[uuid.UUID]int64)
+	for rows.Next() {
+		var projectIDBytes []byte
+		var total int64
+		if err := rows.Scan(&projectIDBytes, &total); err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		projectID, err := uuid.FromBytes(projectIDBytes)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		totals[projectID] = total
+	}
+	return totals, Error.Wrap(rows.Err())
+}
@@ -21,12 +21,12 @@ type repairQueue struct {
 	db *satelliteDB
 }
 
-func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, numHealthy int) (alreadyInserted bool, err error) {
+func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, segmentHealth float64) (alreadyInserted bool, err error) {
 	defer mon.Task()(&ctx)(&err)
-	// insert if not exists, or update healthy count if does exist
+	// insert if not exists, or update segment health if does exist
 	var query string
 
-	// we want to insert the segment if it is not in the queue, but update the number of healthy pieces if it already is in the queue
+	// we want to insert the segment if it is not in the queue, but update the segment health if it already is in the queue
 	// we also want to know if the result was an insert or an update - this is the reasoning for the xmax section of the postgres query
 	// and the separate cockroach query (which the xmax trick does not work for)
 	switch r.db.implementation {
@@ -34,29 +34,29 @@ func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, numHea
 		query = `
 			INSERT INTO injuredsegments
 			(
-				path, data, num_healthy_pieces
+				path, data, segment_health
 			)
 			VALUES (
 				$1, $2, $3
 			)
 			ON CONFLICT (path)
 			DO UPDATE
-			SET num_healthy_pieces=$3
+			SET segment_health=$3
 			RETURNING (xmax != 0) AS alreadyInserted
 		`
 	case dbutil.Cockroach:
 		query = `
 			WITH updater AS (
-				UPDATE injuredsegments SET num_healthy_pieces = $3 WHERE path = $1
+				UPDATE injuredsegments SET segment_health = $3 WHERE path = $1
 				RETURNING *
 			)
-			INSERT INTO injuredsegments (path, data, num_healthy_pieces)
+			INSERT INTO injuredsegments (path, data, segment_health)
 			SELECT $1, $2, $3
 			WHERE NOT EXISTS (SELECT * FROM updater)
 			RETURNING false
 		`
 	}
-	rows, err := r.db.QueryContext(ctx, query, seg.Path, seg, numHealthy)
+	rows, err := r.db.QueryContext(ctx, query, seg.Path, seg, segmentHealth)
 	if err != nil {
 		return false, err
 	}
@@ -82,14 +82,14 @@ func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err e
 				UPDATE injuredsegments SET attempted = now() WHERE path = (
 					SELECT path FROM injuredsegments
 					WHERE attempted IS NULL OR attempted < now() - interval '6 hours'
-					ORDER BY num_healthy_pieces ASC, attempted LIMIT 1
+					ORDER BY segment_health ASC, attempted LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	case dbutil.Postgres:
 		err = r.db.QueryRowContext(ctx, `
 				UPDATE injuredsegments SET attempted = now() WHERE path = (
 					SELECT path FROM injuredsegments
 					WHERE attempted IS NULL OR attempted < now() - interval '6 hours'
-					ORDER BY num_healthy_pieces ASC, attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
+					ORDER BY segment_health ASC, attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	default:
 		return seg, errs.New("invalid dbType: %v", r.db.implementation)
@@ -100,6 +100,21 @@ func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err e
 	return seg, err
 }
 
+func (r *repairQueue) SaveProgress(ctx context.Context, path []byte, progress []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, err = r.db.ExecContext(ctx, r.db.Rebind(`UPDATE injuredsegments SET repair_progress = ? WHERE path = ?`), progress, path)
+	return Error.Wrap(err)
+}
+
+func (r *repairQueue) Progress(ctx context.Context, path []byte) (progress []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = r.db.QueryRowContext(ctx, r.db.Rebind(`SELECT repair_progress FROM injuredsegments WHERE path = ?`), path).Scan(&progress)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return progress, Error.Wrap(err)
+}
+
 func (r *repairQueue) Delete(ctx context.Context, seg *pb.InjuredSegment) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	_, err = r.db.ExecContext(ctx, r.db.Rebind(`DELETE FROM injuredsegments WHERE path = ?`), seg.Path)
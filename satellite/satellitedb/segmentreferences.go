@@ -0,0 +1,160 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/satellitedb/dbx"
+)
+
+var _ metainfo.SegmentReferencesDB = (*segmentReferences)(nil)
+
+// segmentReferences implements metainfo.SegmentReferencesDB.
+type segmentReferences struct {
+	db *satelliteDB
+}
+
+// SegmentReferences returns the database tracking segments which share pieces.
+func (db *satelliteDB) SegmentReferences() metainfo.SegmentReferencesDB {
+	return &segmentReferences{db: db}
+}
+
+// Add records that the segments at paths share the pieces of rootPieceID.
+func (refs *segmentReferences) Add(ctx context.Context, rootPieceID storj.PieceID, paths ...storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(refs.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		for _, path := range paths {
+			_, err := tx.Tx.ExecContext(ctx, refs.db.Rebind(`
+				INSERT INTO segment_references (root_piece_id, path) VALUES (?, ?)
+				ON CONFLICT DO NOTHING
+			`), rootPieceID.Bytes(), []byte(path))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// Get returns the paths of the segments sharing the pieces of rootPieceID.
+func (refs *segmentReferences) Get(ctx context.Context, rootPieceID storj.PieceID) (paths []storj.Path, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := refs.db.QueryContext(ctx, refs.db.Rebind(`
+		SELECT path FROM segment_references WHERE root_piece_id = ? ORDER BY path
+	`), rootPieceID.Bytes())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var path []byte
+		if err := rows.Scan(&path); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		paths = append(paths, storj.Path(path))
+	}
+	return paths, Error.Wrap(rows.Err())
+}
+
+// Rename replaces the path of a segment sharing the pieces of rootPieceID.
+func (refs *segmentReferences) Rename(ctx context.Context, rootPieceID storj.PieceID, oldPath, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = refs.db.ExecContext(ctx, refs.db.Rebind(`
+		UPDATE segment_references SET path = ? WHERE root_piece_id = ? AND path = ?
+	`), []byte(newPath), rootPieceID.Bytes(), []byte(oldPath))
+	return Error.Wrap(err)
+}
+
+// Remove removes the segment at path from the segments sharing the pieces of
+// rootPieceID. When a single segment is left, it becomes the only owner of the
+// pieces and its reference is removed as well.
+func (refs *segmentReferences) Remove(ctx context.Context, rootPieceID storj.PieceID, path storj.Path) (shared bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = refs.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		_, err := tx.Tx.ExecContext(ctx, refs.db.Rebind(`
+			DELETE FROM segment_references WHERE root_piece_id = ? AND path = ?
+		`), rootPieceID.Bytes(), []byte(path))
+		if err != nil {
+			return err
+		}
+
+		var remaining int
+		err = tx.Tx.QueryRowContext(ctx, refs.db.Rebind(`
+			SELECT count(*) FROM segment_references WHERE root_piece_id = ?
+		`), rootPieceID.Bytes()).Scan(&remaining)
+		if err != nil {
+			return err
+		}
+
+		shared = remaining > 0
+		if remaining == 1 {
+			_, err = tx.Tx.ExecContext(ctx, refs.db.Rebind(`
+				DELETE FROM segment_references WHERE root_piece_id = ?
+			`), rootPieceID.Bytes())
+		}
+		return err
+	})
+	return shared, Error.Wrap(err)
+}
+
+// AddRelocated records that the segments at paths were created by a copy or move at relocatedAt.
+func (refs *segmentReferences) AddRelocated(ctx context.Context, relocatedAt time.Time, paths ...storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(refs.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		for _, path := range paths {
+			_, err := tx.Tx.ExecContext(ctx, refs.db.Rebind(`
+				INSERT INTO segment_relocations (path, relocated_at) VALUES (?, ?)
+				ON CONFLICT (path) DO UPDATE SET relocated_at = EXCLUDED.relocated_at
+			`), []byte(path), relocatedAt.UTC())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// ListRelocated returns the paths of the segments created by a copy or move since the given time.
+func (refs *segmentReferences) ListRelocated(ctx context.Context, since time.Time) (paths []storj.Path, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := refs.db.QueryContext(ctx, refs.db.Rebind(`
+		SELECT path FROM segment_relocations WHERE relocated_at >= ? ORDER BY path
+	`), since.UTC())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var path []byte
+		if err := rows.Scan(&path); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		paths = append(paths, storj.Path(path))
+	}
+	return paths, Error.Wrap(rows.Err())
+}
+
+// DeleteRelocatedBefore removes the records of the segments created by a copy or move before the given time.
+func (refs *segmentReferences) DeleteRelocatedBefore(ctx context.Context, before time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = refs.db.ExecContext(ctx, refs.db.Rebind(`
+		DELETE FROM segment_relocations WHERE relocated_at < ?
+	`), before.UTC())
+	return Error.Wrap(err)
+}
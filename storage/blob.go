@@ -102,9 +102,9 @@ type Blobs interface {
 	// ListNamespaces finds all namespaces in which keys might currently be stored.
 	ListNamespaces(ctx context.Context) ([][]byte, error)
 	// WalkNamespace executes walkFunc for each locally stored blob, stored with
-	// storage format V1 or greater, in the given namespace. If walkFunc returns a non-nil
-	// error, WalkNamespace will stop iterating and return the error immediately. The ctx
-	// parameter is intended to allow canceling iteration early.
+	// storage format V1 or greater, in the given namespace, in ascending key order. If
+	// walkFunc returns a non-nil error, WalkNamespace will stop iterating and return the
+	// error immediately. The ctx parameter is intended to allow canceling iteration early.
 	WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(BlobInfo) error) error
 	// Close closes the blob store and any resources associated with it.
 	Close() error
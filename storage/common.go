@@ -84,6 +84,22 @@ type KeyValueStore interface {
 	LookupLimit() int
 }
 
+// Move describes moving the value of a key to another key.
+type Move struct {
+	From, To Key
+	// OldValue is the value expected under From.
+	OldValue Value
+	// NewValue is the value stored under To.
+	NewValue Value
+}
+
+// KeyMover is implemented by stores which can move multiple keys atomically.
+type KeyMover interface {
+	// MoveKeys atomically moves the values, it fails with ErrValueChanged when
+	// any From key doesn't have the expected OldValue or any To key exists.
+	MoveKeys(ctx context.Context, moves []Move) error
+}
+
 // IterateOptions contains options for iterator.
 type IterateOptions struct {
 	// Prefix ensure.
@@ -20,6 +20,10 @@ const (
 	FormatV0 storage.FormatVersion = 0
 	// FormatV1 is the identifier for storage format v1
 	FormatV1 storage.FormatVersion = 1
+	// FormatV2 is the identifier for storage format v2, which is like v1 but also stores a
+	// checksum for every chunk of the piece content. It is only written to the piece data store;
+	// blobs in this store are never written with it.
+	FormatV2 storage.FormatVersion = 2
 
 	// Note: New FormatVersion values should be consecutive, as certain parts of this blob store
 	// iterate over them numerically and check for blobs stored with each version.
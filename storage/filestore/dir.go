@@ -4,6 +4,7 @@
 package filestore
 
 import (
+	"container/heap"
 	"context"
 	"encoding/base32"
 	"io"
@@ -604,9 +605,9 @@ func (dir *Dir) listNamespacesInPath(ctx context.Context, path string) (ids [][]
 }
 
 // WalkNamespace executes walkFunc for each locally stored blob, stored with storage format V1 or
-// greater, in the given namespace. If walkFunc returns a non-nil error, WalkNamespace will stop
-// iterating and return the error immediately. The ctx parameter is intended specifically to allow
-// canceling iteration early.
+// greater, in the given namespace, in ascending key order. If walkFunc returns a non-nil error,
+// WalkNamespace will stop iterating and return the error immediately. The ctx parameter is
+// intended specifically to allow canceling iteration early.
 func (dir *Dir) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	return dir.walkNamespaceInPath(ctx, namespace, dir.blobsdir(), walkFunc)
@@ -616,45 +617,129 @@ func (dir *Dir) walkNamespaceInPath(ctx context.Context, namespace []byte, path
 	defer mon.Task()(&ctx)(&err)
 	namespaceDir := pathEncoding.EncodeToString(namespace)
 	nsDir := filepath.Join(path, namespaceDir)
-	openDir, err := os.Open(nsDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// job accomplished: there are no blobs in this namespace!
+	err = walkSortedNames(ctx, nsDir, func(keyPrefix string) error {
+		if len(keyPrefix) != 2 {
+			// just an invalid subdir; could be garbage of many kinds. probably
+			// don't need to pass on this error
 			return nil
 		}
-		return err
+		return walkNamespaceWithPrefix(ctx, dir.log, namespace, nsDir, keyPrefix, walkFunc)
+	})
+	if os.IsNotExist(err) {
+		// job accomplished: there are no blobs in this namespace!
+		return nil
 	}
-	defer func() { err = errs.Combine(err, openDir.Close()) }()
+	return err
+}
+
+// walkSortedNames calls fn for every name in the directory, in the order of the keys they
+// encode. Encoded keys of the same length sort the same way as the keys themselves, which
+// lets callers walk blobs in ascending key order.
+//
+// The directory is read in batches of at most nameBatchSize names. Every batch reads the
+// whole directory again and keeps only the smallest names after the last name of the
+// previous batch, so memory use doesn't grow with the size of the directory. Names which
+// are created during the walk may be skipped.
+func walkSortedNames(ctx context.Context, path string, fn func(name string) error) error {
+	cursor := ""
 	for {
-		// check for context done both before and after our readdir() call
-		if err := ctx.Err(); err != nil {
+		names, more, err := readSortedNamesAfter(ctx, path, cursor, nameBatchSize)
+		if err != nil {
 			return err
 		}
-		subdirNames, err := openDir.Readdirnames(nameBatchSize)
-		if err != nil {
-			if err == io.EOF || os.IsNotExist(err) {
-				return nil
+		for _, name := range names {
+			if err := fn(name); err != nil {
+				return err
 			}
-			return err
 		}
-		if len(subdirNames) == 0 {
+		if !more || len(names) == 0 {
 			return nil
 		}
+		cursor = names[len(names)-1]
+	}
+}
+
+// readSortedNamesAfter returns the smallest limit names in the directory which sort after
+// cursor, in ascending order, and whether there are more names after them.
+func readSortedNamesAfter(ctx context.Context, path, cursor string, limit int) (_ []string, more bool, err error) {
+	openDir, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { err = errs.Combine(err, openDir.Close()) }()
+
+	smallest := &namesHeap{}
+	for {
+		// check for context done both before and after our readdir() call
 		if err := ctx.Err(); err != nil {
-			return err
+			return nil, false, err
 		}
-		for _, keyPrefix := range subdirNames {
-			if len(keyPrefix) != 2 {
-				// just an invalid subdir; could be garbage of many kinds. probably
-				// don't need to pass on this error
+		names, err := openDir.Readdirnames(nameBatchSize)
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		if len(names) == 0 {
+			break
+		}
+		for _, name := range names {
+			if !encodedLess(cursor, name) {
 				continue
 			}
-			err := walkNamespaceWithPrefix(ctx, dir.log, namespace, nsDir, keyPrefix, walkFunc)
-			if err != nil {
-				return err
+			heap.Push(smallest, name)
+			if smallest.Len() > limit {
+				heap.Pop(smallest)
+				more = true
 			}
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	sorted := make([]string, smallest.Len())
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(smallest).(string)
+	}
+	return sorted, more, nil
+}
+
+// namesHeap is a heap of names with the largest name in key order on top.
+type namesHeap []string
+
+func (h namesHeap) Len() int            { return len(h) }
+func (h namesHeap) Less(i, k int) bool  { return encodedLess(h[k], h[i]) }
+func (h namesHeap) Swap(i, k int)       { h[i], h[k] = h[k], h[i] }
+func (h *namesHeap) Push(x interface{}) { *h = append(*h, x.(string)) }
+func (h *namesHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// encodedLess compares two names using the order of the pathEncoding alphabet, which
+// differs from the byte order of the encoded strings.
+func encodedLess(a, b string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		return encodedRank(a[i]) < encodedRank(b[i])
+	}
+	return len(a) < len(b)
+}
+
+// encodedRank returns the position of c in the pathEncoding alphabet. Characters outside of
+// the alphabet, such as the separator of a file suffix, sort before all of it.
+func encodedRank(c byte) int {
+	switch {
+	case 'a' <= c && c <= 'z':
+		return 1 + int(c-'a')
+	case '2' <= c && c <= '7':
+		return 1 + 26 + int(c-'2')
+	default:
+		return 0
+	}
 }
 
 func decodeBlobInfo(namespace []byte, keyPrefix, keyDir string, keyInfo os.FileInfo) (info storage.BlobInfo, ok bool) {
@@ -678,53 +763,34 @@ func decodeBlobInfo(namespace []byte, keyPrefix, keyDir string, keyInfo os.FileI
 
 func walkNamespaceWithPrefix(ctx context.Context, log *zap.Logger, namespace []byte, nsDir, keyPrefix string, walkFunc func(storage.BlobInfo) error) (err error) {
 	keyDir := filepath.Join(nsDir, keyPrefix)
-	openDir, err := os.Open(keyDir)
-	if err != nil {
-		return err
-	}
-	defer func() { err = errs.Combine(err, openDir.Close()) }()
-	for {
-		// check for context done both before and after our readdir() call
-		if err := ctx.Err(); err != nil {
+	return walkSortedNames(ctx, keyDir, func(name string) error {
+		info, err := os.Lstat(keyDir + "/" + name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// the blob was deleted after we listed the directory.
+				return nil
+			}
+			if pErr, ok := err.(*os.PathError); ok {
+				if pErr.Err.Error() == "lstat" {
+					log.Error("Unable to read the disk, please verify the disk is not corrupt")
+				}
+			}
 			return err
 		}
-		names, err := openDir.Readdirnames(nameBatchSize)
-		if err != nil && err != io.EOF {
-			return err
+		if info.Mode().IsDir() {
+			return nil
 		}
-		if os.IsNotExist(err) || len(names) == 0 {
+		blobInfo, ok := decodeBlobInfo(namespace, keyPrefix, keyDir, info)
+		if !ok {
 			return nil
 		}
-		if err := ctx.Err(); err != nil {
+		err = walkFunc(blobInfo)
+		if err != nil {
 			return err
 		}
-		for _, name := range names {
-			info, err := os.Lstat(keyDir + "/" + name)
-			if err != nil {
-				if pErr, ok := err.(*os.PathError); ok {
-					if pErr.Err.Error() == "lstat" {
-						log.Error("Unable to read the disk, please verify the disk is not corrupt")
-					}
-				}
-				return err
-			}
-			if info.Mode().IsDir() {
-				continue
-			}
-			blobInfo, ok := decodeBlobInfo(namespace, keyPrefix, keyDir, info)
-			if !ok {
-				continue
-			}
-			err = walkFunc(blobInfo)
-			if err != nil {
-				return err
-			}
-			// also check for context done between every walkFunc callback.
-			if err := ctx.Err(); err != nil {
-				return err
-			}
-		}
-	}
+		// also check for context done between every walkFunc callback.
+		return ctx.Err()
+	})
 }
 
 // removeAllContent deletes everything in the folder
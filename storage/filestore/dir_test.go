@@ -3,7 +3,14 @@
 
 package filestore
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
 
 func TestDiskInfoFromPath(t *testing.T) {
 	info, err := diskInfoFromPath(".")
@@ -19,3 +26,42 @@ func TestDiskInfoFromPath(t *testing.T) {
 
 	t.Logf("Got: %v %v", info.ID, info.AvailableSpace)
 }
+
+func TestWalkSortedNamesInBatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "walksortednames")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// more names than fit into a single batch
+	var expected []string
+	for i := 0; i < 2*nameBatchSize+10; i++ {
+		name := pathEncoding.EncodeToString([]byte{byte(i >> 8), byte(i)})
+		expected = append(expected, name)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sort.Slice(expected, func(i, k int) bool {
+		return encodedLess(expected[i], expected[k])
+	})
+
+	var walked []string
+	err = walkSortedNames(context.Background(), dir, func(name string) error {
+		walked = append(walked, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(walked) != len(expected) {
+		t.Fatalf("walked %d names, expected %d", len(walked), len(expected))
+	}
+	for i := range expected {
+		if walked[i] != expected[i] {
+			t.Fatalf("name %d: got %q, expected %q", i, walked[i], expected[i])
+		}
+	}
+}
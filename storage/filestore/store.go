@@ -251,9 +251,9 @@ func (store *blobStore) ListNamespaces(ctx context.Context) (ids [][]byte, err e
 	return store.dir.ListNamespaces(ctx)
 }
 
-// WalkNamespace executes walkFunc for each locally stored blob in the given namespace. If walkFunc
-// returns a non-nil error, WalkNamespace will stop iterating and return the error immediately. The
-// ctx parameter is intended specifically to allow canceling iteration early.
+// WalkNamespace executes walkFunc for each locally stored blob in the given namespace, in ascending
+// key order. If walkFunc returns a non-nil error, WalkNamespace will stop iterating and return the
+// error immediately. The ctx parameter is intended specifically to allow canceling iteration early.
 func (store *blobStore) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
 	return store.dir.WalkNamespace(ctx, namespace, walkFunc)
 }
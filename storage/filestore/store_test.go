@@ -532,6 +532,38 @@ func TestStoreTraversals(t *testing.T) {
 	assert.Equal(t, 2, iterations)
 }
 
+func TestWalkNamespaceOrder(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAt(zaptest.NewLogger(t), ctx.Dir("store"), filestore.DefaultConfig)
+	require.NoError(t, err)
+	ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(namespaceSize)
+
+	var keys [][]byte
+	for i := 0; i < 100; i++ {
+		key := testrand.Bytes(keySize)
+		keys = append(keys, key)
+
+		blobWriter, err := store.Create(ctx, storage.BlobRef{Namespace: namespace, Key: key}, 0)
+		require.NoError(t, err)
+		require.NoError(t, blobWriter.Commit(ctx))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	var walked [][]byte
+	err = store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+		walked = append(walked, info.BlobRef().Key)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, keys, walked)
+}
+
 func TestEmptyTrash(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
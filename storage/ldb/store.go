@@ -2,6 +2,7 @@
 package ldb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"github.com/dgraph-io/badger/v2"
@@ -44,6 +45,14 @@ func (store *PieceDataStore) GetInstance(ctx context.Context) (_ *badger.DB, err
 	return store.db, nil
 }
 
+// Close closes the WiscKey instance.
+func (store *PieceDataStore) Close() error {
+	if store.db == nil {
+		return nil
+	}
+	return Error.Wrap(store.db.Close())
+}
+
 func (store *PieceDataStore) Get(ctx context.Context, id storj.PieceID) (value []byte, err error) {
 	defer mon.Task()(&ctx)(&err)
 	value, err = WiscKeyGet(store.db, id.Bytes())
@@ -80,6 +89,17 @@ func (store *PieceDataStore) Delete(ctx context.Context, id storj.PieceID) (err
 	return nil
 }
 
+// Take deletes the value of the piece and returns it. A nil value is returned when the piece
+// doesn't exist.
+func (store *PieceDataStore) Take(ctx context.Context, id storj.PieceID) (value []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	value, err = WiscKeyTake(store.db, id.Bytes())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return value, nil
+}
+
 func WiscKeyGet(db *badger.DB, key []byte) (value []byte, err error) {
 	err = db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get(key)
@@ -124,3 +144,49 @@ func WiscKeyDel(db *badger.DB, key []byte) (err error) {
 		return txn.Delete(key)
 	})
 }
+
+// WiscKeyTake deletes the key and returns its value in the same transaction, so the value
+// is returned only once when the key is deleted concurrently. A nil value is returned when
+// the key doesn't exist.
+func WiscKeyTake(db *badger.DB, key []byte) (value []byte, err error) {
+	err = db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		value, err = item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return txn.Delete(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// WiscKeyKeys returns at most limit keys in order, starting after the key after.
+// A nil after starts from the first key.
+func WiscKeyKeys(db *badger.DB, after []byte, limit int) (keys [][]byte, err error) {
+	err = db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(after); it.Valid() && len(keys) < limit; it.Next() {
+			key := it.Item().KeyCopy(nil)
+			if after != nil && bytes.Equal(key, after) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
@@ -13,6 +13,7 @@ import (
 
 	"storj.io/storj/private/dbutil"
 	"storj.io/storj/private/dbutil/pgutil"
+	"storj.io/storj/private/dbutil/txutil"
 	"storj.io/storj/private/tagsql"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/postgreskv/schema"
@@ -314,3 +315,49 @@ func (client *Client) CompareAndSwap(ctx context.Context, key storage.Key, oldVa
 
 	return nil
 }
+
+// MoveKeys atomically moves the values of the keys to their new keys.
+func (client *Client) MoveKeys(ctx context.Context, moves []storage.Move) (err error) {
+	defer mon.Task()(&ctx, len(moves))(&err)
+
+	for _, move := range moves {
+		if move.From.IsZero() || move.To.IsZero() {
+			return storage.ErrEmptyKey.New("")
+		}
+	}
+
+	return txutil.WithTx(ctx, client.db, nil, func(ctx context.Context, txn tagsql.Tx) error {
+		for _, move := range moves {
+			res, err := txn.ExecContext(ctx, `
+				DELETE FROM pathdata
+					WHERE fullpath = $1::BYTEA
+						AND metadata = $2::BYTEA
+				`, []byte(move.From), []byte(move.OldValue))
+			if err != nil {
+				return Error.Wrap(err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return Error.Wrap(err)
+			}
+			if affected != 1 {
+				return storage.ErrValueChanged.New("%q", move.From)
+			}
+
+			row := txn.QueryRowContext(ctx, `
+				INSERT INTO pathdata (fullpath, metadata) VALUES ($1::BYTEA, $2::BYTEA)
+					ON CONFLICT DO NOTHING
+					RETURNING 1
+				`, []byte(move.To), []byte(move.NewValue))
+			var val []byte
+			err = row.Scan(&val)
+			if err == sql.ErrNoRows {
+				return storage.ErrValueChanged.New("%q", move.To)
+			}
+			if err != nil {
+				return Error.Wrap(err)
+			}
+		}
+		return nil
+	})
+}
@@ -33,6 +33,10 @@ func RunTests(t *testing.T, store storage.KeyValueStore) {
 	t.Run("ListV2", func(t *testing.T) { testListV2(t, ctx, store) })
 
 	t.Run("Parallel", func(t *testing.T) { testParallel(t, ctx, store) })
+
+	if mover, ok := store.(storage.KeyMover); ok {
+		t.Run("MoveKeys", func(t *testing.T) { testMoveKeys(t, ctx, store, mover) })
+	}
 }
 
 func testConstraints(t *testing.T, ctx *testcontext.Context, store storage.KeyValueStore) {
@@ -0,0 +1,82 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/storage"
+)
+
+func testMoveKeys(t *testing.T, ctx *testcontext.Context, store storage.KeyValueStore, mover storage.KeyMover) {
+	items := storage.Items{
+		newItem("move/a", "a", false),
+		newItem("move/b", "b", false),
+		newItem("move/existing", "existing", false),
+	}
+	defer cleanupItems(t, ctx, store, storage.Items{
+		newItem("move/a", "", false),
+		newItem("move/b", "", false),
+		newItem("move/c", "", false),
+		newItem("move/d", "", false),
+		newItem("move/existing", "", false),
+	})
+
+	for _, item := range items {
+		require.NoError(t, store.Put(ctx, item.Key, item.Value))
+	}
+
+	requireValue := func(key, value string) {
+		got, err := store.Get(ctx, storage.Key(key))
+		require.NoError(t, err)
+		require.Equal(t, value, string(got))
+	}
+	requireMissing := func(key string) {
+		_, err := store.Get(ctx, storage.Key(key))
+		require.True(t, storage.ErrKeyNotFound.Has(err), "%q: %v", key, err)
+	}
+
+	t.Run("Value changed", func(t *testing.T) {
+		err := mover.MoveKeys(ctx, []storage.Move{
+			{From: storage.Key("move/a"), To: storage.Key("move/c"), OldValue: storage.Value("a"), NewValue: storage.Value("c")},
+			{From: storage.Key("move/b"), To: storage.Key("move/d"), OldValue: storage.Value("x"), NewValue: storage.Value("d")},
+		})
+		require.True(t, storage.ErrValueChanged.Has(err), err)
+
+		// nothing is moved when one of the moves fails
+		requireValue("move/a", "a")
+		requireValue("move/b", "b")
+		requireMissing("move/c")
+		requireMissing("move/d")
+	})
+
+	t.Run("Target exists", func(t *testing.T) {
+		err := mover.MoveKeys(ctx, []storage.Move{
+			{From: storage.Key("move/a"), To: storage.Key("move/c"), OldValue: storage.Value("a"), NewValue: storage.Value("c")},
+			{From: storage.Key("move/b"), To: storage.Key("move/existing"), OldValue: storage.Value("b"), NewValue: storage.Value("d")},
+		})
+		require.True(t, storage.ErrValueChanged.Has(err), err)
+
+		requireValue("move/a", "a")
+		requireValue("move/b", "b")
+		requireValue("move/existing", "existing")
+		requireMissing("move/c")
+	})
+
+	t.Run("Move", func(t *testing.T) {
+		err := mover.MoveKeys(ctx, []storage.Move{
+			{From: storage.Key("move/a"), To: storage.Key("move/c"), OldValue: storage.Value("a"), NewValue: storage.Value("c")},
+			{From: storage.Key("move/b"), To: storage.Key("move/d"), OldValue: storage.Value("b"), NewValue: storage.Value("d")},
+		})
+		require.NoError(t, err)
+
+		requireMissing("move/a")
+		requireMissing("move/b")
+		requireValue("move/c", "c")
+		requireValue("move/d", "d")
+	})
+}
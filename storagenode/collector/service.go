@@ -29,13 +29,13 @@ type Config struct {
 type Service struct {
 	log         *zap.Logger
 	pieces      *pieces.Store
-	usedSerials *usedserials.Table
+	usedSerials usedserials.Store
 
 	Loop *sync2.Cycle
 }
 
 // NewService creates a new collector service.
-func NewService(log *zap.Logger, pieces *pieces.Store, usedSerials *usedserials.Table, config Config) *Service {
+func NewService(log *zap.Logger, pieces *pieces.Store, usedSerials usedserials.Store, config Config) *Service {
 	return &Service{
 		log:         log,
 		pieces:      pieces,
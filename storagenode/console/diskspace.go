@@ -9,3 +9,11 @@ type DiskSpaceInfo struct {
 	Available int64 `json:"available"`
 	Trash     int64 `json:"trash"`
 }
+
+// SatelliteDiskSpaceInfo stores info about disk space used by a single satellite.
+type SatelliteDiskSpaceInfo struct {
+	Used int64 `json:"used"`
+	// Quota is nil when the satellite may use all of the allocated disk space.
+	Quota     *int64 `json:"quota"`
+	Available int64  `json:"available"`
+}
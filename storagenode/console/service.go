@@ -20,6 +20,7 @@ import (
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/contact"
 	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/pricing"
 	"storj.io/storj/storagenode/reputation"
@@ -48,6 +49,7 @@ type Service struct {
 	satelliteDB    satellites.DB
 	pieceStore     *pieces.Store
 	contact        *contact.Service
+	monitor        *monitor.Service
 
 	version   *checker.Service
 	pingStats *contact.PingStats
@@ -62,7 +64,7 @@ type Service struct {
 // NewService returns new instance of Service.
 func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Store, version *checker.Service,
 	allocatedDiskSpace memory.Size, walletAddress string, versionInfo version.Info, trust *trust.Pool,
-	reputationDB reputation.DB, storageUsageDB storageusage.DB, pricingDB pricing.DB, satelliteDB satellites.DB, pingStats *contact.PingStats, contact *contact.Service) (*Service, error) {
+	reputationDB reputation.DB, storageUsageDB storageusage.DB, pricingDB pricing.DB, satelliteDB satellites.DB, pingStats *contact.PingStats, contact *contact.Service, monitor *monitor.Service) (*Service, error) {
 	if log == nil {
 		return nil, errs.New("log can't be nil")
 	}
@@ -87,6 +89,10 @@ func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Stor
 		return nil, errs.New("contact service can't be nil")
 	}
 
+	if monitor == nil {
+		return nil, errs.New("monitor service can't be nil")
+	}
+
 	return &Service{
 		log:                log,
 		trust:              trust,
@@ -100,6 +106,7 @@ func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Stor
 		pingStats:          pingStats,
 		allocatedDiskSpace: allocatedDiskSpace,
 		contact:            contact,
+		monitor:            monitor,
 		walletAddress:      walletAddress,
 		startedAt:          time.Now(),
 		versionInfo:        versionInfo,
@@ -216,6 +223,7 @@ type Satellite struct {
 	Uptime           reputation.Metric       `json:"uptime"`
 	PriceModel       PriceModel              `json:"priceModel"`
 	NodeJoinedAt     time.Time               `json:"nodeJoinedAt"`
+	DiskSpace        SatelliteDiskSpaceInfo  `json:"diskSpace"`
 }
 
 // GetSatelliteData returns satellite related data.
@@ -263,6 +271,24 @@ func (s *Service) GetSatelliteData(ctx context.Context, satelliteID storj.NodeID
 		return nil, SNOServiceErr.Wrap(err)
 	}
 
+	_, piecesContentSize, err := s.pieceStore.SpaceUsedBySatellite(ctx, satelliteID)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+
+	available, err := s.monitor.AvailableSpaceForSatellite(ctx, satelliteID)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+
+	diskSpace := SatelliteDiskSpaceInfo{
+		Used:      piecesContentSize,
+		Available: available,
+	}
+	if quota, ok := s.monitor.SatelliteQuota(satelliteID); ok {
+		diskSpace.Quota = &quota
+	}
+
 	satellitePricing := PriceModel{
 		EgressBandwidth: pricingModel.EgressBandwidth,
 		RepairBandwidth: pricingModel.RepairBandwidth,
@@ -282,6 +308,7 @@ func (s *Service) GetSatelliteData(ctx context.Context, satelliteID storj.NodeID
 		Uptime:           rep.Uptime,
 		PriceModel:       satellitePricing,
 		NodeJoinedAt:     rep.JoinedAt,
+		DiskSpace:        diskSpace,
 	}, nil
 }
 
@@ -17,6 +17,7 @@ import (
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/private/nodetags"
 	"storj.io/storj/storagenode/trust"
 )
 
@@ -34,6 +35,7 @@ const initialBackOff = time.Second
 // Config contains configurable values for contact service
 type Config struct {
 	ExternalAddress string `user:"true" help:"the public address of the node, useful for nodes behind NAT" default:""`
+	Tags            string `user:"true" help:"comma-separated region tags, such as countries, reported to the satellites for placement, e.g. DE,EU" default:""`
 
 	// Chore config values
 	Interval time.Duration `help:"how frequently the node contact chore should run" releaseDefault:"1h" devDefault:"30s"`
@@ -46,6 +48,8 @@ type NodeInfo struct {
 	Version  pb.NodeVersion
 	Capacity pb.NodeCapacity
 	Operator pb.NodeOperator
+	// Tags are the region tags reported to the satellites.
+	Tags []string
 }
 
 // Service is the contact service between storage nodes and satellites
@@ -55,6 +59,8 @@ type Service struct {
 
 	mu   sync.Mutex
 	self NodeInfo
+	// satelliteCapacity overrides the capacity reported to specific satellites.
+	satelliteCapacity map[storj.NodeID]pb.NodeCapacity
 
 	trust *trust.Pool
 
@@ -127,7 +133,7 @@ func (service *Service) pingSatelliteOnce(ctx context.Context, id storj.NodeID)
 	}
 	defer func() { err = errs.Combine(err, conn.Close()) }()
 
-	self := service.Local()
+	self := service.LocalForSatellite(id)
 	_, err = pb.NewDRPCNodeClient(conn).CheckIn(ctx, &pb.CheckInRequest{
 		Address:  self.Address,
 		Version:  &self.Version,
@@ -137,6 +143,16 @@ func (service *Service) pingSatelliteOnce(ctx context.Context, id storj.NodeID)
 	if err != nil {
 		return errPingSatellite.Wrap(err)
 	}
+
+	// the tags are sent even when empty, so the satellite forgets removed tags.
+	// satellites which don't support tags reject the request, which doesn't
+	// affect the check-in.
+	_, err = nodetags.NewDRPCNodeTagsClient(conn).SetTags(ctx, &nodetags.SetTagsRequest{
+		Tags: self.Tags,
+	})
+	if err != nil {
+		service.log.Debug("satellite did not accept node tags", zap.Stringer("Satellite ID", id), zap.Error(err))
+	}
 	return nil
 }
 
@@ -156,3 +172,22 @@ func (service *Service) UpdateSelf(capacity *pb.NodeCapacity) {
 	}
 	service.initialized.Release()
 }
+
+// LocalForSatellite returns the storagenode info as it's reported to the satellite.
+func (service *Service) LocalForSatellite(satelliteID storj.NodeID) NodeInfo {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	self := service.self
+	if capacity, ok := service.satelliteCapacity[satelliteID]; ok {
+		self.Capacity = capacity
+	}
+	return self
+}
+
+// UpdateSatelliteCapacity replaces the capacities which are reported to specific satellites
+// instead of the capacity of the node, for example because of their storage quota.
+func (service *Service) UpdateSatelliteCapacity(capacities map[storj.NodeID]pb.NodeCapacity) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.satelliteCapacity = capacities
+}
@@ -14,6 +14,7 @@ import (
 
 	"storj.io/common/memory"
 	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/sync2"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/contact"
@@ -33,6 +34,7 @@ type Config struct {
 	MinimumDiskSpace      memory.Size   `help:"how much disk space a node at minimum has to advertise" default:"500GB"`
 	MinimumBandwidth      memory.Size   `help:"how much bandwidth a node at minimum has to advertise (deprecated)" default:"0TB"`
 	NotifyLowDiskCooldown time.Duration `help:"minimum length of time between capacity reports" default:"10m" hidden:"true"`
+	SatelliteQuotas       Quotas        `user:"true" help:"comma-separated list of storage quotas of satellites, as <satellite id>:<size or percentage of allocated disk space>" default:""`
 }
 
 // Service which monitors disk usage
@@ -153,8 +155,22 @@ func (service *Service) updateNodeInformation(ctx context.Context) (err error) {
 		return Error.Wrap(err)
 	}
 
+	freeDisk := service.allocatedDiskSpace - usedSpace
+
+	satelliteCapacities := make(map[storj.NodeID]pb.NodeCapacity, len(service.Config.SatelliteQuotas))
+	for satelliteID := range service.Config.SatelliteQuotas {
+		available, err := service.availableSpaceForSatellite(ctx, satelliteID, freeDisk)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		satelliteCapacities[satelliteID] = pb.NodeCapacity{
+			FreeDisk: available,
+		}
+	}
+
+	service.contact.UpdateSatelliteCapacity(satelliteCapacities)
 	service.contact.UpdateSelf(&pb.NodeCapacity{
-		FreeDisk: service.allocatedDiskSpace - usedSpace,
+		FreeDisk: freeDisk,
 	})
 
 	return nil
@@ -184,3 +200,48 @@ func (service *Service) AvailableSpace(ctx context.Context) (_ int64, err error)
 
 	return allocatedSpace - usedSpace, nil
 }
+
+// SatelliteQuota returns the storage quota of the satellite in bytes and whether the
+// satellite has a quota at all.
+func (service *Service) SatelliteQuota(satelliteID storj.NodeID) (_ int64, ok bool) {
+	quota, ok := service.Config.SatelliteQuotas[satelliteID]
+	if !ok {
+		return 0, false
+	}
+	return quota.Limit(service.allocatedDiskSpace), true
+}
+
+// AvailableSpaceForSatellite returns available disk space for uploads from the satellite,
+// which is limited by both the allocated disk space and the quota of the satellite.
+func (service *Service) AvailableSpaceForSatellite(ctx context.Context, satelliteID storj.NodeID) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	availableSpace, err := service.AvailableSpace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	availableSpace, err = service.availableSpaceForSatellite(ctx, satelliteID, availableSpace)
+	return availableSpace, Error.Wrap(err)
+}
+
+// availableSpaceForSatellite limits the available disk space by the quota of the satellite.
+func (service *Service) availableSpaceForSatellite(ctx context.Context, satelliteID storj.NodeID, availableSpace int64) (_ int64, err error) {
+	quota, ok := service.SatelliteQuota(satelliteID)
+	if !ok {
+		return availableSpace, nil
+	}
+
+	// the cache keeps per satellite totals, so this doesn't walk the pieces.
+	satelliteUsed, _, err := service.store.SpaceUsedBySatellite(ctx, satelliteID)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := quota - satelliteUsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	if remaining < availableSpace {
+		return remaining, nil
+	}
+	return availableSpace, nil
+}
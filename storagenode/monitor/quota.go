@@ -0,0 +1,114 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package monitor
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+)
+
+// Quota limits how much space a single satellite may use, either as an absolute size
+// or as a percentage of the allocated disk space.
+type Quota struct {
+	Size    memory.Size
+	Percent float64
+}
+
+// Limit returns the quota in bytes, for the given allocated disk space.
+func (quota Quota) Limit(allocatedDiskSpace int64) int64 {
+	if quota.Percent > 0 {
+		return int64(float64(allocatedDiskSpace) * quota.Percent / 100)
+	}
+	return quota.Size.Int64()
+}
+
+// String returns the quota in the format accepted by Quotas.Set.
+func (quota Quota) String() string {
+	if quota.Percent > 0 {
+		return strconv.FormatFloat(quota.Percent, 'f', -1, 64) + "%"
+	}
+	return quota.Size.String()
+}
+
+// Quotas contains the storage quotas of satellites. Satellites without a quota may use
+// all of the allocated disk space.
+type Quotas map[storj.NodeID]Quota
+
+// Set implements pflag.Value. It parses a comma-separated list of <satellite id>:<quota>
+// entries, where the quota is either a size, such as 500GB, or a percentage, such as 25%.
+func (quotas *Quotas) Set(s string) error {
+	parsed := Quotas{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return Error.New("invalid satellite quota %q, expected <satellite id>:<quota>", entry)
+		}
+
+		satelliteID, err := storj.NodeIDFromString(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return Error.New("invalid satellite id in quota %q: %v", entry, err)
+		}
+		if _, ok := parsed[satelliteID]; ok {
+			return Error.New("duplicate quota for satellite %s", satelliteID)
+		}
+
+		quota, err := parseQuota(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return Error.New("invalid quota %q: %v", entry, err)
+		}
+		parsed[satelliteID] = quota
+	}
+
+	*quotas = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (*Quotas) Type() string { return "monitor.Quotas" }
+
+// String implements pflag.Value.
+func (quotas *Quotas) String() string {
+	if quotas == nil {
+		return ""
+	}
+
+	entries := make([]string, 0, len(*quotas))
+	for satelliteID, quota := range *quotas {
+		entries = append(entries, satelliteID.String()+":"+quota.String())
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// parseQuota parses a single quota, which is either a size or a percentage.
+func parseQuota(s string) (Quota, error) {
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return Quota{}, err
+		}
+		if percent <= 0 || percent > 100 {
+			return Quota{}, Error.New("percentage must be more than 0 and at most 100")
+		}
+		return Quota{Percent: percent}, nil
+	}
+
+	size, err := memory.ParseString(s)
+	if err != nil {
+		return Quota{}, err
+	}
+	if size <= 0 {
+		return Quota{}, Error.New("size must be positive")
+	}
+	return Quota{Size: memory.Size(size)}, nil
+}
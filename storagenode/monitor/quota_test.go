@@ -0,0 +1,44 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package monitor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode/monitor"
+)
+
+func TestQuotas(t *testing.T) {
+	satellite1, satellite2 := testrand.NodeID(), testrand.NodeID()
+
+	var quotas monitor.Quotas
+	require.NoError(t, quotas.Set(""))
+	require.Empty(t, quotas)
+
+	require.NoError(t, quotas.Set(satellite1.String()+":500GB, "+satellite2.String()+":25%"))
+	require.Len(t, quotas, 2)
+	require.Equal(t, 500*memory.GB.Int64(), quotas[satellite1].Limit(2*memory.TB.Int64()))
+	require.Equal(t, 500*memory.GB.Int64(), quotas[satellite2].Limit(2*memory.TB.Int64()))
+
+	// the string form parses back to the same quotas
+	var reparsed monitor.Quotas
+	require.NoError(t, reparsed.Set(quotas.String()))
+	require.Equal(t, quotas, reparsed)
+
+	for _, invalid := range []string{
+		"500GB",
+		"not-a-satellite:500GB",
+		satellite1.String() + ":",
+		satellite1.String() + ":0%",
+		satellite1.String() + ":101%",
+		satellite1.String() + ":-5GB",
+		satellite1.String() + ":1GB," + satellite1.String() + ":2GB",
+	} {
+		require.Error(t, quotas.Set(invalid), invalid)
+	}
+}
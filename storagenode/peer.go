@@ -29,6 +29,8 @@ import (
 	"storj.io/private/version"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/private/lifecycle"
+	"storj.io/storj/private/nodetags"
+	"storj.io/storj/private/pieceproof"
 	"storj.io/storj/private/version/checker"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
@@ -47,6 +49,7 @@ import (
 	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/piecestore"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
 	"storj.io/storj/storagenode/piecestore/usedserials"
 	"storj.io/storj/storagenode/preflight"
 	"storj.io/storj/storagenode/pricing"
@@ -85,6 +88,7 @@ type DB interface {
 	Notifications() notifications.DB
 	HeldAmount() heldamount.DB
 	Pricing() pricing.DB
+	Retain() retain.DB
 
 	Preflight(ctx context.Context) error
 }
@@ -124,10 +128,7 @@ type Config struct {
 
 // DatabaseConfig returns the storagenodedb.Config that should be used with this Config.
 func (config *Config) DatabaseConfig() storagenodedb.Config {
-	dbdir := config.Storage2.DatabaseDir
-	if dbdir == "" {
-		dbdir = config.Storage.Path
-	}
+	dbdir := config.databaseDir()
 	return storagenodedb.Config{
 		Storage:   config.Storage.Path,
 		Info:      filepath.Join(dbdir, "piecestore.db"),
@@ -137,6 +138,19 @@ func (config *Config) DatabaseConfig() storagenodedb.Config {
 	}
 }
 
+// usedSerialsPath returns the directory of the persistent used serials store.
+func (config *Config) usedSerialsPath() string {
+	return filepath.Join(config.databaseDir(), "used_serials")
+}
+
+// databaseDir returns the directory to store databases in.
+func (config *Config) databaseDir() string {
+	if config.Storage2.DatabaseDir != "" {
+		return config.Storage2.DatabaseDir
+	}
+	return config.Storage.Path
+}
+
 // Verify verifies whether configuration is consistent and acceptable.
 func (config *Config) Verify(log *zap.Logger) error {
 	err := config.Operator.Verify(log)
@@ -151,6 +165,10 @@ func (config *Config) Verify(log *zap.Logger) error {
 		}
 	}
 
+	if _, err := nodetags.Parse(config.Contact.Tags); err != nil {
+		return errs.New("invalid contact.tags: %v", err)
+	}
+
 	if config.Server.Address != "" {
 		err := isAddressValid(config.Server.Address)
 		if err != nil {
@@ -185,7 +203,7 @@ type Peer struct {
 	Log         *zap.Logger
 	Identity    *identity.FullIdentity
 	DB          DB
-	UsedSerials *usedserials.Table
+	UsedSerials usedserials.Store
 
 	Servers  *lifecycle.Group
 	Services *lifecycle.Group
@@ -225,6 +243,8 @@ type Peer struct {
 		TrashChore    *pieces.TrashChore
 		BlobsCache    *pieces.BlobsUsageCache
 		CacheService  *pieces.CacheService
+		Scrubber      *pieces.Scrubber
+		IOScheduler   *ioscheduler.Scheduler
 		RetainService *retain.Service
 		PieceDeleter  *pieces.Deleter
 		Endpoint      *piecestore.Endpoint
@@ -372,6 +392,10 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		tags, err := nodetags.Parse(c.Tags)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		self := contact.NodeInfo{
 			ID:      peer.ID(),
 			Address: c.ExternalAddress,
@@ -380,6 +404,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 				Wallet: config.Operator.Wallet,
 			},
 			Version: *pbVersion,
+			Tags:    tags,
 		}
 		peer.Contact.PingStats = new(contact.PingStats)
 		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), peer.Dialer, self, peer.Storage2.Trust)
@@ -448,10 +473,16 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			Close: peer.Storage2.TrashChore.Close,
 		})
 
+		peer.Storage2.IOScheduler = ioscheduler.New(
+			log.Named("piecestore:ioscheduler"),
+			config.Storage2.IOScheduler,
+		)
+
 		peer.Storage2.CacheService = pieces.NewService(
 			log.Named("piecestore:cache"),
 			peer.Storage2.BlobsCache,
 			peer.Storage2.Store,
+			peer.Storage2.IOScheduler.Yielder(),
 			config.Storage2.CacheSyncInterval,
 		)
 		peer.Services.Add(lifecycle.Item{
@@ -462,6 +493,22 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		peer.Debug.Server.Panel.Add(
 			debug.Cycle("Piecestore Cache", peer.Storage2.CacheService.Loop))
 
+		if config.Pieces.ScrubInterval > 0 {
+			peer.Storage2.Scrubber = pieces.NewScrubber(
+				log.Named("pieces:scrubber"),
+				peer.Storage2.Store,
+				peer.Storage2.IOScheduler.Yielder(),
+				config.Pieces.ScrubInterval,
+			)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "pieces:scrubber",
+				Run:   peer.Storage2.Scrubber.Run,
+				Close: peer.Storage2.Scrubber.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Pieces Scrubber", peer.Storage2.Scrubber.Loop))
+		}
+
 		peer.Storage2.Monitor = monitor.NewService(
 			log.Named("piecestore:monitor"),
 			peer.Storage2.Store,
@@ -484,6 +531,8 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		peer.Storage2.RetainService = retain.NewService(
 			peer.Log.Named("retain"),
 			peer.Storage2.Store,
+			peer.DB.Retain(),
+			peer.Storage2.IOScheduler,
 			config.Retain,
 		)
 		peer.Services.Add(lifecycle.Item{
@@ -492,7 +541,19 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			Close: peer.Storage2.RetainService.Close,
 		})
 
-		peer.UsedSerials = usedserials.NewTable(config.Storage2.MaxUsedSerialsSize)
+		if config.Storage2.PersistentUsedSerials {
+			usedSerials, err := usedserials.OpenPersistentTable(peer.Log.Named("usedserials"), config.usedSerialsPath())
+			if err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
+			peer.UsedSerials = usedSerials
+			peer.Services.Add(lifecycle.Item{
+				Name:  "usedserials",
+				Close: usedSerials.Close,
+			})
+		} else {
+			peer.UsedSerials = usedserials.NewTable(config.Storage2.MaxUsedSerialsSize)
+		}
 
 		peer.Storage2.Endpoint, err = piecestore.NewEndpoint(
 			peer.Log.Named("piecestore"),
@@ -506,6 +567,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.DB.Orders(),
 			peer.DB.Bandwidth(),
 			peer.UsedSerials,
+			peer.Storage2.IOScheduler,
 			config.Storage2,
 		)
 		if err != nil {
@@ -515,6 +577,9 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		if err := pb.DRPCRegisterPiecestore(peer.Server.DRPC(), peer.Storage2.Endpoint); err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		if err := pieceproof.DRPCRegisterPieceProofs(peer.Server.DRPC(), peer.Storage2.Endpoint); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 
 		// TODO workaround for custom timeout for order sending request (read/write)
 		sc := config.Server
@@ -603,6 +668,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.DB.Satellites(),
 			peer.Contact.PingStats,
 			peer.Contact.Service,
+			peer.Storage2.Monitor,
 		)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
@@ -24,6 +24,7 @@ type CacheService struct {
 	log        *zap.Logger
 	usageCache *BlobsUsageCache
 	store      *Store
+	yielder    Yielder
 	Loop       *sync2.Cycle
 
 	// InitFence is released once the cache's Run method returns or when it has
@@ -32,12 +33,13 @@ type CacheService struct {
 }
 
 // NewService creates a new cache service that updates the space usage cache on startup and syncs the cache values to
-// persistent storage on an interval
-func NewService(log *zap.Logger, usageCache *BlobsUsageCache, pieces *Store, interval time.Duration) *CacheService {
+// persistent storage on an interval. The recalculation yields to foreground traffic using the yielder.
+func NewService(log *zap.Logger, usageCache *BlobsUsageCache, pieces *Store, yielder Yielder, interval time.Duration) *CacheService {
 	return &CacheService{
 		log:        log,
 		usageCache: usageCache,
 		store:      pieces,
+		yielder:    yielder,
 		Loop:       sync2.NewCycle(interval),
 	}
 }
@@ -51,7 +53,7 @@ func (service *CacheService) Run(ctx context.Context) (err error) {
 	totalsAtStart := service.usageCache.copyCacheTotals()
 
 	// recalculate the cache once
-	piecesTotal, piecesContentSize, totalsBySatellite, err := service.store.SpaceUsedTotalAndBySatellite(ctx)
+	piecesTotal, piecesContentSize, totalsBySatellite, err := service.store.spaceUsedTotalAndBySatellite(ctx, service.yielder.Yield)
 	if err != nil {
 		service.log.Error("error getting current space used calculation: ", zap.Error(err))
 		return err
@@ -22,6 +22,7 @@ import (
 	"storj.io/storj/storage/filestore"
 	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
 	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
 )
 
@@ -114,6 +115,7 @@ func TestCacheInit(t *testing.T) {
 		cacheService := pieces.NewService(log,
 			cache,
 			pieces.NewStore(log, cache, nil, nil, spaceUsedDB, pieces.DefaultConfig),
+			ioscheduler.New(log, ioscheduler.Config{}),
 			1*time.Hour,
 		)
 
@@ -152,6 +154,7 @@ func TestCacheInit(t *testing.T) {
 		cacheService = pieces.NewService(log,
 			cache,
 			pieces.NewStore(log, cache, nil, nil, spaceUsedDB, pieces.DefaultConfig),
+			ioscheduler.New(log, ioscheduler.Config{}),
 			1*time.Hour,
 		)
 		err = cacheService.PersistCacheTotals(ctx)
@@ -162,6 +165,7 @@ func TestCacheInit(t *testing.T) {
 		cacheService = pieces.NewService(log,
 			cache,
 			pieces.NewStore(log, cache, nil, nil, spaceUsedDB, pieces.DefaultConfig),
+			ioscheduler.New(log, ioscheduler.Config{}),
 			1*time.Hour,
 		)
 		// Confirm that when we call Init after the cache has been persisted
@@ -225,6 +229,7 @@ func TestCachServiceRun(t *testing.T) {
 		cacheService := pieces.NewService(log,
 			cache,
 			pieces.NewStore(log, cache, nil, nil, spaceUsedDB, pieces.DefaultConfig),
+			ioscheduler.New(log, ioscheduler.Config{}),
 			1*time.Hour,
 		)
 
@@ -311,6 +316,7 @@ func TestPersistCacheTotals(t *testing.T) {
 		cacheService := pieces.NewService(log,
 			cache,
 			pieces.NewStore(log, cache, nil, nil, spaceUsedDB, pieces.DefaultConfig),
+			ioscheduler.New(log, ioscheduler.Config{}),
 			1*time.Hour,
 		)
 		err = cacheService.PersistCacheTotals(ctx)
@@ -0,0 +1,109 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+)
+
+const (
+	// ChecksumChunkSize is the amount of piece content covered by a single checksum in pieces
+	// stored with filestore.FormatV2.
+	ChecksumChunkSize = 64 * memory.KiB
+
+	checksumSize = crc32.Size
+)
+
+// ErrCorrupted is returned when piece content does not match its stored checksums.
+var ErrCorrupted = errs.Class("piece corrupted")
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// checksumsHeader describes the checksums stored after the content of filestore.FormatV2
+// pieces. It is serialized right after the pb.PieceHeader, so it is part of the serialized
+// piece header. Its field numbers aren't used by pb.PieceHeader, which keeps them as
+// unrecognized fields, so both messages can be unmarshaled from the same bytes.
+type checksumsHeader struct {
+	ChunkSize int64 `protobuf:"varint,100,opt,name=checksum_chunk_size,proto3"`
+	Count     int64 `protobuf:"varint,101,opt,name=checksum_count,proto3"`
+}
+
+// Reset resets the header.
+func (m *checksumsHeader) Reset() { *m = checksumsHeader{} }
+
+// String formats the header.
+func (m *checksumsHeader) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage marks the header as a protobuf message.
+func (*checksumsHeader) ProtoMessage() {}
+
+// CorruptedRange is a region of piece content which does not match its checksum.
+type CorruptedRange struct {
+	Offset int64
+	Length int64
+}
+
+// String returns a human readable representation of the range.
+func (r CorruptedRange) String() string {
+	return fmt.Sprintf("[%d, %d)", r.Offset, r.Offset+r.Length)
+}
+
+// corruptedRangesError creates an ErrCorrupted error describing the ranges.
+func corruptedRangesError(ranges []CorruptedRange) error {
+	descriptions := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		descriptions = append(descriptions, r.String())
+	}
+	return ErrCorrupted.New("content does not match checksums at %s", strings.Join(descriptions, ", "))
+}
+
+// chunkChecksums calculates the checksum of every chunkSize bytes of data.
+func chunkChecksums(data []byte, chunkSize int) []byte {
+	count := (len(data) + chunkSize - 1) / chunkSize
+	checksums := make([]byte, count*checksumSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		binary.BigEndian.PutUint32(checksums[i*checksumSize:], crc32.Checksum(data[i*chunkSize:end], checksumTable))
+	}
+	return checksums
+}
+
+// verifyChunkChecksums verifies the chunks of data overlapping [offset, offset+length) and
+// returns the ranges which do not match their checksum. Adjacent corrupted chunks are merged.
+func verifyChunkChecksums(data, checksums []byte, chunkSize, offset, length int64) []CorruptedRange {
+	if length <= 0 {
+		return nil
+	}
+
+	var corrupted []CorruptedRange
+	first, last := offset/chunkSize, (offset+length-1)/chunkSize
+	for i := first; i <= last; i++ {
+		start, end := i*chunkSize, (i+1)*chunkSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+
+		expected := binary.BigEndian.Uint32(checksums[i*checksumSize:])
+		if crc32.Checksum(data[start:end], checksumTable) == expected {
+			continue
+		}
+
+		if n := len(corrupted); n > 0 && corrupted[n-1].Offset+corrupted[n-1].Length == start {
+			corrupted[n-1].Length += end - start
+			continue
+		}
+		corrupted = append(corrupted, CorruptedRange{Offset: start, Length: end - start})
+	}
+	return corrupted
+}
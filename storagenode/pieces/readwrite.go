@@ -70,6 +70,8 @@ type Writer struct {
 	db        *badger.DB
 	pieceSize int64 // piece size only; i.e., not including piece header
 
+	formatVersion storage.FormatVersion // only used when writing to WiscKey
+
 	blobs     storage.Blobs
 	satellite storj.NodeID
 	piece     storj.PieceID
@@ -102,13 +104,18 @@ func NewWriter(log *zap.Logger, blobWriter storage.BlobWriter, blobs storage.Blo
 
 // @author ousing9
 // 使用 WiscKey 创建 Writer
-func NewWriterWithWiscKey(log *zap.Logger, db *badger.DB, satellite storj.NodeID, piece storj.PieceID) (*Writer, error) {
+func NewWriterWithWiscKey(log *zap.Logger, db *badger.DB, blobs storage.Blobs, satellite storj.NodeID, piece storj.PieceID, formatVersion storage.FormatVersion) (*Writer, error) {
+	if formatVersion < filestore.FormatV1 || formatVersion > filestore.FormatV2 {
+		return nil, BadFormatVersion.New("can't write storage format V%d pieces to WiscKey", formatVersion)
+	}
 	return &Writer{
-		db:        db,
-		log:       log,
-		satellite: satellite,
-		piece:     piece,
-		hash:      pkcrypto.NewHash(),
+		db:            db,
+		log:           log,
+		blobs:         blobs,
+		satellite:     satellite,
+		piece:         piece,
+		hash:          pkcrypto.NewHash(),
+		formatVersion: formatVersion,
 	}, nil
 }
 
@@ -237,12 +244,29 @@ func (w *Writer) CommitWithWiscKey(ctx context.Context, pieceHeader *pb.PieceHea
 		err = Error.Wrap(err)
 	}()
 
-	formatVer := filestore.MaxFormatVersionSupported
+	formatVer := w.formatVersion
 	pieceHeader.FormatVersion = pb.PieceHeader_FormatVersion(formatVer)
 	headerBytes, err := pb.Marshal(pieceHeader)
 	if err != nil {
 		return err
 	}
+
+	var checksums []byte
+	if formatVer >= filestore.FormatV2 {
+		// FormatV2 pieces are followed by a checksum for every ChecksumChunkSize bytes of
+		// content. The chunk size and the number of checksums are part of the piece header,
+		// so readers know how much of the value is content.
+		checksums = chunkChecksums(bytes.Join(chunkDataAll, nil), ChecksumChunkSize.Int())
+		checksumsBytes, err := pb.Marshal(&checksumsHeader{
+			ChunkSize: ChecksumChunkSize.Int64(),
+			Count:     int64(len(checksums) / checksumSize),
+		})
+		if err != nil {
+			return err
+		}
+		headerBytes = append(headerBytes, checksumsBytes...)
+	}
+
 	mon.IntVal("storagenode_pieces_pieceheader_size").Observe(int64(len(headerBytes)))
 	if len(headerBytes) > (V1PieceHeaderReservedArea - v1PieceHeaderFramingSize) {
 		// This should never happen under normal circumstances, and it might deserve a panic(),
@@ -267,9 +291,20 @@ func (w *Writer) CommitWithWiscKey(ctx context.Context, pieceHeader *pb.PieceHea
 		headerBytes,
 		make([]byte, V1PieceHeaderReservedArea-v1PieceHeaderFramingSize-len(headerBytes)),
 	}, chunkDataAll...)
+	chunkDataAll = append(chunkDataAll, checksums)
 
 	// WiscKey Set 拼接好的 Chunk Data
-	return ldb.WiscKeySet(w.db, w.piece.Bytes(), bytes.Join(chunkDataAll, []byte("")))
+	value := bytes.Join(chunkDataAll, []byte(""))
+	if err := ldb.WiscKeySet(w.db, w.piece.Bytes(), value); err != nil {
+		return err
+	}
+
+	// if the blob store is a cache, update the cache, so the space used by the
+	// satellite includes the pieces in WiscKey.
+	if cache, ok := w.blobs.(*BlobsUsageCache); ok {
+		cache.Update(ctx, w.satellite, int64(len(value)), w.Size(), 0)
+	}
+	return nil
 }
 
 // Cancel deletes any temporarily written data.
@@ -302,6 +337,11 @@ type Reader struct {
 	pos       int64 // relative to file start; i.e., it includes piece header
 	pieceSize int64 // piece size only; i.e., not including piece header
 	piece     storj.PieceID
+	value     []byte // the whole WiscKey value, including the piece header
+
+	// checksums and checksumChunkSize are only set for FormatV2 pieces.
+	checksums         []byte
+	checksumChunkSize int64
 }
 
 // NewReader creates a new reader for storage.BlobReader.
@@ -333,6 +373,11 @@ func NewReaderWithWiscKey(db *badger.DB, pieceID storj.PieceID) (*Reader, error)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
+	return newReaderWithWiscKeyValue(db, pieceID, data)
+}
+
+// newReaderWithWiscKeyValue creates a new reader for the WiscKey value of the piece.
+func newReaderWithWiscKeyValue(db *badger.DB, pieceID storj.PieceID, data []byte) (*Reader, error) {
 	size := int64(len(data))
 
 	formatVersion := filestore.MaxFormatVersionSupported
@@ -343,12 +388,57 @@ func NewReaderWithWiscKey(db *badger.DB, pieceID storj.PieceID) (*Reader, error)
 		size -= V1PieceHeaderReservedArea
 	}
 
-	return &Reader{
+	reader := &Reader{
 		formatVersion: formatVersion,
 		db:            db,
 		pieceSize:     size,
 		piece:         pieceID,
-	}, nil
+		value:         data,
+	}
+
+	// the format version is only known after looking at the header, because
+	// WiscKey does not keep it outside of the value. A header which can't be
+	// unmarshaled is reported by GetPieceHeaderWithWiscKey, so the content of
+	// pieces written before FormatV2 can still be read.
+	headerBytes, ok := pieceHeaderBytes(data)
+	if !ok {
+		return reader, nil
+	}
+	header := &pb.PieceHeader{}
+	if err := pb.Unmarshal(headerBytes, header); err != nil {
+		return reader, nil
+	}
+	if storage.FormatVersion(header.FormatVersion) < filestore.FormatV2 {
+		return reader, nil
+	}
+	reader.formatVersion = storage.FormatVersion(header.FormatVersion)
+
+	var checksums checksumsHeader
+	if err := pb.Unmarshal(headerBytes, &checksums); err != nil {
+		return nil, Error.New("piece header: %w", err)
+	}
+	checksumsSize := checksums.Count * checksumSize
+	if checksums.ChunkSize <= 0 || checksums.Count < 0 || checksumsSize > size {
+		return nil, Error.New("invalid checksums header: chunk size %d, checksums size %d", checksums.ChunkSize, checksumsSize)
+	}
+
+	reader.pieceSize = size - checksumsSize
+	if (reader.pieceSize+checksums.ChunkSize-1)/checksums.ChunkSize*checksumSize != checksumsSize {
+		return nil, Error.New("invalid checksums header: %d bytes of checksums for %d bytes of content", checksumsSize, reader.pieceSize)
+	}
+	reader.checksums = data[int64(len(data))-checksumsSize:]
+	reader.checksumChunkSize = checksums.ChunkSize
+
+	return reader, nil
+}
+
+// pieceHeaderBytes returns the serialized piece header at the start of the piece data.
+func pieceHeaderBytes(pieceData []byte) ([]byte, bool) {
+	headerSize := int(binary.BigEndian.Uint16(pieceData[:v1PieceHeaderFramingSize]))
+	if headerSize > (V1PieceHeaderReservedArea - v1PieceHeaderFramingSize) {
+		return nil, false
+	}
+	return pieceData[v1PieceHeaderFramingSize : v1PieceHeaderFramingSize+headerSize], true
 }
 
 // StorageFormatVersion returns the storage format version of the piece being read.
@@ -415,10 +505,7 @@ func (r *Reader) GetPieceHeaderWithWiscKey() (*pb.PieceHeader, error) {
 	// reserved header area is supposed to make up the serialized header protobuf.
 
 	// 和 blob 的一段段地读不同，WiscKey 需要 Get 出来之后拆解
-	pieceData, err := ldb.WiscKeyGet(r.db, r.piece.Bytes())
-	if err != nil {
-		return nil, Error.Wrap(err)
-	}
+	pieceData := r.value
 
 	framingBytes := pieceData[:v1PieceHeaderFramingSize]
 
@@ -458,11 +545,42 @@ func (r *Reader) Read(data []byte) (int, error) {
 
 // @author ousing9
 func (r *Reader) ReadWithWiscKey() (res []byte, err error) {
-	res, err = ldb.WiscKeyGet(r.db, r.piece.Bytes())
+	return r.value[V1PieceHeaderReservedArea : V1PieceHeaderReservedArea+r.pieceSize], nil
+}
+
+// ReadRangeWithWiscKey reads length bytes of piece content starting at offset. For FormatV2
+// pieces the content is verified against the stored checksums first, and an ErrCorrupted error
+// naming the mismatching regions is returned instead of the data when any of them don't match.
+func (r *Reader) ReadRangeWithWiscKey(offset, length int64) (_ []byte, err error) {
+	if offset < 0 || length < 0 || offset+length > r.pieceSize {
+		return nil, Error.New("invalid range: offset %d, length %d, piece size %d", offset, length, r.pieceSize)
+	}
+
+	content, err := r.ReadWithWiscKey()
 	if err != nil {
-		return nil, Error.Wrap(err)
+		return nil, err
+	}
+
+	if r.checksums != nil {
+		if corrupted := verifyChunkChecksums(content, r.checksums, r.checksumChunkSize, offset, length); len(corrupted) > 0 {
+			return nil, corruptedRangesError(corrupted)
+		}
+	}
+	return content[offset : offset+length], nil
+}
+
+// VerifyWithWiscKey verifies the whole piece content against the stored checksums and returns
+// the regions which do not match. Pieces stored without checksums never report corruption.
+func (r *Reader) VerifyWithWiscKey() ([]CorruptedRange, error) {
+	if r.checksums == nil {
+		return nil, nil
+	}
+
+	content, err := r.ReadWithWiscKey()
+	if err != nil {
+		return nil, err
 	}
-	return res[V1PieceHeaderReservedArea:], nil
+	return verifyChunkChecksums(content, r.checksums, r.checksumChunkSize, 0, r.pieceSize), nil
 }
 
 // Seek seeks to the specified location within the piece content (ignoring the header).
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -20,6 +21,7 @@ import (
 	"storj.io/common/testrand"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/ldb"
 	"storj.io/storj/storagenode/pieces"
 )
 
@@ -216,3 +218,108 @@ func TestEmptyPiece(t *testing.T) {
 	var content [0]byte
 	readAndWritePiece(t, content[:])
 }
+
+func TestChunkChecksumsWithWiscKey(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db, err := badger.Open(badger.DefaultOptions(ctx.Dir("wisckey")).WithLogger(nil))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+
+	satelliteID := testrand.NodeID()
+	chunkSize := pieces.ChecksumChunkSize.Int64()
+	content := testrand.Bytes(memory.Size(3*chunkSize + 100))
+
+	for _, formatVersion := range []storage.FormatVersion{filestore.FormatV1, filestore.FormatV2} {
+		pieceID := testrand.PieceID()
+
+		w, err := pieces.NewWriterWithWiscKey(zaptest.NewLogger(t), db, nil, satelliteID, pieceID, formatVersion)
+		require.NoError(t, err)
+		_, err = w.WriteWithWiscKey(content)
+		require.NoError(t, err)
+		require.NoError(t, w.CommitWithWiscKey(ctx, &pb.PieceHeader{Hash: w.Hash()}, [][]byte{content}))
+
+		r, err := pieces.NewReaderWithWiscKey(db, pieceID)
+		require.NoError(t, err)
+		require.Equal(t, formatVersion, r.StorageFormatVersion())
+		require.Equal(t, int64(len(content)), r.Size())
+
+		header, err := r.GetPieceHeaderWithWiscKey()
+		require.NoError(t, err)
+		require.Equal(t, w.Hash(), header.Hash)
+
+		data, err := r.ReadRangeWithWiscKey(0, r.Size())
+		require.NoError(t, err)
+		require.Equal(t, content, data)
+
+		// flip a bit in the second chunk
+		value, err := ldb.WiscKeyGet(db, pieceID.Bytes())
+		require.NoError(t, err)
+		value[pieces.V1PieceHeaderReservedArea+chunkSize+10] ^= 1
+		require.NoError(t, ldb.WiscKeySet(db, pieceID.Bytes(), value))
+
+		r, err = pieces.NewReaderWithWiscKey(db, pieceID)
+		require.NoError(t, err)
+
+		corrupted, err := r.VerifyWithWiscKey()
+		require.NoError(t, err)
+
+		if formatVersion < filestore.FormatV2 {
+			// there is nothing to detect corruption with
+			require.Empty(t, corrupted)
+			continue
+		}
+		require.Equal(t, []pieces.CorruptedRange{{Offset: chunkSize, Length: chunkSize}}, corrupted)
+
+		// ranges outside of the corrupted chunk can still be served
+		data, err = r.ReadRangeWithWiscKey(0, chunkSize)
+		require.NoError(t, err)
+		require.Equal(t, content[:chunkSize], data)
+
+		data, err = r.ReadRangeWithWiscKey(2*chunkSize, r.Size()-2*chunkSize)
+		require.NoError(t, err)
+		require.Equal(t, content[2*chunkSize:], data)
+
+		_, err = r.ReadRangeWithWiscKey(chunkSize-1, 2)
+		require.True(t, pieces.ErrCorrupted.Has(err))
+	}
+}
+
+func TestBrokenPieceHeaderWithWiscKey(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db, err := badger.Open(badger.DefaultOptions(ctx.Dir("wisckey")).WithLogger(nil))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+
+	pieceID := testrand.PieceID()
+	content := testrand.Bytes(10 * memory.KiB)
+
+	w, err := pieces.NewWriterWithWiscKey(zaptest.NewLogger(t), db, nil, testrand.NodeID(), pieceID, filestore.FormatV1)
+	require.NoError(t, err)
+	_, err = w.WriteWithWiscKey(content)
+	require.NoError(t, err)
+	require.NoError(t, w.CommitWithWiscKey(ctx, &pb.PieceHeader{Hash: w.Hash()}, [][]byte{content}))
+
+	// garble the serialized header
+	value, err := ldb.WiscKeyGet(db, pieceID.Bytes())
+	require.NoError(t, err)
+	for i := 2; i < 12; i++ {
+		value[i] = 0xff
+	}
+	require.NoError(t, ldb.WiscKeySet(db, pieceID.Bytes(), value))
+
+	// the content can still be read, only the header can't.
+	r, err := pieces.NewReaderWithWiscKey(db, pieceID)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(content)), r.Size())
+
+	data, err := r.ReadWithWiscKey()
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	_, err = r.GetPieceHeaderWithWiscKey()
+	require.Error(t, err)
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/storage/filestore"
+)
+
+// ScrubResult is the result of verifying all stored pieces against their chunk checksums.
+type ScrubResult struct {
+	Finished  time.Time
+	Checked   int64
+	Corrupted map[storj.PieceID][]CorruptedRange
+}
+
+// Yielder gives way to foreground traffic. Background chores call Yield between units of
+// work.
+type Yielder interface {
+	Yield(ctx context.Context) error
+}
+
+// Scrubber verifies the stored pieces against their chunk checksums in the background,
+// so corrupted pieces are found before they are audited or downloaded.
+//
+// architecture: Chore
+type Scrubber struct {
+	log     *zap.Logger
+	store   *Store
+	yielder Yielder
+	Loop    *sync2.Cycle
+
+	mu   sync.Mutex
+	last ScrubResult
+}
+
+// NewScrubber creates a new scrubber, which verifies all pieces on every interval.
+// The verification yields to foreground traffic using the yielder, which is only used by
+// one verification at a time.
+func NewScrubber(log *zap.Logger, store *Store, yielder Yielder, interval time.Duration) *Scrubber {
+	return &Scrubber{
+		log:     log,
+		store:   store,
+		yielder: yielder,
+		Loop:    sync2.NewCycle(interval),
+	}
+}
+
+// Run verifies the pieces on every interval.
+func (scrubber *Scrubber) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return scrubber.Loop.Run(ctx, func(ctx context.Context) error {
+		result, err := scrubber.Scrub(ctx)
+		if err != nil {
+			scrubber.log.Error("verifying pieces failed", zap.Error(err))
+			return nil
+		}
+		scrubber.log.Info("verified pieces",
+			zap.Int64("checked", result.Checked),
+			zap.Int("corrupted", len(result.Corrupted)))
+		return nil
+	})
+}
+
+// Scrub verifies all pieces stored in WiscKey once. Pieces without chunk checksums are
+// skipped, since there is nothing to verify them against.
+func (scrubber *Scrubber) Scrub(ctx context.Context) (_ ScrubResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result := ScrubResult{
+		Corrupted: map[storj.PieceID][]CorruptedRange{},
+	}
+
+	err = scrubber.store.WalkPiecesWithWiscKey(ctx, func(pieceID storj.PieceID) error {
+		if err := scrubber.yielder.Yield(ctx); err != nil {
+			return err
+		}
+
+		// the satellite isn't part of the key of the pieces in WiscKey.
+		reader, err := scrubber.store.ReaderWithWiscKey(ctx, storj.NodeID{}, pieceID)
+		if err != nil || reader == nil {
+			// the piece was deleted during the walk.
+			return nil
+		}
+		defer func() { _ = reader.CloseWithWiscKey() }()
+
+		if reader.StorageFormatVersion() < filestore.FormatV2 {
+			return nil
+		}
+
+		corrupted, err := reader.VerifyWithWiscKey()
+		if err != nil {
+			scrubber.log.Warn("unable to verify piece", zap.Stringer("Piece ID", pieceID), zap.Error(err))
+			return nil
+		}
+		result.Checked++
+
+		if len(corrupted) > 0 {
+			mon.Meter("piece_scrub_corrupted").Mark(1)
+			scrubber.log.Error("piece corrupted",
+				zap.Stringer("Piece ID", pieceID),
+				zap.Error(corruptedRangesError(corrupted)))
+			result.Corrupted[pieceID] = corrupted
+		}
+		return nil
+	})
+	if err != nil {
+		return ScrubResult{}, err
+	}
+
+	result.Finished = time.Now()
+	mon.IntVal("piece_scrub_checked").Observe(result.Checked)
+	mon.IntVal("piece_scrub_corrupted_pieces").Observe(int64(len(result.Corrupted)))
+
+	scrubber.mu.Lock()
+	scrubber.last = result
+	scrubber.mu.Unlock()
+
+	return result, nil
+}
+
+// LastResult returns the result of the last finished verification of all pieces.
+func (scrubber *Scrubber) LastResult() ScrubResult {
+	scrubber.mu.Lock()
+	defer scrubber.mu.Unlock()
+	return scrubber.last
+}
+
+// Close stops the scrubber.
+func (scrubber *Scrubber) Close() error {
+	scrubber.Loop.Close()
+	return nil
+}
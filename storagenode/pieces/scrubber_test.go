@@ -0,0 +1,84 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/ldb"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
+)
+
+func TestScrubber(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+
+	dir, err := filestore.NewDir(log, ctx.Dir("storage"))
+	require.NoError(t, err)
+	pieceDataStore := ldb.New(log, dir)
+	defer ctx.Check(pieceDataStore.Close)
+
+	store := pieces.NewStoreWithWiscKey(log, nil, nil, nil, nil, pieceDataStore, pieces.Config{
+		ChunkChecksums: true,
+	})
+	scrubber := pieces.NewScrubber(log, store, ioscheduler.New(log, ioscheduler.Config{}).Yielder(), time.Hour)
+
+	satelliteID := testrand.NodeID()
+	chunkSize := pieces.ChecksumChunkSize.Int64()
+
+	var pieceIDs []storj.PieceID
+	for i := 0; i < 3; i++ {
+		pieceID := testrand.PieceID()
+		pieceIDs = append(pieceIDs, pieceID)
+
+		content := testrand.Bytes(memory.Size(2*chunkSize + 10))
+		w, err := store.WriterWithWiscKey(ctx, satelliteID, pieceID)
+		require.NoError(t, err)
+		_, err = w.WriteWithWiscKey(content)
+		require.NoError(t, err)
+		require.NoError(t, w.CommitWithWiscKey(ctx, &pb.PieceHeader{Hash: w.Hash()}, [][]byte{content}))
+	}
+
+	// pieces without chunk checksums are skipped
+	db, err := pieceDataStore.GetInstance(ctx)
+	require.NoError(t, err)
+	w, err := pieces.NewWriterWithWiscKey(log, db, nil, satelliteID, testrand.PieceID(), filestore.FormatV1)
+	require.NoError(t, err)
+	content := testrand.Bytes(memory.Size(chunkSize))
+	_, err = w.WriteWithWiscKey(content)
+	require.NoError(t, err)
+	require.NoError(t, w.CommitWithWiscKey(ctx, &pb.PieceHeader{Hash: w.Hash()}, [][]byte{content}))
+
+	result, err := scrubber.Scrub(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, result.Checked)
+	require.Empty(t, result.Corrupted)
+
+	// flip a bit in the last chunk of a piece
+	value, err := ldb.WiscKeyGet(db, pieceIDs[1].Bytes())
+	require.NoError(t, err)
+	value[pieces.V1PieceHeaderReservedArea+2*chunkSize+5] ^= 1
+	require.NoError(t, ldb.WiscKeySet(db, pieceIDs[1].Bytes(), value))
+
+	result, err = scrubber.Scrub(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, result.Checked)
+	require.Equal(t, map[storj.PieceID][]pieces.CorruptedRange{
+		pieceIDs[1]: {{Offset: 2 * chunkSize, Length: 10}},
+	}, result.Corrupted)
+	require.Equal(t, result, scrubber.LastResult())
+}
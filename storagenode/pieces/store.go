@@ -155,7 +155,9 @@ type SatelliteUsage struct {
 
 // Config is configuration for Store.
 type Config struct {
-	WritePreallocSize memory.Size `help:"file preallocated for uploading" default:"4MiB"`
+	WritePreallocSize memory.Size   `help:"file preallocated for uploading" default:"4MiB"`
+	ChunkChecksums    bool          `help:"store a checksum for every chunk of new pieces (storage format v2), so corrupted regions are detected and never served" default:"false"`
+	ScrubInterval     time.Duration `help:"how often pieces with chunk checksums are verified in the background. 0 disables the verification" default:"168h0m0s"`
 }
 
 // DefaultConfig is the default value for the Config.
@@ -233,7 +235,12 @@ func (store *Store) WriterWithWiscKey(ctx context.Context, satellite storj.NodeI
 		return nil, Error.Wrap(err)
 	}
 
-	writer, err := NewWriterWithWiscKey(store.log.Named("WiscKey"), db, satellite, pieceID)
+	formatVersion := filestore.FormatV1
+	if store.config.ChunkChecksums {
+		formatVersion = filestore.FormatV2
+	}
+
+	writer, err := NewWriterWithWiscKey(store.log.Named("WiscKey"), db, store.blobs, satellite, pieceID, formatVersion)
 	return writer, Error.Wrap(err)
 }
 
@@ -348,9 +355,20 @@ func (store *Store) DeleteWithWiscKey(ctx context.Context, satellite storj.NodeI
 	defer mon.Task()(&ctx)(&err)
 
 	// 直接根据 Piece ID 删除
-	err = store.pieceDataStore.Delete(ctx, pieceID)
-	if err != nil {
-		return Error.Wrap(err)
+	if cache, ok := store.blobs.(*BlobsUsageCache); ok {
+		// the value is needed to know how much space is freed for the cache.
+		value, err := store.pieceDataStore.Take(ctx, pieceID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if value != nil {
+			store.updateCacheForDeletedWiscKeyPiece(ctx, cache, satellite, pieceID, value)
+		}
+	} else {
+		err = store.pieceDataStore.Delete(ctx, pieceID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
 	}
 
 	// delete records in both the piece_expirations and pieceinfo DBs, wherever we find it.
@@ -365,6 +383,19 @@ func (store *Store) DeleteWithWiscKey(ctx context.Context, satellite storj.NodeI
 	return Error.Wrap(err)
 }
 
+// updateCacheForDeletedWiscKeyPiece subtracts the size of the deleted WiscKey value from the
+// space used by the satellite.
+func (store *Store) updateCacheForDeletedWiscKeyPiece(ctx context.Context, cache *BlobsUsageCache, satellite storj.NodeID, pieceID storj.PieceID, value []byte) {
+	reader, err := newReaderWithWiscKeyValue(nil, pieceID, value)
+	if err != nil {
+		store.log.Error("Failed to calculate piece size, cannot update the cache",
+			zap.Error(err), zap.Stringer("Piece ID", pieceID),
+			zap.Stringer("Satellite ID", satellite))
+		return
+	}
+	cache.Update(ctx, satellite, -int64(len(value)), -reader.Size(), 0)
+}
+
 // Trash moves the specified piece to the blob trash. If necessary, it converts
 // the v0 piece to a v1 piece. It also marks the item as "trashed" in the
 // pieceExpirationDB.
@@ -575,6 +606,40 @@ func (store *Store) WalkSatellitePieces(ctx context.Context, satellite storj.Nod
 	return err
 }
 
+// WalkPiecesWithWiscKey executes walkFunc for the ID of each piece stored in WiscKey, in the
+// order of the IDs. The pieces are listed in batches, so walkFunc doesn't hold up WiscKey.
+func (store *Store) WalkPiecesWithWiscKey(ctx context.Context, walkFunc func(storj.PieceID) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	db, err := store.pieceDataStore.GetInstance(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	const batchSize = 1000
+	var after []byte
+	for {
+		keys, err := ldb.WiscKeyKeys(db, after, batchSize)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		for _, key := range keys {
+			pieceID, err := storj.PieceIDFromBytes(key)
+			if err != nil {
+				// not a piece.
+				continue
+			}
+			if err := walkFunc(pieceID); err != nil {
+				return err
+			}
+		}
+		if len(keys) < batchSize {
+			return nil
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
 // GetExpired gets piece IDs that are expired and were created before the given time
 func (store *Store) GetExpired(ctx context.Context, expiredAt time.Time, limit int64) (_ []ExpiredInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -705,8 +770,16 @@ func (store *Store) SpaceUsedBySatellite(ctx context.Context, satelliteID storj.
 }
 
 // SpaceUsedTotalAndBySatellite adds up the space used by and for all satellites for blob storage
+// and the WiscKey piece data store
 func (store *Store) SpaceUsedTotalAndBySatellite(ctx context.Context) (piecesTotal, piecesContentSize int64, totalBySatellite map[storj.NodeID]SatelliteUsage, err error) {
 	defer mon.Task()(&ctx)(&err)
+	return store.spaceUsedTotalAndBySatellite(ctx, nil)
+}
+
+// spaceUsedTotalAndBySatellite is SpaceUsedTotalAndBySatellite, which calls yield, when it's
+// not nil, before looking at each piece.
+func (store *Store) spaceUsedTotalAndBySatellite(ctx context.Context, yield func(context.Context) error) (piecesTotal, piecesContentSize int64, totalBySatellite map[storj.NodeID]SatelliteUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
 
 	satelliteIDs, err := store.getAllStoringSatellites(ctx)
 	if err != nil {
@@ -721,6 +794,11 @@ func (store *Store) SpaceUsedTotalAndBySatellite(ctx context.Context) (piecesTot
 		var satPiecesContentSize int64
 
 		err := store.WalkSatellitePieces(ctx, satelliteID, func(access StoredPieceAccess) error {
+			if yield != nil {
+				if err := yield(ctx); err != nil {
+					return err
+				}
+			}
 			pieceTotal, pieceContentSize, err := access.Size(ctx)
 			if err != nil {
 				return err
@@ -740,6 +818,43 @@ func (store *Store) SpaceUsedTotalAndBySatellite(ctx context.Context) (piecesTot
 			ContentSize: satPiecesContentSize,
 		}
 	}
+
+	if store.pieceDataStore != nil {
+		// the satellite isn't part of the key of the pieces in WiscKey, so it's
+		// taken from the order limit in the piece header.
+		err := store.WalkPiecesWithWiscKey(ctx, func(pieceID storj.PieceID) error {
+			if yield != nil {
+				if err := yield(ctx); err != nil {
+					return err
+				}
+			}
+			reader, err := store.ReaderWithWiscKey(ctx, storj.NodeID{}, pieceID)
+			if err != nil || reader == nil {
+				// the piece was deleted during the walk.
+				return nil
+			}
+			header, err := reader.GetPieceHeaderWithWiscKey()
+			if err != nil {
+				store.log.Error("failed to read piece header", zap.Error(err), zap.Stringer("Piece ID", pieceID))
+				// keep iterating; we want a best effort total here.
+				return nil
+			}
+
+			pieceTotal := int64(len(reader.value))
+			piecesTotal += pieceTotal
+			piecesContentSize += reader.Size()
+
+			usage := totalBySatellite[header.OrderLimit.SatelliteId]
+			usage.Total += pieceTotal
+			usage.ContentSize += reader.Size()
+			totalBySatellite[header.OrderLimit.SatelliteId] = usage
+			return nil
+		})
+		if err != nil {
+			group.Add(err)
+		}
+	}
+
 	return piecesTotal, piecesContentSize, totalBySatellite, group.Err()
 }
 
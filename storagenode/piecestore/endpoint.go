@@ -4,6 +4,7 @@
 package piecestore
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -27,10 +28,12 @@ import (
 	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/private/pieceproof"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
 	"storj.io/storj/storagenode/piecestore/usedserials"
 	"storj.io/storj/storagenode/retain"
 	"storj.io/storj/storagenode/trust"
@@ -62,11 +65,13 @@ type Config struct {
 	RetainTimeBuffer        time.Duration `help:"allows for small differences in the satellite and storagenode clocks" default:"48h0m0s"`
 	ReportCapacityThreshold memory.Size   `help:"threshold below which to immediately notify satellite of capacity" default:"500MB" hidden:"true"`
 	MaxUsedSerialsSize      memory.Size   `help:"amount of memory allowed for used serials store - once surpassed, serials will be dropped at random" default:"1MB"`
+	PersistentUsedSerials   bool          `help:"keep used serials on disk, so they survive restarts and are never dropped before expiration" releaseDefault:"true" devDefault:"false"`
 
 	Trust trust.Config
 
-	Monitor monitor.Config
-	Orders  orders.Config
+	Monitor     monitor.Config
+	IOScheduler ioscheduler.Config
+	Orders      orders.Config
 }
 
 type pingStatsSource interface {
@@ -89,14 +94,15 @@ type Endpoint struct {
 	store        *pieces.Store
 	orders       orders.DB
 	usage        bandwidth.DB
-	usedSerials  *usedserials.Table
+	usedSerials  usedserials.Store
 	pieceDeleter *pieces.Deleter
+	scheduler    *ioscheduler.Scheduler
 
 	liveRequests int32
 }
 
 // NewEndpoint creates a new piecestore endpoint.
-func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, pieceDeleter *pieces.Deleter, orders orders.DB, usage bandwidth.DB, usedSerials *usedserials.Table, config Config) (*Endpoint, error) {
+func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, pieceDeleter *pieces.Deleter, orders orders.DB, usage bandwidth.DB, usedSerials usedserials.Store, scheduler *ioscheduler.Scheduler, config Config) (*Endpoint, error) {
 	return &Endpoint{
 		log:    log,
 		config: config,
@@ -112,6 +118,7 @@ func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, moni
 		usage:        usage,
 		usedSerials:  usedSerials,
 		pieceDeleter: pieceDeleter,
+		scheduler:    scheduler,
 
 		liveRequests: 0,
 	}, nil
@@ -227,7 +234,8 @@ func (endpoint *Endpoint) Upload(stream pb.DRPCPiecestore_UploadStream) (err err
 		return err
 	}
 
-	availableSpace, err := endpoint.monitor.AvailableSpace(ctx)
+	// the satellite may only use the space within its quota
+	availableSpace, err := endpoint.monitor.AvailableSpaceForSatellite(ctx, limit.SatelliteId)
 	if err != nil {
 		return rpcstatus.Wrap(rpcstatus.Internal, err)
 	}
@@ -387,7 +395,13 @@ func (endpoint *Endpoint) Upload(stream pb.DRPCPiecestore_UploadStream) (err err
 					Signature:    message.Done.GetSignature(),
 					OrderLimit:   *limit,
 				}
-				if err := pieceWriter.CommitWithWiscKey(ctx, info, chunkDataAll); err != nil {
+				release, err := endpoint.scheduler.Acquire(ctx, limit.SatelliteId, ioscheduler.ClassOf(limit.Action), pieceWriter.Size())
+				if err != nil {
+					return rpcstatus.Wrap(rpcstatus.Unavailable, err)
+				}
+				err = pieceWriter.CommitWithWiscKey(ctx, info, chunkDataAll)
+				release()
+				if err != nil {
 					return rpcstatus.Wrap(rpcstatus.Internal, err)
 				}
 				if !limit.PieceExpiration.IsZero() {
@@ -527,9 +541,9 @@ func (endpoint *Endpoint) Download(stream pb.DRPCPiecestore_DownloadStream) (err
 		}
 	}()
 
-	// for repair traffic, send along the PieceHash and original OrderLimit for validation
-	// before sending the piece itself
-	if message.Limit.Action == pb.PieceAction_GET_REPAIR {
+	// for repair and audit traffic, send along the PieceHash and original OrderLimit for
+	// validation before sending the piece itself, audits use them as a proof of storage
+	if message.Limit.Action == pb.PieceAction_GET_REPAIR || message.Limit.Action == pb.PieceAction_GET_AUDIT {
 		pieceHash, orderLimit, err := endpoint.store.GetHashAndLimitWithWiscKey(ctx, limit.SatelliteId, limit.PieceId, pieceReader)
 		if err != nil {
 			endpoint.log.Error("could not get hash and order limit", zap.Error(err))
@@ -552,16 +566,27 @@ func (endpoint *Endpoint) Download(stream pb.DRPCPiecestore_DownloadStream) (err
 			chunk.Offset+chunk.ChunkSize, pieceReader.Size())
 	}
 
+	release, err := endpoint.scheduler.Acquire(ctx, limit.SatelliteId, ioscheduler.ClassOf(limit.Action), chunk.ChunkSize)
+	if err != nil {
+		return rpcstatus.Wrap(rpcstatus.Unavailable, err)
+	}
+	// corrupted data is never sent, regardless of what the requester does with it
+	pieceData, err := pieceReader.ReadRangeWithWiscKey(chunk.Offset, chunk.ChunkSize)
+	release()
+	if err != nil {
+		if pieces.ErrCorrupted.Has(err) {
+			// the corrupted regions are part of the error, which is logged on return
+			mon.Meter("download_corrupted_piece").Mark(1)
+			return rpcstatus.Wrap(rpcstatus.DataLoss, err)
+		}
+		return rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+
 	throttle := sync2.NewThrottle()
 	// TODO: see whether this can be implemented without a goroutine
 
 	group, ctx := errgroup.WithContext(ctx)
 	group.Go(func() (err error) {
-		pieceData, err := pieceReader.ReadWithWiscKey()
-		if err != nil {
-			return rpcstatus.Wrap(rpcstatus.Internal, err)
-		}
-
 		var maximumChunkSize = 1 * memory.MiB.Int64()
 
 		currentOffset := chunk.Offset
@@ -580,7 +605,7 @@ func (endpoint *Endpoint) Download(stream pb.DRPCPiecestore_DownloadStream) (err
 				return stream.Send(&pb.PieceDownloadResponse{
 					Chunk: &pb.PieceDownloadResponse_Chunk{
 						Offset: currentOffset,
-						Data:   pieceData[currentOffset : currentOffset+chunkSize],
+						Data:   pieceData[currentOffset-chunk.Offset : currentOffset-chunk.Offset+chunkSize],
 					},
 				})
 			})
@@ -646,6 +671,98 @@ func (endpoint *Endpoint) Download(stream pb.DRPCPiecestore_DownloadStream) (err
 	return rpcstatus.Wrap(rpcstatus.Internal, errs.Combine(sendErr, recvErr))
 }
 
+// ProveRange sends a range proof of a piece to an auditing satellite: the state of the
+// piece hash up to the requested offset and the piece after it, signed by the node.
+func (endpoint *Endpoint) ProveRange(ctx context.Context, req *pieceproof.ProveRangeRequest) (_ *pieceproof.ProveRangeResponse, err error) {
+	defer monLiveRequests(&ctx)(&err)
+	defer mon.Task()(&ctx)(&err)
+
+	atomic.AddInt32(&endpoint.liveRequests, 1)
+	defer atomic.AddInt32(&endpoint.liveRequests, -1)
+
+	endpoint.pingStats.WasPinged(time.Now())
+
+	if req.Limit == nil || req.Order == nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "expected order limit and order")
+	}
+	limit := req.Limit
+	if limit.Action != pb.PieceAction_GET_AUDIT {
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "expected audit action got %v", limit.Action)
+	}
+	if req.Offset < 0 || req.Offset%pieceproof.BlockSize != 0 {
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "invalid offset %d", req.Offset)
+	}
+
+	if err := endpoint.verifyOrderLimit(ctx, limit); err != nil {
+		mon.Meter("prove_range_verify_orderlimit_failed").Mark(1)
+		return nil, err
+	}
+
+	pieceReader, err := endpoint.store.ReaderWithWiscKey(ctx, limit.SatelliteId, limit.PieceId)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, rpcstatus.Wrap(rpcstatus.NotFound, err)
+		}
+		return nil, rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+	defer func() {
+		err := pieceReader.CloseWithWiscKey()
+		if err != nil && !errs2.IsCanceled(err) {
+			endpoint.log.Error("failed to close piece reader", zap.Error(err))
+		}
+	}()
+
+	size := pieceReader.Size()
+	if req.Offset > size {
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument,
+			"offset beyond the piece, offset=%v size=%v", req.Offset, size)
+	}
+	// the order pays for the data which is sent, hashing the rest is part of the audit.
+	if err := endpoint.VerifyOrder(ctx, limit, req.Order, size-req.Offset); err != nil {
+		return nil, err
+	}
+
+	pieceHash, orderLimit, err := endpoint.store.GetHashAndLimitWithWiscKey(ctx, limit.SatelliteId, limit.PieceId, pieceReader)
+	if err != nil {
+		endpoint.log.Error("could not get hash and order limit", zap.Error(err))
+		return nil, rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+
+	release, err := endpoint.scheduler.Acquire(ctx, limit.SatelliteId, ioscheduler.ClassOf(limit.Action), size)
+	if err != nil {
+		return nil, rpcstatus.Wrap(rpcstatus.Unavailable, err)
+	}
+	pieceData, err := pieceReader.ReadRangeWithWiscKey(0, size)
+	release()
+	if err != nil {
+		if pieces.ErrCorrupted.Has(err) {
+			mon.Meter("prove_range_corrupted_piece").Mark(1)
+			return nil, rpcstatus.Wrap(rpcstatus.DataLoss, err)
+		}
+		return nil, rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+
+	state, err := pieceproof.HashState(bytes.NewReader(pieceData), req.Offset)
+	if err != nil {
+		return nil, rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+
+	proof := &pieceproof.ProveRangeResponse{
+		Hash:   &pieceHash,
+		Limit:  &orderLimit,
+		Offset: req.Offset,
+		State:  state,
+		Data:   pieceData[req.Offset:],
+	}
+	if err := pieceproof.Sign(ctx, endpoint.signer, limit.SerialNumber, proof); err != nil {
+		return nil, rpcstatus.Wrap(rpcstatus.Internal, err)
+	}
+
+	endpoint.saveOrder(ctx, limit, req.Order)
+	endpoint.log.Info("range proved", zap.Stringer("Piece ID", limit.PieceId), zap.Stringer("Satellite ID", limit.SatelliteId), zap.Int64("Offset", req.Offset))
+	return proof, nil
+}
+
 // saveOrder saves the order with all necessary information. It assumes it has been already verified.
 func (endpoint *Endpoint) saveOrder(ctx context.Context, limit *pb.OrderLimit, order *pb.Order) {
 	// We always want to save order to the database to be able to settle.
@@ -719,7 +836,7 @@ func (endpoint *Endpoint) Retain(ctx context.Context, retainReq *pb.RetainReques
 	}
 
 	// the queue function will update the created before time based on the configurable retain buffer
-	queued := endpoint.retain.Queue(retain.Request{
+	queued := endpoint.retain.Queue(ctx, retain.Request{
 		SatelliteID:   peer.ID,
 		CreatedBefore: retainReq.GetCreationDate(),
 		Filter:        filter,
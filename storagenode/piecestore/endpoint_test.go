@@ -29,6 +29,7 @@ import (
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/monitor"
 	"storj.io/uplink/private/piecestore"
 )
 
@@ -231,6 +232,72 @@ func TestUploadOverAvailable(t *testing.T) {
 	})
 }
 
+func TestUploadOverSatelliteQuota(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		node := planet.StorageNodes[0]
+
+		// the quota is only changed while the monitor isn't looking at it.
+		node.Storage2.Monitor.Loop.Pause()
+		node.Storage2.Monitor.Config.SatelliteQuotas = monitor.Quotas{
+			satellite.ID(): {Size: 15 * memory.KiB},
+		}
+
+		// the first piece fits into the quota
+		pieceID := testrand.PieceID()
+		uploadPiece(t, ctx, pieceID, node, planet.Uplinks[0], satellite)
+
+		// the second one doesn't, since the first one uses most of the quota
+		upload := func() error {
+			client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+			require.NoError(t, err)
+			defer ctx.Check(client.Close)
+
+			data := testrand.Bytes(10 * memory.KiB)
+			orderLimit, piecePrivateKey := GenerateOrderLimit(
+				t,
+				satellite.ID(),
+				node.ID(),
+				testrand.PieceID(),
+				pb.PieceAction_PUT,
+				testrand.SerialNumber(),
+				24*time.Hour,
+				24*time.Hour,
+				int64(len(data)),
+			)
+			signer := signing.SignerFromFullIdentity(satellite.Identity)
+			orderLimit, err = signing.SignOrderLimit(ctx, signer, orderLimit)
+			require.NoError(t, err)
+
+			uploader, err := client.Upload(ctx, orderLimit, piecePrivateKey)
+			require.NoError(t, err)
+
+			_, writeErr := uploader.Write(data)
+			_, err = uploader.Commit(ctx)
+			return errs.Combine(writeErr, err)
+		}
+
+		err := upload()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "out of space")
+
+		// deleting the first piece frees the quota again
+		conn, err := satellite.Dialer.DialNodeURL(ctx, node.NodeURL())
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+
+		_, err = pb.NewDRPCPiecestoreClient(conn).DeletePieces(ctx, &pb.DeletePiecesRequest{
+			PieceIds: []storj.PieceID{pieceID},
+		})
+		require.NoError(t, err)
+		planet.WaitForStorageNodeDeleters(ctx)
+
+		require.NoError(t, upload())
+	})
+}
+
 func TestDownload(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
@@ -302,7 +369,7 @@ func TestDownload(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			// these should only be not-nil if action = pb.PieceAction_GET_REPAIR
+			// these should only be not-nil if action = pb.PieceAction_GET_REPAIR or pb.PieceAction_GET_AUDIT
 			hash, originalLimit := downloader.GetHashAndLimit()
 			require.Nil(t, hash)
 			require.Nil(t, originalLimit)
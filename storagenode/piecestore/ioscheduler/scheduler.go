@@ -0,0 +1,377 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ioscheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default error class for the io scheduler.
+	Error = errs.Class("io scheduler")
+)
+
+// Config defines parameters for the io scheduler.
+type Config struct {
+	MaxConcurrentIO    int           `help:"how many piece reads and writes may happen at the same time. 0 represents unlimited." default:"0"`
+	GetWeight          int           `help:"relative share of disk io given to customer downloads" default:"8"`
+	PutWeight          int           `help:"relative share of disk io given to customer uploads" default:"8"`
+	AuditWeight        int           `help:"relative share of disk io given to audits" default:"16"`
+	RepairWeight       int           `help:"relative share of disk io given to repair and graceful exit traffic" default:"4"`
+	SatelliteRateLimit memory.Size   `help:"maximum bytes per second read or written for a single satellite. 0 represents unlimited." default:"0B"`
+	BackgroundMaxDelay time.Duration `help:"how long background chores wait for foreground traffic to finish before doing io anyway" default:"1s"`
+	BackgroundSlice    time.Duration `help:"how long background chores work between giving way to foreground traffic" default:"250ms"`
+}
+
+// Class is the kind of traffic an io request belongs to.
+type Class int
+
+const (
+	// ClassGet is customer downloads.
+	ClassGet Class = iota
+	// ClassPut is customer uploads.
+	ClassPut
+	// ClassAudit is audit downloads.
+	ClassAudit
+	// ClassRepair is repair and graceful exit traffic.
+	ClassRepair
+	// ClassBackground is io done by background chores, such as retain or
+	// the space used recalculation. It only runs when no foreground traffic
+	// is waiting, or once it has waited for BackgroundMaxDelay.
+	ClassBackground
+
+	numClasses = int(ClassBackground) + 1
+)
+
+// String returns the name of the class.
+func (class Class) String() string {
+	switch class {
+	case ClassGet:
+		return "get"
+	case ClassPut:
+		return "put"
+	case ClassAudit:
+		return "audit"
+	case ClassRepair:
+		return "repair"
+	case ClassBackground:
+		return "background"
+	default:
+		return "invalid"
+	}
+}
+
+// ClassOf returns the class of requests with the given action.
+func ClassOf(action pb.PieceAction) Class {
+	switch action {
+	case pb.PieceAction_GET:
+		return ClassGet
+	case pb.PieceAction_PUT:
+		return ClassPut
+	case pb.PieceAction_GET_AUDIT:
+		return ClassAudit
+	case pb.PieceAction_GET_REPAIR, pb.PieceAction_PUT_REPAIR, pb.PieceAction_PUT_GRACEFUL_EXIT:
+		return ClassRepair
+	default:
+		return ClassBackground
+	}
+}
+
+// waiter is a request waiting for its turn to do io.
+type waiter struct {
+	class Class
+	// start and finish are the virtual times used for start-time fair queuing.
+	start  float64
+	finish float64
+	// overdue is set for background requests which have waited for too long.
+	overdue bool
+
+	granted bool
+	ready   chan struct{}
+}
+
+// Scheduler decides the order in which piece reads and writes access the disk.
+//
+// Foreground requests are served with weighted fair queuing between their
+// classes, so that no kind of traffic can starve the others. Background
+// requests yield to all foreground traffic.
+//
+// architecture: Service
+type Scheduler struct {
+	log    *zap.Logger
+	config Config
+
+	weights [numClasses]float64
+
+	mu               sync.Mutex
+	virtualTime      float64
+	lastFinish       [numClasses]float64
+	foreground       []*waiter
+	background       []*waiter
+	active           int
+	activeForeground int
+
+	limitersMu sync.Mutex
+	limiters   map[storj.NodeID]*rate.Limiter
+}
+
+// New creates a new io scheduler.
+func New(log *zap.Logger, config Config) *Scheduler {
+	scheduler := &Scheduler{
+		log:      log,
+		config:   config,
+		limiters: make(map[storj.NodeID]*rate.Limiter),
+	}
+
+	scheduler.weights[ClassGet] = float64(config.GetWeight)
+	scheduler.weights[ClassPut] = float64(config.PutWeight)
+	scheduler.weights[ClassAudit] = float64(config.AuditWeight)
+	scheduler.weights[ClassRepair] = float64(config.RepairWeight)
+	scheduler.weights[ClassBackground] = 1
+	for class, weight := range scheduler.weights {
+		if weight <= 0 {
+			scheduler.weights[class] = 1
+		}
+	}
+
+	return scheduler
+}
+
+// Acquire waits until a request of the given class may read or write size bytes of a
+// piece belonging to the satellite. The returned function must be called once the io
+// is done.
+func (scheduler *Scheduler) Acquire(ctx context.Context, satellite storj.NodeID, class Class, size int64) (release func(), err error) {
+	defer mon.Task()(&ctx, class.String())(&err)
+
+	if err := scheduler.waitRate(ctx, satellite, size); err != nil {
+		return nil, err
+	}
+	return scheduler.acquire(ctx, class, size)
+}
+
+// Yield waits until background chores may do some io. Chores call it between
+// units of work, to give way to foreground traffic.
+func (scheduler *Scheduler) Yield(ctx context.Context) error {
+	release, err := scheduler.acquire(ctx, ClassBackground, 0)
+	if err != nil {
+		return err
+	}
+	release()
+	return nil
+}
+
+// Yielder returns a Yielder for a single background chore.
+func (scheduler *Scheduler) Yielder() *Yielder {
+	return &Yielder{scheduler: scheduler}
+}
+
+// Yielder gives way to foreground traffic once per BackgroundSlice of work, so
+// that chores walking many pieces don't wait for every single piece.
+//
+// It's not safe for concurrent use.
+type Yielder struct {
+	scheduler *Scheduler
+	last      time.Time
+}
+
+// Yield waits until background chores may do some io, when the chore has worked
+// for at least BackgroundSlice since it last gave way.
+func (yielder *Yielder) Yield(ctx context.Context) error {
+	if !yielder.last.IsZero() && time.Since(yielder.last) < yielder.scheduler.config.BackgroundSlice {
+		return ctx.Err()
+	}
+	if err := yielder.scheduler.Yield(ctx); err != nil {
+		return err
+	}
+	yielder.last = time.Now()
+	return nil
+}
+
+// acquire waits for the turn of the request.
+func (scheduler *Scheduler) acquire(ctx context.Context, class Class, size int64) (release func(), err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	startWait := time.Now()
+	w := &waiter{
+		class: class,
+		ready: make(chan struct{}),
+	}
+
+	scheduler.mu.Lock()
+	if class == ClassBackground {
+		scheduler.background = append(scheduler.background, w)
+	} else {
+		// the minimum cost keeps zero sized requests fairly ordered as well.
+		cost := float64(size)
+		if cost < 1 {
+			cost = 1
+		}
+		w.start = scheduler.virtualTime
+		if scheduler.lastFinish[class] > w.start {
+			w.start = scheduler.lastFinish[class]
+		}
+		w.finish = w.start + cost/scheduler.weights[class]
+		scheduler.lastFinish[class] = w.finish
+		scheduler.foreground = append(scheduler.foreground, w)
+	}
+	scheduler.dispatch()
+	scheduler.mu.Unlock()
+
+	var overdue <-chan time.Time
+	if class == ClassBackground {
+		timer := time.NewTimer(scheduler.config.BackgroundMaxDelay)
+		defer timer.Stop()
+		overdue = timer.C
+	}
+
+	for {
+		select {
+		case <-w.ready:
+			mon.IntVal("io_scheduler_wait_ns").Observe(time.Since(startWait).Nanoseconds())
+			var once sync.Once
+			return func() {
+				once.Do(func() { scheduler.release(w) })
+			}, nil
+
+		case <-overdue:
+			overdue = nil
+			scheduler.mu.Lock()
+			w.overdue = true
+			scheduler.dispatch()
+			scheduler.mu.Unlock()
+
+		case <-ctx.Done():
+			scheduler.mu.Lock()
+			if w.granted {
+				scheduler.mu.Unlock()
+				scheduler.release(w)
+			} else {
+				scheduler.remove(w)
+				scheduler.mu.Unlock()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// release gives the slot of the request to the next waiting request.
+func (scheduler *Scheduler) release(w *waiter) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	scheduler.active--
+	if w.class != ClassBackground {
+		scheduler.activeForeground--
+	}
+	scheduler.dispatch()
+}
+
+// dispatch grants free slots to waiting requests, requires mutex to be held.
+//
+// Overdue background requests go first, since they have already given way for
+// long enough. Then foreground requests are served in the order of their virtual
+// finish time. The rest of the background requests only run when there's no
+// foreground traffic at all.
+func (scheduler *Scheduler) dispatch() {
+	for i := 0; i < len(scheduler.background) && scheduler.hasSlot(); {
+		if w := scheduler.background[i]; w.overdue {
+			scheduler.background = append(scheduler.background[:i], scheduler.background[i+1:]...)
+			scheduler.grant(w)
+			continue
+		}
+		i++
+	}
+
+	for len(scheduler.foreground) > 0 && scheduler.hasSlot() {
+		next := 0
+		for i, w := range scheduler.foreground {
+			if w.finish < scheduler.foreground[next].finish {
+				next = i
+			}
+		}
+		w := scheduler.foreground[next]
+		scheduler.foreground = append(scheduler.foreground[:next], scheduler.foreground[next+1:]...)
+		scheduler.virtualTime = w.start
+		scheduler.grant(w)
+	}
+
+	for len(scheduler.background) > 0 && scheduler.hasSlot() && scheduler.activeForeground == 0 && len(scheduler.foreground) == 0 {
+		w := scheduler.background[0]
+		scheduler.background = scheduler.background[1:]
+		scheduler.grant(w)
+	}
+}
+
+// hasSlot returns whether another request may do io, requires mutex to be held.
+func (scheduler *Scheduler) hasSlot() bool {
+	return scheduler.config.MaxConcurrentIO <= 0 || scheduler.active < scheduler.config.MaxConcurrentIO
+}
+
+// grant lets the waiting request do io, requires mutex to be held.
+func (scheduler *Scheduler) grant(w *waiter) {
+	scheduler.active++
+	if w.class != ClassBackground {
+		scheduler.activeForeground++
+	}
+	w.granted = true
+	close(w.ready)
+}
+
+// remove drops a request which stopped waiting, requires mutex to be held.
+func (scheduler *Scheduler) remove(w *waiter) {
+	queue := &scheduler.foreground
+	if w.class == ClassBackground {
+		queue = &scheduler.background
+	}
+	for i, other := range *queue {
+		if other == w {
+			*queue = append((*queue)[:i], (*queue)[i+1:]...)
+			return
+		}
+	}
+}
+
+// waitRate waits until the satellite is allowed to transfer size more bytes.
+func (scheduler *Scheduler) waitRate(ctx context.Context, satellite storj.NodeID, size int64) error {
+	limit := scheduler.config.SatelliteRateLimit.Int64()
+	if limit <= 0 || size <= 0 {
+		return nil
+	}
+
+	scheduler.limitersMu.Lock()
+	limiter, ok := scheduler.limiters[satellite]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit), int(limit))
+		scheduler.limiters[satellite] = limiter
+	}
+	scheduler.limitersMu.Unlock()
+
+	// WaitN refuses to wait for more than the burst at once.
+	for size > 0 {
+		n := size
+		if n > limit {
+			n = limit
+		}
+		if err := limiter.WaitN(ctx, int(n)); err != nil {
+			return Error.Wrap(err)
+		}
+		size -= n
+	}
+	return nil
+}
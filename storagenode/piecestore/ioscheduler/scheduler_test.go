@@ -0,0 +1,162 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ioscheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+)
+
+func TestWeightedFairQueuing(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	scheduler := New(zaptest.NewLogger(t), Config{
+		MaxConcurrentIO: 1,
+		GetWeight:       1,
+		AuditWeight:     2,
+	})
+	satellite := testrand.NodeID()
+
+	// occupy the only slot, so that everything else has to queue up.
+	release, err := scheduler.acquire(ctx, ClassBackground, 0)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []Class
+
+	var group sync.WaitGroup
+	queued := 0
+	queue := func(class Class) {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			release, err := scheduler.Acquire(ctx, satellite, class, 100)
+			if !assert.NoError(t, err) {
+				return
+			}
+			mu.Lock()
+			order = append(order, class)
+			mu.Unlock()
+			release()
+		}()
+
+		// wait until the request is queued, to have a deterministic order.
+		queued++
+		require.Eventually(t, func() bool {
+			scheduler.mu.Lock()
+			defer scheduler.mu.Unlock()
+			return len(scheduler.foreground) == queued
+		}, 5*time.Second, time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		queue(ClassGet)
+	}
+	for i := 0; i < 4; i++ {
+		queue(ClassAudit)
+	}
+
+	release()
+	group.Wait()
+
+	// audits have twice the weight, so they get twice the turns.
+	require.Equal(t, []Class{
+		ClassAudit, ClassGet, ClassAudit, ClassAudit,
+		ClassGet, ClassAudit, ClassGet, ClassGet,
+	}, order)
+}
+
+func TestBackgroundYields(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	scheduler := New(zaptest.NewLogger(t), Config{
+		BackgroundMaxDelay: time.Hour,
+	})
+
+	release, err := scheduler.Acquire(ctx, testrand.NodeID(), ClassGet, memory.KiB.Int64())
+	require.NoError(t, err)
+
+	yielded := make(chan error, 1)
+	go func() { yielded <- scheduler.Yield(ctx) }()
+
+	select {
+	case err := <-yielded:
+		t.Fatalf("background io ran during foreground io: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	release()
+	require.NoError(t, <-yielded)
+
+	// background io doesn't wait for longer than the maximum delay.
+	scheduler = New(zaptest.NewLogger(t), Config{
+		BackgroundMaxDelay: 10 * time.Millisecond,
+	})
+
+	release, err = scheduler.Acquire(ctx, testrand.NodeID(), ClassGet, memory.KiB.Int64())
+	require.NoError(t, err)
+	defer release()
+
+	require.NoError(t, scheduler.Yield(ctx))
+}
+
+func TestYielderSlice(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	scheduler := New(zaptest.NewLogger(t), Config{
+		BackgroundMaxDelay: 50 * time.Millisecond,
+		BackgroundSlice:    time.Hour,
+	})
+
+	release, err := scheduler.Acquire(ctx, testrand.NodeID(), ClassGet, memory.KiB.Int64())
+	require.NoError(t, err)
+	defer release()
+
+	// only the first call of the slice gives way to the foreground io.
+	yielder := scheduler.Yielder()
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, yielder.Yield(ctx))
+	}
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 50*time.Millisecond, elapsed)
+	assert.True(t, elapsed < time.Second, elapsed)
+}
+
+func TestSatelliteRateLimit(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	scheduler := New(zaptest.NewLogger(t), Config{
+		SatelliteRateLimit: memory.KiB,
+	})
+	satellite := testrand.NodeID()
+
+	release, err := scheduler.Acquire(ctx, satellite, ClassGet, memory.KiB.Int64())
+	require.NoError(t, err)
+	release()
+
+	// the satellite used up its bytes for the next second.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = scheduler.Acquire(timeoutCtx, satellite, ClassGet, memory.KiB.Int64())
+	require.Error(t, err)
+
+	// other satellites are not affected.
+	release, err = scheduler.Acquire(ctx, testrand.NodeID(), ClassGet, memory.KiB.Int64())
+	require.NoError(t, err)
+	release()
+}
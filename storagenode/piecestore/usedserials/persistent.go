@@ -0,0 +1,223 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package usedserials
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+)
+
+// Store keeps track of serial numbers that have already been used.
+type Store interface {
+	// Add adds a serial to the store, or returns an error if the serial number was already added.
+	Add(satelliteID storj.NodeID, serialNumber storj.SerialNumber, expiration time.Time) error
+	// DeleteExpired deletes expired serial numbers if their expiration hour has passed.
+	DeleteExpired(now time.Time)
+	// Count returns the number of serials in the store.
+	Count() int
+}
+
+var _ Store = (*Table)(nil)
+var _ Store = (*PersistentTable)(nil)
+
+const (
+	// keySize is the size of a persistent key: satellite ID, expiration hour and serial number.
+	keySize = len(storj.NodeID{}) + 8 + len(storj.SerialNumber{})
+
+	// deleteBatchSize is the number of expired keys deleted in a single write batch.
+	deleteBatchSize = 1000
+)
+
+// PersistentTable is an on-disk store for serial numbers backed by badger.
+//
+// Keys are ordered by satellite ID, expiration hour and serial number, and
+// every key is written with a TTL matching its expiration hour, so serials
+// survive restarts and are never dropped before they expire.
+type PersistentTable struct {
+	log *zap.Logger
+	db  *badger.DB
+}
+
+// OpenPersistentTable opens or creates the used serials store in dir.
+func OpenPersistentTable(log *zap.Logger, dir string) (*PersistentTable, error) {
+	options := badger.DefaultOptions(dir).
+		WithLogger(badgerLogger{log.Sugar()}).
+		WithMaxTableSize(8 << 20).
+		WithValueLogFileSize(64 << 20).
+		WithNumMemtables(2)
+
+	db, err := badger.Open(options)
+	if err != nil {
+		return nil, ErrSerials.Wrap(err)
+	}
+
+	return &PersistentTable{
+		log: log,
+		db:  db,
+	}, nil
+}
+
+// Close closes the underlying database.
+func (table *PersistentTable) Close() error {
+	return ErrSerials.Wrap(table.db.Close())
+}
+
+// Add adds a serial to the store, or returns an error if the serial number was already added.
+func (table *PersistentTable) Add(satelliteID storj.NodeID, serialNumber storj.SerialNumber, expiration time.Time) error {
+	expirationHour := ceilExpirationHour(expiration)
+	key := persistentKey(satelliteID, expirationHour, serialNumber)
+
+	err := table.db.Update(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		switch {
+		case err == nil:
+			return ErrSerialAlreadyExists.New("")
+		case !errors.Is(err, badger.ErrKeyNotFound):
+			return err
+		}
+
+		entry := badger.NewEntry(key, nil)
+		if ttl := time.Until(time.Unix(expirationHour, 0)); ttl > 0 {
+			entry = entry.WithTTL(ttl)
+		}
+		return txn.SetEntry(entry)
+	})
+	switch {
+	case ErrSerialAlreadyExists.Has(err):
+		return err
+	case errors.Is(err, badger.ErrConflict):
+		// the only key touched by the transaction is the serial itself,
+		// so a conflict means it was added concurrently.
+		return ErrSerialAlreadyExists.New("")
+	}
+	return ErrSerials.Wrap(err)
+}
+
+// Exists determines whether a serial number exists in the store.
+func (table *PersistentTable) Exists(satelliteID storj.NodeID, serialNumber storj.SerialNumber, expiration time.Time) (exists bool, err error) {
+	key := persistentKey(satelliteID, ceilExpirationHour(expiration), serialNumber)
+
+	err = table.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		exists = err == nil
+		return err
+	})
+	return exists, ErrSerials.Wrap(err)
+}
+
+// DeleteExpired deletes expired serial numbers if their expiration hour has passed.
+//
+// Keys whose TTL has passed are already hidden and dropped during compaction,
+// this removes the rest and reclaims value log space.
+func (table *PersistentTable) DeleteExpired(now time.Time) {
+	if err := table.deleteExpired(now); err != nil {
+		table.log.Error("unable to delete expired serials", zap.Error(err))
+	}
+}
+
+func (table *PersistentTable) deleteExpired(now time.Time) error {
+	var expired [][]byte
+	err := table.db.View(func(txn *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.PrefetchValues = false
+
+		it := txn.NewIterator(options)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().Key()
+			if len(key) != keySize {
+				continue
+			}
+			if persistentKeyHour(key) < now.Unix() {
+				expired = append(expired, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ErrSerials.Wrap(err)
+	}
+
+	for len(expired) > 0 {
+		batch := expired
+		if len(batch) > deleteBatchSize {
+			batch = batch[:deleteBatchSize]
+		}
+		expired = expired[len(batch):]
+
+		wb := table.db.NewWriteBatch()
+		for _, key := range batch {
+			if err := wb.Delete(key); err != nil {
+				wb.Cancel()
+				return ErrSerials.Wrap(err)
+			}
+		}
+		if err := wb.Flush(); err != nil {
+			return ErrSerials.Wrap(err)
+		}
+	}
+
+	err = table.db.RunValueLogGC(0.5)
+	if err != nil && !errors.Is(err, badger.ErrNoRewrite) {
+		return ErrSerials.Wrap(err)
+	}
+	return nil
+}
+
+// Count iterates over all the items in the store and returns the number.
+func (table *PersistentTable) Count() int {
+	count := 0
+	err := table.db.View(func(txn *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.PrefetchValues = false
+
+		it := txn.NewIterator(options)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		table.log.Error("unable to count serials", zap.Error(err))
+	}
+	return count
+}
+
+// persistentKey creates the key for a serial number ordered by satellite and expiration hour.
+func persistentKey(satelliteID storj.NodeID, expirationHour int64, serialNumber storj.SerialNumber) []byte {
+	key := make([]byte, 0, keySize)
+	key = append(key, satelliteID[:]...)
+	key = append(key, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(satelliteID):], uint64(expirationHour))
+	key = append(key, serialNumber[:]...)
+	return key
+}
+
+// persistentKeyHour returns the expiration hour stored in a persistent key.
+func persistentKeyHour(key []byte) int64 {
+	offset := len(storj.NodeID{})
+	return int64(binary.BigEndian.Uint64(key[offset : offset+8]))
+}
+
+// badgerLogger adapts zap to the badger logger interface.
+type badgerLogger struct {
+	*zap.SugaredLogger
+}
+
+// Warningf logs a warning message.
+func (logger badgerLogger) Warningf(template string, args ...interface{}) {
+	logger.Warnf(template, args...)
+}
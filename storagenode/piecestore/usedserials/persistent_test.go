@@ -0,0 +1,67 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package usedserials_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode/piecestore/usedserials"
+)
+
+func TestPersistentUsedSerials(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("usedserials")
+	log := zaptest.NewLogger(t)
+
+	table, err := usedserials.OpenPersistentTable(log, dir)
+	require.NoError(t, err)
+
+	now := time.Now()
+	satellite := testrand.NodeID()
+	serial1 := testrand.SerialNumber()
+	serial2 := testrand.SerialNumber()
+
+	require.NoError(t, table.Add(satellite, serial1, now.Add(time.Hour)))
+	require.NoError(t, table.Add(satellite, serial2, now.Add(8*time.Hour)))
+
+	err = table.Add(satellite, serial1, now.Add(time.Hour))
+	require.True(t, usedserials.ErrSerialAlreadyExists.Has(err))
+
+	// the same serial from another satellite is a different serial
+	require.NoError(t, table.Add(testrand.NodeID(), serial1, now.Add(time.Hour)))
+	require.Equal(t, 3, table.Count())
+
+	// serials survive a restart
+	require.NoError(t, table.Close())
+	table, err = usedserials.OpenPersistentTable(log, dir)
+	require.NoError(t, err)
+	defer ctx.Check(table.Close)
+
+	require.Equal(t, 3, table.Count())
+	exists, err := table.Exists(satellite, serial2, now.Add(8*time.Hour))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	err = table.Add(satellite, serial2, now.Add(8*time.Hour))
+	require.True(t, usedserials.ErrSerialAlreadyExists.Has(err))
+
+	// only serials with a passed expiration hour are deleted
+	table.DeleteExpired(now.Add(2 * time.Hour))
+	require.Equal(t, 1, table.Count())
+
+	exists, err = table.Exists(satellite, serial1, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	table.DeleteExpired(now.Add(10 * time.Hour))
+	require.Zero(t, table.Count())
+}
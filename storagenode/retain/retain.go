@@ -4,6 +4,7 @@
 package retain
 
 import (
+	"bytes"
 	"context"
 	"runtime"
 	"sync"
@@ -15,8 +16,11 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/bloomfilter"
+	"storj.io/common/context2"
 	"storj.io/common/storj"
+	"storj.io/storj/storage/filestore"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
 )
 
 var (
@@ -31,6 +35,8 @@ type Config struct {
 	MaxTimeSkew time.Duration `help:"allows for small differences in the satellite and storagenode clocks" default:"72h0m0s"`
 	Status      Status        `help:"allows configuration to enable, disable, or test retain requests from the satellite. Options: (disabled/enabled/debug)" default:"enabled"`
 	Concurrency int           `help:"how many concurrent retain requests can be processed at the same time." default:"5"`
+
+	CheckpointInterval int `help:"how many pieces to check between saving the progress of a retain request" default:"1000"`
 }
 
 // Request contains all the info necessary to process a retain request.
@@ -38,6 +44,48 @@ type Request struct {
 	SatelliteID   storj.NodeID
 	CreatedBefore time.Time
 	Filter        *bloomfilter.Filter
+
+	// Progress is where processing of the request continues from.
+	Progress Progress
+}
+
+// Progress is the saved state of a partially processed retain request.
+//
+// Pieces stored with filestore.FormatV1 or greater are walked in ascending piece ID order,
+// so everything up to and including LastPieceID has already been checked. V0 pieces are
+// walked afterwards and are always checked again.
+type Progress struct {
+	LastPieceID   storj.PieceID
+	PiecesChecked int64
+	PiecesTrashed int64
+	BytesTrashed  int64
+}
+
+// Summary describes the latest retain request from a satellite.
+type Summary struct {
+	SatelliteID   storj.NodeID
+	CreatedBefore time.Time
+	ReceivedAt    time.Time
+	// FinishedAt is nil while the request is still being processed.
+	FinishedAt *time.Time
+
+	Progress
+}
+
+// DB persists retain requests and their progress, so that they survive restarts.
+//
+// architecture: Database
+type DB interface {
+	// Queue stores the request, replacing any earlier request from the same satellite.
+	Queue(ctx context.Context, req Request, receivedAt time.Time) error
+	// Pending returns all requests which haven't finished yet.
+	Pending(ctx context.Context) ([]Request, error)
+	// Checkpoint stores the progress of the request.
+	Checkpoint(ctx context.Context, req Request) error
+	// Finish stores the final progress of the request and drops its bloom filter.
+	Finish(ctx context.Context, req Request, finishedAt time.Time) error
+	// Summaries returns the summary of the latest request from every satellite.
+	Summaries(ctx context.Context) ([]Summary, error)
 }
 
 // Status is a type defining the enabled/disabled status of retain requests.
@@ -86,6 +134,10 @@ func (v *Status) String() string {
 
 // Service queues and processes retain requests from satellites.
 //
+// Only the pieces in the blob store are processed. Pieces in the WiscKey piece data store
+// aren't garbage collected by retain: their keys don't include the satellite, so finding the
+// pieces of a satellite needs the header of every piece, and there is no trash to move them to.
+//
 // architecture: Worker
 type Service struct {
 	log    *zap.Logger
@@ -100,11 +152,13 @@ type Service struct {
 	closed     chan struct{}
 	started    bool
 
-	store *pieces.Store
+	store     *pieces.Store
+	db        DB
+	scheduler *ioscheduler.Scheduler
 }
 
-// NewService creates a new retain service.
-func NewService(log *zap.Logger, store *pieces.Store, config Config) *Service {
+// NewService creates a new retain service. Processing yields to foreground traffic using the scheduler.
+func NewService(log *zap.Logger, store *pieces.Store, db DB, scheduler *ioscheduler.Scheduler, config Config) *Service {
 	return &Service{
 		log:    log,
 		config: config,
@@ -114,14 +168,34 @@ func NewService(log *zap.Logger, store *pieces.Store, config Config) *Service {
 		working: make(map[storj.NodeID]struct{}),
 		closed:  make(chan struct{}),
 
-		store: store,
+		store:     store,
+		db:        db,
+		scheduler: scheduler,
 	}
 }
 
-// Queue adds a retain request to the queue.
-// It discards a request for a satellite that already has a queued request.
+// Queue adds a retain request to the queue and saves it, so it can be resumed after a restart.
+// It replaces the request for a satellite that already has a queued request.
 // true is returned if the request is queued and false is returned if it is discarded
-func (s *Service) Queue(req Request) bool {
+func (s *Service) Queue(ctx context.Context, req Request) bool {
+	select {
+	case <-s.closed:
+		return false
+	default:
+	}
+
+	// the request is saved before taking the lock, so the workers aren't
+	// blocked on the database.
+	req.Progress = Progress{}
+	if s.config.Status != Disabled {
+		if err := s.db.Queue(ctx, req, time.Now()); err != nil {
+			// the request can still be processed, it just won't survive a restart.
+			s.log.Warn("failed to save retain request",
+				zap.Stringer("Satellite ID", req.SatelliteID),
+				zap.Error(err))
+		}
+	}
+
 	s.cond.L.Lock()
 	defer s.cond.L.Unlock()
 
@@ -160,6 +234,20 @@ func (s *Service) Run(ctx context.Context) error {
 	default:
 	}
 
+	// Resume the requests which were interrupted by a restart, unless newer
+	// requests from the same satellites have already been queued.
+	if s.config.Status != Disabled {
+		pending, err := s.db.Pending(ctx)
+		if err != nil {
+			s.log.Error("failed to load pending retain requests", zap.Error(err))
+		}
+		for _, request := range pending {
+			if _, ok := s.queued[request.SatelliteID]; !ok {
+				s.queued[request.SatelliteID] = request
+			}
+		}
+	}
+
 	// Create a sub-context that we can cancel.
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -295,6 +383,13 @@ func (s *Service) Status() Status {
 	return s.config.Status
 }
 
+// Summaries returns the summary of the latest retain request from every satellite.
+func (s *Service) Summaries(ctx context.Context) (_ []Summary, err error) {
+	defer mon.Task()(&ctx)(&err)
+	summaries, err := s.db.Summaries(ctx)
+	return summaries, Error.Wrap(err)
+}
+
 // ------------------------------------------------------------------------------------------------
 // On the correctness of using access.ModTime() in place of the more precise access.CreationTime()
 // in retainPieces():
@@ -349,9 +444,10 @@ func (s *Service) retainPieces(ctx context.Context, req Request) (err error) {
 
 	defer mon.Task()(&ctx, req.SatelliteID, req.CreatedBefore, req.Filter.Size())(&err)
 
-	numDeleted := 0
 	satelliteID := req.SatelliteID
 	filter := req.Filter
+	progress := req.Progress
+	resuming := !progress.LastPieceID.IsZero()
 
 	// subtract some time to leave room for clock difference between the satellite and storage node
 	createdBefore := req.CreatedBefore.Add(-s.config.MaxTimeSkew)
@@ -359,41 +455,55 @@ func (s *Service) retainPieces(ctx context.Context, req Request) (err error) {
 	s.log.Debug("Prepared to run a Retain request.",
 		zap.Time("Created Before", createdBefore),
 		zap.Int64("Filter Size", filter.Size()),
-		zap.Stringer("Satellite ID", satelliteID))
+		zap.Stringer("Satellite ID", satelliteID),
+		zap.Stringer("Resume After", progress.LastPieceID))
+
+	// checkpoint saves the progress even when ctx is canceled, so that the request
+	// can continue where it stopped.
+	checkpoint := func() {
+		req.Progress = progress
+		if err := s.db.Checkpoint(context2.WithoutCancellation(ctx), req); err != nil {
+			s.log.Warn("failed to save retain progress",
+				zap.Stringer("Satellite ID", satelliteID),
+				zap.Error(err))
+		}
+	}
 
+	sinceCheckpoint := 0
+	yielder := s.scheduler.Yielder()
 	err = s.store.WalkSatellitePieces(ctx, satelliteID, func(access pieces.StoredPieceAccess) error {
 		// We call Gosched() when done because the GC process is expected to be long and we want to keep it at low priority,
 		// so other goroutines can continue serving requests.
 		defer runtime.Gosched()
-		// See the comment above the retainPieces() function for a discussion on the correctness
-		// of using ModTime in place of the more precise CreationTime.
-		mTime, err := access.ModTime(ctx)
-		if err != nil {
-			s.log.Warn("failed to determine mtime of blob", zap.Error(err))
-			// but continue iterating.
-			return nil
+
+		// give way to uploads and downloads, so that retain doesn't slow them down.
+		if err := yielder.Yield(ctx); err != nil {
+			return err
 		}
-		if !mTime.Before(createdBefore) {
+
+		pieceID := access.PieceID()
+		ordered := access.StorageFormatVersion() >= filestore.FormatV1
+		if ordered && resuming && bytes.Compare(pieceID.Bytes(), progress.LastPieceID.Bytes()) <= 0 {
+			// already checked before the request was interrupted.
 			return nil
 		}
-		pieceID := access.PieceID()
-		if !filter.Contains(pieceID) {
-			s.log.Debug("About to delete piece id",
-				zap.Stringer("Satellite ID", satelliteID),
-				zap.Stringer("Piece ID", pieceID),
-				zap.String("Status", s.config.Status.String()))
-
-			// if retain status is enabled, delete pieceid
-			if s.config.Status == Enabled {
-				if err = s.store.Trash(ctx, satelliteID, pieceID); err != nil {
-					s.log.Warn("failed to delete piece",
-						zap.Stringer("Satellite ID", satelliteID),
-						zap.Stringer("Piece ID", pieceID),
-						zap.Error(err))
-					return nil
-				}
+
+		trashed, size := s.retainPiece(ctx, satelliteID, createdBefore, filter, access)
+		progress.PiecesChecked++
+		if trashed {
+			progress.PiecesTrashed++
+			progress.BytesTrashed += size
+		}
+
+		// V0 pieces are not walked in order and are always checked again, so only
+		// the V1 pieces move the checkpoint.
+		if ordered {
+			progress.LastPieceID = pieceID
+			sinceCheckpoint++
+			if s.config.CheckpointInterval > 0 && sinceCheckpoint >= s.config.CheckpointInterval {
+				checkpoint()
+				sinceCheckpoint = 0
 			}
-			numDeleted++
 		}
 
 		select {
@@ -405,10 +515,68 @@ func (s *Service) retainPieces(ctx context.Context, req Request) (err error) {
 		return nil
 	})
 	if err != nil {
+		checkpoint()
 		return Error.Wrap(err)
 	}
-	mon.IntVal("garbage_collection_pieces_deleted").Observe(int64(numDeleted))
-	s.log.Debug("Deleted pieces during retain", zap.Int("num deleted", numDeleted), zap.String("Retain Status", s.config.Status.String()))
+
+	req.Progress = progress
+	if err := s.db.Finish(ctx, req, time.Now()); err != nil {
+		s.log.Warn("failed to save retain summary",
+			zap.Stringer("Satellite ID", satelliteID),
+			zap.Error(err))
+	}
+
+	mon.IntVal("garbage_collection_pieces_deleted").Observe(progress.PiecesTrashed)
+	s.log.Debug("Deleted pieces during retain",
+		zap.Int64("num checked", progress.PiecesChecked),
+		zap.Int64("num deleted", progress.PiecesTrashed),
+		zap.Int64("bytes deleted", progress.BytesTrashed),
+		zap.String("Retain Status", s.config.Status.String()))
 
 	return nil
 }
+
+// retainPiece trashes the piece if it is old enough and missing from the filter. It returns
+// whether the piece was trashed, or would have been in debug mode, and the size of the piece.
+func (s *Service) retainPiece(ctx context.Context, satelliteID storj.NodeID, createdBefore time.Time, filter *bloomfilter.Filter, access pieces.StoredPieceAccess) (trashed bool, size int64) {
+	// See the comment above the retainPieces() function for a discussion on the correctness
+	// of using ModTime in place of the more precise CreationTime.
+	mTime, err := access.ModTime(ctx)
+	if err != nil {
+		s.log.Warn("failed to determine mtime of blob", zap.Error(err))
+		// but continue iterating.
+		return false, 0
+	}
+	if !mTime.Before(createdBefore) {
+		return false, 0
+	}
+	pieceID := access.PieceID()
+	if filter.Contains(pieceID) {
+		return false, 0
+	}
+
+	s.log.Debug("About to delete piece id",
+		zap.Stringer("Satellite ID", satelliteID),
+		zap.Stringer("Piece ID", pieceID),
+		zap.String("Status", s.config.Status.String()))
+
+	size, _, err = access.Size(ctx)
+	if err != nil {
+		s.log.Warn("failed to determine size of piece",
+			zap.Stringer("Satellite ID", satelliteID),
+			zap.Stringer("Piece ID", pieceID),
+			zap.Error(err))
+	}
+
+	// if retain status is enabled, delete pieceid
+	if s.config.Status == Enabled {
+		if err = s.store.Trash(ctx, satelliteID, pieceID); err != nil {
+			s.log.Warn("failed to delete piece",
+				zap.Stringer("Satellite ID", satelliteID),
+				zap.Stringer("Piece ID", pieceID),
+				zap.Error(err))
+			return false, 0
+		}
+	}
+	return true, size
+}
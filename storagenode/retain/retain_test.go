@@ -4,7 +4,9 @@
 package retain_test
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"testing"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	"storj.io/storj/storage/filestore"
 	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore/ioscheduler"
 	"storj.io/storj/storagenode/retain"
 	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
 )
@@ -104,19 +107,19 @@ func TestRetainPieces(t *testing.T) {
 			}
 		}
 
-		retainEnabled := retain.NewService(zaptest.NewLogger(t), store, retain.Config{
+		retainEnabled := retain.NewService(zaptest.NewLogger(t), store, db.Retain(), ioscheduler.New(zaptest.NewLogger(t), ioscheduler.Config{}), retain.Config{
 			Status:      retain.Enabled,
 			Concurrency: 1,
 			MaxTimeSkew: 0,
 		})
 
-		retainDisabled := retain.NewService(zaptest.NewLogger(t), store, retain.Config{
+		retainDisabled := retain.NewService(zaptest.NewLogger(t), store, db.Retain(), ioscheduler.New(zaptest.NewLogger(t), ioscheduler.Config{}), retain.Config{
 			Status:      retain.Disabled,
 			Concurrency: 1,
 			MaxTimeSkew: 0,
 		})
 
-		retainDebug := retain.NewService(zaptest.NewLogger(t), store, retain.Config{
+		retainDebug := retain.NewService(zaptest.NewLogger(t), store, db.Retain(), ioscheduler.New(zaptest.NewLogger(t), ioscheduler.Config{}), retain.Config{
 			Status:      retain.Debug,
 			Concurrency: 1,
 			MaxTimeSkew: 0,
@@ -143,11 +146,11 @@ func TestRetainPieces(t *testing.T) {
 			CreatedBefore: time.Now(),
 			Filter:        filter,
 		}
-		queued := retainDisabled.Queue(req)
+		queued := retainDisabled.Queue(ctx, req)
 		require.True(t, queued)
 		retainDisabled.TestWaitUntilEmpty()
 
-		queued = retainDebug.Queue(req)
+		queued = retainDebug.Queue(ctx, req)
 		require.True(t, queued)
 		retainDebug.TestWaitUntilEmpty()
 
@@ -160,7 +163,7 @@ func TestRetainPieces(t *testing.T) {
 		require.Equal(t, numPieces, len(satellite0Pieces))
 
 		// expect that enabled endpoint deletes the correct pieces
-		queued = retainEnabled.Queue(req)
+		queued = retainEnabled.Queue(ctx, req)
 		require.True(t, queued)
 		retainEnabled.TestWaitUntilEmpty()
 
@@ -194,6 +197,140 @@ func TestRetainPieces(t *testing.T) {
 	})
 }
 
+func TestRetainPiecesSummary(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces(), db.V0PieceInfo(), db.PieceExpirationDB(), db.PieceSpaceUsedDB(), pieces.DefaultConfig)
+		testStore := pieces.StoreForTest{Store: store}
+
+		const numPieces = 10
+		const numPiecesToKeep = 4
+		satellite := testrand.NodeID()
+
+		pieceIDs := generateTestIDs(numPieces)
+		filter := bloomfilter.NewOptimal(numPieces, 0.000000001)
+		for i, id := range pieceIDs {
+			w, err := testStore.WriterForFormatVersion(ctx, satellite, id, filestore.FormatV1)
+			require.NoError(t, err)
+			_, err = w.Write(testrand.Bytes(100 * memory.B))
+			require.NoError(t, err)
+			require.NoError(t, w.Commit(ctx, &pb.PieceHeader{CreationTime: time.Now()}))
+
+			if i < numPiecesToKeep {
+				filter.Add(id)
+			}
+		}
+
+		service := retain.NewService(zaptest.NewLogger(t), store, db.Retain(), ioscheduler.New(zaptest.NewLogger(t), ioscheduler.Config{}), retain.Config{
+			Status:      retain.Enabled,
+			Concurrency: 1,
+		})
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var group errgroup.Group
+		group.Go(func() error {
+			return service.Run(runCtx)
+		})
+
+		queued := service.Queue(ctx, retain.Request{
+			SatelliteID:   satellite,
+			CreatedBefore: time.Now(),
+			Filter:        filter,
+		})
+		require.True(t, queued)
+		service.TestWaitUntilEmpty()
+
+		// the summary of the finished request is saved
+		summaries, err := service.Summaries(ctx)
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, satellite, summaries[0].SatelliteID)
+		require.NotNil(t, summaries[0].FinishedAt)
+		require.EqualValues(t, numPieces, summaries[0].PiecesChecked)
+		require.EqualValues(t, numPieces-numPiecesToKeep, summaries[0].PiecesTrashed)
+		require.NotZero(t, summaries[0].BytesTrashed)
+
+		cancel()
+		err = group.Wait()
+		require.True(t, errs2.IsCanceled(err))
+	})
+}
+
+func TestRetainPiecesResume(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces(), db.V0PieceInfo(), db.PieceExpirationDB(), db.PieceSpaceUsedDB(), pieces.DefaultConfig)
+		testStore := pieces.StoreForTest{Store: store}
+
+		const numPieces = 20
+		satellite := testrand.NodeID()
+
+		pieceIDs := generateTestIDs(numPieces)
+		sort.Slice(pieceIDs, func(i, k int) bool {
+			return bytes.Compare(pieceIDs[i].Bytes(), pieceIDs[k].Bytes()) < 0
+		})
+		for _, id := range pieceIDs {
+			w, err := testStore.WriterForFormatVersion(ctx, satellite, id, filestore.FormatV1)
+			require.NoError(t, err)
+			_, err = w.Write(testrand.Bytes(100 * memory.B))
+			require.NoError(t, err)
+			require.NoError(t, w.Commit(ctx, &pb.PieceHeader{CreationTime: time.Now()}))
+		}
+
+		// save a request which was interrupted halfway, as if the node restarted
+		const numChecked = numPieces / 2
+		req := retain.Request{
+			SatelliteID:   satellite,
+			CreatedBefore: time.Now(),
+			Filter:        bloomfilter.NewOptimal(numPieces, 0.000000001),
+			Progress: retain.Progress{
+				LastPieceID:   pieceIDs[numChecked-1],
+				PiecesChecked: numChecked,
+			},
+		}
+		require.NoError(t, db.Retain().Queue(ctx, req, time.Now()))
+
+		service := retain.NewService(zaptest.NewLogger(t), store, db.Retain(), ioscheduler.New(zaptest.NewLogger(t), ioscheduler.Config{}), retain.Config{
+			Status:             retain.Enabled,
+			Concurrency:        1,
+			CheckpointInterval: 3,
+		})
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var group errgroup.Group
+		group.Go(func() error {
+			return service.Run(runCtx)
+		})
+
+		// the request continues without being queued again
+		require.Eventually(t, func() bool {
+			pending, err := db.Retain().Pending(ctx)
+			require.NoError(t, err)
+			return len(pending) == 0
+		}, 10*time.Second, 10*time.Millisecond)
+		service.TestWaitUntilEmpty()
+
+		// only the pieces after the checkpoint were checked
+		remaining, err := getAllPieceIDs(ctx, store, satellite)
+		require.NoError(t, err)
+		require.ElementsMatch(t, pieceIDs[:numChecked], remaining)
+
+		summaries, err := service.Summaries(ctx)
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.NotNil(t, summaries[0].FinishedAt)
+		require.Equal(t, pieceIDs[numPieces-1], summaries[0].LastPieceID)
+		require.EqualValues(t, numPieces, summaries[0].PiecesChecked)
+		require.EqualValues(t, numPieces-numChecked, summaries[0].PiecesTrashed)
+
+		cancel()
+		err = group.Wait()
+		require.True(t, errs2.IsCanceled(err))
+	})
+}
+
 func getAllPieceIDs(ctx context.Context, store *pieces.Store, satellite storj.NodeID) (pieceIDs []storj.PieceID, err error) {
 	err = store.WalkSatellitePieces(ctx, satellite, func(pieceAccess pieces.StoredPieceAccess) error {
 		pieceIDs = append(pieceIDs, pieceAccess.PieceID())
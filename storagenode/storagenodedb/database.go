@@ -30,6 +30,7 @@ import (
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/pricing"
 	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/retain"
 	"storj.io/storj/storagenode/satellites"
 	"storj.io/storj/storagenode/storageusage"
 )
@@ -104,6 +105,7 @@ type DB struct {
 	notificationsDB   *notificationDB
 	heldamountDB      *heldamountDB
 	pricingDB         *pricingDB
+	retainDB          *retainDB
 
 	SQLDBs map[string]DBContainer
 }
@@ -129,6 +131,7 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	notificationsDB := &notificationDB{}
 	heldamountDB := &heldamountDB{}
 	pricingDB := &pricingDB{}
+	retainDB := &retainDB{}
 
 	db := &DB{
 		log:    log,
@@ -151,6 +154,7 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 		notificationsDB:   notificationsDB,
 		heldamountDB:      heldamountDB,
 		pricingDB:         pricingDB,
+		retainDB:          retainDB,
 
 		SQLDBs: map[string]DBContainer{
 			DeprecatedInfoDBName:  deprecatedInfoDB,
@@ -166,6 +170,7 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 			NotificationsDBName:   notificationsDB,
 			HeldAmountDBName:      heldamountDB,
 			PricingDBName:         pricingDB,
+			RetainDBName:          retainDB,
 		},
 	}
 
@@ -246,6 +251,11 @@ func (db *DB) openDatabases() error {
 	if err != nil {
 		return errs.Combine(err, db.closeDatabases())
 	}
+
+	err = db.openDatabase(RetainDBName)
+	if err != nil {
+		return errs.Combine(err, db.closeDatabases())
+	}
 	return nil
 }
 
@@ -455,6 +465,11 @@ func (db *DB) Pricing() pricing.DB {
 	return db.pricingDB
 }
 
+// Retain returns instance of the Retain database.
+func (db *DB) Retain() retain.DB {
+	return db.retainDB
+}
+
 // RawDatabases are required for testing purposes
 func (db *DB) RawDatabases() map[string]DBContainer {
 	return db.SQLDBs
@@ -1458,6 +1473,25 @@ func (db *DB) Migration(ctx context.Context) *migrate.Migration {
 					return nil
 				}),
 			},
+			{
+				DB:          db.retainDB,
+				Description: "Create retain requests table",
+				Version:     43,
+				Action: migrate.SQL{
+					`CREATE TABLE retain_requests (
+						satellite_id BLOB NOT NULL,
+						created_before TIMESTAMP NOT NULL,
+						filter BLOB,
+						received_at TIMESTAMP NOT NULL,
+						last_piece_id BLOB NOT NULL,
+						pieces_checked INTEGER NOT NULL,
+						pieces_trashed INTEGER NOT NULL,
+						bytes_trashed INTEGER NOT NULL,
+						finished_at TIMESTAMP,
+						PRIMARY KEY ( satellite_id )
+					);`,
+				},
+			},
 		},
 	}
 }
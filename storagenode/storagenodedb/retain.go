@@ -0,0 +1,201 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/storj/storagenode/retain"
+)
+
+// ensures that retainDB implements retain.DB interface.
+var _ retain.DB = (*retainDB)(nil)
+
+// ErrRetain represents errors from the retain database.
+var ErrRetain = errs.Class("retain error")
+
+// RetainDBName represents the database name.
+const RetainDBName = "retain"
+
+// retainDB stores retain requests and their progress.
+//
+// architecture: Database
+type retainDB struct {
+	dbContainerImpl
+}
+
+// Queue stores the request, replacing any earlier request from the same satellite.
+func (db *retainDB) Queue(ctx context.Context, req retain.Request, receivedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := `INSERT OR REPLACE INTO retain_requests (
+			satellite_id,
+			created_before,
+			filter,
+			received_at,
+			last_piece_id,
+			pieces_checked,
+			pieces_trashed,
+			bytes_trashed,
+			finished_at
+		) VALUES(?,?,?,?,?,?,?,?,NULL)`
+
+	_, err = db.ExecContext(ctx, query,
+		req.SatelliteID,
+		req.CreatedBefore.UTC(),
+		req.Filter.Bytes(),
+		receivedAt.UTC(),
+		req.Progress.LastPieceID,
+		req.Progress.PiecesChecked,
+		req.Progress.PiecesTrashed,
+		req.Progress.BytesTrashed,
+	)
+
+	return ErrRetain.Wrap(err)
+}
+
+// Pending returns all requests which haven't finished yet.
+func (db *retainDB) Pending(ctx context.Context) (_ []retain.Request, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT satellite_id,
+			created_before,
+			filter,
+			last_piece_id,
+			pieces_checked,
+			pieces_trashed,
+			bytes_trashed
+		FROM retain_requests WHERE finished_at IS NULL`,
+	)
+	if err != nil {
+		return nil, ErrRetain.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var requests []retain.Request
+	for rows.Next() {
+		var req retain.Request
+		var filter []byte
+
+		err := rows.Scan(
+			&req.SatelliteID,
+			&req.CreatedBefore,
+			&filter,
+			&req.Progress.LastPieceID,
+			&req.Progress.PiecesChecked,
+			&req.Progress.PiecesTrashed,
+			&req.Progress.BytesTrashed,
+		)
+		if err != nil {
+			return nil, ErrRetain.Wrap(err)
+		}
+
+		req.Filter, err = bloomfilter.NewFromBytes(filter)
+		if err != nil {
+			return nil, ErrRetain.Wrap(err)
+		}
+
+		requests = append(requests, req)
+	}
+
+	return requests, ErrRetain.Wrap(rows.Err())
+}
+
+// Checkpoint stores the progress of the request. It does nothing if the request has
+// since been replaced by a newer one.
+func (db *retainDB) Checkpoint(ctx context.Context, req retain.Request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx,
+		`UPDATE retain_requests SET
+			last_piece_id = ?,
+			pieces_checked = ?,
+			pieces_trashed = ?,
+			bytes_trashed = ?
+		WHERE satellite_id = ? AND created_before = ?`,
+		req.Progress.LastPieceID,
+		req.Progress.PiecesChecked,
+		req.Progress.PiecesTrashed,
+		req.Progress.BytesTrashed,
+		req.SatelliteID,
+		req.CreatedBefore.UTC(),
+	)
+
+	return ErrRetain.Wrap(err)
+}
+
+// Finish stores the final progress of the request and drops its bloom filter. It does
+// nothing if the request has since been replaced by a newer one.
+func (db *retainDB) Finish(ctx context.Context, req retain.Request, finishedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx,
+		`UPDATE retain_requests SET
+			filter = NULL,
+			last_piece_id = ?,
+			pieces_checked = ?,
+			pieces_trashed = ?,
+			bytes_trashed = ?,
+			finished_at = ?
+		WHERE satellite_id = ? AND created_before = ?`,
+		req.Progress.LastPieceID,
+		req.Progress.PiecesChecked,
+		req.Progress.PiecesTrashed,
+		req.Progress.BytesTrashed,
+		finishedAt.UTC(),
+		req.SatelliteID,
+		req.CreatedBefore.UTC(),
+	)
+
+	return ErrRetain.Wrap(err)
+}
+
+// Summaries returns the summary of the latest request from every satellite.
+func (db *retainDB) Summaries(ctx context.Context) (_ []retain.Summary, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT satellite_id,
+			created_before,
+			received_at,
+			finished_at,
+			last_piece_id,
+			pieces_checked,
+			pieces_trashed,
+			bytes_trashed
+		FROM retain_requests`,
+	)
+	if err != nil {
+		return nil, ErrRetain.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var summaries []retain.Summary
+	for rows.Next() {
+		var summary retain.Summary
+
+		err := rows.Scan(
+			&summary.SatelliteID,
+			&summary.CreatedBefore,
+			&summary.ReceivedAt,
+			&summary.FinishedAt,
+			&summary.LastPieceID,
+			&summary.PiecesChecked,
+			&summary.PiecesTrashed,
+			&summary.BytesTrashed,
+		)
+		if err != nil {
+			return nil, ErrRetain.Wrap(err)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, ErrRetain.Wrap(rows.Err())
+}
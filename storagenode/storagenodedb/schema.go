@@ -574,6 +574,61 @@ func Schema() map[string]*dbschema.Schema {
 				},
 			},
 		},
+		"retain": &dbschema.Schema{
+			Tables: []*dbschema.Table{
+				&dbschema.Table{
+					Name:       "retain_requests",
+					PrimaryKey: []string{"satellite_id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "bytes_trashed",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "created_before",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "filter",
+							Type:       "BLOB",
+							IsNullable: true,
+						},
+						&dbschema.Column{
+							Name:       "finished_at",
+							Type:       "TIMESTAMP",
+							IsNullable: true,
+						},
+						&dbschema.Column{
+							Name:       "last_piece_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "pieces_checked",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "pieces_trashed",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "received_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+					},
+				},
+			},
+		},
 		"satellites": &dbschema.Schema{
 			Tables: []*dbschema.Table{
 				&dbschema.Table{
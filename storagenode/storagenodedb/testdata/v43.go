@@ -0,0 +1,41 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testdata
+
+import "storj.io/storj/storagenode/storagenodedb"
+
+var v43 = MultiDBState{
+	Version: 43,
+	DBStates: DBStates{
+		storagenodedb.UsedSerialsDBName:     v42.DBStates[storagenodedb.UsedSerialsDBName],
+		storagenodedb.StorageUsageDBName:    v42.DBStates[storagenodedb.StorageUsageDBName],
+		storagenodedb.ReputationDBName:      v42.DBStates[storagenodedb.ReputationDBName],
+		storagenodedb.PieceSpaceUsedDBName:  v42.DBStates[storagenodedb.PieceSpaceUsedDBName],
+		storagenodedb.PieceInfoDBName:       v42.DBStates[storagenodedb.PieceInfoDBName],
+		storagenodedb.PieceExpirationDBName: v42.DBStates[storagenodedb.PieceExpirationDBName],
+		storagenodedb.OrdersDBName:          v42.DBStates[storagenodedb.OrdersDBName],
+		storagenodedb.BandwidthDBName:       v42.DBStates[storagenodedb.BandwidthDBName],
+		storagenodedb.SatellitesDBName:      v42.DBStates[storagenodedb.SatellitesDBName],
+		storagenodedb.DeprecatedInfoDBName:  v42.DBStates[storagenodedb.DeprecatedInfoDBName],
+		storagenodedb.NotificationsDBName:   v42.DBStates[storagenodedb.NotificationsDBName],
+		storagenodedb.HeldAmountDBName:      v42.DBStates[storagenodedb.HeldAmountDBName],
+		storagenodedb.PricingDBName:         v42.DBStates[storagenodedb.PricingDBName],
+		storagenodedb.RetainDBName: &DBState{
+			SQL: `
+				-- table to hold retain requests and their progress
+				CREATE TABLE retain_requests (
+					satellite_id BLOB NOT NULL,
+					created_before TIMESTAMP NOT NULL,
+					filter BLOB,
+					received_at TIMESTAMP NOT NULL,
+					last_piece_id BLOB NOT NULL,
+					pieces_checked INTEGER NOT NULL,
+					pieces_trashed INTEGER NOT NULL,
+					bytes_trashed INTEGER NOT NULL,
+					finished_at TIMESTAMP,
+					PRIMARY KEY ( satellite_id )
+				);`,
+		},
+	},
+}
@@ -33,7 +33,8 @@ export class ApiKeysApiGql extends BaseGql implements ApiKeysApi {
                         apiKeys {
                             id,
                             name,
-                            createdAt
+                            createdAt,
+                            requestCount
                         }
                         search,
                         limit,
@@ -130,7 +131,7 @@ export class ApiKeysApiGql extends BaseGql implements ApiKeysApi {
 
         const apiKeysPage: ApiKeysPage = new ApiKeysPage();
 
-        apiKeysPage.apiKeys = page.apiKeys.map(key => new ApiKey(key.id, key.name, key.createdAt, ''));
+        apiKeysPage.apiKeys = page.apiKeys.map(key => new ApiKey(key.id, key.name, key.createdAt, '', key.requestCount));
 
         apiKeysPage.search = page.search;
         apiKeysPage.limit = page.limit;
@@ -79,13 +79,15 @@ export class ApiKey {
     public secret: string;
     public name: string;
     public createdAt: string;
+    public requestCount: number;
     public isSelected: boolean = false;
 
-    constructor(id: string, name: string, createdAt: string, secret: string) {
+    constructor(id: string, name: string, createdAt: string, secret: string, requestCount: number = 0) {
         this.id = id || '';
         this.name = name || '';
         this.createdAt = createdAt || '';
         this.secret = secret || '';
+        this.requestCount = requestCount || 0;
 
         this.isSelected = false;
     }
@@ -15,6 +15,6 @@ describe('ApiKeysItem.vue', () => {
     it('renders correctly with default props', () => {
         const wrapper = mount(ApiKeysItem);
 
-        expect(wrapper.vm.$props.itemData).toEqual({ createdAt: '', id: '', isSelected: false, name: '', secret: '' });
+        expect(wrapper.vm.$props.itemData).toEqual({ createdAt: '', id: '', isSelected: false, name: '', requestCount: 0, secret: '' });
     });
 });